@@ -25,6 +25,7 @@ import (
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
 	"vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
 
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
@@ -74,6 +75,16 @@ var (
 		Keyspace string
 	}{}
 
+	checkOptions = struct {
+		Keyspace                     string
+		Cells                        []string
+		TabletTypes                  []topodatapb.TabletType
+		TabletTypesInPreferenceOrder bool
+		Repair                       bool
+		RepairBatchSize              int64
+		MaxReportSampleRows          int64
+	}{}
+
 	parseAndValidateCreate = func(cmd *cobra.Command, args []string) error {
 		if createOptions.TableName == "" { // Use vindex name
 			createOptions.TableName = baseOptions.Name
@@ -142,6 +153,18 @@ var (
 		RunE:                  commandCancel,
 	}
 
+	// check makes a LookupVindexCheck call to a vtctld.
+	check = &cobra.Command{
+		Use:                   "check",
+		Short:                 "Compare the Lookup Vindex with its owner table, report the orphaned and missing entries of the lookup table, and optionally repair them.",
+		Example:               `vtctldclient --server localhost:15999 LookupVindex --name corder_lookup_vdx --table-keyspace customer check --keyspace customer --repair`,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Check"},
+		Args:                  cobra.NoArgs,
+		RunE:                  commandCheck,
+	}
+
 	// create makes a LookupVindexCreate call to a vtctld.
 	create = &cobra.Command{
 		Use:                   "create",
@@ -199,6 +222,41 @@ func commandCancel(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func commandCheck(cmd *cobra.Command, args []string) error {
+	if checkOptions.Keyspace == "" {
+		checkOptions.Keyspace = baseOptions.TableKeyspace
+	}
+	tsp := tabletmanagerdatapb.TabletSelectionPreference_ANY
+	if checkOptions.TabletTypesInPreferenceOrder {
+		tsp = tabletmanagerdatapb.TabletSelectionPreference_INORDER
+	}
+	cli.FinishedParsing(cmd)
+
+	resp, err := common.GetClient().LookupVindexCheck(common.GetCommandCtx(), &vtctldatapb.LookupVindexCheckRequest{
+		Keyspace:                  checkOptions.Keyspace,
+		Name:                      baseOptions.Name,
+		TableKeyspace:             baseOptions.TableKeyspace,
+		Cells:                     checkOptions.Cells,
+		TabletTypes:               checkOptions.TabletTypes,
+		TabletSelectionPreference: tsp,
+		Repair:                    checkOptions.Repair,
+		RepairBatchSize:           checkOptions.RepairBatchSize,
+		MaxReportSampleRows:       checkOptions.MaxReportSampleRows,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSONPretty(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
 func commandCreate(cmd *cobra.Command, args []string) error {
 	tsp := common.GetTabletSelectionPreference(cmd)
 	cli.FinishedParsing(cmd)
@@ -310,6 +368,17 @@ func registerCommands(root *cobra.Command) {
 	externalize.Flags().StringVar(&externalizeOptions.Keyspace, "keyspace", "", "The keyspace containing the Lookup Vindex. If no value is specified then the table-keyspace will be used.")
 	base.AddCommand(externalize)
 
+	// This compares the lookup table with the owner table of
+	// the lookup vindex, and optionally repairs the lookup table.
+	check.Flags().StringVar(&checkOptions.Keyspace, "keyspace", "", "The keyspace containing the Lookup Vindex. If no value is specified then the table-keyspace will be used.")
+	check.Flags().StringSliceVar(&checkOptions.Cells, "cells", nil, "Cells to look in for tablets to stream the owner and lookup tables from.")
+	check.Flags().Var((*topoprotopb.TabletTypeListFlag)(&checkOptions.TabletTypes), "tablet-types", "Tablet types to stream the owner and lookup tables from.")
+	check.Flags().BoolVar(&checkOptions.TabletTypesInPreferenceOrder, "tablet-types-in-preference-order", true, "When performing tablet selection, look for candidates in the type order as they are listed in the tablet-types flag.")
+	check.Flags().BoolVar(&checkOptions.Repair, "repair", false, "Delete the orphaned entries from the lookup table and insert the missing ones, in batches throttled by the tablet throttler of the lookup shards.")
+	check.Flags().Int64Var(&checkOptions.RepairBatchSize, "repair-batch-size", 1000, "The maximum number of entries written to a lookup shard by a single repair statement.")
+	check.Flags().Int64Var(&checkOptions.MaxReportSampleRows, "max-report-sample-rows", 10, "The maximum number of orphaned and missing entries to report.")
+	base.AddCommand(check)
+
 	// The cancel command deletes the VReplication workflow used
 	// to backfill the lookup vindex. It ends up making a
 	// WorkflowDelete VtctldServer call.
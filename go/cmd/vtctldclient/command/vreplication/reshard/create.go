@@ -17,19 +17,25 @@ limitations under the License.
 package reshard
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/spf13/cobra"
 
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
 	"vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
+	"vitess.io/vitess/go/vt/key"
 
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 var (
 	reshardCreateOptions = struct {
-		sourceShards   []string
-		targetShards   []string
-		skipSchemaCopy bool
+		sourceShards       []string
+		targetShards       []string
+		targetShardWeights []uint
+		skipSchemaCopy     bool
 	}{}
 
 	// reshardCreate makes a ReshardCreate gRPC call to a vtctld.
@@ -59,6 +65,14 @@ func commandReshardCreate(cmd *cobra.Command, args []string) error {
 	tsp := common.GetTabletSelectionPreference(cmd)
 	cli.FinishedParsing(cmd)
 
+	targetShards := reshardCreateOptions.targetShards
+	if len(reshardCreateOptions.targetShardWeights) > 0 {
+		targetShards, err = targetShardsFromWeights(common.GetCommandCtx(), common.BaseOptions.TargetKeyspace, reshardCreateOptions.targetShardWeights)
+		if err != nil {
+			return err
+		}
+	}
+
 	req := &vtctldatapb.ReshardCreateRequest{
 		Workflow:                  common.BaseOptions.Workflow,
 		Keyspace:                  common.BaseOptions.TargetKeyspace,
@@ -70,7 +84,7 @@ func commandReshardCreate(cmd *cobra.Command, args []string) error {
 		AutoStart:                 common.CreateOptions.AutoStart,
 		StopAfterCopy:             common.CreateOptions.StopAfterCopy,
 		SourceShards:              reshardCreateOptions.sourceShards,
-		TargetShards:              reshardCreateOptions.targetShards,
+		TargetShards:              targetShards,
 		SkipSchemaCopy:            reshardCreateOptions.skipSchemaCopy,
 	}
 	resp, err := common.GetClient().ReshardCreate(common.GetCommandCtx(), req)
@@ -83,10 +97,54 @@ func commandReshardCreate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// targetShardsFromWeights returns the names of the shards whose sizes are proportional
+// to the given weights, except for the shards whose key ranges are already served by
+// the keyspace.
+func targetShardsFromWeights(ctx context.Context, keyspace string, weights []uint) ([]string, error) {
+	keyRanges, err := key.GenerateWeightedShardKeyRanges(weights)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target shard weights: %w", err)
+	}
+	resp, err := common.GetClient().FindAllShardsInKeyspace(ctx, &vtctldatapb.FindAllShardsInKeyspaceRequest{
+		Keyspace: keyspace,
+	})
+	if err != nil {
+		return nil, err
+	}
+	shards := newShards(keyRanges, resp.Shards)
+	if len(shards) == 0 {
+		return nil, fmt.Errorf("the shards of these weights are already served by keyspace %s, there is nothing to reshard", keyspace)
+	}
+	return shards, nil
+}
+
+// newShards returns the names of the given key ranges that are not the key range
+// of a serving shard. The shards whose key ranges do not change with the layout
+// are left out of the Reshard workflow, which would otherwise reject them as
+// being both a source and a target.
+func newShards(keyRanges []*topodatapb.KeyRange, existing map[string]*vtctldatapb.Shard) []string {
+	var shards []string
+	for _, kr := range keyRanges {
+		served := false
+		for _, shard := range existing {
+			if shard.GetShard().GetIsPrimaryServing() && key.KeyRangeEqual(shard.GetShard().GetKeyRange(), kr) {
+				served = true
+				break
+			}
+		}
+		if !served {
+			shards = append(shards, key.KeyRangeString(kr))
+		}
+	}
+	return shards
+}
+
 func registerCreateCommand(root *cobra.Command) {
 	common.AddCommonCreateFlags(reshardCreate)
 	reshardCreate.Flags().StringSliceVar(&reshardCreateOptions.sourceShards, "source-shards", nil, "Source shards.")
 	reshardCreate.Flags().StringSliceVar(&reshardCreateOptions.targetShards, "target-shards", nil, "Target shards.")
+	reshardCreate.Flags().UintSliceVar(&reshardCreateOptions.targetShardWeights, "target-shard-weights", nil, "Weights of the shards of the keyspace, in key range order. The target shards are the shards whose key ranges are proportional to these weights, except for the ones already served.")
+	reshardCreate.MarkFlagsMutuallyExclusive("target-shards", "target-shard-weights")
 	reshardCreate.Flags().BoolVar(&reshardCreateOptions.skipSchemaCopy, "skip-schema-copy", false, "Skip copying the schema from the source shards to the target shards.")
 	root.AddCommand(reshardCreate)
 }
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reshard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/topo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestNewShards(t *testing.T) {
+	shardKeyRanges := func(weights []uint) []*topodatapb.KeyRange {
+		keyRanges, err := key.GenerateWeightedShardKeyRanges(weights)
+		require.NoError(t, err)
+		return keyRanges
+	}
+	shards := func(serving bool, names ...string) map[string]*vtctldatapb.Shard {
+		out := make(map[string]*vtctldatapb.Shard, len(names))
+		for _, name := range names {
+			_, kr, err := topo.ValidateShardName(name)
+			require.NoError(t, err)
+			out[name] = &vtctldatapb.Shard{
+				Name:  name,
+				Shard: &topodatapb.Shard{KeyRange: kr, IsPrimaryServing: serving},
+			}
+		}
+		return out
+	}
+
+	testCases := []struct {
+		name         string
+		shardWeights []uint
+		existing     map[string]*vtctldatapb.Shard
+		want         []string
+	}{{
+		name:         "unsharded keyspace",
+		shardWeights: []uint{1, 1, 2},
+		existing:     shards(true, "0"),
+		want:         []string{"-40", "40-80", "80-"},
+	}, {
+		// Splitting the weight of the last shard only moves the boundaries of that shard.
+		name:         "weight change that moves some boundaries",
+		shardWeights: []uint{1, 1, 1, 1},
+		existing:     shards(true, "-40", "40-80", "80-"),
+		want:         []string{"80-c0", "c0-"},
+	}, {
+		name:         "weight change that moves every boundary",
+		shardWeights: []uint{1, 1, 1},
+		existing:     shards(true, "-40", "40-80", "80-"),
+		want:         []string{"-5555", "5555-aaaa", "aaaa-"},
+	}, {
+		// The target shards were already created, but are not serving yet.
+		name:         "existing target shards",
+		shardWeights: []uint{1, 1, 1, 1},
+		existing: func() map[string]*vtctldatapb.Shard {
+			existing := shards(true, "-40", "40-80", "80-")
+			for name, shard := range shards(false, "80-c0", "c0-") {
+				existing[name] = shard
+			}
+			return existing
+		}(),
+		want: []string{"80-c0", "c0-"},
+	}, {
+		name:         "unchanged layout",
+		shardWeights: []uint{1, 1, 2},
+		existing:     shards(true, "-40", "40-80", "80-"),
+		want:         nil,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, newShards(shardKeyRanges(tc.shardWeights), tc.existing))
+		})
+	}
+}
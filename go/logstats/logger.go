@@ -188,6 +188,27 @@ func (log *Logger) Strings(strs []string) {
 	log.b = append(log.b, ']')
 }
 
+// StringMap writes the map as a JSON object sorted by key, in both the JSON
+// and the text formats.
+func (log *Logger) StringMap(m map[string]string) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	log.b = append(log.b, '{')
+	for i, k := range keys {
+		if i > 0 {
+			log.b = append(log.b, ',', ' ')
+		}
+		log.b = strconv.AppendQuote(log.b, k)
+		log.b = append(log.b, ':', ' ')
+		log.b = strconv.AppendQuote(log.b, m[k])
+	}
+	log.b = append(log.b, '}')
+}
+
 func (log *Logger) Flush(w io.Writer) (err error) {
 	if log.json {
 		log.b = append(log.b, '}')
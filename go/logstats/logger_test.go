@@ -221,6 +221,20 @@ func TestStrings(t *testing.T) {
 	assert.Equal(t, []byte("{[\"testValue1\"]"), tl.b)
 }
 
+func TestStringMap(t *testing.T) {
+	tl := Logger{}
+	tl.Init(false)
+
+	tl.StringMap(map[string]string{"tenant": "acme", "request_id": "42"})
+	assert.Equal(t, []byte(`{"request_id": "42", "tenant": "acme"}`), tl.b)
+
+	tl.b = []byte{}
+	tl.Init(true)
+
+	tl.StringMap(nil)
+	assert.Equal(t, []byte("{{}"), tl.b)
+}
+
 var calledValue []byte
 
 type mockWriter struct{}
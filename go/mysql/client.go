@@ -174,7 +174,7 @@ func Connect(ctx context.Context, params *ConnParams) (*Conn, error) {
 // Ping implements mysql ping command.
 func (c *Conn) Ping() error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
 	data, pos := c.startEphemeralPacketWithHeader(1)
 	data[pos] = ComPing
 
@@ -229,6 +229,16 @@ func (c *Conn) clientHandshake(params *ConnParams) error {
 		c.Capabilities = capabilities & (CapabilityClientDeprecateEOF)
 	}
 
+	// Ask for compression if the server supports it.
+	compression, err := params.compressionCapability()
+	if err != nil {
+		return err
+	}
+	c.Capabilities |= capabilities & compression
+
+	// Ask for query attributes if the server supports them.
+	c.Capabilities |= capabilities & CapabilityClientQueryAttributes & uint32(params.Flags)
+
 	// Handle switch to SSL if necessary.
 	if params.SslEnabled() {
 		// If client asked for SSL, but server doesn't support it,
@@ -306,6 +316,11 @@ func (c *Conn) clientHandshake(params *ConnParams) error {
 		return err
 	}
 
+	// Everything after the OK packet is compressed, if the server agreed to it.
+	if err := c.enableCompression(params.ZstdCompressionLevel); err != nil {
+		return sqlerror.NewSQLErrorf(sqlerror.CRUnknownError, sqlerror.SSUnknownSQLState, "cannot enable compression: %v", err)
+	}
+
 	// If the server didn't support DbName in its handshake, set
 	// it now. This is what the 'mysql' client does.
 	if capabilities&CapabilityClientConnectWithDB == 0 && params.DbName != "" {
@@ -479,7 +494,11 @@ func (c *Conn) writeSSLRequest(capabilities uint32, characterSet uint8, params *
 		// CapabilityClientSessionTrack, we also support it.
 		c.Capabilities&CapabilityClientSessionTrack |
 		// Pass-through ClientFoundRows flag.
-		CapabilityClientFoundRows&uint32(params.Flags)
+		CapabilityClientFoundRows&uint32(params.Flags) |
+		// The compression we asked for.
+		c.Capabilities&(CapabilityClientCompress|CapabilityClientZstdCompressionAlgorithm) |
+		// The query attributes we asked for.
+		c.Capabilities&CapabilityClientQueryAttributes
 
 	length :=
 		4 + // Client capability flags.
@@ -537,7 +556,11 @@ func (c *Conn) writeHandshakeResponse41(capabilities uint32, scrambledPassword [
 		CapabilityClientFoundRows&uint32(params.Flags) |
 		// If the server supported
 		// CapabilityClientSessionTrack, we also support it.
-		c.Capabilities&CapabilityClientSessionTrack
+		c.Capabilities&CapabilityClientSessionTrack |
+		// The compression we asked for.
+		c.Capabilities&(CapabilityClientCompress|CapabilityClientZstdCompressionAlgorithm) |
+		// The query attributes we asked for.
+		c.Capabilities&CapabilityClientQueryAttributes
 
 	// FIXME(alainjobart) add multi statement.
 
@@ -564,6 +587,15 @@ func (c *Conn) writeHandshakeResponse41(capabilities uint32, scrambledPassword [
 		length++
 	}
 
+	// Add the zstd compression level if we ask for zstd.
+	zstdLevel := params.ZstdCompressionLevel
+	if zstdLevel <= 0 {
+		zstdLevel = DefaultZstdCompressionLevel
+	}
+	if capabilityFlags&CapabilityClientZstdCompressionAlgorithm != 0 {
+		length++
+	}
+
 	data, pos := c.startEphemeralPacketWithHeader(length)
 
 	// Client capability flags.
@@ -600,6 +632,11 @@ func (c *Conn) writeHandshakeResponse41(capabilities uint32, scrambledPassword [
 	// Assume native client during response
 	pos = writeNullString(data, pos, string(c.authPluginName))
 
+	// zstd compression level.
+	if capabilityFlags&CapabilityClientZstdCompressionAlgorithm != 0 {
+		pos = writeByte(data, pos, byte(zstdLevel))
+	}
+
 	// Sanity-check the length.
 	if pos != len(data) {
 		return sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "writeHandshakeResponse41: only packed %v bytes, out of %v allocated", pos, len(data))
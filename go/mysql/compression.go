@@ -0,0 +1,319 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zlib"
+	"github.com/klauspost/compress/zstd"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// This file contains the compressed protocol.
+//
+// Once compression was negotiated in the handshake, everything is sent
+// in compressed packets, which are made of a 7 bytes header followed by a payload:
+//   - 3 bytes: length of the payload.
+//   - 1 byte: sequence number, which is independent of the sequence number of the packets.
+//   - 3 bytes: length of the payload once uncompressed, or 0 if the payload is not compressed.
+//
+// Once uncompressed, the payloads are the stream of regular packets, headers included.
+// A compressed packet can hold several regular packets, and a regular packet can span
+// several compressed packets.
+// See https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_basic_compression.html
+
+const (
+	// CompressionZlib is the zlib compression, negotiated with CapabilityClientCompress.
+	CompressionZlib = "zlib"
+
+	// CompressionZstd is the zstd compression, negotiated with CapabilityClientZstdCompressionAlgorithm.
+	CompressionZstd = "zstd"
+
+	// DefaultZstdCompressionLevel is the zstd compression level used when none is set.
+	DefaultZstdCompressionLevel = 3
+
+	compressedPacketHeaderSize = 7
+
+	// minCompressLength is the length under which payloads are sent uncompressed, as MySQL does.
+	minCompressLength = 50
+)
+
+var (
+	zlibWriters = sync.Pool{New: func() any { return zlib.NewWriter(nil) }}
+	zlibReaders sync.Pool
+
+	// zstdEncoders holds one encoder per encoder level. They are shared by all the
+	// connections, as EncodeAll can be called concurrently.
+	zstdEncodersMu sync.Mutex
+	zstdEncoders   = map[zstd.EncoderLevel]*zstd.Encoder{}
+)
+
+// zstdEncoder returns the encoder for the given zstd compression level
+func zstdEncoder(level int) (*zstd.Encoder, error) {
+	encoderLevel := zstd.EncoderLevelFromZstd(level)
+
+	zstdEncodersMu.Lock()
+	defer zstdEncodersMu.Unlock()
+	if enc, ok := zstdEncoders[encoderLevel]; ok {
+		return enc, nil
+	}
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(encoderLevel))
+	if err != nil {
+		return nil, err
+	}
+	zstdEncoders[encoderLevel] = enc
+	return enc, nil
+}
+
+// enableCompression switches the connection to the compressed protocol, if compression
+// was negotiated in the handshake. It must be called right after the OK packet of the handshake.
+func (c *Conn) enableCompression(zstdLevel int) error {
+	var algorithm string
+	switch {
+	case c.Capabilities&CapabilityClientZstdCompressionAlgorithm != 0:
+		algorithm = CompressionZstd
+	case c.Capabilities&CapabilityClientCompress != 0:
+		algorithm = CompressionZlib
+	default:
+		return nil
+	}
+
+	w := &compressedWriter{c: c, w: c.conn, algorithm: algorithm}
+	if algorithm == CompressionZstd {
+		if zstdLevel <= 0 {
+			zstdLevel = DefaultZstdCompressionLevel
+		}
+		enc, err := zstdEncoder(zstdLevel)
+		if err != nil {
+			return err
+		}
+		w.zstd = enc
+	}
+
+	c.bufMu.Lock()
+	defer c.bufMu.Unlock()
+	c.compressedReader = &compressedReader{c: c, r: c.getReader(), algorithm: algorithm}
+	c.compressedWriter = w
+	if c.bufferedWriter != nil {
+		// make sure nothing buffered so far gets compressed
+		if err := c.bufferedWriter.Flush(); err != nil {
+			return err
+		}
+		c.bufferedWriter.Reset(w)
+	}
+	return nil
+}
+
+// Compression returns the compression algorithm of the connection, or an empty string
+// if the connection is not compressed.
+func (c *Conn) Compression() string {
+	if c.compressedWriter == nil {
+		return ""
+	}
+	return c.compressedWriter.algorithm
+}
+
+// compressedReader reads the compressed packets, and returns their uncompressed payloads.
+type compressedReader struct {
+	c         *Conn
+	r         io.Reader
+	algorithm string
+
+	// payload is the compressed payload of the last packet, and data its uncompressed version.
+	// Both are reused from one packet to the next.
+	payload []byte
+	data    []byte
+
+	// buf is what was not read yet from the last packet.
+	buf []byte
+
+	zlib io.ReadCloser
+}
+
+// Read implements the io.Reader interface
+func (cr *compressedReader) Read(p []byte) (int, error) {
+	for len(cr.buf) == 0 {
+		if err := cr.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, cr.buf)
+	cr.buf = cr.buf[n:]
+	return n, nil
+}
+
+// next reads the next compressed packet
+func (cr *compressedReader) next() error {
+	var header [compressedPacketHeaderSize]byte
+	if _, err := io.ReadFull(cr.r, header[:]); err != nil {
+		// io.EOF is returned as is, so a client disconnecting between two commands is not an error
+		return err
+	}
+	length := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
+	// Like the MySQL clients, we don't check the sequence number of the packets we read,
+	// but we start from it for the packets we write.
+	cr.c.compressedSequence = header[3] + 1
+	uncompressedLength := int(uint32(header[4]) | uint32(header[5])<<8 | uint32(header[6])<<16)
+
+	if cap(cr.payload) < length {
+		cr.payload = make([]byte, length)
+	}
+	cr.payload = cr.payload[:length]
+	if _, err := io.ReadFull(cr.r, cr.payload); err != nil {
+		return vterrors.Wrapf(err, "io.ReadFull(compressed packet body of length %v) failed", length)
+	}
+
+	if uncompressedLength == 0 {
+		cr.buf = cr.payload
+		return nil
+	}
+
+	if cap(cr.data) < uncompressedLength {
+		cr.data = make([]byte, uncompressedLength)
+	}
+	cr.data = cr.data[:uncompressedLength]
+	if err := cr.decompress(cr.data, cr.payload); err != nil {
+		return err
+	}
+	cr.buf = cr.data
+	return nil
+}
+
+// decompress uncompresses src into dst, which must be exactly as long as the uncompressed payload
+func (cr *compressedReader) decompress(dst, src []byte) error {
+	switch cr.algorithm {
+	case CompressionZstd:
+		out, err := statelessDecoder.DecodeAll(src, dst[:0])
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot decompress zstd packet")
+		}
+		if len(out) != len(dst) {
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "decompressed zstd packet has length %v, expected %v", len(out), len(dst))
+		}
+		if &out[0] != &dst[0] {
+			copy(dst, out)
+		}
+		return nil
+	default:
+		zr, err := cr.zlibReader(src)
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot decompress zlib packet")
+		}
+		defer zlibReaders.Put(zr)
+		if _, err := io.ReadFull(zr, dst); err != nil {
+			return vterrors.Wrapf(err, "cannot decompress zlib packet")
+		}
+		return nil
+	}
+}
+
+// zlibReader returns a zlib reader from the pool, reading from src
+func (cr *compressedReader) zlibReader(src []byte) (io.ReadCloser, error) {
+	if zr, ok := zlibReaders.Get().(io.ReadCloser); ok {
+		if err := zr.(zlib.Resetter).Reset(bytes.NewReader(src), nil); err != nil {
+			return nil, err
+		}
+		return zr, nil
+	}
+	return zlib.NewReader(bytes.NewReader(src))
+}
+
+// compressedWriter sends everything that is written to it in compressed packets.
+// Every call to Write sends its data right away, so it is meant to be used
+// behind the buffered writer of the connection when buffering.
+type compressedWriter struct {
+	c         *Conn
+	w         io.Writer
+	algorithm string
+	zstd      *zstd.Encoder
+
+	// buf holds the packet being written, and is reused from one packet to the next.
+	buf []byte
+}
+
+// Write implements the io.Writer interface
+func (cw *compressedWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		// the uncompressed length of a packet has to fit in 3 bytes too
+		chunk := p[:min(len(p), MaxPacketSize)]
+		if err := cw.writePacket(chunk); err != nil {
+			return written, err
+		}
+		written += len(chunk)
+		p = p[len(chunk):]
+	}
+	return written, nil
+}
+
+// writePacket sends a single compressed packet
+func (cw *compressedWriter) writePacket(payload []byte) error {
+	packet := cw.buf[:0]
+	packet = append(packet, make([]byte, compressedPacketHeaderSize)...)
+
+	uncompressedLength := 0
+	if len(payload) >= minCompressLength {
+		packet = cw.compress(packet, payload)
+		if len(packet)-compressedPacketHeaderSize < len(payload) {
+			uncompressedLength = len(payload)
+		}
+	}
+	if uncompressedLength == 0 {
+		// the payload is too short, or doesn't compress
+		packet = append(packet[:compressedPacketHeaderSize], payload...)
+	}
+
+	length := len(packet) - compressedPacketHeaderSize
+	packet[0] = byte(length)
+	packet[1] = byte(length >> 8)
+	packet[2] = byte(length >> 16)
+	packet[3] = cw.c.compressedSequence
+	packet[4] = byte(uncompressedLength)
+	packet[5] = byte(uncompressedLength >> 8)
+	packet[6] = byte(uncompressedLength >> 16)
+	cw.c.compressedSequence++
+
+	cw.buf = packet[:0]
+	if n, err := cw.w.Write(packet); err != nil {
+		return vterrors.Wrapf(err, "Write(compressed packet) failed")
+	} else if n != len(packet) {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "Write(compressed packet) returned a short write: %v < %v", n, len(packet))
+	}
+	return nil
+}
+
+// compress appends the compressed payload to dst
+func (cw *compressedWriter) compress(dst, payload []byte) []byte {
+	if cw.zstd != nil {
+		return cw.zstd.EncodeAll(payload, dst)
+	}
+
+	buf := bytes.NewBuffer(dst)
+	zw := zlibWriters.Get().(*zlib.Writer)
+	defer zlibWriters.Put(zw)
+	zw.Reset(buf)
+	// writes to a bytes.Buffer can't fail
+	_, _ = zw.Write(payload)
+	_ = zw.Close()
+	return buf.Bytes()
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+)
+
+func TestCompressedPackets(t *testing.T) {
+	for _, tcase := range []struct {
+		algorithm  string
+		capability uint32
+	}{
+		{CompressionZlib, CapabilityClientCompress},
+		{CompressionZstd, CapabilityClientZstdCompressionAlgorithm},
+	} {
+		t.Run(tcase.algorithm, func(t *testing.T) {
+			listener, sConn, cConn := createSocketPair(t)
+			defer func() {
+				listener.Close()
+				sConn.Close()
+				cConn.Close()
+			}()
+
+			for _, c := range []*Conn{sConn, cConn} {
+				c.Capabilities |= tcase.capability
+				require.NoError(t, c.enableCompression(0))
+				assert.Equal(t, tcase.algorithm, c.Compression())
+			}
+
+			random := make([]byte, 100000)
+			_, err := rand.Read(random)
+			require.NoError(t, err)
+
+			for _, data := range [][]byte{
+				// too short to be compressed
+				{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+				{},
+				// compressed
+				bytes.Repeat([]byte("compressible "), 1000),
+				// doesn't compress
+				random,
+				// more than one packet, and more than one compressed packet
+				bytes.Repeat([]byte{0xab}, MaxPacketSize+1000),
+			} {
+				for _, write := range []func(t *testing.T, cConn *Conn, data []byte){useWritePacket, useWriteEphemeralPacketBuffered, useWriteEphemeralPacketDirect} {
+					verifyPacketCommsSpecific(t, cConn, data, write, sConn.ReadPacket)
+					verifyPacketCommsSpecific(t, cConn, data, write, sConn.readEphemeralPacket)
+					sConn.recycleReadPacket()
+				}
+			}
+		})
+	}
+}
+
+func TestCompressedPacketFormat(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	cConn.Capabilities |= CapabilityClientCompress
+	require.NoError(t, cConn.enableCompression(0))
+
+	// a short packet is sent uncompressed, in a compressed packet of its own
+	errc := make(chan error, 1)
+	go func() {
+		errc <- cConn.writePacket([]byte{0, 0, 0, 0, ComPing})
+	}()
+	header := make([]byte, compressedPacketHeaderSize+packetHeaderSize+1)
+	_, err := io.ReadFull(sConn.conn, header)
+	require.NoError(t, err)
+	require.NoError(t, <-errc)
+	assert.Equal(t, []byte{5, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, ComPing}, header)
+	assert.EqualValues(t, 1, cConn.compressedSequence)
+
+	// the sequence numbers start over with every command
+	cConn.resetSequence()
+	assert.EqualValues(t, 0, cConn.compressedSequence)
+}
+
+func TestServerCompression(t *testing.T) {
+	th := &testHandler{}
+
+	l, err := NewListener("tcp", "127.0.0.1:", NewAuthServerNone(), th, 0, 0, false, false, 0, 0)
+	require.NoError(t, err)
+	defer l.Close()
+	l.EnableCompression.Store(true)
+	l.EnableZstdCompression.Store(true)
+	go l.Accept()
+
+	host, port := getHostPort(t, l.Addr())
+	for _, algorithm := range []string{"", CompressionZlib, CompressionZstd} {
+		t.Run(algorithm, func(t *testing.T) {
+			params := &ConnParams{
+				Host:                 host,
+				Port:                 port,
+				Compression:          algorithm,
+				ZstdCompressionLevel: 7,
+			}
+			c, err := Connect(context.Background(), params)
+			require.NoError(t, err)
+			defer c.Close()
+
+			assert.Equal(t, algorithm, c.Compression())
+			assert.Equal(t, algorithm, th.LastConn().Compression())
+			if algorithm == CompressionZstd {
+				assert.Equal(t, 7, th.LastConn().zstdCompressionLevel)
+			}
+
+			// more than one command, so the sequence numbers start over
+			for i := 0; i < 3; i++ {
+				result, err := c.ExecuteFetch("select rows", 10, true)
+				require.NoError(t, err)
+				assert.Equal(t, selectRowsResult.Rows, result.Rows)
+			}
+
+			// a result that needs more than one compressed packet
+			big := &sqltypes.Result{
+				Fields: selectRowsResult.Fields,
+				Rows: [][]sqltypes.Value{{
+					sqltypes.NewInt32(1),
+					sqltypes.NewVarChar(string(bytes.Repeat([]byte("x"), 2*MaxPacketSize))),
+				}},
+			}
+			th.mu.Lock()
+			th.result = big
+			th.mu.Unlock()
+			defer func() {
+				th.mu.Lock()
+				th.result = nil
+				th.mu.Unlock()
+			}()
+			result, err := c.ExecuteFetch("select big", 10, true)
+			require.NoError(t, err)
+			assert.Equal(t, big.Rows, result.Rows)
+		})
+	}
+}
+
+func TestServerCompressionDisabled(t *testing.T) {
+	th := &testHandler{}
+
+	l, err := NewListener("tcp", "127.0.0.1:", NewAuthServerNone(), th, 0, 0, false, false, 0, 0)
+	require.NoError(t, err)
+	defer l.Close()
+	go l.Accept()
+
+	host, port := getHostPort(t, l.Addr())
+	params := &ConnParams{
+		Host:        host,
+		Port:        port,
+		Compression: CompressionZstd,
+	}
+	c, err := Connect(context.Background(), params)
+	require.NoError(t, err)
+	defer c.Close()
+
+	// the server doesn't support compression, so the connection is not compressed
+	assert.Empty(t, c.Compression())
+	assert.Empty(t, th.LastConn().Compression())
+	result, err := c.ExecuteFetch("select rows", 10, true)
+	require.NoError(t, err)
+	assert.Equal(t, selectRowsResult.Rows, result.Rows)
+
+	params.Compression = "lz4"
+	_, err = Connect(context.Background(), params)
+	assert.ErrorContains(t, err, "unknown compression algorithm: lz4")
+}
@@ -2,3 +2,5 @@ package config
 
 const DefaultSQLMode = "ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION"
 const DefaultMySQLVersion = "8.0.30"
+const DefaultGroupConcatMaxLen = 1024
+const DefaultBlockEncryptionMode = "aes-128-ecb"
@@ -87,7 +87,8 @@ type Conn struct {
 	// fields, this is set to an empty array (but not nil).
 	fields []*querypb.Field
 
-	// salt is sent by the server during initial handshake to be used for authentication
+	// salt is sent by the server during initial handshake to be used for authentication.
+	// The server keeps it too, as COM_CHANGE_USER is authenticated with it.
 	salt []byte
 
 	// authPluginName is the name of server's authentication plugin.
@@ -151,6 +152,15 @@ type Conn struct {
 	// PrepareData is the map to use a prepared statement.
 	PrepareData map[uint32]*PrepareData
 
+	// streamingCursor is the cursor whose handler is still streaming its rows, if any.
+	// It is only used by the server.
+	streamingCursor *stmtCursor
+
+	// queryAttributes are the query attributes of CapabilityClientQueryAttributes.
+	// On the server, they are the attributes of the command being handled.
+	// On the client, they are sent along with the following queries.
+	queryAttributes map[string]string
+
 	// protects the bufferedWriter and bufferedReader
 	bufMu sync.Mutex
 
@@ -194,6 +204,18 @@ type Conn struct {
 	// Packet encoding variables.
 	sequence uint8
 
+	// compressedReader and compressedWriter read and write the compressed packets,
+	// once compression was negotiated in the handshake. They are nil otherwise.
+	compressedReader *compressedReader
+	compressedWriter *compressedWriter
+
+	// compressedSequence is the sequence number of the compressed packets.
+	compressedSequence uint8
+
+	// zstdCompressionLevel is the zstd compression level the client asked for in the handshake.
+	// It is only used by the server.
+	zstdCompressionLevel int
+
 	// ExpectSemiSyncIndicator is applicable when the connection is used for replication (ComBinlogDump).
 	// When 'true', events are assumed to be padded with 2-byte semi-sync information
 	// See https://dev.mysql.com/doc/internals/en/semi-sync-binlog-event.html
@@ -227,6 +249,16 @@ type PrepareData struct {
 	BindVars    map[string]*querypb.BindVariable
 	StatementID uint32
 	ParamsCount uint16
+	// CursorType holds the cursor type flags of the last COM_STMT_EXECUTE.
+	CursorType byte
+
+	// cursor is the cursor opened by the last COM_STMT_EXECUTE, if any.
+	cursor *stmtCursor
+
+	// attributeTypes and attributeNames are those of the query attributes of the
+	// last COM_STMT_EXECUTE, which are only sent along with new parameter types.
+	attributeTypes []querypb.Type
+	attributeNames []string
 }
 
 // execResult is an enum signifying the result of executing a query
@@ -323,7 +355,7 @@ func (c *Conn) startWriterBuffering() {
 	defer c.bufMu.Unlock()
 
 	c.bufferedWriter = writersPool.Get().(*bufio.Writer)
-	c.bufferedWriter.Reset(c.conn)
+	c.bufferedWriter.Reset(c.getWriter())
 }
 
 // endWriterBuffering must be called to terminate startWriteBuffering.
@@ -371,14 +403,33 @@ func (c *Conn) startFlushTimer() {
 }
 
 // getReader returns reader for connection. It can be *bufio.Reader or net.Conn
-// depending on which buffer size was passed to newServerConn.
+// depending on which buffer size was passed to newServerConn, or the
+// compressedReader reading from them once compression is enabled.
 func (c *Conn) getReader() io.Reader {
+	if c.compressedReader != nil {
+		return c.compressedReader
+	}
 	if c.bufferedReader != nil {
 		return c.bufferedReader
 	}
 	return c.conn
 }
 
+// getWriter returns the unbuffered writer for connection. It is the net.Conn,
+// or the compressedWriter writing to it once compression is enabled.
+func (c *Conn) getWriter() io.Writer {
+	if c.compressedWriter != nil {
+		return c.compressedWriter
+	}
+	return c.conn
+}
+
+// resetSequence resets the sequence numbers, which is needed at the start of every command.
+func (c *Conn) resetSequence() {
+	c.sequence = 0
+	c.compressedSequence = 0
+}
+
 func (c *Conn) readHeaderFrom(r io.Reader) (int, error) {
 	// Note io.ReadFull will return two different types of errors:
 	// 1. if the socket is already closed, and the go runtime knows it,
@@ -400,11 +451,14 @@ func (c *Conn) readHeaderFrom(r io.Reader) (int, error) {
 	}
 
 	sequence := uint8(c.header[3])
-	if sequence != c.sequence {
+	// With compression, the sequence numbers of the packets are not reliable, as MySQL
+	// syncs them with the sequence numbers of the compressed packets whenever it flushes.
+	// The MySQL clients don't check them either.
+	if sequence != c.sequence && c.compressedReader == nil {
 		return 0, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid sequence, expected %v got %v", c.sequence, sequence)
 	}
 
-	c.sequence++
+	c.sequence = sequence + 1
 
 	return int(uint32(c.header[0]) | uint32(c.header[1])<<8 | uint32(c.header[2])<<16), nil
 }
@@ -611,7 +665,7 @@ func (c *Conn) writePacket(data []byte) error {
 		}()
 	} else {
 		c.bufMu.Unlock()
-		w = c.conn
+		w = c.getWriter()
 	}
 
 	var header [packetHeaderSize]byte
@@ -715,7 +769,7 @@ func (c *Conn) recycleWritePacket() {
 // Returns SQLError(CRServerGone) if it can't.
 func (c *Conn) writeComQuit() error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
 
 	data, pos := c.startEphemeralPacketWithHeader(1)
 	data[pos] = ComQuit
@@ -888,7 +942,7 @@ func (c *Conn) writeEOFPacket(flags uint16, warnings uint16) error {
 // handleNextCommand is called in the server loop to process
 // incoming packets.
 func (c *Conn) handleNextCommand(handler Handler) bool {
-	c.sequence = 0
+	c.resetSequence()
 	data, err := c.readEphemeralPacket()
 	if err != nil {
 		// Don't log EOF errors. They cause too much spam.
@@ -905,6 +959,14 @@ func (c *Conn) handleNextCommand(handler Handler) bool {
 		return false
 	}
 
+	switch data[0] {
+	case ComQuit, ComPing, ComSetOption, ComStmtExecute, ComStmtSendLongData, ComStmtClose, ComStmtReset, ComStmtFetch, ComResetConnection, ComChangeUser:
+		// these commands take care of the cursors themselves, or don't call the handler
+	default:
+		// the handler of a cursor has to return before the handler is called for anything else
+		c.materializeCursor()
+	}
+
 	switch data[0] {
 	case ComQuit:
 		c.recycleReadPacket()
@@ -930,13 +992,20 @@ func (c *Conn) handleNextCommand(handler Handler) bool {
 		stmtID, ok := c.parseComStmtClose(data)
 		c.recycleReadPacket()
 		if ok {
+			if prepare, ok := c.PrepareData[stmtID]; ok {
+				c.closeCursor(prepare)
+			}
 			delete(c.PrepareData, stmtID)
 		}
 	case ComStmtReset:
 		return c.handleComStmtReset(data)
+	case ComStmtFetch:
+		return c.handleComStmtFetch(handler, data)
 	case ComResetConnection:
 		c.handleComResetConnection(handler)
 		return true
+	case ComChangeUser:
+		return c.handleComChangeUser(handler, data)
 	case ComFieldList:
 		c.recycleReadPacket()
 		if !c.writeErrorAndLog(sqlerror.ERUnknownComError, sqlerror.SSNetError, "command handling not implemented yet: %v", data[0]) {
@@ -1028,6 +1097,7 @@ func (c *Conn) handleComBinlogDumpGTID(handler Handler, data []byte) (kontinue b
 func (c *Conn) handleComResetConnection(handler Handler) {
 	// Clean up and reset the connection
 	c.recycleReadPacket()
+	c.closeCursors()
 	handler.ComResetConnection(c)
 	// Reset prepared statements
 	c.PrepareData = make(map[uint32]*PrepareData)
@@ -1037,6 +1107,55 @@ func (c *Conn) handleComResetConnection(handler Handler) {
 	}
 }
 
+// handleComChangeUser authenticates the connection as another user. If that fails,
+// the connection is closed, as MySQL does, so it can't keep going as the previous
+// user. Otherwise it starts over, as after the handshake.
+func (c *Conn) handleComChangeUser(handler Handler, data []byte) bool {
+	user, schemaName, characterSet, authMethod, authResponse, err := c.parseComChangeUser(data)
+	c.recycleReadPacket()
+	if err != nil {
+		log.Errorf("Cannot parse COM_CHANGE_USER from %s: %v", c, err)
+		c.writeErrorPacketFromErrorAndLog(err)
+		return false
+	}
+
+	userData, salt, err := c.listener.authenticate(c, user, authMethod, authResponse, c.salt)
+	if err != nil {
+		c.writeErrorPacketFromErrorAndLog(err)
+		return false
+	}
+
+	c.closeCursors()
+	c.PrepareData = make(map[uint32]*PrepareData)
+	if c.User != "" {
+		connCountPerUser.Add(c.User, -1)
+	}
+	c.User = user
+	c.UserData = userData
+	c.salt = salt
+	c.schemaName = schemaName
+	c.CharacterSet = characterSet
+	if c.User != "" {
+		connCountPerUser.Add(c.User, 1)
+	}
+	handler.ComChangeUser(c)
+
+	if c.schemaName != "" {
+		err := handler.ComQuery(c, "use "+sqlescape.EscapeID(c.schemaName), func(result *sqltypes.Result) error {
+			return nil
+		})
+		if err != nil {
+			return c.writeErrorPacketFromErrorAndLog(err)
+		}
+	}
+
+	if err := c.writeOKPacket(&PacketOK{statusFlags: c.StatusFlags}); err != nil {
+		log.Errorf("Error writing ComChangeUser OK packet to %s: %v", c, err)
+		return false
+	}
+	return true
+}
+
 func (c *Conn) handleComStmtReset(data []byte) bool {
 	stmtID, ok := c.parseComStmtReset(data)
 	c.recycleReadPacket()
@@ -1055,6 +1174,7 @@ func (c *Conn) handleComStmtReset(data []byte) bool {
 		}
 	}
 
+	c.closeCursor(prepare)
 	if prepare.BindVars != nil {
 		for k := range prepare.BindVars {
 			prepare.BindVars[k] = nil
@@ -1107,8 +1227,9 @@ func (c *Conn) handleComStmtExecute(handler Handler, data []byte) (kontinue bool
 		}
 	}()
 	queryStart := time.Now()
-	stmtID, _, err := c.parseComStmtExecute(c.PrepareData, data)
+	stmtID, cursorType, attributes, err := c.parseComStmtExecute(c.PrepareData, data)
 	c.recycleReadPacket()
+	c.queryAttributes = attributes
 
 	if stmtID != uint32(0) {
 		defer func() {
@@ -1122,10 +1243,20 @@ func (c *Conn) handleComStmtExecute(handler Handler, data []byte) (kontinue bool
 		return c.writeErrorPacketFromErrorAndLog(err)
 	}
 
+	prepare := c.PrepareData[stmtID]
+	prepare.CursorType = cursorType
+	// executing the statement again closes its cursor
+	c.closeCursor(prepare)
+	c.materializeCursor()
+	if cursorType&CursorTypeReadOnly != 0 {
+		kontinue = c.executeWithCursor(handler, prepare)
+		timings.Record(queryTimingKey, queryStart)
+		return kontinue
+	}
+
 	fieldSent := false
 	// sendFinished is set if the response should just be an OK packet.
 	sendFinished := false
-	prepare := c.PrepareData[stmtID]
 	err = handler.ComStmtExecute(c, prepare, func(qr *sqltypes.Result) error {
 		if sendFinished {
 			// Failsafe: Unreachable if server is well-behaved.
@@ -1324,11 +1455,14 @@ func (c *Conn) handleComQuery(handler Handler, data []byte) (kontinue bool) {
 	}()
 
 	queryStart := time.Now()
-	query := c.parseComQuery(data)
+	query, attributes, err := c.parseComQuery(data)
 	c.recycleReadPacket()
+	if err != nil {
+		return c.writeErrorPacketFromErrorAndLog(err)
+	}
+	c.queryAttributes = attributes
 
 	var queries []string
-	var err error
 	if c.Capabilities&CapabilityClientMultiStatements != 0 {
 		queries, err = handler.Env().Parser().SplitStatementToPieces(query)
 		if err != nil {
@@ -1624,6 +1758,19 @@ func (c *Conn) TLSEnabled() bool {
 	return c.Capabilities&CapabilityClientSSL > 0
 }
 
+// QueryAttributes returns the query attributes the client sent along with
+// the COM_QUERY or COM_STMT_EXECUTE being handled, if any.
+func (c *Conn) QueryAttributes() map[string]string {
+	return c.queryAttributes
+}
+
+// SetQueryAttributes sets the query attributes to send along with the following
+// queries. They are only sent if the server supports CapabilityClientQueryAttributes,
+// and if they were asked for with ConnParams.EnableQueryAttributes.
+func (c *Conn) SetQueryAttributes(attributes map[string]string) {
+	c.queryAttributes = attributes
+}
+
 // IsUnixSocket returns true if the server connection is over a Unix socket.
 func (c *Conn) IsUnixSocket() bool {
 	_, ok := c.listener.listener.(*net.UnixListener)
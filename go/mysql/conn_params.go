@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/vt/vttls"
 )
 
@@ -64,6 +65,14 @@ type ConnParams struct {
 	// FlushDelay is the delay after which buffered response will be flushed to the client.
 	FlushDelay time.Duration
 
+	// Compression is the compression algorithm to ask the server for: CompressionZlib or CompressionZstd.
+	// The connection is not compressed if it is empty, or if the server doesn't support the algorithm.
+	Compression string
+
+	// ZstdCompressionLevel is the zstd compression level, from 1 to 22.
+	// DefaultZstdCompressionLevel is used if it is not set.
+	ZstdCompressionLevel int
+
 	TruncateErrLen int
 }
 
@@ -90,6 +99,27 @@ func (cp *ConnParams) EnableClientFoundRows() {
 	cp.Flags |= CapabilityClientFoundRows
 }
 
+// EnableQueryAttributes sets the flag for CLIENT_QUERY_ATTRIBUTES, so the
+// query attributes set with Conn.SetQueryAttributes are sent to the server.
+func (cp *ConnParams) EnableQueryAttributes() {
+	cp.Flags |= CapabilityClientQueryAttributes
+}
+
+// compressionCapability returns the capability flag of the compression algorithm, or 0 if
+// compression is disabled.
+func (cp *ConnParams) compressionCapability() (uint32, error) {
+	switch cp.Compression {
+	case "":
+		return 0, nil
+	case CompressionZlib:
+		return CapabilityClientCompress, nil
+	case CompressionZstd:
+		return CapabilityClientZstdCompressionAlgorithm, nil
+	default:
+		return 0, sqlerror.NewSQLErrorf(sqlerror.CRUnknownError, sqlerror.SSUnknownSQLState, "unknown compression algorithm: %v", cp.Compression)
+	}
+}
+
 // SslRequired returns whether the connection parameters
 // define that SSL is a requirement. If SslMode is set, it uses
 // that to determine this, if it's not set it falls back to
@@ -76,15 +76,18 @@ const (
 	// CLIENT_NO_SCHEMA 1 << 4
 	// Do not permit database.table.column. We do permit it.
 
-	// CLIENT_COMPRESS 1 << 5
-	// We do not support compression. CPU is usually our bottleneck.
+	// CapabilityClientCompress is CLIENT_COMPRESS.
+	// Use the zlib compressed protocol after the handshake.
+	// Only negotiated when compression is enabled on the listener or requested in ConnParams,
+	// as CPU is usually our bottleneck.
+	CapabilityClientCompress = 1 << 5
 
 	// CLIENT_ODBC 1 << 6
 	// No special behavior since 3.22.
 
-	// CLIENT_LOCAL_FILES 1 << 7
+	// CapabilityClientLocalFiles is CLIENT_LOCAL_FILES.
 	// Client can use LOCAL INFILE request of LOAD DATA|XML.
-	// We do not set it.
+	CapabilityClientLocalFiles = 1 << 7
 
 	// CLIENT_IGNORE_SPACE 1 << 8
 	// Parser can ignore spaces before '('.
@@ -148,6 +151,19 @@ const (
 	// CapabilityClientDeprecateEOF is CLIENT_DEPRECATE_EOF
 	// Expects an OK (instead of EOF) after the resultset rows of a Text Resultset.
 	CapabilityClientDeprecateEOF = 1 << 24
+
+	// CLIENT_OPTIONAL_RESULTSET_METADATA 1 << 25
+	// Not supported.
+
+	// CapabilityClientZstdCompressionAlgorithm is CLIENT_ZSTD_COMPRESSION_ALGORITHM.
+	// Use the zstd compressed protocol after the handshake. The client sends
+	// the compression level at the end of Protocol::HandshakeResponse41.
+	CapabilityClientZstdCompressionAlgorithm = 1 << 26
+
+	// CapabilityClientQueryAttributes is CLIENT_QUERY_ATTRIBUTES.
+	// COM_QUERY and COM_STMT_EXECUTE can carry name/value attributes
+	// along with the query.
+	CapabilityClientQueryAttributes = 1 << 27
 )
 
 // Status flags. They are returned by the server in a few cases.
@@ -209,6 +225,9 @@ const (
 	// ComPing is COM_PING.
 	ComPing = 0x0e
 
+	// ComChangeUser is COM_CHANGE_USER.
+	ComChangeUser = 0x11
+
 	// ComBinlogDump is COM_BINLOG_DUMP.
 	ComBinlogDump = 0x12
 
@@ -257,6 +276,10 @@ const (
 
 	// NullValue is the encoded value of NULL.
 	NullValue = 0xfb
+
+	// LocalInfilePacket is the header of the packet asking the client
+	// for the file of a LOAD DATA LOCAL INFILE statement.
+	LocalInfilePacket = 0xfb
 )
 
 // Auth packet types
@@ -274,6 +297,25 @@ const (
 	AuthSwitchRequestPacket = 0xfe
 )
 
+// Cursor type flags of COM_STMT_EXECUTE.
+// Originally found in include/mysql/mysql_com.h
+const (
+	// CursorTypeNoCursor sends the whole result set in the response to COM_STMT_EXECUTE.
+	CursorTypeNoCursor byte = 0x00
+
+	// CursorTypeReadOnly opens a cursor, whose rows are read with COM_STMT_FETCH.
+	CursorTypeReadOnly byte = 0x01
+
+	// CursorTypeForUpdate and CursorTypeScrollable are not supported by MySQL.
+	CursorTypeForUpdate  byte = 0x02
+	CursorTypeScrollable byte = 0x04
+
+	// ParameterCountAvailable is set when the parameter count of COM_STMT_EXECUTE
+	// is sent, which is how the client sends query attributes for statements
+	// without parameters.
+	ParameterCountAvailable byte = 0x08
+)
+
 var typeInt24, _ = sqltypes.TypeToMySQL(sqltypes.Int24)
 var typeTimestamp, _ = sqltypes.TypeToMySQL(sqltypes.Timestamp)
 var typeYear, _ = sqltypes.TypeToMySQL(sqltypes.Year)
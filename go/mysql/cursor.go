@@ -0,0 +1,286 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"errors"
+	"io"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// This file contains the server side cursors, which are opened by COM_STMT_EXECUTE
+// with CursorTypeReadOnly, and whose rows are read with COM_STMT_FETCH.
+//
+// The result set of a cursor is streamed by Handler.ComStmtExecute, in a go routine
+// of its own. This go routine only runs when the connection asks it for its next result:
+// the rest of the time it waits in the callback, so the handler and the connection never
+// run concurrently. The handler of a cursor returns before the handler is called for
+// anything else, the remaining rows of its cursor being read in memory, so the methods
+// of the Handler are still serialized for a given connection. A cursor with too many
+// rows left is closed instead of being read in memory.
+
+// DefaultMaxMaterializedCursorRows is the default for Listener.MaxMaterializedCursorRows.
+const DefaultMaxMaterializedCursorRows = 10000
+
+// stmtCursor is the cursor of a prepared statement.
+type stmtCursor struct {
+	// next resumes the handler, which waits in the callback once it sent a result.
+	// The handler stops streaming if false is sent.
+	next chan bool
+	// results are the results sent by the handler. It is closed once the handler returned,
+	// and err is then the error it returned.
+	results chan *sqltypes.Result
+	err     error
+
+	// waiting is set when the handler waits in the callback, and done once it returned.
+	waiting bool
+	done    bool
+
+	fields []*querypb.Field
+	// rows were sent by the handler, but not to the client yet.
+	rows [][]sqltypes.Value
+}
+
+// newStmtCursor calls the handler to stream the result set of the statement.
+// nextResult has to be called right away, as the handler is running until it returns.
+func newStmtCursor(c *Conn, handler Handler, prepare *PrepareData) *stmtCursor {
+	cur := &stmtCursor{
+		next:    make(chan bool),
+		results: make(chan *sqltypes.Result),
+	}
+	go func() {
+		defer close(cur.results)
+		stopped := false
+		cur.err = handler.ComStmtExecute(c, prepare, func(qr *sqltypes.Result) error {
+			if stopped {
+				return io.EOF
+			}
+			cur.results <- qr
+			if !<-cur.next {
+				stopped = true
+				return io.EOF
+			}
+			return nil
+		})
+	}()
+	return cur
+}
+
+// nextResult resumes the handler until it sends its next result, or returns nil once
+// the handler returned.
+func (cur *stmtCursor) nextResult() *sqltypes.Result {
+	if cur.done {
+		return nil
+	}
+	if cur.waiting {
+		cur.next <- true
+	}
+	qr, ok := <-cur.results
+	cur.waiting = ok
+	cur.done = !ok
+	return qr
+}
+
+// fetch returns the next numRows rows of the cursor at most, and whether they
+// are its last rows.
+func (cur *stmtCursor) fetch(numRows int) ([][]sqltypes.Value, bool) {
+	// one more row is read, to know if these are the last rows
+	for len(cur.rows) <= numRows {
+		qr := cur.nextResult()
+		if qr == nil {
+			break
+		}
+		cur.rows = append(cur.rows, qr.Rows...)
+	}
+	n := min(numRows, len(cur.rows))
+	rows := cur.rows[:n:n]
+	cur.rows = cur.rows[n:]
+	return rows, cur.done && cur.err == nil && len(cur.rows) == 0
+}
+
+// materialize reads all the remaining rows of the handler, so it returns.
+// If more than maxRows rows are left, the handler is stopped instead, and
+// the cursor fails.
+func (cur *stmtCursor) materialize(maxRows int) {
+	for qr := cur.nextResult(); qr != nil; qr = cur.nextResult() {
+		cur.rows = append(cur.rows, qr.Rows...)
+		if len(cur.rows) > maxRows {
+			cur.close()
+			cur.rows = nil
+			cur.err = sqlerror.NewSQLErrorf(sqlerror.EROutOfResources, sqlerror.SSUnknownSQLState,
+				"cursor closed: more than %d rows were left to fetch when another command was run", maxRows)
+			return
+		}
+	}
+}
+
+// close stops the handler, and waits for it to return.
+func (cur *stmtCursor) close() {
+	if cur.done {
+		return
+	}
+	if cur.waiting {
+		cur.next <- false
+	}
+	for range cur.results {
+		// the handler was not waiting yet
+		cur.next <- false
+	}
+	cur.waiting = false
+	cur.done = true
+}
+
+// executeWithCursor executes a prepared statement with a cursor. Only the fields of
+// the result set are sent, along with ServerStatusCursorExists, and its rows are then
+// read with COM_STMT_FETCH. Statements without a result set get an OK packet, as usual.
+func (c *Conn) executeWithCursor(handler Handler, prepare *PrepareData) bool {
+	cur := newStmtCursor(c, handler, prepare)
+	qr := cur.nextResult()
+	if qr == nil {
+		err := cur.err
+		if err == nil {
+			// This is just a failsafe. Should never happen.
+			err = sqlerror.NewSQLErrorFromError(errors.New("unexpected: query ended without no results and no error"))
+		}
+		return c.writeErrorPacketFromErrorAndLog(err)
+	}
+
+	if len(qr.Fields) == 0 {
+		// There is no result set, so no cursor either: the handler returns as it would without one.
+		for cur.nextResult() != nil {
+		}
+		if cur.err != nil {
+			return c.writeErrorPacketFromErrorAndLog(cur.err)
+		}
+		if err := c.writeOKPacket(&PacketOK{
+			affectedRows:     qr.RowsAffected,
+			lastInsertID:     qr.InsertID,
+			statusFlags:      c.StatusFlags,
+			sessionStateData: qr.SessionStateChanges,
+		}); err != nil {
+			log.Errorf("Error writing result to %s: %v", c, err)
+			return false
+		}
+		return true
+	}
+
+	cur.fields = qr.Fields
+	cur.rows = qr.Rows
+	prepare.cursor = cur
+	c.streamingCursor = cur
+
+	// Unlike a result set, the fields are always followed by an end packet,
+	// which tells the client the cursor exists.
+	if err := c.writeColumnDefinitions(cur.fields); err != nil {
+		log.Errorf("Error writing fields to %s: %v", c, err)
+		return false
+	}
+	if err := c.writeEndPacket(c.StatusFlags|ServerStatusCursorExists, 0, 0, 0); err != nil {
+		log.Errorf("Error writing result to %s: %v", c, err)
+		return false
+	}
+	return true
+}
+
+// handleComStmtFetch sends the next rows of the cursor of a prepared statement.
+// The last rows are sent with ServerStatusLastRowSent, and the cursor is then closed.
+func (c *Conn) handleComStmtFetch(handler Handler, data []byte) (kontinue bool) {
+	c.startWriterBuffering()
+	defer func() {
+		if err := c.endWriterBuffering(); err != nil {
+			log.Errorf("conn %v: flush() failed: %v", c.ID(), err)
+			kontinue = false
+		}
+	}()
+
+	stmtID, numRows, ok := c.parseComStmtFetch(data)
+	c.recycleReadPacket()
+	if !ok {
+		return c.writeErrorAndLog(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "error parsing statement fetch from client %v", c.ConnectionID)
+	}
+
+	prepare, ok := c.PrepareData[stmtID]
+	if !ok {
+		return c.writeErrorAndLog(sqlerror.ERUnknownStmtHandler, sqlerror.SSUnknownSQLState, "Unknown prepared statement handler (%v) given to mysqld_stmt_fetch", stmtID)
+	}
+	cur := prepare.cursor
+	if cur == nil {
+		return c.writeErrorAndLog(sqlerror.ERStmtHasNoOpenCursor, sqlerror.SSUnknownSQLState, "The statement (%v) has no open cursor.", stmtID)
+	}
+
+	rows, last := cur.fetch(int(numRows))
+	if len(rows) == 0 && cur.done && cur.err != nil {
+		// the rows sent before the error were all fetched
+		c.closeCursor(prepare)
+		return c.writeErrorPacketFromErrorAndLog(cur.err)
+	}
+
+	for _, row := range rows {
+		if err := c.writeBinaryRow(cur.fields, row); err != nil {
+			log.Errorf("Error writing row to %s: %v", c, err)
+			return false
+		}
+	}
+
+	flags := c.StatusFlags | ServerStatusCursorExists
+	var warnings uint16
+	if last {
+		flags |= ServerStatusLastRowSent
+		warnings = handler.WarningCount(c)
+		c.closeCursor(prepare)
+	}
+	if err := c.writeEndPacket(flags, 0, 0, warnings); err != nil {
+		log.Errorf("Error writing result to %s: %v", c, err)
+		return false
+	}
+	return true
+}
+
+// materializeCursor reads the remaining rows of the cursor whose handler is still
+// streaming, if any, so the handler can be called for something else. The cursor
+// fails if it has more than MaxMaterializedCursorRows rows left.
+func (c *Conn) materializeCursor() {
+	if c.streamingCursor == nil {
+		return
+	}
+	c.streamingCursor.materialize(int(c.listener.MaxMaterializedCursorRows.Load()))
+	c.streamingCursor = nil
+}
+
+// closeCursor closes the cursor of a prepared statement, if it has one.
+func (c *Conn) closeCursor(prepare *PrepareData) {
+	if prepare.cursor == nil {
+		return
+	}
+	prepare.cursor.close()
+	if c.streamingCursor == prepare.cursor {
+		c.streamingCursor = nil
+	}
+	prepare.cursor = nil
+}
+
+// closeCursors closes the cursors of all the prepared statements.
+func (c *Conn) closeCursors() {
+	for _, prepare := range c.PrepareData {
+		c.closeCursor(prepare)
+	}
+}
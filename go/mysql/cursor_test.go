@@ -0,0 +1,348 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// cursorHandler streams its results one by one, and records what the callback returned.
+type cursorHandler struct {
+	testRun
+	results []*sqltypes.Result
+	err     error
+
+	// streaming is set while ComStmtExecute is running.
+	streaming   bool
+	callbackErr error
+	queries     []string
+}
+
+func (th *cursorHandler) ComStmtExecute(c *Conn, prepare *PrepareData, callback func(*sqltypes.Result) error) error {
+	th.streaming = true
+	defer func() {
+		th.streaming = false
+	}()
+	for _, qr := range th.results {
+		if err := callback(qr); err != nil {
+			th.callbackErr = err
+			return err
+		}
+	}
+	return th.err
+}
+
+func (th *cursorHandler) ComQuery(c *Conn, query string, callback func(*sqltypes.Result) error) error {
+	if th.streaming {
+		return errors.New("ComQuery called while ComStmtExecute is running")
+	}
+	th.queries = append(th.queries, query)
+	return callback(&sqltypes.Result{})
+}
+
+func (th *cursorHandler) WarningCount(c *Conn) uint16 {
+	return 0
+}
+
+func newCursorHandler(t *testing.T, rowCounts ...int) *cursorHandler {
+	th := &cursorHandler{testRun: testRun{t: t}}
+	th.results = append(th.results, &sqltypes.Result{
+		Fields: []*querypb.Field{{Name: "id", Type: sqltypes.VarChar}},
+	})
+	id := 0
+	for _, count := range rowCounts {
+		qr := &sqltypes.Result{}
+		for range count {
+			id++
+			qr.Rows = append(qr.Rows, []sqltypes.Value{sqltypes.NewVarChar(string(rune('0' + id)))})
+		}
+		th.results = append(th.results, qr)
+	}
+	return th
+}
+
+func createStmtExecutePacket(stmtID uint32, cursorType byte) []byte {
+	packet := []byte{0, 0, 0, 0, ComStmtExecute}
+	packet = binary.LittleEndian.AppendUint32(packet, stmtID)
+	packet = append(packet, cursorType)
+	return binary.LittleEndian.AppendUint32(packet, 1) // iteration count
+}
+
+func createStmtFetchPacket(stmtID uint32, numRows uint32) []byte {
+	packet := []byte{0, 0, 0, 0, ComStmtFetch}
+	packet = binary.LittleEndian.AppendUint32(packet, stmtID)
+	return binary.LittleEndian.AppendUint32(packet, numRows)
+}
+
+// sendCommand sends a command from the client, and has the server handle it.
+func sendCommand(t *testing.T, sConn, cConn *Conn, handler Handler, packet []byte) {
+	t.Helper()
+	cConn.resetSequence()
+	require.NoError(t, cConn.writePacket(packet))
+	require.True(t, sConn.handleNextCommand(handler))
+}
+
+// readCursorOpened reads the response to a COM_STMT_EXECUTE that opened a cursor.
+func readCursorOpened(t *testing.T, cConn *Conn) {
+	t.Helper()
+	numFields, err := cConn.readComQueryResponse(&PacketOK{})
+	require.NoError(t, err)
+	require.Equal(t, 1, numFields)
+	field := &querypb.Field{}
+	require.NoError(t, cConn.readColumnDefinition(field, 0))
+	assert.Equal(t, "id", field.Name)
+
+	data, err := cConn.ReadPacket()
+	require.NoError(t, err)
+	_, statusFlags, err := parseEOFPacket(data)
+	require.NoError(t, err)
+	assert.Equal(t, ServerStatusCursorExists, statusFlags&ServerStatusCursorExists)
+}
+
+// readFetchedRows reads the response to a COM_STMT_FETCH, and returns the rows of
+// the single VARCHAR column, along with the status flags.
+func readFetchedRows(t *testing.T, cConn *Conn) ([]string, uint16) {
+	t.Helper()
+	var rows []string
+	for {
+		data, err := cConn.ReadPacket()
+		require.NoError(t, err)
+		if cConn.isEOFPacket(data) {
+			_, statusFlags, err := parseEOFPacket(data)
+			require.NoError(t, err)
+			return rows, statusFlags
+		}
+		// packet header, NULL bitmap and length of the value
+		require.Equal(t, byte(OKPacket), data[0])
+		rows = append(rows, string(data[3:3+data[2]]))
+	}
+}
+
+func TestComStmtFetch(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	sConn.PrepareData[1] = &PrepareData{StatementID: 1, PrepareStmt: "select id from t"}
+	th := newCursorHandler(t, 2, 3)
+
+	sendCommand(t, sConn, cConn, th, createStmtExecutePacket(1, CursorTypeReadOnly))
+	readCursorOpened(t, cConn)
+	assert.True(t, th.streaming)
+	assert.Equal(t, CursorTypeReadOnly, sConn.PrepareData[1].CursorType)
+
+	for _, tcase := range []struct {
+		rows []string
+		last bool
+	}{
+		{[]string{"1", "2"}, false},
+		{[]string{"3", "4"}, false},
+		{[]string{"5"}, true},
+	} {
+		sendCommand(t, sConn, cConn, th, createStmtFetchPacket(1, 2))
+		rows, statusFlags := readFetchedRows(t, cConn)
+		assert.Equal(t, tcase.rows, rows)
+		assert.Equal(t, ServerStatusCursorExists, statusFlags&ServerStatusCursorExists)
+		assert.Equal(t, tcase.last, statusFlags&ServerStatusLastRowSent != 0)
+	}
+	assert.False(t, th.streaming)
+	assert.NoError(t, th.callbackErr)
+
+	// the cursor was closed with the last row
+	sendCommand(t, sConn, cConn, th, createStmtFetchPacket(1, 2))
+	data, err := cConn.ReadPacket()
+	require.NoError(t, err)
+	require.True(t, isErrorPacket(data))
+	assert.Equal(t, sqlerror.ERStmtHasNoOpenCursor, ParseErrorPacket(data).(*sqlerror.SQLError).Number())
+}
+
+func TestComStmtFetchOtherCommand(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	sConn.listener = &Listener{}
+	sConn.listener.MaxMaterializedCursorRows.Store(DefaultMaxMaterializedCursorRows)
+
+	sConn.PrepareData[1] = &PrepareData{StatementID: 1, PrepareStmt: "select id from t"}
+	th := newCursorHandler(t, 1, 2)
+
+	sendCommand(t, sConn, cConn, th, createStmtExecutePacket(1, CursorTypeReadOnly))
+	readCursorOpened(t, cConn)
+	assert.True(t, th.streaming)
+
+	// the handler of the cursor returns before the query is handled
+	sendCommand(t, sConn, cConn, th, []byte{0, 0, 0, 0, ComQuery, 's', 'e', 'l', 'e', 'c', 't', ' ', '1'})
+	_, err := cConn.readComQueryResponse(&PacketOK{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"select 1"}, th.queries)
+	assert.False(t, th.streaming)
+
+	sendCommand(t, sConn, cConn, th, createStmtFetchPacket(1, 10))
+	rows, statusFlags := readFetchedRows(t, cConn)
+	assert.Equal(t, []string{"1", "2", "3"}, rows)
+	assert.NotZero(t, statusFlags&ServerStatusLastRowSent)
+}
+
+func TestComStmtFetchOtherCommandMaxRows(t *testing.T) {
+	for _, tcase := range []struct {
+		name    string
+		maxRows int64
+		rows    []string
+	}{{
+		// the cursor has as many rows left as can be read in memory
+		name:    "at the limit",
+		maxRows: 6,
+		rows:    []string{"1", "2", "3", "4", "5", "6"},
+	}, {
+		// the cursor has too many rows left to be read in memory, so it is closed instead
+		name:    "over the limit",
+		maxRows: 5,
+	}} {
+		t.Run(tcase.name, func(t *testing.T) {
+			listener, sConn, cConn := createSocketPair(t)
+			defer func() {
+				listener.Close()
+				sConn.Close()
+				cConn.Close()
+			}()
+			sConn.listener = &Listener{}
+			sConn.listener.MaxMaterializedCursorRows.Store(tcase.maxRows)
+
+			sConn.PrepareData[1] = &PrepareData{StatementID: 1, PrepareStmt: "select id from t"}
+			th := newCursorHandler(t, 1, 2, 3)
+
+			sendCommand(t, sConn, cConn, th, createStmtExecutePacket(1, CursorTypeReadOnly))
+			readCursorOpened(t, cConn)
+			assert.True(t, th.streaming)
+
+			sendCommand(t, sConn, cConn, th, []byte{0, 0, 0, 0, ComQuery, 's', 'e', 'l', 'e', 'c', 't', ' ', '1'})
+			_, err := cConn.readComQueryResponse(&PacketOK{})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"select 1"}, th.queries)
+			assert.False(t, th.streaming)
+			assert.Nil(t, sConn.streamingCursor)
+
+			sendCommand(t, sConn, cConn, th, createStmtFetchPacket(1, 10))
+			if tcase.rows != nil {
+				assert.NoError(t, th.callbackErr)
+				rows, statusFlags := readFetchedRows(t, cConn)
+				assert.Equal(t, tcase.rows, rows)
+				assert.NotZero(t, statusFlags&ServerStatusLastRowSent)
+				return
+			}
+			assert.Error(t, th.callbackErr)
+			data, err := cConn.ReadPacket()
+			require.NoError(t, err)
+			require.True(t, isErrorPacket(data))
+			sqlErr := ParseErrorPacket(data).(*sqlerror.SQLError)
+			assert.Equal(t, sqlerror.EROutOfResources, sqlErr.Number())
+			assert.Contains(t, sqlErr.Error(), "more than 5 rows were left to fetch")
+			assert.Nil(t, sConn.PrepareData[1].cursor)
+		})
+	}
+}
+
+func TestComStmtFetchError(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	sConn.PrepareData[1] = &PrepareData{StatementID: 1, PrepareStmt: "select id from t"}
+	th := newCursorHandler(t, 2)
+	th.err = sqlerror.NewSQLError(sqlerror.ERQueryInterrupted, sqlerror.SSUnknownSQLState, "interrupted")
+
+	sendCommand(t, sConn, cConn, th, createStmtExecutePacket(1, CursorTypeReadOnly))
+	readCursorOpened(t, cConn)
+
+	// the rows sent before the error are fetched first
+	sendCommand(t, sConn, cConn, th, createStmtFetchPacket(1, 10))
+	rows, statusFlags := readFetchedRows(t, cConn)
+	assert.Equal(t, []string{"1", "2"}, rows)
+	assert.Zero(t, statusFlags&ServerStatusLastRowSent)
+
+	sendCommand(t, sConn, cConn, th, createStmtFetchPacket(1, 10))
+	data, err := cConn.ReadPacket()
+	require.NoError(t, err)
+	require.True(t, isErrorPacket(data))
+	assert.Equal(t, sqlerror.ERQueryInterrupted, ParseErrorPacket(data).(*sqlerror.SQLError).Number())
+	assert.Nil(t, sConn.PrepareData[1].cursor)
+}
+
+func TestComStmtCloseCursor(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	sConn.PrepareData[1] = &PrepareData{StatementID: 1, PrepareStmt: "select id from t"}
+	th := newCursorHandler(t, 1, 1)
+
+	sendCommand(t, sConn, cConn, th, createStmtExecutePacket(1, CursorTypeReadOnly))
+	readCursorOpened(t, cConn)
+	assert.True(t, th.streaming)
+
+	// closing the statement stops the handler
+	sendCommand(t, sConn, cConn, th, []byte{0, 0, 0, 0, ComStmtClose, 1, 0, 0, 0})
+	assert.False(t, th.streaming)
+	assert.Error(t, th.callbackErr)
+	assert.Nil(t, sConn.streamingCursor)
+	assert.Empty(t, sConn.PrepareData)
+}
+
+func TestComStmtExecuteCursorWithoutResultSet(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	sConn.PrepareData[1] = &PrepareData{StatementID: 1, PrepareStmt: "update t set id = 1"}
+	th := &cursorHandler{testRun: testRun{t: t}, results: []*sqltypes.Result{{RowsAffected: 3}}}
+
+	// there is no cursor without a result set
+	sendCommand(t, sConn, cConn, th, createStmtExecutePacket(1, CursorTypeReadOnly))
+	packetOK := &PacketOK{}
+	numFields, err := cConn.readComQueryResponse(packetOK)
+	require.NoError(t, err)
+	assert.Zero(t, numFields)
+	assert.EqualValues(t, 3, packetOK.affectedRows)
+	assert.False(t, th.streaming)
+	assert.Nil(t, sConn.PrepareData[1].cursor)
+}
@@ -0,0 +1,414 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datetime
+
+import "time"
+
+// maxDayNumber is the day number of 9999-12-31, the largest
+// date that MySQL can represent.
+const maxDayNumber = 3652424
+
+var longMonthNames = []string{
+	"January",
+	"February",
+	"March",
+	"April",
+	"May",
+	"June",
+	"July",
+	"August",
+	"September",
+	"October",
+	"November",
+	"December",
+}
+
+// The day names used by STR_TO_DATE start on Monday, so that
+// the matched weekday is 1 for Monday and 7 for Sunday.
+var longDayNamesMonday = []string{
+	"Monday",
+	"Tuesday",
+	"Wednesday",
+	"Thursday",
+	"Friday",
+	"Saturday",
+	"Sunday",
+}
+
+var shortDayNamesMonday = []string{
+	"Mon",
+	"Tue",
+	"Wed",
+	"Thu",
+	"Fri",
+	"Sat",
+	"Sun",
+}
+
+const (
+	strToDateTimeAMPM = "%I:%i:%S %p"
+	strToDate24Hrs    = "%H:%i:%S"
+)
+
+// strToDateParts holds the parts of a datetime value as they are
+// extracted by STR_TO_DATE. The parts are not validated against
+// each other, so e.g. April 31st is a valid value.
+type strToDateParts struct {
+	year, month, day             int
+	hour, minute, second, micros int
+}
+
+func isStrToDateSpace(c byte) bool {
+	return c == ' ' || (c >= '\t' && c <= '\r')
+}
+
+func isStrToDateAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= 0xC0 && c != 0xD7 && c != 0xF7)
+}
+
+func isStrToDatePunct(c byte) bool {
+	return (c >= '!' && c <= '/') || (c >= ':' && c <= '@') || (c >= '[' && c <= '`') || (c >= '{' && c <= '~')
+}
+
+// strToDateInt parses an unsigned integer from the first max bytes of s.
+// It returns the parsed value and the number of bytes consumed.
+func strToDateInt(s string, max int) (int, int, bool) {
+	if max > len(s) {
+		max = len(s)
+	}
+	var i int
+	for i < max && (s[i] == ' ' || s[i] == '\t') {
+		i++
+	}
+	if i < max && s[i] == '+' {
+		i++
+	}
+	start := i
+	var n int
+	for i < max && isDigit(s, i) {
+		n = n*10 + int(s[i]-'0')
+		i++
+	}
+	if i == start {
+		return 0, 0, false
+	}
+	return n, i, true
+}
+
+// strToDateWord matches the word at the start of s against names, returning
+// its 1-based position. A word matches a name if it is a unique prefix of it,
+// ignoring case.
+func strToDateWord(names []string, s string) (int, string, bool) {
+	var n int
+	for n < len(s) && isStrToDateAlpha(s[n]) {
+		n++
+	}
+	if n == 0 {
+		return 0, s, false
+	}
+
+	word := s[:n]
+	found, matches := 0, 0
+	for i, name := range names {
+		if len(word) > len(name) || !match(word, name[:len(word)]) {
+			continue
+		}
+		if len(word) == len(name) {
+			return i + 1, s[n:], true
+		}
+		found = i + 1
+		matches++
+	}
+	if matches != 1 {
+		return 0, s, false
+	}
+	return found, s[n:], true
+}
+
+func strToDateYear2000(year int) int {
+	if year += 1900; year < 1970 {
+		year += 100
+	}
+	return year
+}
+
+func strToDateWeekday(daynr int, sundayFirst bool) int {
+	if sundayFirst {
+		daynr++
+	}
+	return (daynr + 5) % 7
+}
+
+func (tp *strToDateParts) setDayNumber(daynr int) bool {
+	if daynr <= 0 || daynr > maxDayNumber {
+		return false
+	}
+	d := DateFromDayNumber(daynr)
+	tp.year, tp.month, tp.day = d.Year(), d.Month(), d.Day()
+	return true
+}
+
+// extract parses val according to format, storing the parsed parts in tp.
+// When sub is set, format is one of the compound formats used by %r and %T
+// and the unparsed remainder of val is returned without further validation.
+func (tp *strToDateParts) extract(val, format string, sub bool) (string, bool) {
+	var (
+		weekday, yearday, daypart int
+		weekNumber                = -1
+		strictWeekNumberYear      = -1
+		usaTime                   bool
+		sundayFirst               bool
+		strictWeekNumber          bool
+		strictWeekNumberYearType  bool
+	)
+
+	for ; len(format) > 0 && len(val) > 0; format = format[1:] {
+		for len(val) > 0 && isStrToDateSpace(val[0]) {
+			val = val[1:]
+		}
+		if len(val) == 0 {
+			break
+		}
+
+		if format[0] != '%' || len(format) == 1 {
+			if !isStrToDateSpace(format[0]) {
+				if val[0] != format[0] {
+					return "", false
+				}
+				val = val[1:]
+			}
+			continue
+		}
+
+		format = format[1:]
+
+		var n, l int
+		var ok bool
+		switch format[0] {
+		case 'Y':
+			n, l, ok = strToDateInt(val, 4)
+			if l <= 2 {
+				n = strToDateYear2000(n)
+			}
+			tp.year = n
+		case 'y':
+			n, l, ok = strToDateInt(val, 2)
+			tp.year = strToDateYear2000(n)
+		case 'm', 'c':
+			tp.month, l, ok = strToDateInt(val, 2)
+		case 'M':
+			tp.month, val, ok = strToDateWord(longMonthNames, val)
+		case 'b':
+			tp.month, val, ok = strToDateWord(shortMonthNames, val)
+		case 'd', 'e':
+			tp.day, l, ok = strToDateInt(val, 2)
+		case 'D':
+			tp.day, l, ok = strToDateInt(val, 2)
+			// Skip the ordinal suffix ("st", "nd", "th"...)
+			l = min(len(val), l+2)
+		case 'h', 'I', 'l':
+			usaTime = true
+			tp.hour, l, ok = strToDateInt(val, 2)
+		case 'k', 'H':
+			tp.hour, l, ok = strToDateInt(val, 2)
+		case 'i':
+			tp.minute, l, ok = strToDateInt(val, 2)
+		case 's', 'S':
+			tp.second, l, ok = strToDateInt(val, 2)
+		case 'f':
+			tp.micros, l, ok = strToDateInt(val, 6)
+			for i := l; i < 6; i++ {
+				tp.micros *= 10
+			}
+		case 'p':
+			if len(val) < 2 || !usaTime {
+				return "", false
+			}
+			switch {
+			case match(val[:2], "PM"):
+				daypart = 12
+			case match(val[:2], "AM"):
+			default:
+				return "", false
+			}
+			val = val[2:]
+			ok = true
+		case 'W':
+			weekday, val, ok = strToDateWord(longDayNamesMonday, val)
+		case 'a':
+			weekday, val, ok = strToDateWord(shortDayNamesMonday, val)
+		case 'w':
+			weekday, l, ok = strToDateInt(val, 1)
+			if ok && weekday >= 7 {
+				return "", false
+			}
+			// Use the same 1 to 7 scale as %W
+			if weekday == 0 {
+				weekday = 7
+			}
+		case 'j':
+			yearday, l, ok = strToDateInt(val, 3)
+		case 'V', 'U', 'v', 'u':
+			sundayFirst = format[0] == 'U' || format[0] == 'V'
+			strictWeekNumber = format[0] == 'V' || format[0] == 'v'
+			weekNumber, l, ok = strToDateInt(val, 2)
+			if (strictWeekNumber && weekNumber == 0) || weekNumber > 53 {
+				return "", false
+			}
+		case 'X', 'x':
+			strictWeekNumberYearType = format[0] == 'X'
+			strictWeekNumberYear, l, ok = strToDateInt(val, 4)
+		case 'r':
+			val, ok = tp.extract(val, strToDateTimeAMPM, true)
+		case 'T':
+			val, ok = tp.extract(val, strToDate24Hrs, true)
+		case '.':
+			for len(val) > 0 && isStrToDatePunct(val[0]) {
+				val = val[1:]
+			}
+			ok = true
+		case '@':
+			for len(val) > 0 && isStrToDateAlpha(val[0]) && val[0] < 0x80 {
+				val = val[1:]
+			}
+			ok = true
+		case '#':
+			for len(val) > 0 && isDigit(val, 0) {
+				val = val[1:]
+			}
+			ok = true
+		}
+		if !ok {
+			return "", false
+		}
+		val = val[l:]
+	}
+
+	if usaTime {
+		if tp.hour > 12 || tp.hour < 1 {
+			return "", false
+		}
+		tp.hour = tp.hour%12 + daypart
+	}
+
+	if sub {
+		return val, true
+	}
+
+	if yearday > 0 {
+		if !tp.setDayNumber(MysqlDayNumber(tp.year, 1, 1) + yearday - 1) {
+			return "", false
+		}
+	}
+
+	if weekNumber >= 0 && weekday != 0 {
+		// %V and %v require %X and %x respectively, while
+		// %U and %u must be used with %Y and not with %X or %x
+		if strictWeekNumber && (strictWeekNumberYear < 0 || strictWeekNumberYearType != sundayFirst) {
+			return "", false
+		}
+		if !strictWeekNumber && strictWeekNumberYear >= 0 {
+			return "", false
+		}
+
+		year := tp.year
+		if strictWeekNumber {
+			year = strictWeekNumberYear
+		}
+
+		days := MysqlDayNumber(year, 1, 1)
+		firstWeekday := strToDateWeekday(days, sundayFirst)
+
+		if sundayFirst {
+			if firstWeekday != 0 {
+				days += 7
+			}
+			days += (weekNumber-1)*7 - firstWeekday + weekday%7
+		} else {
+			if firstWeekday > 3 {
+				days += 7
+			}
+			days += (weekNumber-1)*7 - firstWeekday + weekday - 1
+		}
+		if !tp.setDayNumber(days) {
+			return "", false
+		}
+	}
+
+	if tp.month > 12 || tp.day > 31 || tp.hour > 23 || tp.minute > 59 || tp.second > 59 {
+		return "", false
+	}
+	return val, true
+}
+
+// StrToDate parses s according to format the same way MySQL's STR_TO_DATE does.
+// Parts that do not appear in the format are left as zero, and any trailing
+// characters in s are ignored. When onlyTime is set, the parsed day is added
+// to the hours of the returned time and the date is left as zero. Otherwise,
+// a day that does not exist in its month, like February 30, is rejected; zero
+// parts are accepted and left to the caller to check against the SQL mode.
+func StrToDate(s, format string, onlyTime bool) (DateTime, bool) {
+	var tp strToDateParts
+	if _, ok := tp.extract(s, format, false); !ok {
+		return DateTime{}, false
+	}
+
+	var dt DateTime
+	dt.Time = Time{
+		hour:       uint16(tp.hour),
+		minute:     uint8(tp.minute),
+		second:     uint8(tp.second),
+		nanosecond: uint32(tp.micros * 1000),
+	}
+	if onlyTime {
+		dt.Time.hour += uint16(tp.day * 24)
+	} else {
+		if tp.month > 0 && tp.day > daysIn(time.Month(tp.month), tp.year) {
+			return DateTime{}, false
+		}
+		dt.Date = Date{
+			year:  uint16(tp.year),
+			month: uint8(tp.month),
+			day:   uint8(tp.day),
+		}
+	}
+	return dt, true
+}
+
+// StrToDateParts returns which parts of a datetime value will be set when
+// parsing a string with the given STR_TO_DATE format.
+func StrToDateParts(format string) (hasDate, hasTime, hasFrac bool) {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			continue
+		}
+		i++
+		switch format[i] {
+		case 'f':
+			hasFrac = true
+			hasTime = true
+		case 'H', 'I', 'S', 'T', 'h', 'i', 'k', 'l', 'r', 's':
+			hasTime = true
+		case 'M', 'V', 'U', 'X', 'Y', 'W', 'a', 'b', 'c', 'd', 'D', 'e', 'j', 'm', 'u', 'v', 'w', 'x', 'y':
+			hasDate = true
+		}
+		if hasDate && hasFrac {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datetime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrToDate(t *testing.T) {
+	testCases := []struct {
+		in       string
+		format   string
+		onlyTime bool
+		want     string
+		ok       bool
+	}{
+		{"01,5,2013", "%d,%m,%Y", false, "2013-05-01 00:00:00", true},
+		{"May 1, 2013", "%M %d,%Y", false, "2013-05-01 00:00:00", true},
+		{"a09:30:17", "a%h:%i:%s", false, "0000-00-00 09:30:17", true},
+		{"a09:30:17", "%h:%i:%s", false, "", false},
+		{"09:30:17a", "%h:%i:%s", false, "0000-00-00 09:30:17", true},
+		{"abc", "abc", false, "0000-00-00 00:00:00", true},
+		{"9", "%m", false, "0000-09-00 00:00:00", true},
+		{"9", "%s", true, "00:00:09", true},
+		{"04/31/2004", "%m/%d/%Y", false, "", false},
+		{"2013-02-30", "%Y-%m-%d", false, "", false},
+		{"2012-02-29", "%Y-%m-%d", false, "2012-02-29 00:00:00", true},
+		{"2013-02-29", "%Y-%m-%d", false, "", false},
+		{"0000-02-29", "%Y-%m-%d", false, "", false},
+		{"0000-01-05", "%Y-%m-%d", false, "0000-01-05 00:00:00", true},
+		{"2013-00-31", "%Y-%m-%d", false, "2013-00-31 00:00:00", true},
+		{"30 10:11", "%d %H:%i", true, "730:11:00", true},
+		{"13/01/2004", "%m/%d/%Y", false, "", false},
+		{"1/2/69", "%m/%d/%y", false, "2069-01-02 00:00:00", true},
+		{"1/2/70", "%m/%d/%Y", false, "1970-01-02 00:00:00", true},
+		{"Jun 3rd 2020", "%b %D %Y", false, "2020-06-03 00:00:00", true},
+		{"Ju 3 2020", "%b %d %Y", false, "", false},
+		{"2020-06-03 11:22:33.12", "%Y-%m-%d %T.%f", false, "2020-06-03 11:22:33.120000", true},
+		{"2020-06-03 11:22:33 pm", "%Y-%m-%d %r", false, "2020-06-03 23:22:33", true},
+		{"12:00:00 AM", "%r", true, "00:00:00", true},
+		{"13:00:00 AM", "%r", true, "", false},
+		{"10:00 PM", "%H:%i %p", true, "", false},
+		{"10:00", "%h:%i %p", true, "10:00:00", true},
+		{"2 10:11", "%d %H:%i", true, "58:11:00", true},
+		{"2020 60", "%Y %j", false, "2020-02-29 00:00:00", true},
+		{"200442 Monday", "%X%V %W", false, "2004-10-18 00:00:00", true},
+		{"200442 Monday", "%Y%V %W", false, "", false},
+		{"2004 42 1", "%Y %U %w", false, "2004-10-18 00:00:00", true},
+		{"2020/06/03", "%Y%.%m%.%d", false, "2020-06-03 00:00:00", true},
+		{"abc2020", "%@%Y", false, "2020-00-00 00:00:00", true},
+		{"2020", "%Y-%m-%d", false, "2020-00-00 00:00:00", true},
+		{"2020", "%Q", false, "", false},
+		{"-1", "%d", false, "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.in+"/"+tc.format, func(t *testing.T) {
+			dt, ok := StrToDate(tc.in, tc.format, tc.onlyTime)
+			assert.Equal(t, tc.ok, ok)
+			if !ok {
+				return
+			}
+			if tc.onlyTime {
+				assert.Equal(t, tc.want, string(dt.Time.Format(6)[:len(tc.want)]))
+			} else {
+				assert.Equal(t, tc.want, string(dt.Format(6)[:len(tc.want)]))
+			}
+		})
+	}
+}
+
+func TestStrToDateParts(t *testing.T) {
+	testCases := []struct {
+		format                    string
+		hasDate, hasTime, hasFrac bool
+	}{
+		{"%Y-%m-%d", true, false, false},
+		{"%H:%i:%s", false, true, false},
+		{"%Y-%m-%d %T", true, true, false},
+		{"%s.%f", false, true, true},
+		{"%d %f", true, true, true},
+		{"%%Y", false, false, false},
+		{"abc", false, false, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			hasDate, hasTime, hasFrac := StrToDateParts(tc.format)
+			assert.Equal(t, tc.hasDate, hasDate)
+			assert.Equal(t, tc.hasTime, hasTime)
+			assert.Equal(t, tc.hasFrac, hasFrac)
+		})
+	}
+}
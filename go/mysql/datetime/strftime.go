@@ -69,6 +69,78 @@ func Format(p string, t DateTime, prec uint8) ([]byte, error) {
 	return dst, err
 }
 
+// FormatTime formats the time `t` with the format `p` the same way
+// MySQL's TIME_FORMAT does. Only the hour, minute and second
+// specifiers are meaningful for a time value: the numeric date
+// specifiers are formatted as zero, and any other date specifier
+// makes the formatting fail.
+func FormatTime(p string, t Time) ([]byte, bool) {
+	var dst []byte
+	if t.Neg() {
+		dst = append(dst, '-')
+	}
+
+	hour := t.Hour()
+	hour12 := (hour%24+11)%12 + 1
+	for i := 0; i < len(p); i++ {
+		if p[i] != '%' || i+1 == len(p) {
+			dst = append(dst, p[i])
+			continue
+		}
+		i++
+		switch p[i] {
+		case 'M', 'b', 'W', 'a', 'D', 'j', 'U', 'u', 'V', 'v', 'X', 'x', 'w':
+			return nil, false
+		case 'Y':
+			dst = append(dst, "0000"...)
+		case 'y', 'm', 'd':
+			dst = append(dst, "00"...)
+		case 'c', 'e':
+			dst = append(dst, '0')
+		case 'f':
+			dst = appendInt(dst, t.Nanosecond()/1000, 6)
+		case 'H':
+			dst = appendInt(dst, hour, 2)
+		case 'h', 'I':
+			dst = appendInt(dst, hour12, 2)
+		case 'i':
+			dst = appendInt(dst, t.Minute(), 2)
+		case 'k':
+			dst = appendInt(dst, hour, 0)
+		case 'l':
+			dst = appendInt(dst, hour12, 0)
+		case 'p':
+			dst = appendAMorPM(dst, hour)
+		case 'r':
+			dst = appendInt(dst, hour12, 2)
+			dst = append(dst, ':')
+			dst = appendInt(dst, t.Minute(), 2)
+			dst = append(dst, ':')
+			dst = appendInt(dst, t.Second(), 2)
+			dst = append(dst, ' ')
+			dst = appendAMorPM(dst, hour)
+		case 'S', 's':
+			dst = appendInt(dst, t.Second(), 2)
+		case 'T':
+			dst = appendInt(dst, hour, 2)
+			dst = append(dst, ':')
+			dst = appendInt(dst, t.Minute(), 2)
+			dst = append(dst, ':')
+			dst = appendInt(dst, t.Second(), 2)
+		default:
+			dst = append(dst, p[i])
+		}
+	}
+	return dst, true
+}
+
+func appendAMorPM(dst []byte, hour int) []byte {
+	if hour%24 < 12 {
+		return append(dst, "AM"...)
+	}
+	return append(dst, "PM"...)
+}
+
 // Strftime is the object that represents a compiled strftime pattern
 type Strftime struct {
 	pattern  string
@@ -102,3 +102,34 @@ func TestFormatNumeric(t *testing.T) {
 		assert.Equal(t, tc.want, n)
 	}
 }
+
+func TestFormatTime(t *testing.T) {
+	testCases := []struct {
+		format string
+		time   string
+		want   string
+		ok     bool
+	}{
+		{"%H:%i:%s", "10:11:12", "10:11:12", true},
+		{"%H %k %h %I %l %p", "100:00:00", "100 100 04 04 4 AM", true},
+		{"%r", "13:05:09", "01:05:09 PM", true},
+		{"%T.%f", "-01:02:03.456", "-01:02:03.456000", true},
+		{"%Y-%m-%d %c %e %y", "01:02:03", "0000-00-00 0 0 00", true},
+		{"%H%", "01:02:03", "01%", true},
+		{"%Q %%", "01:02:03", "Q %", true},
+		{"%M", "01:02:03", "", false},
+		{"%W", "01:02:03", "", false},
+		{"%j", "01:02:03", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			tm, _, state := ParseTime(tc.time, -1)
+			require.Equal(t, TimeOK, state)
+
+			got, ok := FormatTime(tc.format, tm)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, string(got))
+		})
+	}
+}
@@ -217,20 +217,38 @@ func (jp *Path) Match(doc *Value, wrap bool, match func(value *Value)) {
 	m.value(jp, doc)
 }
 
-func (jp *Path) transform(v *Value, t func(pp *Path, vv *Value)) {
+// IsRoot returns whether jp is the bare document root path, `$`.
+func (jp *Path) IsRoot() bool {
+	return jp.kind == jpDocumentRoot && jp.next == nil
+}
+
+// last returns the final leg of jp.
+func (jp *Path) last() *Path {
+	for jp.next != nil {
+		jp = jp.next
+	}
+	return jp
+}
+
+// transform walks v following jp and calls t with the last leg of the
+// path and the value it applies to. The value returned by t replaces
+// the value it was called with in the document, and the (possibly
+// replaced) v is returned.
+func (jp *Path) transform(v *Value, t func(pp *Path, vv *Value) *Value) *Value {
 	if v == nil {
-		return
+		return nil
 	}
 	if jp.next == nil {
-		t(jp, v)
-		return
+		return t(jp, v)
 	}
 	switch jp.kind {
 	case jpDocumentRoot:
-		jp.next.transform(v, t)
+		return jp.next.transform(v, t)
 	case jpMember:
 		if obj, ok := v.Object(); ok {
-			jp.next.transform(obj.Get(jp.name), t)
+			if child := obj.Get(jp.name); child != nil {
+				obj.Set(jp.name, jp.next.transform(child, t), Replace)
+			}
 		}
 	case jpArrayLocation:
 		if ary, ok := v.Array(); ok {
@@ -239,7 +257,7 @@ func (jp *Path) transform(v *Value, t func(pp *Path, vv *Value)) {
 				panic("range in transformation path expression")
 			}
 			if from >= 0 && from < len(ary) {
-				jp.next.transform(ary[from], t)
+				ary[from] = jp.next.transform(ary[from], t)
 			}
 		} else if jp.offset0 == 0 || jp.offset0 == -1 {
 			/*
@@ -247,11 +265,12 @@ func (jp *Path) transform(v *Value, t func(pp *Path, vv *Value)) {
 				the result of the evaluation is the same as if the value had been
 				wrapped in a single-element array:
 			*/
-			jp.next.transform(v, t)
+			return jp.next.transform(v, t)
 		}
 	case jpMemberAny, jpArrayLocationAny, jpAny:
 		panic("wildcard in transformation path expression")
 	}
+	return v
 }
 
 type Transformation int
@@ -261,40 +280,164 @@ const (
 	Insert
 	Replace
 	Remove
+	ArrayAppend
+	ArrayInsert
+)
+
+var (
+	errTransformWildcards = vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "In this situation, path expressions may not contain the * and ** tokens or an array range.")
+	errTransformRoot      = vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "The path expression '$' is not allowed in this context.")
+	errTransformNotCell   = vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "A path expression is not a path to a cell in an array.")
 )
 
-func ApplyTransform(t Transformation, doc *Value, paths []*Path, values []*Value) error {
+// ApplyTransform applies the transformation t to doc for every one of the
+// given paths, in order, using the matching entry in values as the new value
+// for all transformations except Remove. It follows the semantics of MySQL's
+// JSON_SET, JSON_INSERT, JSON_REPLACE, JSON_REMOVE, JSON_ARRAY_APPEND and
+// JSON_ARRAY_INSERT. The document is modified in place, and the resulting
+// document is returned, since transforming the root path replaces it.
+func ApplyTransform(t Transformation, doc *Value, paths []*Path, values []*Value) (*Value, error) {
 	if t != Remove && len(paths) != len(values) {
 		panic("missing Values for transformation")
 	}
+	for _, p := range paths {
+		if p.ContainsWildcards() {
+			return nil, errTransformWildcards
+		}
+		switch t {
+		case Remove:
+			if p.IsRoot() {
+				return nil, errTransformRoot
+			}
+		case ArrayInsert:
+			if p.last().kind != jpArrayLocation {
+				return nil, errTransformNotCell
+			}
+		}
+	}
 	for i, p := range paths {
-		transform := func(pp *Path, vv *Value) {
-			switch pp.kind {
-			case jpArrayLocation:
-				if ary, ok := vv.Array(); ok {
-					from, to := pp.arrayOffsets(ary)
-					if from != to {
-						return
-					}
-					if t == Remove {
-						vv.DelArrayItem(from)
-					} else {
-						vv.SetArrayItem(from, values[i], t)
-					}
-				}
-			case jpMember:
-				if obj, ok := vv.Object(); ok {
-					if t == Remove {
-						obj.Del(pp.name)
-					} else {
-						obj.Set(pp.name, values[i], t)
-					}
+		var value *Value
+		if t != Remove {
+			value = values[i]
+		}
+		doc = p.transform(doc, func(pp *Path, vv *Value) *Value {
+			return transformLeg(t, pp, vv, value)
+		})
+	}
+	return doc, nil
+}
+
+// transformLeg applies the transformation t for the final leg pp of a path
+// to vv, the value that leg applies to, and returns the value that must
+// replace vv in the document.
+func transformLeg(t Transformation, pp *Path, vv *Value, value *Value) *Value {
+	switch pp.kind {
+	case jpDocumentRoot:
+		switch t {
+		case Set, Replace:
+			return value
+		case ArrayAppend:
+			return vv.AppendArrayItem(value)
+		}
+	case jpMember:
+		obj, ok := vv.Object()
+		if !ok {
+			break
+		}
+		switch t {
+		case Remove:
+			obj.Del(pp.name)
+		case ArrayAppend:
+			if child := obj.Get(pp.name); child != nil {
+				obj.Set(pp.name, child.AppendArrayItem(value), Replace)
+			}
+		case Set, Insert, Replace:
+			obj.Set(pp.name, value, t)
+		}
+	case jpArrayLocation:
+		ary, ok := vv.Array()
+		if !ok {
+			ary = []*Value{vv}
+		}
+		from, to := pp.arrayOffsets(ary)
+		if from != to {
+			break
+		}
+		if !ok {
+			// A scalar or an object is treated as a single-element array;
+			// growing that array wraps the value into a real one.
+			switch {
+			case from == 0 && (t == Set || t == Replace):
+				return value
+			case from == 0 && t == ArrayAppend:
+				return vv.AppendArrayItem(value)
+			case from > 0 && (t == Set || t == Insert):
+				return NewArray([]*Value{vv, value})
+			}
+			break
+		}
+		switch t {
+		case Remove:
+			vv.DelArrayItem(from)
+		case ArrayAppend:
+			if from >= 0 && from < len(ary) {
+				ary[from] = ary[from].AppendArrayItem(value)
+			}
+		case ArrayInsert:
+			vv.InsertArrayItem(from, value)
+		case Set, Insert, Replace:
+			vv.SetArrayItem(from, value, t)
+		}
+	}
+	return vv
+}
+
+// Search walks doc in document order and returns the paths of all the
+// string values for which match returns true, formatted the same way as
+// MySQL's JSON_SEARCH does. When roots are given, only the values found
+// inside any of the values matched by them are searched. If one is set,
+// the search stops at the first matching value.
+func Search(doc *Value, roots []*Path, one bool, match func(s string) bool) []string {
+	var scope map[*Value]struct{}
+	if len(roots) > 0 {
+		scope = make(map[*Value]struct{})
+		for _, root := range roots {
+			root.Match(doc, true, func(v *Value) {
+				scope[v] = struct{}{}
+			})
+		}
+	}
+
+	var found []string
+	var walk func(v *Value, path []byte, inside bool)
+	walk = func(v *Value, path []byte, inside bool) {
+		if one && len(found) > 0 {
+			return
+		}
+		if !inside {
+			_, inside = scope[v]
+		}
+		switch v.Type() {
+		case TypeString:
+			if inside && match(v.s) {
+				found = append(found, string(path))
+			}
+		case TypeObject:
+			v.o.Visit(func(key string, vv *Value) {
+				if jpIsIdentifier(key) {
+					walk(vv, append(append(path, '.'), key...), inside)
+				} else {
+					walk(vv, fmt.Appendf(path, ".%q", key), inside)
 				}
+			})
+		case TypeArray:
+			for i, vv := range v.a {
+				walk(vv, fmt.Appendf(path, "[%d]", i), inside)
 			}
 		}
-		p.transform(doc, transform)
 	}
-	return nil
+	walk(doc, []byte{'$'}, scope == nil)
+	return found
 }
 
 func MatchPath(rawJSON, rawPath []byte, match func(value *Value)) error {
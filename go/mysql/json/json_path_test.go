@@ -171,6 +171,34 @@ func TestTransformations(t *testing.T) {
 			Paths:    []string{`$[2]`, `$[1].b[1]`, `$[1].b[1]`},
 			Expected: `["a", {"b": [true]}]`,
 		},
+		{
+			T:        Set,
+			Document: `{"a": 1}`,
+			Paths:    []string{`$.a[1]`, `$.b`, `$[0].c`},
+			Values:   []string{"2", "3", "4"},
+			Expected: `{"a": [1, 2], "b": 3, "c": 4}`,
+		},
+		{
+			T:        Replace,
+			Document: `{"a": 1}`,
+			Paths:    []string{`$`},
+			Values:   []string{"[1]"},
+			Expected: `[1]`,
+		},
+		{
+			T:        ArrayAppend,
+			Document: Document1,
+			Paths:    []string{`$[0]`, `$[1].b`, `$[2]`},
+			Values:   []string{"1", "2", "3"},
+			Expected: `[["a", 1], {"b": [true, false, 2]}, [10, 20, 3]]`,
+		},
+		{
+			T:        ArrayInsert,
+			Document: Document1,
+			Paths:    []string{`$[1]`, `$[3][100]`},
+			Values:   []string{"1", "2"},
+			Expected: `["a", 1, {"b": [true, false]}, [10, 20, 2]]`,
+		},
 	}
 
 	for _, tc := range cases {
@@ -186,7 +214,7 @@ func TestTransformations(t *testing.T) {
 			values = append(values, json(t, v))
 		}
 
-		err := ApplyTransform(tc.T, doc, paths, values)
+		doc, err := ApplyTransform(tc.T, doc, paths, values)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -197,3 +225,31 @@ func TestTransformations(t *testing.T) {
 		}
 	}
 }
+
+func TestSearch(t *testing.T) {
+	const Document = `["abc", [{"k": "10"}, "def"], {"x": "abc"}, {"y": "bcd"}, {"a b": "abc"}]`
+	isABC := func(s string) bool { return s == "abc" }
+
+	cases := []struct {
+		Roots    []string
+		One      bool
+		Expected []string
+	}{
+		{Expected: []string{`$[0]`, `$[2].x`, `$[4]."a b"`}},
+		{One: true, Expected: []string{`$[0]`}},
+		{Roots: []string{`$[2]`, `$[3]`}, Expected: []string{`$[2].x`}},
+		{Roots: []string{`$**.x`}, Expected: []string{`$[2].x`}},
+		{Roots: []string{`$[1]`}},
+	}
+
+	for _, tc := range cases {
+		var roots []*Path
+		for _, p := range tc.Roots {
+			roots = append(roots, path(t, p))
+		}
+		found := Search(json(t, Document), roots, tc.One, isABC)
+		if !slices.Equal(found, tc.Expected) {
+			t.Errorf("bad search (roots %v)\nwant: %v\ngot:  %v", tc.Roots, tc.Expected, found)
+		}
+	}
+}
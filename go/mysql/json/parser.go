@@ -761,12 +761,10 @@ func (v *Value) MarshalTo(dst []byte) []byte {
 	case TypeBlob, TypeBit:
 		const prefix = "base64:type15:"
 
-		size := 2 + len(prefix) + base64.StdEncoding.EncodedLen(len(v.s))
-		dst := make([]byte, size)
-		dst[0] = '"'
-		copy(dst[1:], prefix)
-		base64.StdEncoding.Encode(dst[len(prefix)+1:], []byte(v.s))
-		dst[size-1] = '"'
+		dst = append(dst, '"')
+		dst = append(dst, prefix...)
+		dst = base64.StdEncoding.AppendEncode(dst, []byte(v.s))
+		dst = append(dst, '"')
 		return dst
 	case TypeNumber:
 		if v.NumberType() == NumberTypeFloat {
@@ -785,6 +783,58 @@ func (v *Value) MarshalTo(dst []byte) []byte {
 	}
 }
 
+// MarshalPrettyTo appends v to dst formatted the same way as MySQL's
+// JSON_PRETTY does: every array element and object member goes on its
+// own line, indented by two spaces per nesting level.
+func (v *Value) MarshalPrettyTo(dst []byte) []byte {
+	return v.marshalPretty(dst, 0)
+}
+
+func (v *Value) marshalPretty(dst []byte, depth int) []byte {
+	indent := func(dst []byte, depth int) []byte {
+		dst = append(dst, '\n')
+		for i := 0; i < depth; i++ {
+			dst = append(dst, ' ', ' ')
+		}
+		return dst
+	}
+
+	switch v.Type() {
+	case TypeObject:
+		if v.o.Len() == 0 {
+			return append(dst, '{', '}')
+		}
+		dst = append(dst, '{')
+		for i, kv := range v.o.kvs {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = indent(dst, depth+1)
+			dst = escapeString(dst, kv.k)
+			dst = append(dst, ':', ' ')
+			dst = kv.v.marshalPretty(dst, depth+1)
+		}
+		dst = indent(dst, depth)
+		return append(dst, '}')
+	case TypeArray:
+		if len(v.a) == 0 {
+			return append(dst, '[', ']')
+		}
+		dst = append(dst, '[')
+		for i, vv := range v.a {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = indent(dst, depth+1)
+			dst = vv.marshalPretty(dst, depth+1)
+		}
+		dst = indent(dst, depth)
+		return append(dst, ']')
+	default:
+		return v.MarshalTo(dst)
+	}
+}
+
 // String returns string representation of the v.
 //
 // The function is for debugging purposes only. It isn't optimized for speed.
@@ -801,3 +801,26 @@ func TestParserParse(t *testing.T) {
 		}
 	})
 }
+
+func TestMarshalPretty(t *testing.T) {
+	var p Parser
+	v, err := p.Parse(`{"a": [1, {}, []], "b": {"c": "d"}, "e": null}`)
+	if err != nil {
+		t.Fatalf("unexpected error during parse: %s", err)
+	}
+
+	const expected = `{
+  "a": [
+    1,
+    {},
+    []
+  ],
+  "b": {
+    "c": "d"
+  },
+  "e": null
+}`
+	if got := string(v.MarshalPrettyTo(nil)); got != expected {
+		t.Fatalf("unexpected pretty output\nwant: %s\ngot:  %s", expected, got)
+	}
+}
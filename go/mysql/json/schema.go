@@ -0,0 +1,480 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package json
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// ValidateSchema returns whether doc is valid against the given JSON Schema.
+// It supports the validation keywords of JSON Schema draft 4 that MySQL's
+// JSON_SCHEMA_VALID supports, plus the numeric form of exclusiveMinimum and
+// exclusiveMaximum from later drafts. References are only supported when
+// they point inside the schema itself. Unknown keywords are ignored.
+func ValidateSchema(schema, doc *Value) (bool, error) {
+	v := schemaValidator{root: schema}
+	return v.valid(schema, doc)
+}
+
+type schemaValidator struct {
+	root     *Value
+	patterns map[string]*regexp.Regexp
+}
+
+func (sv *schemaValidator) pattern(expr string) (*regexp.Regexp, error) {
+	if re, ok := sv.patterns[expr]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "Invalid JSON Schema pattern '%s': %v", expr, err)
+	}
+	if sv.patterns == nil {
+		sv.patterns = make(map[string]*regexp.Regexp)
+	}
+	sv.patterns[expr] = re
+	return re, nil
+}
+
+func (sv *schemaValidator) resolve(ref string) (*Value, error) {
+	if ref != "#" && !strings.HasPrefix(ref, "#/") {
+		return nil, vterrors.Errorf(vtrpc.Code_UNIMPLEMENTED, "Unsupported JSON Schema reference '%s'", ref)
+	}
+	v := sv.root
+	for _, token := range strings.Split(ref, "/")[1:] {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch v.Type() {
+		case TypeObject:
+			v = v.o.Get(token)
+		case TypeArray:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(v.a) {
+				v = nil
+			} else {
+				v = v.a[idx]
+			}
+		default:
+			v = nil
+		}
+		if v == nil {
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "Unresolved JSON Schema reference '%s'", ref)
+		}
+	}
+	return v, nil
+}
+
+func (sv *schemaValidator) valid(schema, doc *Value) (bool, error) {
+	if schema.Type() == TypeBoolean {
+		return schema == ValueTrue, nil
+	}
+	obj, ok := schema.Object()
+	if !ok {
+		return true, nil
+	}
+	if ref := obj.Get("$ref"); ref != nil && ref.Type() == TypeString {
+		resolved, err := sv.resolve(ref.s)
+		if err != nil {
+			return false, err
+		}
+		return sv.valid(resolved, doc)
+	}
+
+	checks := []func(*Object, *Value) (bool, error){
+		sv.validType,
+		sv.validEnum,
+		sv.validCombinators,
+		sv.validNumber,
+		sv.validString,
+		sv.validArray,
+		sv.validObject,
+	}
+	for _, check := range checks {
+		if ok, err := check(obj, doc); !ok || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (sv *schemaValidator) validType(schema *Object, doc *Value) (bool, error) {
+	t := schema.Get("type")
+	if t == nil {
+		return true, nil
+	}
+	types := []*Value{t}
+	if ary, ok := t.Array(); ok {
+		types = ary
+	}
+	for _, t := range types {
+		if t.Type() != TypeString {
+			continue
+		}
+		switch name := schemaTypeName(doc); {
+		case t.s == name:
+			return true, nil
+		case t.s == "number" && name == "integer":
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (sv *schemaValidator) validEnum(schema *Object, doc *Value) (bool, error) {
+	if c := schema.Get("const"); c != nil && !schemaEqual(c, doc) {
+		return false, nil
+	}
+	if enum := schema.Get("enum"); enum != nil {
+		values, _ := enum.Array()
+		for _, v := range values {
+			if schemaEqual(v, doc) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+func (sv *schemaValidator) validCombinators(schema *Object, doc *Value) (bool, error) {
+	if all := schema.Get("allOf"); all != nil {
+		subschemas, _ := all.Array()
+		for _, sub := range subschemas {
+			if ok, err := sv.valid(sub, doc); !ok || err != nil {
+				return false, err
+			}
+		}
+	}
+	if anyOf := schema.Get("anyOf"); anyOf != nil {
+		subschemas, _ := anyOf.Array()
+		var matched bool
+		for _, sub := range subschemas {
+			ok, err := sv.valid(sub, doc)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	if oneOf := schema.Get("oneOf"); oneOf != nil {
+		subschemas, _ := oneOf.Array()
+		var matched int
+		for _, sub := range subschemas {
+			ok, err := sv.valid(sub, doc)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return false, nil
+		}
+	}
+	if not := schema.Get("not"); not != nil {
+		ok, err := sv.valid(not, doc)
+		if ok || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (sv *schemaValidator) validNumber(schema *Object, doc *Value) (bool, error) {
+	if doc.Type() != TypeNumber {
+		return true, nil
+	}
+	d, ok := doc.Decimal()
+	if !ok {
+		return true, nil
+	}
+
+	if minimum := schema.Get("minimum"); minimum != nil {
+		if m, ok := minimum.Decimal(); ok {
+			cmp := d.Cmp(m)
+			if cmp < 0 || cmp == 0 && schema.Get("exclusiveMinimum") == ValueTrue {
+				return false, nil
+			}
+		}
+	}
+	if maximum := schema.Get("maximum"); maximum != nil {
+		if m, ok := maximum.Decimal(); ok {
+			cmp := d.Cmp(m)
+			if cmp > 0 || cmp == 0 && schema.Get("exclusiveMaximum") == ValueTrue {
+				return false, nil
+			}
+		}
+	}
+	if minimum := schema.Get("exclusiveMinimum"); minimum != nil && minimum.Type() == TypeNumber {
+		if m, ok := minimum.Decimal(); ok && d.Cmp(m) <= 0 {
+			return false, nil
+		}
+	}
+	if maximum := schema.Get("exclusiveMaximum"); maximum != nil && maximum.Type() == TypeNumber {
+		if m, ok := maximum.Decimal(); ok && d.Cmp(m) >= 0 {
+			return false, nil
+		}
+	}
+	if multiple := schema.Get("multipleOf"); multiple != nil {
+		if m, ok := multiple.Decimal(); ok && m.Sign() > 0 {
+			if _, rem := d.QuoRem(m, 0); rem.Sign() != 0 {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+func (sv *schemaValidator) validString(schema *Object, doc *Value) (bool, error) {
+	if doc.Type() != TypeString {
+		return true, nil
+	}
+	length := int64(utf8.RuneCountInString(doc.s))
+	if n, ok := schemaCount(schema, "minLength"); ok && length < n {
+		return false, nil
+	}
+	if n, ok := schemaCount(schema, "maxLength"); ok && length > n {
+		return false, nil
+	}
+	if p := schema.Get("pattern"); p != nil && p.Type() == TypeString {
+		re, err := sv.pattern(p.s)
+		if err != nil {
+			return false, err
+		}
+		if !re.MatchString(doc.s) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (sv *schemaValidator) validArray(schema *Object, doc *Value) (bool, error) {
+	ary, ok := doc.Array()
+	if !ok {
+		return true, nil
+	}
+	if n, ok := schemaCount(schema, "minItems"); ok && int64(len(ary)) < n {
+		return false, nil
+	}
+	if n, ok := schemaCount(schema, "maxItems"); ok && int64(len(ary)) > n {
+		return false, nil
+	}
+	if schema.Get("uniqueItems") == ValueTrue {
+		for i := range ary {
+			for j := i + 1; j < len(ary); j++ {
+				if schemaEqual(ary[i], ary[j]) {
+					return false, nil
+				}
+			}
+		}
+	}
+
+	items := schema.Get("items")
+	if items == nil {
+		return true, nil
+	}
+	rest := ary
+	if tuple, ok := items.Array(); ok {
+		for i, sub := range tuple {
+			if i >= len(ary) {
+				break
+			}
+			if ok, err := sv.valid(sub, ary[i]); !ok || err != nil {
+				return false, err
+			}
+		}
+		rest = ary[min(len(tuple), len(ary)):]
+		items = schema.Get("additionalItems")
+		if items == nil {
+			return true, nil
+		}
+	}
+	for _, v := range rest {
+		if ok, err := sv.valid(items, v); !ok || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (sv *schemaValidator) validObject(schema *Object, doc *Value) (bool, error) {
+	obj, ok := doc.Object()
+	if !ok {
+		return true, nil
+	}
+	if n, ok := schemaCount(schema, "minProperties"); ok && int64(obj.Len()) < n {
+		return false, nil
+	}
+	if n, ok := schemaCount(schema, "maxProperties"); ok && int64(obj.Len()) > n {
+		return false, nil
+	}
+	if required := schema.Get("required"); required != nil {
+		keys, _ := required.Array()
+		for _, key := range keys {
+			if key.Type() == TypeString && obj.Get(key.s) == nil {
+				return false, nil
+			}
+		}
+	}
+
+	properties := schemaObject(schema, "properties")
+	patterns := schemaObject(schema, "patternProperties")
+	additional := schema.Get("additionalProperties")
+	for _, kv := range obj.kvs {
+		var matched bool
+		if sub := properties.Get(kv.k); sub != nil {
+			matched = true
+			if ok, err := sv.valid(sub, kv.v); !ok || err != nil {
+				return false, err
+			}
+		}
+		for _, pkv := range patterns.kvs {
+			re, err := sv.pattern(pkv.k)
+			if err != nil {
+				return false, err
+			}
+			if !re.MatchString(kv.k) {
+				continue
+			}
+			matched = true
+			if ok, err := sv.valid(pkv.v, kv.v); !ok || err != nil {
+				return false, err
+			}
+		}
+		if !matched && additional != nil {
+			if ok, err := sv.valid(additional, kv.v); !ok || err != nil {
+				return false, err
+			}
+		}
+	}
+
+	for _, dkv := range schemaObject(schema, "dependencies").kvs {
+		if obj.Get(dkv.k) == nil {
+			continue
+		}
+		if keys, ok := dkv.v.Array(); ok {
+			for _, key := range keys {
+				if key.Type() == TypeString && obj.Get(key.s) == nil {
+					return false, nil
+				}
+			}
+		} else if ok, err := sv.valid(dkv.v, doc); !ok || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// schemaTypeName returns the JSON Schema type name for v. MySQL-specific
+// scalar types, such as dates or opaque values, are serialized as strings
+// in JSON text, so they are validated as such.
+func schemaTypeName(v *Value) string {
+	switch v.Type() {
+	case TypeNull:
+		return "null"
+	case TypeBoolean:
+		return "boolean"
+	case TypeNumber:
+		switch v.NumberType() {
+		case NumberTypeSigned, NumberTypeUnsigned:
+			return "integer"
+		}
+		return "number"
+	case TypeObject:
+		return "object"
+	case TypeArray:
+		return "array"
+	default:
+		return "string"
+	}
+}
+
+// schemaObject returns the object for the given keyword of schema, or an
+// empty object if it's missing or it's not an object.
+func schemaObject(schema *Object, keyword string) *Object {
+	if v := schema.Get(keyword); v != nil {
+		if obj, ok := v.Object(); ok {
+			return obj
+		}
+	}
+	return &Object{}
+}
+
+func schemaCount(schema *Object, keyword string) (int64, bool) {
+	v := schema.Get(keyword)
+	if v == nil || v.Type() != TypeNumber {
+		return 0, false
+	}
+	d, ok := v.Decimal()
+	if !ok {
+		return 0, false
+	}
+	return d.Int64()
+}
+
+// schemaEqual returns whether a and b are equal JSON values, comparing
+// numbers by their numeric value.
+func schemaEqual(a, b *Value) bool {
+	if a.Type() != b.Type() {
+		return false
+	}
+	switch a.Type() {
+	case TypeNull:
+		return true
+	case TypeBoolean:
+		return a == b
+	case TypeNumber:
+		ad, aok := a.Decimal()
+		bd, bok := b.Decimal()
+		return aok && bok && ad.Equal(bd)
+	case TypeArray:
+		if len(a.a) != len(b.a) {
+			return false
+		}
+		for i := range a.a {
+			if !schemaEqual(a.a[i], b.a[i]) {
+				return false
+			}
+		}
+		return true
+	case TypeObject:
+		if a.o.Len() != b.o.Len() {
+			return false
+		}
+		for _, kv := range a.o.kvs {
+			other := b.o.Get(kv.k)
+			if other == nil || !schemaEqual(kv.v, other) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a.s == b.s
+	}
+}
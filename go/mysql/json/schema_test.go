@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSchema(t *testing.T) {
+	const geo = `{
+		"id": "http://json-schema.org/geo",
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"description": "A geographical coordinate",
+		"type": "object",
+		"properties": {
+			"latitude": {"type": "number", "minimum": -90, "maximum": 90},
+			"longitude": {"type": "number", "minimum": -180, "maximum": 180}
+		},
+		"required": ["latitude", "longitude"]
+	}`
+
+	cases := []struct {
+		schema string
+		doc    string
+		valid  bool
+	}{
+		{geo, `{"latitude": 63.444697, "longitude": 10.445118}`, true},
+		{geo, `{"latitude": 63.444697}`, false},
+		{geo, `{"latitude": 91, "longitude": 0}`, false},
+		{geo, `{"latitude": "63", "longitude": 0}`, false},
+		{`{"type": "integer"}`, `1`, true},
+		{`{"type": "integer"}`, `1.5`, false},
+		{`{"type": ["string", "null"]}`, `null`, true},
+		{`{"enum": [1, "a", [true]]}`, `[true]`, true},
+		{`{"enum": [1, "a", [true]]}`, `"b"`, false},
+		{`{"type": "string", "minLength": 2, "maxLength": 3, "pattern": "^a"}`, `"abc"`, true},
+		{`{"type": "string", "minLength": 2, "maxLength": 3, "pattern": "^a"}`, `"bcd"`, false},
+		{`{"items": {"type": "number"}, "minItems": 1, "uniqueItems": true}`, `[1, 2, 3]`, true},
+		{`{"items": {"type": "number"}, "minItems": 1, "uniqueItems": true}`, `[1, 2, 1.0]`, false},
+		{`{"items": [{"type": "string"}], "additionalItems": false}`, `["a", 1]`, false},
+		{`{"additionalProperties": false, "patternProperties": {"^x": {}}}`, `{"x1": 1, "x2": 2}`, true},
+		{`{"additionalProperties": false, "patternProperties": {"^x": {}}}`, `{"x1": 1, "y": 2}`, false},
+		{`{"oneOf": [{"multipleOf": 3}, {"multipleOf": 5}]}`, `15`, false},
+		{`{"anyOf": [{"multipleOf": 3}, {"multipleOf": 5}]}`, `10`, true},
+		{`{"not": {"type": "object"}}`, `{}`, false},
+		{`{"definitions": {"pos": {"minimum": 0}}, "properties": {"a": {"$ref": "#/definitions/pos"}}}`, `{"a": -1}`, false},
+		{`{"maximum": 10, "exclusiveMaximum": true}`, `10`, false},
+		{`{"exclusiveMinimum": 10}`, `11`, true},
+	}
+
+	for _, tc := range cases {
+		valid, err := ValidateSchema(MustParse(tc.schema), MustParse(tc.doc))
+		require.NoError(t, err)
+		assert.Equalf(t, tc.valid, valid, "schema %s, document %s", tc.schema, tc.doc)
+	}
+}
@@ -64,27 +64,43 @@ func (o *Object) Set(key string, value *Value, t Transformation) {
 	}
 }
 
-// SetArrayItem sets the value in the array v at idx position.
+// SetArrayItem sets the value in the array v at idx position. Setting
+// or inserting a value past the end of the array appends it.
 //
 // The value must be unchanged during v lifetime.
 func (v *Value) SetArrayItem(idx int, value *Value, t Transformation) {
 	if v == nil || v.t != TypeArray || idx < 0 {
 		return
 	}
-	switch t {
-	case Insert:
-		if idx < len(v.a) {
-			return
-		}
-		fallthrough
-	case Set:
-		for idx >= len(v.a) {
-			v.a = append(v.a, ValueNull)
+	switch {
+	case idx < len(v.a):
+		if t == Set || t == Replace {
+			v.a[idx] = value
 		}
+	case t == Set || t == Insert:
+		v.a = append(v.a, value)
+	}
+}
+
+// InsertArrayItem inserts the value in the array v at idx position,
+// shifting the following items. Positions past the end of the array
+// append the value.
+func (v *Value) InsertArrayItem(idx int, value *Value) {
+	if v == nil || v.t != TypeArray {
+		return
 	}
-	if idx < len(v.a) {
-		v.a[idx] = value
+	idx = max(0, min(idx, len(v.a)))
+	v.a = slices.Insert(v.a, idx, value)
+}
+
+// AppendArrayItem appends the value to the array v and returns v. If v
+// is not an array, it returns a new array holding both v and value.
+func (v *Value) AppendArrayItem(value *Value) *Value {
+	if v.t != TypeArray {
+		return NewArray([]*Value{v, value})
 	}
+	v.a = append(v.a, value)
+	return v
 }
 
 func (v *Value) DelArrayItem(n int) {
@@ -96,3 +112,26 @@ func (v *Value) DelArrayItem(n int) {
 	}
 	v.a = append(v.a[:n], v.a[n+1:]...)
 }
+
+// Clone returns a deep copy of v that can be modified without
+// affecting v.
+func (v *Value) Clone() *Value {
+	switch v.t {
+	case TypeObject:
+		obj := Object{kvs: make([]kv, 0, len(v.o.kvs))}
+		for _, kv := range v.o.kvs {
+			obj.kvs = append(obj.kvs, kv)
+			obj.kvs[len(obj.kvs)-1].v = kv.v.Clone()
+		}
+		return &Value{o: obj, t: TypeObject}
+	case TypeArray:
+		ary := make([]*Value, 0, len(v.a))
+		for _, vv := range v.a {
+			ary = append(ary, vv.Clone())
+		}
+		return &Value{a: ary, t: TypeArray}
+	default:
+		// scalar values are never modified in place
+		return v
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"io"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+)
+
+// This file contains the server side of the LOAD DATA LOCAL INFILE exchange.
+//
+// While executing a LOAD DATA LOCAL INFILE statement, the server sends a
+// LocalInfilePacket with the name of the file to the client, and the client
+// replies with the contents of the file split over as many packets as needed,
+// followed by an empty packet. The server then sends the usual OK or error packet.
+
+// ReadLocalInfile asks the client for the contents of the given file.
+// It can only be called from within the Handler.ComQuery of this connection,
+// before anything was sent through the callback.
+// The returned reader must be closed before the query returns, which makes sure
+// that all the packets the client sends were read, even if the statement fails half way.
+func (c *Conn) ReadLocalInfile(fileName string) (io.ReadCloser, error) {
+	if c.Capabilities&CapabilityClientLocalFiles == 0 {
+		return nil, sqlerror.NewSQLErrorf(sqlerror.ERNotAllowedCommand, sqlerror.SSClientError, "Loading local data is disabled; this must be enabled on both the client and server sides")
+	}
+
+	data, pos := c.startEphemeralPacketWithHeader(1 + len(fileName))
+	data[pos] = LocalInfilePacket
+	copy(data[pos+1:], fileName)
+	if err := c.writeEphemeralPacket(); err != nil {
+		return nil, sqlerror.NewSQLErrorf(sqlerror.CRServerGone, sqlerror.SSUnknownSQLState, "%v", err)
+	}
+	if err := c.flushBufferedWriter(); err != nil {
+		return nil, sqlerror.NewSQLErrorf(sqlerror.CRServerGone, sqlerror.SSUnknownSQLState, "%v", err)
+	}
+	return &localInfileReader{c: c}, nil
+}
+
+// flushBufferedWriter sends everything that was buffered so far, without stopping the buffering.
+func (c *Conn) flushBufferedWriter() error {
+	c.bufMu.Lock()
+	defer c.bufMu.Unlock()
+
+	if c.bufferedWriter == nil {
+		return nil
+	}
+	return c.bufferedWriter.Flush()
+}
+
+// localInfileReader reads the contents of a file sent by the client, until the empty packet
+type localInfileReader struct {
+	c    *Conn
+	buf  []byte
+	done bool
+	err  error
+}
+
+// Read implements the io.Reader interface
+func (r *localInfileReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if err := r.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// next reads the next packet from the client, returning io.EOF once the empty packet was read
+func (r *localInfileReader) next() error {
+	if r.done {
+		return io.EOF
+	}
+	if r.err != nil {
+		return r.err
+	}
+	data, err := r.c.readPacket()
+	if err != nil {
+		r.err = sqlerror.NewSQLErrorf(sqlerror.CRServerLost, sqlerror.SSUnknownSQLState, "%v", err)
+		return r.err
+	}
+	if len(data) == 0 {
+		r.done = true
+		return io.EOF
+	}
+	r.buf = data
+	return nil
+}
+
+// Close reads whatever the client still has to send, so the connection is ready for the response
+func (r *localInfileReader) Close() error {
+	r.buf = nil
+	for {
+		err := r.next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		r.buf = nil
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+)
+
+// sendLocalInfile plays the client side of the exchange: it reads the request
+// for the file, and replies with the given chunks followed by the empty packet.
+func sendLocalInfile(t *testing.T, cConn *Conn, fileName string, chunks ...string) {
+	data, err := cConn.readPacket()
+	require.NoError(t, err)
+	require.Equal(t, append([]byte{LocalInfilePacket}, fileName...), data)
+
+	for _, chunk := range chunks {
+		useWritePacket(t, cConn, []byte(chunk))
+	}
+	useWritePacket(t, cConn, nil)
+}
+
+func TestReadLocalInfile(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+	sConn.Capabilities |= CapabilityClientLocalFiles
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sendLocalInfile(t, cConn, "/tmp/data.csv", "1,a\n", "2,b\n3,", "c\n")
+	}()
+
+	r, err := sConn.ReadLocalInfile("/tmp/data.csv")
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	<-done
+	assert.Equal(t, "1,a\n2,b\n3,c\n", string(content))
+
+	// the connection is ready for the response
+	go func() {
+		require.NoError(t, sConn.writeOKPacket(&PacketOK{affectedRows: 3}))
+	}()
+	data, err := cConn.readPacket()
+	require.NoError(t, err)
+	assert.EqualValues(t, OKPacket, data[0])
+}
+
+func TestReadLocalInfileClose(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+	sConn.Capabilities |= CapabilityClientLocalFiles
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sendLocalInfile(t, cConn, "data.csv", "1,a\n", "2,b\n", "3,c\n")
+	}()
+
+	// closing the reader before reading all of it drains the remaining packets
+	r, err := sConn.ReadLocalInfile("data.csv")
+	require.NoError(t, err)
+	buf := make([]byte, 2)
+	n, err := r.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "1,", string(buf[:n]))
+	require.NoError(t, r.Close())
+	<-done
+
+	go func() {
+		require.NoError(t, sConn.writeOKPacket(&PacketOK{}))
+	}()
+	data, err := cConn.readPacket()
+	require.NoError(t, err)
+	assert.EqualValues(t, OKPacket, data[0])
+}
+
+func TestReadLocalInfileNotEnabled(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	_, err := sConn.ReadLocalInfile("data.csv")
+	require.Error(t, err)
+	assert.Equal(t, sqlerror.ERNotAllowedCommand, sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError).Number())
+}
+
+func TestLocalInfileCapability(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("enabled=%v", enabled), func(t *testing.T) {
+			listener, sConn, cConn := createSocketPair(t)
+			defer func() {
+				listener.Close()
+				sConn.Close()
+				cConn.Close()
+			}()
+			l := &Listener{}
+			l.EnableLocalInfile.Store(enabled)
+
+			// The server only advertises the capability when it is enabled.
+			salt, err := sConn.writeHandshakeV10("8.0.30-Vitess", NewAuthServerNone(), 0, false, enabled, 0)
+			require.NoError(t, err)
+			data, err := cConn.readPacket()
+			require.NoError(t, err)
+			capabilities, _, err := cConn.parseInitialHandshakePacket(data)
+			require.NoError(t, err)
+			assert.Equal(t, enabled, capabilities&CapabilityClientLocalFiles != 0)
+
+			// A client that asks for it anyway only gets it when it is enabled.
+			scrambled := ScrambleMysqlNativePassword(salt, []byte("password"))
+			require.NoError(t, cConn.writeHandshakeResponse41(capabilities, scrambled, 0, &ConnParams{Uname: "user"}))
+			data, err = sConn.readPacket()
+			require.NoError(t, err)
+			data[0] |= CapabilityClientLocalFiles
+			_, _, _, err = l.parseClientHandshakePacket(sConn, true, data)
+			require.NoError(t, err)
+			assert.Equal(t, enabled, sConn.Capabilities&CapabilityClientLocalFiles != 0)
+		})
+	}
+}
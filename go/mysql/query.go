@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -55,7 +56,11 @@ const (
 // Returns SQLError(CRServerGone) if it can't.
 func (c *Conn) WriteComQuery(query string) error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
+
+	if c.Capabilities&CapabilityClientQueryAttributes != 0 {
+		return c.writeComQueryWithAttributes(query)
+	}
 
 	data, pos := c.startEphemeralPacketWithHeader(len(query) + 1)
 	data[pos] = ComQuery
@@ -67,6 +72,54 @@ func (c *Conn) WriteComQuery(query string) error {
 	return nil
 }
 
+// writeComQueryWithAttributes writes a query along with the query attributes of
+// the connection, once CapabilityClientQueryAttributes was negotiated.
+// The attributes are sent as strings, sorted by name.
+func (c *Conn) writeComQueryWithAttributes(query string) error {
+	names := make([]string, 0, len(c.queryAttributes))
+	for name := range c.queryAttributes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	length := 1 + // ComQuery
+		lenEncIntSize(uint64(len(names))) +
+		1 + // parameter set count
+		len(query)
+	if len(names) > 0 {
+		length += (len(names)+7)/8 + // NULL-bitmap
+			1 // new params bind flag
+		for _, name := range names {
+			length += 2 + // type
+				lenEncStringSize(name) +
+				lenEncStringSize(c.queryAttributes[name])
+		}
+	}
+
+	data, pos := c.startEphemeralPacketWithHeader(length)
+	pos = writeByte(data, pos, ComQuery)
+	pos = writeLenEncInt(data, pos, uint64(len(names)))
+	pos = writeLenEncInt(data, pos, 1)
+	if len(names) > 0 {
+		// none of the attributes is NULL
+		pos = writeZeroes(data, pos, (len(names)+7)/8)
+		pos = writeByte(data, pos, 1)
+		typ, flags := sqltypes.TypeToMySQL(sqltypes.VarChar)
+		for _, name := range names {
+			pos = writeByte(data, pos, typ)
+			pos = writeByte(data, pos, byte(flags))
+			pos = writeLenEncString(data, pos, name)
+		}
+		for _, name := range names {
+			pos = writeLenEncString(data, pos, c.queryAttributes[name])
+		}
+	}
+	copy(data[pos:], query)
+	if err := c.writeEphemeralPacket(); err != nil {
+		return sqlerror.NewSQLError(sqlerror.CRServerGone, sqlerror.SSUnknownSQLState, err.Error())
+	}
+	return nil
+}
+
 // writeComInitDB changes the default database to use.
 // Client -> Server.
 // Returns SQLError(CRServerGone) if it can't.
@@ -574,8 +627,93 @@ func (c *Conn) readComQueryResponse(packetOk *PacketOK) (int, error) {
 // Server side methods.
 //
 
-func (c *Conn) parseComQuery(data []byte) string {
-	return string(data[1:])
+// parseComQuery returns the query of a COM_QUERY, along with its query attributes
+// if the client sends them.
+func (c *Conn) parseComQuery(data []byte) (string, map[string]string, error) {
+	if c.Capabilities&CapabilityClientQueryAttributes == 0 {
+		return string(data[1:]), nil, nil
+	}
+
+	count, pos, ok := readLenEncInt(data, 1)
+	if !ok {
+		return "", nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attributes count failed")
+	}
+	// the parameter set count, which is always 1
+	if _, pos, ok = readLenEncInt(data, pos); !ok {
+		return "", nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attributes set count failed")
+	}
+	// every attribute takes at least one byte, so don't trust a count that the packet can't hold
+	if count > uint64(len(data)-pos) {
+		return "", nil, sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "query attributes count %d is more than the packet can hold", count)
+	}
+	var attributes map[string]string
+	if count > 0 {
+		var err error
+		attributes, pos, err = c.parseQueryAttributes(data, pos, int(count))
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	return string(data[pos:]), attributes, nil
+}
+
+// parseQueryAttributes parses the query attributes of a COM_QUERY: their NULL-bitmap,
+// followed by their types and names, and then by their values. The NULL attributes
+// are left out.
+func (c *Conn) parseQueryAttributes(data []byte, pos int, count int) (map[string]string, int, error) {
+	bitMap, pos, ok := readBytes(data, pos, (count+7)/8)
+	if !ok {
+		return nil, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attributes NULL-bitmap failed")
+	}
+	// the new params bind flag, which is always 1
+	if _, pos, ok = readByte(data, pos); !ok {
+		return nil, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attributes bind flag failed")
+	}
+
+	types := make([]querypb.Type, count)
+	names := make([]string, count)
+	for i := range count {
+		var err error
+		types[i], names[i], pos, err = readQueryAttributeType(data, pos)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	attributes := make(map[string]string, count)
+	for i := range count {
+		if bitMap[i/8]&(1<<uint(i%8)) > 0 {
+			continue
+		}
+		var val sqltypes.Value
+		val, pos, ok = c.parseStmtArgs(data, types[i], pos)
+		if !ok {
+			return nil, 0, sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "decoding query attribute value failed: %v", types[i])
+		}
+		attributes[names[i]] = val.ToString()
+	}
+	return attributes, pos, nil
+}
+
+// readQueryAttributeType reads the type and the name of a query attribute.
+func readQueryAttributeType(data []byte, pos int) (querypb.Type, string, int, error) {
+	mysqlType, pos, ok := readByte(data, pos)
+	if !ok {
+		return 0, "", 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attribute type failed")
+	}
+	flags, pos, ok := readByte(data, pos)
+	if !ok {
+		return 0, "", 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attribute flags failed")
+	}
+	typ, err := sqltypes.MySQLToType(mysqlType, int64(flags))
+	if err != nil {
+		return 0, "", 0, sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "MySQLToType(%v,%v) failed: %v", mysqlType, flags, err)
+	}
+	name, pos, ok := readLenEncString(data, pos)
+	if !ok {
+		return 0, "", 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attribute name failed")
+	}
+	return typ, name, pos, nil
 }
 
 func (c *Conn) parseComSetOption(data []byte) (uint16, bool) {
@@ -587,7 +725,10 @@ func (c *Conn) parseComPrepare(data []byte) string {
 	return string(data[1:])
 }
 
-func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []byte) (uint32, byte, error) {
+// parseComStmtExecute parses the parameters of a COM_STMT_EXECUTE into the bind variables
+// of its statement, and returns its statement ID, its cursor type flags, and its query
+// attributes if the client sends them.
+func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []byte) (uint32, byte, map[string]string, error) {
 	pos := 0
 	payload := data[1:]
 	bitMap := make([]byte, 0)
@@ -595,32 +736,52 @@ func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []b
 	// statement ID
 	stmtID, pos, ok := readUint32(payload, 0)
 	if !ok {
-		return 0, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading statement ID failed")
+		return 0, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading statement ID failed")
 	}
 	prepare, ok := prepareData[stmtID]
 	if !ok {
-		return 0, 0, sqlerror.NewSQLError(sqlerror.CRCommandsOutOfSync, sqlerror.SSUnknownSQLState, "statement ID is not found from record")
+		return 0, 0, nil, sqlerror.NewSQLError(sqlerror.CRCommandsOutOfSync, sqlerror.SSUnknownSQLState, "statement ID is not found from record")
 	}
 
 	// cursor type flags
 	cursorType, pos, ok := readByte(payload, pos)
 	if !ok {
-		return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading cursor type flags failed")
+		return stmtID, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading cursor type flags failed")
 	}
 
 	// iteration count
 	iterCount, pos, ok := readUint32(payload, pos)
 	if !ok {
-		return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading iteration count failed")
+		return stmtID, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading iteration count failed")
 	}
 	if iterCount != uint32(1) {
-		return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "iteration count is not equal to 1")
+		return stmtID, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "iteration count is not equal to 1")
 	}
 
-	if prepare.ParamsCount > 0 {
-		bitMap, pos, ok = readBytes(payload, pos, (int(prepare.ParamsCount)+7)/8)
+	// With query attributes, the parameter count is sent, and the query attributes
+	// follow the parameters.
+	queryAttributes := c.Capabilities&CapabilityClientQueryAttributes != 0
+	attributesCount := 0
+	if queryAttributes && (prepare.ParamsCount > 0 || cursorType&ParameterCountAvailable != 0) {
+		var count uint64
+		count, pos, ok = readLenEncInt(payload, pos)
 		if !ok {
-			return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading NULL-bitmap failed")
+			return stmtID, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter count failed")
+		}
+		if count < uint64(prepare.ParamsCount) {
+			return stmtID, 0, nil, sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "parameter count %v is less than the %v parameters of the statement", count, prepare.ParamsCount)
+		}
+		// every query attribute takes at least one byte, so don't trust a count that the packet can't hold
+		if count-uint64(prepare.ParamsCount) > uint64(len(payload)-pos) {
+			return stmtID, 0, nil, sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "query attributes count %v is more than the packet can hold", count-uint64(prepare.ParamsCount))
+		}
+		attributesCount = int(count) - int(prepare.ParamsCount)
+	}
+
+	if count := int(prepare.ParamsCount) + attributesCount; count > 0 {
+		bitMap, pos, ok = readBytes(payload, pos, (count+7)/8)
+		if !ok {
+			return stmtID, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading NULL-bitmap failed")
 		}
 	}
 
@@ -630,22 +791,42 @@ func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []b
 		for i := range uint16(prepare.ParamsCount) {
 			mysqlType, pos, ok = readByte(payload, pos)
 			if !ok {
-				return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter type failed")
+				return stmtID, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter type failed")
 			}
 
 			flags, pos, ok = readByte(payload, pos)
 			if !ok {
-				return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter flags failed")
+				return stmtID, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter flags failed")
 			}
 
 			// convert MySQL type to internal type.
 			valType, err := sqltypes.MySQLToType(mysqlType, int64(flags))
 			if err != nil {
-				return stmtID, 0, sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "MySQLToType(%v,%v) failed: %v", mysqlType, flags, err)
+				return stmtID, 0, nil, sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "MySQLToType(%v,%v) failed: %v", mysqlType, flags, err)
 			}
 
 			prepare.ParamsType[i] = int32(valType)
+
+			if queryAttributes {
+				// the names of the parameters are not used
+				pos, ok = skipLenEncString(payload, pos)
+				if !ok {
+					return stmtID, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter name failed")
+				}
+			}
 		}
+
+		prepare.attributeTypes = make([]querypb.Type, attributesCount)
+		prepare.attributeNames = make([]string, attributesCount)
+		for i := range attributesCount {
+			var err error
+			prepare.attributeTypes[i], prepare.attributeNames[i], pos, err = readQueryAttributeType(payload, pos)
+			if err != nil {
+				return stmtID, 0, nil, err
+			}
+		}
+	} else if attributesCount != len(prepare.attributeTypes) {
+		return stmtID, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "query attributes sent without their types")
 	}
 
 	for i := range len(prepare.ParamsType) {
@@ -663,13 +844,29 @@ func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []b
 			val, pos, ok = c.parseStmtArgs(payload, querypb.Type(prepare.ParamsType[i]), pos)
 		}
 		if !ok {
-			return stmtID, 0, sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "decoding parameter value failed: %v", prepare.ParamsType[i])
+			return stmtID, 0, nil, sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "decoding parameter value failed: %v", prepare.ParamsType[i])
 		}
 
 		prepare.BindVars[parameterID] = sqltypes.ValueBindVariable(val)
 	}
 
-	return stmtID, cursorType, nil
+	var attributes map[string]string
+	if attributesCount > 0 {
+		attributes = make(map[string]string, attributesCount)
+	}
+	for i := range attributesCount {
+		if j := int(prepare.ParamsCount) + i; (bitMap[j/8] & (1 << uint(j%8))) > 0 {
+			continue
+		}
+		var val sqltypes.Value
+		val, pos, ok = c.parseStmtArgs(payload, prepare.attributeTypes[i], pos)
+		if !ok {
+			return stmtID, 0, nil, sqlerror.NewSQLErrorf(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "decoding query attribute value failed: %v", prepare.attributeTypes[i])
+		}
+		attributes[prepare.attributeNames[i]] = val.ToString()
+	}
+
+	return stmtID, cursorType, attributes, nil
 }
 
 func (c *Conn) parseStmtArgs(data []byte, typ querypb.Type, pos int) (sqltypes.Value, int, bool) {
@@ -928,6 +1125,15 @@ func (c *Conn) parseComStmtReset(data []byte) (uint32, bool) {
 	return val, ok
 }
 
+func (c *Conn) parseComStmtFetch(data []byte) (uint32, uint32, bool) {
+	stmtID, pos, ok := readUint32(data, 1)
+	if !ok {
+		return 0, 0, false
+	}
+	numRows, _, ok := readUint32(data, pos)
+	return stmtID, numRows, ok
+}
+
 func (c *Conn) parseComInitDB(data []byte) string {
 	return string(data[1:])
 }
@@ -1013,18 +1219,10 @@ func (c *Conn) writeRow(row []sqltypes.Value) error {
 // writeFields writes the fields of a Result. It should be called only
 // if there are valid columns in the result.
 func (c *Conn) writeFields(result *sqltypes.Result) error {
-	// Send the number of fields first.
-	if err := c.sendColumnCount(uint64(len(result.Fields))); err != nil {
+	if err := c.writeColumnDefinitions(result.Fields); err != nil {
 		return err
 	}
 
-	// Now send each Field.
-	for _, field := range result.Fields {
-		if err := c.writeColumnDefinition(field); err != nil {
-			return err
-		}
-	}
-
 	// Now send an EOF packet.
 	if c.Capabilities&CapabilityClientDeprecateEOF == 0 {
 		// With CapabilityClientDeprecateEOF, we do not send this EOF.
@@ -1035,6 +1233,22 @@ func (c *Conn) writeFields(result *sqltypes.Result) error {
 	return nil
 }
 
+// writeColumnDefinitions writes the number of fields, and then each field.
+func (c *Conn) writeColumnDefinitions(fields []*querypb.Field) error {
+	// Send the number of fields first.
+	if err := c.sendColumnCount(uint64(len(fields))); err != nil {
+		return err
+	}
+
+	// Now send each Field.
+	for _, field := range fields {
+		if err := c.writeColumnDefinition(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // writeRows sends the rows of a Result.
 func (c *Conn) writeRows(result *sqltypes.Result) error {
 	for _, row := range result.Rows {
@@ -1054,6 +1268,12 @@ func (c *Conn) writeEndResult(more bool, affectedRows, lastInsertID uint64, warn
 	if more {
 		flags |= ServerMoreResultsExists
 	}
+	return c.writeEndPacket(flags, affectedRows, lastInsertID, warnings)
+}
+
+// writeEndPacket writes the packet that concludes a result set with the given status flags:
+// an EOF packet, or an OK packet with an EOF header with CapabilityClientDeprecateEOF.
+func (c *Conn) writeEndPacket(flags uint16, affectedRows, lastInsertID uint64, warnings uint16) error {
 	if c.Capabilities&CapabilityClientDeprecateEOF == 0 {
 		if err := c.writeEOFPacket(flags, warnings); err != nil {
 			return err
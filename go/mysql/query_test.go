@@ -248,7 +248,7 @@ func TestComStmtExecute(t *testing.T) {
 	// This is simulated packets for `select * from test_table where id = ?`
 	data := []byte{23, 18, 0, 0, 0, 128, 1, 0, 0, 0, 0, 1, 1, 128, 1}
 
-	stmtID, _, err := sConn.parseComStmtExecute(cConn.PrepareData, data)
+	stmtID, _, _, err := sConn.parseComStmtExecute(cConn.PrepareData, data)
 	require.NoError(t, err, "parseComStmtExeute failed: %v", err)
 	require.Equal(t, uint32(18), stmtID, "Parsed incorrect values")
 
@@ -292,7 +292,7 @@ func TestComStmtExecuteUpdStmt(t *testing.T) {
 		0x35, 0x36, 0x37, 0x38, 0x0c, 0xe9, 0x9f, 0xa9, 0xe5, 0x86, 0xac, 0xe7, 0x9c, 0x9f, 0xe8, 0xb5,
 		0x9e, 0x03, 0x66, 0x6f, 0x6f, 0x07, 0x66, 0x6f, 0x6f, 0x2c, 0x62, 0x61, 0x72}
 
-	stmtID, _, err := sConn.parseComStmtExecute(prepareDataMap, data[4:]) // first 4 are header
+	stmtID, _, _, err := sConn.parseComStmtExecute(prepareDataMap, data[4:]) // first 4 are header
 	require.NoError(t, err)
 	require.EqualValues(t, 1, stmtID)
 
@@ -328,6 +328,148 @@ func TestComStmtExecuteUpdStmt(t *testing.T) {
 	assert.EqualValues(t, querypb.Type_CHAR, prepData.ParamsType[28], "got: %s", querypb.Type(prepData.ParamsType[28]))
 }
 
+func TestComQueryAttributes(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+	cConn.Capabilities |= CapabilityClientQueryAttributes
+	sConn.Capabilities |= CapabilityClientQueryAttributes
+
+	for _, attributes := range []map[string]string{
+		nil,
+		{"request_id": "42"},
+		{"request_id": "42", "tenant": "acme", "empty": ""},
+	} {
+		cConn.SetQueryAttributes(attributes)
+		require.NoError(t, cConn.WriteComQuery("select 1"))
+		sConn.resetSequence()
+		data, err := sConn.ReadPacket()
+		require.NoError(t, err)
+
+		query, parsed, err := sConn.parseComQuery(data)
+		require.NoError(t, err)
+		assert.Equal(t, "select 1", query)
+		assert.Equal(t, attributes, parsed)
+	}
+
+	// the attributes are not sent without CapabilityClientQueryAttributes
+	cConn.Capabilities &^= CapabilityClientQueryAttributes
+	sConn.Capabilities &^= CapabilityClientQueryAttributes
+	cConn.SetQueryAttributes(map[string]string{"request_id": "42"})
+	require.NoError(t, cConn.WriteComQuery("select 1"))
+	sConn.resetSequence()
+	data, err := sConn.ReadPacket()
+	require.NoError(t, err)
+	query, parsed, err := sConn.parseComQuery(data)
+	require.NoError(t, err)
+	assert.Equal(t, "select 1", query)
+	assert.Nil(t, parsed)
+
+	// a count of attributes that the packet can't hold is rejected before anything is allocated
+	sConn.Capabilities |= CapabilityClientQueryAttributes
+	for _, data := range [][]byte{
+		{ComQuery, 0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 1, 's', 'e', 'l', 'e', 'c', 't', ' ', '1'},
+		{ComQuery, 0xfc, 0x00, 0x01, 1, 0, 1},
+	} {
+		_, _, err = sConn.parseComQuery(data)
+		require.Error(t, err)
+		assert.Equal(t, sqlerror.CRMalformedPacket, sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError).Number())
+	}
+}
+
+func TestComStmtExecuteQueryAttributes(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+	sConn.Capabilities |= CapabilityClientQueryAttributes
+
+	prepareDataMap := map[uint32]*PrepareData{
+		1: {
+			StatementID: 1,
+			ParamsCount: 1,
+			ParamsType:  make([]int32, 1),
+			BindVars:    map[string]*querypb.BindVariable{},
+		},
+		2: {
+			StatementID: 2,
+			BindVars:    map[string]*querypb.BindVariable{},
+		},
+	}
+
+	// `select * from t where id = ?` with 1, along with a NULL `request_id` and `tenant` = 'acme'
+	data := []byte{
+		ComStmtExecute, 1, 0, 0, 0, CursorTypeNoCursor, 1, 0, 0, 0,
+		3,             // parameter count
+		0b00000010,    // NULL-bitmap
+		1,             // new params bind flag
+		0x08, 0x00, 0, // BIGINT with no name
+		0xfd, 0x00, 10, 'r', 'e', 'q', 'u', 'e', 's', 't', '_', 'i', 'd',
+		0xfd, 0x00, 6, 't', 'e', 'n', 'a', 'n', 't',
+		1, 0, 0, 0, 0, 0, 0, 0,
+		4, 'a', 'c', 'm', 'e',
+	}
+	stmtID, _, attributes, err := sConn.parseComStmtExecute(prepareDataMap, data)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, stmtID)
+	assert.Equal(t, map[string]string{"tenant": "acme"}, attributes)
+	assert.Equal(t, sqltypes.Int64BindVariable(1), prepareDataMap[1].BindVars["v1"])
+
+	// the types and names are only sent with new parameter types
+	prepareDataMap[1].BindVars = map[string]*querypb.BindVariable{}
+	data = []byte{
+		ComStmtExecute, 1, 0, 0, 0, CursorTypeNoCursor, 1, 0, 0, 0,
+		3,          // parameter count
+		0b00000000, // NULL-bitmap
+		0,          // new params bind flag
+		2, 0, 0, 0, 0, 0, 0, 0,
+		2, '4', '2',
+		4, 'a', 'c', 'm', 'e',
+	}
+	_, _, attributes, err = sConn.parseComStmtExecute(prepareDataMap, data)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"request_id": "42", "tenant": "acme"}, attributes)
+	assert.Equal(t, sqltypes.Int64BindVariable(2), prepareDataMap[1].BindVars["v1"])
+
+	// statements without parameters send their parameter count with ParameterCountAvailable
+	data = []byte{
+		ComStmtExecute, 2, 0, 0, 0, ParameterCountAvailable, 1, 0, 0, 0,
+		1,          // parameter count
+		0b00000000, // NULL-bitmap
+		1,          // new params bind flag
+		0xfd, 0x00, 6, 't', 'e', 'n', 'a', 'n', 't',
+		4, 'a', 'c', 'm', 'e',
+	}
+	_, cursorType, attributes, err := sConn.parseComStmtExecute(prepareDataMap, data)
+	require.NoError(t, err)
+	assert.Equal(t, CursorTypeNoCursor, cursorType&CursorTypeReadOnly)
+	assert.Equal(t, map[string]string{"tenant": "acme"}, attributes)
+
+	// the parameter count includes the parameters of the statement
+	data = []byte{
+		ComStmtExecute, 1, 0, 0, 0, ParameterCountAvailable, 1, 0, 0, 0,
+		0, // parameter count
+	}
+	_, _, _, err = sConn.parseComStmtExecute(prepareDataMap, data)
+	assert.ErrorContains(t, err, "parameter count 0 is less than the 1 parameters of the statement")
+
+	// and can't be more than the packet can hold
+	data = []byte{
+		ComStmtExecute, 1, 0, 0, 0, ParameterCountAvailable, 1, 0, 0, 0,
+		0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, // parameter count
+		0b00000000, // NULL-bitmap
+		1,          // new params bind flag
+	}
+	_, _, _, err = sConn.parseComStmtExecute(prepareDataMap, data)
+	require.Error(t, err)
+	assert.Equal(t, sqlerror.CRMalformedPacket, sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError).Number())
+}
+
 func TestComStmtClose(t *testing.T) {
 	listener, sConn, cConn := createSocketPair(t)
 	defer func() {
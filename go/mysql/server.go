@@ -133,6 +133,11 @@ type Handler interface {
 
 	ComResetConnection(c *Conn)
 
+	// ComChangeUser is called when a connection was authenticated as
+	// another user with COM_CHANGE_USER, which c.User and c.UserData
+	// are now set to. The state of the session has to be reset.
+	ComChangeUser(c *Conn)
+
 	Env() *vtenv.Environment
 }
 
@@ -146,6 +151,7 @@ func (UnimplementedHandler) NewConnection(*Conn)      {}
 func (UnimplementedHandler) ConnectionReady(*Conn)    {}
 func (UnimplementedHandler) ConnectionClosed(*Conn)   {}
 func (UnimplementedHandler) ComResetConnection(*Conn) {}
+func (UnimplementedHandler) ComChangeUser(*Conn)      {}
 
 // Listener is the MySQL server protocol listener.
 type Listener struct {
@@ -178,10 +184,26 @@ type Listener struct {
 	// by the server when TLS is not in use.
 	AllowClearTextWithoutTLS atomic.Bool
 
+	// EnableCompression and EnableZstdCompression make the server
+	// advertise that it supports the zlib and zstd compressed protocol.
+	// A client still has to ask for compression in its handshake.
+	EnableCompression     atomic.Bool
+	EnableZstdCompression atomic.Bool
+
+	// EnableLocalInfile makes the server advertise that it can read files
+	// from the client for LOAD DATA LOCAL INFILE, like the local_infile
+	// system variable of MySQL. A client still has to allow it in its handshake.
+	EnableLocalInfile atomic.Bool
+
 	// SlowConnectWarnThreshold if non-zero specifies an amount of time
 	// beyond which a warning is logged to identify the slow connection
 	SlowConnectWarnThreshold atomic.Int64
 
+	// MaxMaterializedCursorRows is the number of rows a cursor can read in memory
+	// when another command is run before all of its rows were fetched. The cursor
+	// is closed instead if more rows are left, and the next fetch returns an error.
+	MaxMaterializedCursorRows atomic.Int64
+
 	// The following parameters are changed by the Accept routine.
 
 	// Incrementing ID for connection id.
@@ -302,7 +324,7 @@ func NewListenerWithConfig(cfg ListenerConfig) (*Listener, error) {
 		l = listener
 	}
 
-	return &Listener{
+	listener := &Listener{
 		authServer:          cfg.AuthServer,
 		handler:             cfg.Handler,
 		listener:            l,
@@ -316,7 +338,9 @@ func NewListenerWithConfig(cfg ListenerConfig) (*Listener, error) {
 		flushDelay:          cfg.FlushDelay,
 		truncateErrLen:      cfg.Handler.Env().TruncateErrLen(),
 		charset:             cfg.Handler.Env().CollationEnv().DefaultConnectionCharset(),
-	}, nil
+	}
+	listener.MaxMaterializedCursorRows.Store(DefaultMaxMaterializedCursorRows)
+	return listener, nil
 }
 
 // Addr returns the listener address.
@@ -386,12 +410,14 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 	// Tell the handler about the connection coming and going.
 	l.handler.NewConnection(c)
 	defer l.handler.ConnectionClosed(c)
+	// The handlers of the cursors return before the handler is told the connection is closed.
+	defer c.closeCursors()
 
 	// Adjust the count of open connections
 	defer connCount.Add(-1)
 
 	// First build and send the server handshake packet.
-	serverAuthPluginData, err := c.writeHandshakeV10(l.ServerVersion, l.authServer, uint8(l.charset), l.TLSConfig.Load() != nil)
+	serverAuthPluginData, err := c.writeHandshakeV10(l.ServerVersion, l.authServer, uint8(l.charset), l.TLSConfig.Load() != nil, l.EnableLocalInfile.Load(), l.compressionCapabilities())
 	if err != nil {
 		if err != io.EOF {
 			log.Errorf("Cannot send HandshakeV10 packet to %s: %v", c, err)
@@ -450,6 +476,76 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 		defer connCountByTLSVer.Add(versionNoTLS, -1)
 	}
 
+	userData, serverAuthPluginData, err := l.authenticate(c, user, clientAuthMethod, clientAuthResponse, serverAuthPluginData)
+	if err != nil {
+		c.writeErrorPacketFromError(err)
+		return
+	}
+
+	c.User = user
+	c.UserData = userData
+	c.salt = serverAuthPluginData
+
+	if c.User != "" {
+		connCountPerUser.Add(c.User, 1)
+	}
+	// The user may be changed by COM_CHANGE_USER.
+	defer func() {
+		if c.User != "" {
+			connCountPerUser.Add(c.User, -1)
+		}
+	}()
+
+	// Set initial db name.
+	if c.schemaName != "" {
+		err = l.handler.ComQuery(c, "use "+sqlescape.EscapeID(c.schemaName), func(result *sqltypes.Result) error {
+			return nil
+		})
+		if err != nil {
+			c.writeErrorPacketFromError(err)
+			return
+		}
+	}
+
+	// Negotiation worked, send OK packet.
+	if err := c.writeOKPacket(&PacketOK{statusFlags: c.StatusFlags}); err != nil {
+		log.Errorf("Cannot write OK packet to %s: %v", c, err)
+		return
+	}
+
+	// Everything after the OK packet is compressed, if the client asked for it.
+	if err := c.enableCompression(c.zstdCompressionLevel); err != nil {
+		log.Errorf("Cannot enable compression for %s: %v", c, err)
+		return
+	}
+
+	// Record how long we took to establish the connection
+	timings.Record(connectTimingKey, acceptTime)
+
+	// Log a warning if it took too long to connect
+	connectTime := time.Since(acceptTime).Nanoseconds()
+	if threshold := l.SlowConnectWarnThreshold.Load(); threshold != 0 && connectTime > threshold {
+		connSlow.Add(1)
+		log.Warningf("Slow connection from %s: %v", c, connectTime)
+	}
+
+	// Tell our handler that we're finished handshake and are ready to
+	// process commands.
+	l.handler.ConnectionReady(c)
+
+	for {
+		kontinue := c.handleNextCommand(l.handler)
+		// before going for next command check if the connection should be closed or not.
+		if !kontinue || c.IsMarkedForClose() {
+			return
+		}
+	}
+}
+
+// authenticate negotiates the auth method with the client, and authenticates the user with it.
+// It is used by the handshake and by COM_CHANGE_USER, and returns the user data along with
+// the auth plugin data that was sent to the client last. The errors are meant for the client.
+func (l *Listener) authenticate(c *Conn, user string, clientAuthMethod AuthMethodDescription, clientAuthResponse, serverAuthPluginData []byte) (Getter, []byte, error) {
 	// See what auth method the AuthServer wants to use for that user.
 	negotiatedAuthMethod, err := negotiateAuthMethod(c, l.authServer, user, clientAuthMethod)
 
@@ -474,87 +570,50 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 		}
 
 		if negotiatedAuthMethod == nil {
-			c.writeErrorPacket(sqlerror.CRServerHandshakeErr, sqlerror.SSUnknownSQLState, "No authentication methods available for authentication.")
-			return
+			return nil, nil, sqlerror.NewSQLError(sqlerror.CRServerHandshakeErr, sqlerror.SSUnknownSQLState, "No authentication methods available for authentication.")
 		}
 
 		if !l.AllowClearTextWithoutTLS.Load() && !c.TLSEnabled() && !negotiatedAuthMethod.AllowClearTextWithoutTLS() {
-			c.writeErrorPacket(sqlerror.CRServerHandshakeErr, sqlerror.SSUnknownSQLState, "Cannot use clear text authentication over non-SSL connections.")
-			return
+			return nil, nil, sqlerror.NewSQLError(sqlerror.CRServerHandshakeErr, sqlerror.SSUnknownSQLState, "Cannot use clear text authentication over non-SSL connections.")
 		}
 
 		serverAuthPluginData, err = negotiatedAuthMethod.AuthPluginData()
 		if err != nil {
 			log.Errorf("Error generating auth switch packet for %s: %v", c, err)
-			return
+			return nil, nil, err
 		}
 
 		if err := c.writeAuthSwitchRequest(string(negotiatedAuthMethod.Name()), serverAuthPluginData); err != nil {
 			log.Errorf("Error writing auth switch packet for %s: %v", c, err)
-			return
+			return nil, nil, err
 		}
 
 		clientAuthResponse, err = c.readEphemeralPacket()
 		if err != nil {
 			log.Errorf("Error reading auth switch response for %s: %v", c, err)
-			return
+			return nil, nil, err
 		}
 		c.recycleReadPacket()
 	}
 
-	userData, err := negotiatedAuthMethod.HandleAuthPluginData(c, user, serverAuthPluginData, clientAuthResponse, conn.RemoteAddr())
+	userData, err := negotiatedAuthMethod.HandleAuthPluginData(c, user, serverAuthPluginData, clientAuthResponse, c.RemoteAddr())
 	if err != nil {
 		log.Warningf("Error authenticating user %s using: %s", user, negotiatedAuthMethod.Name())
-		c.writeErrorPacketFromError(err)
-		return
-	}
-
-	c.User = user
-	c.UserData = userData
-
-	if c.User != "" {
-		connCountPerUser.Add(c.User, 1)
-		defer connCountPerUser.Add(c.User, -1)
-	}
-
-	// Set initial db name.
-	if c.schemaName != "" {
-		err = l.handler.ComQuery(c, "use "+sqlescape.EscapeID(c.schemaName), func(result *sqltypes.Result) error {
-			return nil
-		})
-		if err != nil {
-			c.writeErrorPacketFromError(err)
-			return
-		}
+		return nil, nil, err
 	}
+	return userData, serverAuthPluginData, nil
+}
 
-	// Negotiation worked, send OK packet.
-	if err := c.writeOKPacket(&PacketOK{statusFlags: c.StatusFlags}); err != nil {
-		log.Errorf("Cannot write OK packet to %s: %v", c, err)
-		return
+// compressionCapabilities returns the compression capabilities the server advertises.
+func (l *Listener) compressionCapabilities() uint32 {
+	var capabilities uint32
+	if l.EnableCompression.Load() {
+		capabilities |= CapabilityClientCompress
 	}
-
-	// Record how long we took to establish the connection
-	timings.Record(connectTimingKey, acceptTime)
-
-	// Log a warning if it took too long to connect
-	connectTime := time.Since(acceptTime).Nanoseconds()
-	if threshold := l.SlowConnectWarnThreshold.Load(); threshold != 0 && connectTime > threshold {
-		connSlow.Add(1)
-		log.Warningf("Slow connection from %s: %v", c, connectTime)
-	}
-
-	// Tell our handler that we're finished handshake and are ready to
-	// process commands.
-	l.handler.ConnectionReady(c)
-
-	for {
-		kontinue := c.handleNextCommand(l.handler)
-		// before going for next command check if the connection should be closed or not.
-		if !kontinue || c.IsMarkedForClose() {
-			return
-		}
+	if l.EnableZstdCompression.Load() {
+		capabilities |= CapabilityClientZstdCompressionAlgorithm
 	}
+	return capabilities
 }
 
 // Close stops the listener, which prevents accept of any new connections. Existing connections won't be closed.
@@ -572,7 +631,7 @@ func (l *Listener) Shutdown() {
 
 // writeHandshakeV10 writes the Initial Handshake Packet, server side.
 // It returns the salt data.
-func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, charset uint8, enableTLS bool) ([]byte, error) {
+func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, charset uint8, enableTLS, enableLocalInfile bool, compression uint32) ([]byte, error) {
 	capabilities := CapabilityClientLongPassword |
 		CapabilityClientFoundRows |
 		CapabilityClientLongFlag |
@@ -585,10 +644,15 @@ func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, ch
 		CapabilityClientPluginAuth |
 		CapabilityClientPluginAuthLenencClientData |
 		CapabilityClientDeprecateEOF |
-		CapabilityClientConnAttr
+		CapabilityClientConnAttr |
+		CapabilityClientQueryAttributes
 	if enableTLS {
 		capabilities |= CapabilityClientSSL
 	}
+	if enableLocalInfile {
+		capabilities |= CapabilityClientLocalFiles
+	}
+	capabilities |= int(compression)
 
 	// Grab the default auth method. This can only be either
 	// mysql_native_password or caching_sha2_password. Both
@@ -714,6 +778,22 @@ func (l *Listener) parseClientHandshakePacket(c *Conn, firstTime bool, data []by
 		c.Capabilities |= CapabilityClientMultiStatements
 	}
 
+	// set connection capability for sending files for LOAD DATA LOCAL INFILE, if we advertised it
+	if clientFlags&CapabilityClientLocalFiles > 0 && l.EnableLocalInfile.Load() {
+		c.Capabilities |= CapabilityClientLocalFiles
+	}
+
+	// set connection capability for query attributes in COM_QUERY and COM_STMT_EXECUTE
+	if clientFlags&CapabilityClientQueryAttributes > 0 {
+		c.Capabilities |= CapabilityClientQueryAttributes
+	}
+
+	// set connection capabilities for the compressed protocol, if we advertised them
+	c.Capabilities |= clientFlags & l.compressionCapabilities()
+
+	// set connection capabilities needed to parse COM_CHANGE_USER
+	c.Capabilities |= clientFlags & (CapabilityClientSecureConnection | CapabilityClientPluginAuth | CapabilityClientConnAttr)
+
 	// Max packet size. Don't do anything with this now.
 	// See doc.go for more information.
 	_, pos, ok = readUint32(data, pos)
@@ -806,14 +886,88 @@ func (l *Listener) parseClientHandshakePacket(c *Conn, firstTime bool, data []by
 
 	// Decode connection attributes send by the client
 	if clientFlags&CapabilityClientConnAttr != 0 {
-		if _, _, err := parseConnAttrs(data, pos); err != nil {
+		var err error
+		if _, pos, err = parseConnAttrs(data, pos); err != nil {
 			log.Warningf("Decode connection attributes send by the client: %v", err)
+			// we don't know where the attributes end, so nothing after them can be read
+			pos = len(data)
+		}
+	}
+
+	// zstd compression level, which comes last
+	if clientFlags&CapabilityClientZstdCompressionAlgorithm != 0 {
+		if level, _, ok := readByte(data, pos); ok {
+			c.zstdCompressionLevel = int(level)
 		}
 	}
 
 	return username, AuthMethodDescription(authMethod), authResponse, nil
 }
 
+// parseComChangeUser parses a COM_CHANGE_USER packet, with the capabilities of the
+// handshake. It returns the user, schema and character set to change to, along with
+// the auth method and auth response of the client.
+func (c *Conn) parseComChangeUser(data []byte) (string, string, collations.ID, AuthMethodDescription, []byte, error) {
+	// Skip the command byte.
+	pos := 1
+
+	username, pos, ok := readNullString(data, pos)
+	if !ok {
+		return "", "", 0, "", nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseComChangeUser: can't read username")
+	}
+
+	var authResponse []byte
+	if c.Capabilities&CapabilityClientSecureConnection != 0 {
+		var l byte
+		l, pos, ok = readByte(data, pos)
+		if !ok {
+			return "", "", 0, "", nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseComChangeUser: can't read auth-response length")
+		}
+		authResponse, pos, ok = readBytesCopy(data, pos, int(l))
+		if !ok {
+			return "", "", 0, "", nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseComChangeUser: can't read auth-response")
+		}
+	} else {
+		a := ""
+		a, pos, ok = readNullString(data, pos)
+		if !ok {
+			return "", "", 0, "", nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseComChangeUser: can't read auth-response")
+		}
+		authResponse = []byte(a)
+	}
+
+	dbname, pos, ok := readNullString(data, pos)
+	if !ok {
+		return "", "", 0, "", nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseComChangeUser: can't read dbname")
+	}
+
+	// The character set and the auth method are optional.
+	characterSet := c.CharacterSet
+	if pos < len(data) {
+		var cs uint16
+		cs, pos, ok = readUint16(data, pos)
+		if !ok {
+			return "", "", 0, "", nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseComChangeUser: can't read characterSet")
+		}
+		characterSet = collations.ID(cs)
+	}
+
+	authMethod := MysqlNativePassword
+	if pos < len(data) && c.Capabilities&CapabilityClientPluginAuth != 0 {
+		var authMethodStr string
+		authMethodStr, _, ok = readNullString(data, pos)
+		if !ok {
+			return "", "", 0, "", nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseComChangeUser: can't read authMethod")
+		}
+		if authMethodStr != "" {
+			authMethod = AuthMethodDescription(authMethodStr)
+		}
+	}
+
+	// The connection attributes, which come last, are ignored.
+	return username, dbname, characterSet, authMethod, authResponse, nil
+}
+
 func parseConnAttrs(data []byte, pos int) (map[string]string, int, error) {
 	var attrLen uint64
 
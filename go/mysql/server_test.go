@@ -1538,3 +1538,92 @@ func TestTcpKeepAlive(t *testing.T) {
 	err = setTcpConnProperties(th.lastConn.conn.(*net.TCPConn), 0)
 	require.ErrorContains(t, err, "unable to enable keepalive on tcp connection")
 }
+
+func writeComChangeUser(t *testing.T, c *Conn, user string, authResponse []byte, dbname string) {
+	t.Helper()
+	packet := []byte{0, 0, 0, 0, ComChangeUser}
+	packet = append(packet, user...)
+	packet = append(packet, 0, byte(len(authResponse)))
+	packet = append(packet, authResponse...)
+	packet = append(packet, dbname...)
+	packet = append(packet, 0, byte(c.CharacterSet), byte(c.CharacterSet>>8))
+	packet = append(packet, MysqlNativePassword...)
+	packet = append(packet, 0)
+
+	c.resetSequence()
+	require.NoError(t, c.writePacket(packet))
+}
+
+func TestComChangeUser(t *testing.T) {
+	th := &testHandler{}
+
+	authServer := NewAuthServerStatic("", "", 0)
+	authServer.entries["user1"] = []*AuthServerStaticEntry{{
+		Password: "password1",
+		UserData: "userData1",
+	}}
+	authServer.entries["user2"] = []*AuthServerStaticEntry{{
+		Password: "password2",
+		UserData: "userData2",
+	}}
+	defer authServer.close()
+	l, err := NewListener("tcp", "127.0.0.1:", authServer, th, 0, 0, false, false, 0, 0)
+	require.NoError(t, err)
+	defer l.Close()
+	go l.Accept()
+
+	host, port := getHostPort(t, l.Addr())
+	params := &ConnParams{
+		Host:  host,
+		Port:  port,
+		Uname: "user1",
+		Pass:  "password1",
+	}
+	c, err := Connect(context.Background(), params)
+	require.NoError(t, err)
+	defer c.Close()
+
+	echo := func(query string) []sqltypes.Value {
+		t.Helper()
+		result, err := c.ExecuteFetch(query, 10, false)
+		require.NoError(t, err)
+		require.Len(t, result.Rows, 1)
+		return result.Rows[0]
+	}
+	user1Count := connCountPerUser.Counts()["user1"]
+	user2Count := connCountPerUser.Counts()["user2"]
+
+	// the auth response is scrambled with the salt of the handshake
+	writeComChangeUser(t, c, "user2", ScrambleMysqlNativePassword(c.salt, []byte("password2")), "db2")
+	data, err := c.ReadPacket()
+	require.NoError(t, err)
+	require.Equal(t, byte(OKPacket), data[0])
+	assert.Equal(t, "[VARCHAR(\"user2\") VARCHAR(\"userData2\")]", fmt.Sprint(echo("userData echo")))
+	assert.Equal(t, "[VARCHAR(\"db2\")]", fmt.Sprint(echo("schema echo")))
+	assert.Equal(t, user1Count-1, connCountPerUser.Counts()["user1"])
+	assert.Equal(t, user2Count+1, connCountPerUser.Counts()["user2"])
+
+	// without an auth response, the server asks for it with a new salt
+	writeComChangeUser(t, c, "user1", nil, "")
+	data, err = c.ReadPacket()
+	require.NoError(t, err)
+	pluginName, salt, err := parseAuthSwitchRequest(data)
+	require.NoError(t, err)
+	assert.Equal(t, MysqlNativePassword, pluginName)
+	require.NoError(t, c.writePacket(append(make([]byte, packetHeaderSize), ScrambleMysqlNativePassword(salt, []byte("password1"))...)))
+	data, err = c.ReadPacket()
+	require.NoError(t, err)
+	require.Equal(t, byte(OKPacket), data[0])
+	assert.Equal(t, "[VARCHAR(\"user1\") VARCHAR(\"userData1\")]", fmt.Sprint(echo("userData echo")))
+	assert.Equal(t, "[VARCHAR(\"\")]", fmt.Sprint(echo("schema echo")))
+
+	// a wrong password fails, and the connection is closed rather than kept as the previous user
+	writeComChangeUser(t, c, "user2", ScrambleMysqlNativePassword(c.salt, []byte("password1")), "")
+	data, err = c.ReadPacket()
+	require.NoError(t, err)
+	require.True(t, isErrorPacket(data))
+	assert.Equal(t, sqlerror.ERAccessDeniedError, ParseErrorPacket(data).(*sqlerror.SQLError).Number())
+	_, err = c.ExecuteFetch("userData echo", 10, false)
+	require.Error(t, err)
+	assert.Equal(t, sqlerror.CRServerLost, err.(*sqlerror.SQLError).Number())
+}
@@ -121,6 +121,7 @@ const (
 	ERSPDoesNotExist                = ErrorCode(1305)
 	ERNoDefaultForField             = ErrorCode(1364)
 	ErSPNotVarArg                   = ErrorCode(1414)
+	ERStmtHasNoOpenCursor           = ErrorCode(1421)
 	ERRowIsReferenced2              = ErrorCode(1451)
 	ErNoReferencedRow2              = ErrorCode(1452)
 	ERDupIndex                      = ErrorCode(1831)
@@ -285,6 +286,7 @@ const (
 	ERCharacterSetMismatch = ErrorCode(3995)
 
 	ERWrongParametersToNativeFct = ErrorCode(1583)
+	ERAESInvalidIV               = ErrorCode(1882)
 
 	// max execution time exceeded
 	ERQueryTimeout = ErrorCode(3024)
@@ -246,6 +246,7 @@ var stateToMysqlCode = map[vterrors.State]mysqlCode{
 	vterrors.RegexpInvalidCaptureGroup:           {num: ERRegexpInvalidCaptureGroup, state: SSUnknownSQLState},
 	vterrors.CharacterSetMismatch:                {num: ERCharacterSetMismatch, state: SSUnknownSQLState},
 	vterrors.WrongParametersToNativeFct:          {num: ERWrongParametersToNativeFct, state: SSUnknownSQLState},
+	vterrors.AESInvalidIV:                        {num: ERAESInvalidIV, state: SSUnknownSQLState},
 	vterrors.KillDeniedError:                     {num: ERKillDenied, state: SSUnknownSQLState},
 	vterrors.BadNullError:                        {num: ERBadNullError, state: SSConstraintViolation},
 	vterrors.InvalidGroupFuncUse:                 {num: ERInvalidGroupFuncUse, state: SSUnknownSQLState},
@@ -89,6 +89,9 @@ type DBConfigs struct {
 	ConnectTimeoutMilliseconds int           `json:"connectTimeoutMilliseconds,omitempty"`
 	DBName                     string        `json:"dbName,omitempty"`
 	EnableQueryInfo            bool          `json:"enableQueryInfo,omitempty"`
+	Compression                string        `json:"compression,omitempty"`
+	ZstdCompressionLevel       int           `json:"zstdCompressionLevel,omitempty"`
+	EnableQueryAttributes      bool          `json:"enableQueryAttributes,omitempty"`
 
 	App          UserConfig `json:"app,omitempty"`
 	Dba          UserConfig `json:"dba,omitempty"`
@@ -145,6 +148,9 @@ func registerBaseFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&GlobalDBConfigs.ServerName, "db_server_name", "", "server name of the DB we are connecting to.")
 	fs.IntVar(&GlobalDBConfigs.ConnectTimeoutMilliseconds, "db_connect_timeout_ms", 0, "connection timeout to mysqld in milliseconds (0 for no timeout)")
 	fs.BoolVar(&GlobalDBConfigs.EnableQueryInfo, "db_conn_query_info", false, "enable parsing and processing of QUERY_OK info fields")
+	fs.StringVar(&GlobalDBConfigs.Compression, "db_compression", "", "Compression of the MySQL protocol to ask mysqld for: zlib or zstd. Connections are not compressed if it is empty, or if mysqld doesn't support it.")
+	fs.IntVar(&GlobalDBConfigs.ZstdCompressionLevel, "db_zstd_compression_level", mysql.DefaultZstdCompressionLevel, "zstd compression level of the MySQL protocol, from 1 to 22, when --db_compression is zstd.")
+	fs.BoolVar(&GlobalDBConfigs.EnableQueryAttributes, "db_query_attributes", false, "Send the query attributes of the client queries to mysqld along with the queries, if mysqld supports them.")
 }
 
 // The flags will change the global singleton
@@ -355,6 +361,11 @@ func (dbcfgs *DBConfigs) InitWithSocket(defaultSocketFile string, collationEnv *
 		}
 		cp.ConnectTimeoutMs = uint64(dbcfgs.ConnectTimeoutMilliseconds)
 		cp.EnableQueryInfo = dbcfgs.EnableQueryInfo
+		cp.Compression = dbcfgs.Compression
+		cp.ZstdCompressionLevel = dbcfgs.ZstdCompressionLevel
+		if dbcfgs.EnableQueryAttributes {
+			cp.EnableQueryAttributes()
+		}
 
 		cp.Uname = uc.User
 		cp.Pass = uc.Password
@@ -435,3 +435,45 @@ func GenerateShardRanges(shards int) ([]string, error) {
 
 	return shardRanges, nil
 }
+
+// GenerateWeightedShardKeyRanges returns the key ranges of shards whose sizes are
+// proportional to the given weights, in order. With a vindex that spreads the keyspace
+// ids uniformly, such as hash or xxhash, each shard then gets its weight's share of the
+// rows. The bounds are aligned on the first two bytes of the keyspace ids, so the
+// weights must add up to 65536 at most.
+func GenerateWeightedShardKeyRanges(weights []uint) ([]*topodatapb.KeyRange, error) {
+	const units = 1 << 16
+
+	if len(weights) == 0 {
+		return nil, errors.New("the shard weights are empty")
+	}
+	var total uint64
+	for _, weight := range weights {
+		if weight == 0 {
+			return nil, errors.New("the shard weights must be greater than zero")
+		}
+		total += uint64(weight)
+		if total > units {
+			return nil, fmt.Errorf("the shard weights add up to more than %d", units)
+		}
+	}
+
+	// bound returns the key range bound of the given unit, without trailing zero bytes,
+	// as ParseKeyRangeParts does.
+	bound := func(unit uint64) []byte {
+		if unit == 0 || unit == units {
+			return nil
+		}
+		return bytes.TrimRight(binary.BigEndian.AppendUint16(nil, uint16(unit)), "\x00")
+	}
+
+	keyRanges := make([]*topodatapb.KeyRange, 0, len(weights))
+	var start, cumulative uint64
+	for _, weight := range weights {
+		cumulative += uint64(weight)
+		end := cumulative * units / total
+		keyRanges = append(keyRanges, &topodatapb.KeyRange{Start: bound(start), End: bound(end)})
+		start = end
+	}
+	return keyRanges, nil
+}
@@ -18,6 +18,7 @@ package key
 
 import (
 	"encoding/hex"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -1545,6 +1546,53 @@ func TestGenerateShardRanges(t *testing.T) {
 	}
 }
 
+func TestGenerateWeightedShardKeyRanges(t *testing.T) {
+	tests := []struct {
+		weights []uint
+		want    []string
+		wantErr string
+	}{{
+		weights: []uint{1},
+		want:    []string{"-"},
+	}, {
+		weights: []uint{1, 1},
+		want:    []string{"-80", "80-"},
+	}, {
+		weights: []uint{1, 1, 1},
+		want:    []string{"-5555", "5555-aaaa", "aaaa-"},
+	}, {
+		weights: []uint{1, 1, 2},
+		want:    []string{"-40", "40-80", "80-"},
+	}, {
+		weights: []uint{3, 1},
+		want:    []string{"-c0", "c0-"},
+	}, {
+		weights: nil,
+		wantErr: "the shard weights are empty",
+	}, {
+		weights: []uint{1, 0},
+		wantErr: "the shard weights must be greater than zero",
+	}, {
+		weights: []uint{65536, 1},
+		wantErr: "the shard weights add up to more than 65536",
+	}}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.weights), func(t *testing.T) {
+			keyRanges, err := GenerateWeightedShardKeyRanges(tt.weights)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			var got []string
+			for _, kr := range keyRanges {
+				got = append(got, KeyRangeString(kr))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestShardCalculatorForShardsGreaterThan512(t *testing.T) {
 	got, err := GenerateShardRanges(512)
 	assert.NoError(t, err)
@@ -569,8 +569,38 @@ type (
 	// DDLAction is an enum for DDL.Action
 	DDLAction int8
 
-	// Load represents a LOAD statement
+	// Load represents a LOAD DATA statement.
+	// LOAD DATA FROM S3 is not modelled and is parsed as an empty Load.
 	Load struct {
+		Local       bool
+		Infile      string
+		Action      InsertAction
+		Ignore      Ignore
+		Table       TableName
+		Partitions  Partitions
+		Charset     ColumnCharset
+		Fields      *LoadFields
+		Lines       *LoadLines
+		IgnoreLines int
+		// Columns holds the *ColName or user *Variable each input field is assigned to
+		Columns  Exprs
+		SetExprs UpdateExprs
+	}
+
+	// LoadFields represents the FIELDS clause of a LOAD DATA statement.
+	// Options that are not given are nil.
+	LoadFields struct {
+		TerminatedBy       *string
+		EnclosedBy         *string
+		OptionallyEnclosed bool
+		EscapedBy          *string
+	}
+
+	// LoadLines represents the LINES clause of a LOAD DATA statement.
+	// Options that are not given are nil.
+	LoadLines struct {
+		StartingBy   *string
+		TerminatedBy *string
 	}
 
 	// PurgeBinaryLogs represents a PURGE BINARY LOGS statement
@@ -307,6 +307,10 @@ func CloneSQLNode(in SQLNode) SQLNode {
 		return CloneRefOfLiteral(in)
 	case *Load:
 		return CloneRefOfLoad(in)
+	case *LoadFields:
+		return CloneRefOfLoadFields(in)
+	case *LoadLines:
+		return CloneRefOfLoadLines(in)
 	case *LocateExpr:
 		return CloneRefOfLocateExpr(in)
 	case *LockOption:
@@ -2091,6 +2095,36 @@ func CloneRefOfLoad(n *Load) *Load {
 		return nil
 	}
 	out := *n
+	out.Table = CloneTableName(n.Table)
+	out.Partitions = ClonePartitions(n.Partitions)
+	out.Charset = CloneColumnCharset(n.Charset)
+	out.Fields = CloneRefOfLoadFields(n.Fields)
+	out.Lines = CloneRefOfLoadLines(n.Lines)
+	out.Columns = CloneExprs(n.Columns)
+	out.SetExprs = CloneUpdateExprs(n.SetExprs)
+	return &out
+}
+
+// CloneRefOfLoadFields creates a deep clone of the input.
+func CloneRefOfLoadFields(n *LoadFields) *LoadFields {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.TerminatedBy = CloneRefOfString(n.TerminatedBy)
+	out.EnclosedBy = CloneRefOfString(n.EnclosedBy)
+	out.EscapedBy = CloneRefOfString(n.EscapedBy)
+	return &out
+}
+
+// CloneRefOfLoadLines creates a deep clone of the input.
+func CloneRefOfLoadLines(n *LoadLines) *LoadLines {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.StartingBy = CloneRefOfString(n.StartingBy)
+	out.TerminatedBy = CloneRefOfString(n.TerminatedBy)
 	return &out
 }
 
@@ -4572,6 +4606,15 @@ func CloneRefOfJtNestedPathColDef(n *JtNestedPathColDef) *JtNestedPathColDef {
 	return &out
 }
 
+// CloneRefOfString creates a deep clone of the input.
+func CloneRefOfString(n *string) *string {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	return &out
+}
+
 // CloneTableAndLockTypes creates a deep clone of the input.
 func CloneTableAndLockTypes(n TableAndLockTypes) TableAndLockTypes {
 	if n == nil {
@@ -878,6 +878,18 @@ func (cmp *Comparator) SQLNode(inA, inB SQLNode) bool {
 			return false
 		}
 		return cmp.RefOfLoad(a, b)
+	case *LoadFields:
+		b, ok := inB.(*LoadFields)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfLoadFields(a, b)
+	case *LoadLines:
+		b, ok := inB.(*LoadLines)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfLoadLines(a, b)
 	case *LocateExpr:
 		b, ok := inB.(*LocateExpr)
 		if !ok {
@@ -3426,7 +3438,44 @@ func (cmp *Comparator) RefOfLoad(a, b *Load) bool {
 	if a == nil || b == nil {
 		return false
 	}
-	return true
+	return a.Local == b.Local &&
+		a.Infile == b.Infile &&
+		a.IgnoreLines == b.IgnoreLines &&
+		a.Action == b.Action &&
+		a.Ignore == b.Ignore &&
+		cmp.TableName(a.Table, b.Table) &&
+		cmp.Partitions(a.Partitions, b.Partitions) &&
+		cmp.ColumnCharset(a.Charset, b.Charset) &&
+		cmp.RefOfLoadFields(a.Fields, b.Fields) &&
+		cmp.RefOfLoadLines(a.Lines, b.Lines) &&
+		cmp.Exprs(a.Columns, b.Columns) &&
+		cmp.UpdateExprs(a.SetExprs, b.SetExprs)
+}
+
+// RefOfLoadFields does deep equals between the two objects.
+func (cmp *Comparator) RefOfLoadFields(a, b *LoadFields) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.OptionallyEnclosed == b.OptionallyEnclosed &&
+		cmp.RefOfString(a.TerminatedBy, b.TerminatedBy) &&
+		cmp.RefOfString(a.EnclosedBy, b.EnclosedBy) &&
+		cmp.RefOfString(a.EscapedBy, b.EscapedBy)
+}
+
+// RefOfLoadLines does deep equals between the two objects.
+func (cmp *Comparator) RefOfLoadLines(a, b *LoadLines) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return cmp.RefOfString(a.StartingBy, b.StartingBy) &&
+		cmp.RefOfString(a.TerminatedBy, b.TerminatedBy)
 }
 
 // RefOfLocateExpr does deep equals between the two objects.
@@ -7482,6 +7531,17 @@ func (cmp *Comparator) RefOfJtNestedPathColDef(a, b *JtNestedPathColDef) bool {
 		cmp.SliceOfRefOfJtColumnDefinition(a.Columns, b.Columns)
 }
 
+// RefOfString does deep equals between the two objects.
+func (cmp *Comparator) RefOfString(a, b *string) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return *a == *b
+}
+
 // TableAndLockTypes does deep equals between the two objects.
 func (cmp *Comparator) TableAndLockTypes(a, b TableAndLockTypes) bool {
 	if len(a) != len(b) {
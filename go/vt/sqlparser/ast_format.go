@@ -2132,7 +2132,67 @@ func (node IdentifierCS) Format(buf *TrackedBuffer) {
 
 // Format formats the node.
 func (node *Load) Format(buf *TrackedBuffer) {
-	buf.literal("AST node missing for Load type")
+	if node.Table.IsEmpty() {
+		// LOAD DATA FROM S3 is passed through as is, it has nothing to format
+		buf.literal("AST node missing for Load type")
+		return
+	}
+	buf.literal("load data ")
+	if node.Local {
+		buf.literal("local ")
+	}
+	buf.astPrintf(node, "infile %s ", encodeSQLString(node.Infile))
+	if node.Action == ReplaceAct {
+		buf.literal("replace ")
+	}
+	buf.astPrintf(node, "%sinto table %v%v", node.Ignore.ToString(), node.Table, node.Partitions)
+	if node.Charset.Name != "" {
+		buf.astPrintf(node, " character set %#s", node.Charset.Name)
+	}
+	buf.astPrintf(node, "%v%v", node.Fields, node.Lines)
+	if node.IgnoreLines > 0 {
+		buf.astPrintf(node, " ignore %d lines", node.IgnoreLines)
+	}
+	if len(node.Columns) > 0 {
+		buf.astPrintf(node, " (%v)", node.Columns)
+	}
+	if len(node.SetExprs) > 0 {
+		buf.astPrintf(node, " set %v", node.SetExprs)
+	}
+}
+
+// Format formats the node.
+func (node *LoadFields) Format(buf *TrackedBuffer) {
+	if node == nil {
+		return
+	}
+	buf.literal(" fields")
+	if node.TerminatedBy != nil {
+		buf.astPrintf(node, " terminated by %s", encodeSQLString(*node.TerminatedBy))
+	}
+	if node.EnclosedBy != nil {
+		if node.OptionallyEnclosed {
+			buf.literal(" optionally")
+		}
+		buf.astPrintf(node, " enclosed by %s", encodeSQLString(*node.EnclosedBy))
+	}
+	if node.EscapedBy != nil {
+		buf.astPrintf(node, " escaped by %s", encodeSQLString(*node.EscapedBy))
+	}
+}
+
+// Format formats the node.
+func (node *LoadLines) Format(buf *TrackedBuffer) {
+	if node == nil {
+		return
+	}
+	buf.literal(" lines")
+	if node.StartingBy != nil {
+		buf.astPrintf(node, " starting by %s", encodeSQLString(*node.StartingBy))
+	}
+	if node.TerminatedBy != nil {
+		buf.astPrintf(node, " terminated by %s", encodeSQLString(*node.TerminatedBy))
+	}
 }
 
 // Format formats the node.
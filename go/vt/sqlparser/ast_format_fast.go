@@ -2810,7 +2810,84 @@ func (node IdentifierCS) FormatFast(buf *TrackedBuffer) {
 
 // FormatFast formats the node.
 func (node *Load) FormatFast(buf *TrackedBuffer) {
-	buf.WriteString("AST node missing for Load type")
+	if node.Table.IsEmpty() {
+		// LOAD DATA FROM S3 is passed through as is, it has nothing to format
+		buf.WriteString("AST node missing for Load type")
+		return
+	}
+	buf.WriteString("load data ")
+	if node.Local {
+		buf.WriteString("local ")
+	}
+	buf.WriteString("infile ")
+	buf.WriteString(encodeSQLString(node.Infile))
+	buf.WriteByte(' ')
+	if node.Action == ReplaceAct {
+		buf.WriteString("replace ")
+	}
+	buf.WriteString(node.Ignore.ToString())
+	buf.WriteString("into table ")
+	node.Table.FormatFast(buf)
+	node.Partitions.FormatFast(buf)
+	if node.Charset.Name != "" {
+		buf.WriteString(" character set ")
+		buf.WriteString(node.Charset.Name)
+	}
+	node.Fields.FormatFast(buf)
+	node.Lines.FormatFast(buf)
+	if node.IgnoreLines > 0 {
+		buf.WriteString(" ignore ")
+		buf.WriteString(fmt.Sprintf("%d", node.IgnoreLines))
+		buf.WriteString(" lines")
+	}
+	if len(node.Columns) > 0 {
+		buf.WriteString(" (")
+		node.Columns.FormatFast(buf)
+		buf.WriteByte(')')
+	}
+	if len(node.SetExprs) > 0 {
+		buf.WriteString(" set ")
+		node.SetExprs.FormatFast(buf)
+	}
+}
+
+// FormatFast formats the node.
+func (node *LoadFields) FormatFast(buf *TrackedBuffer) {
+	if node == nil {
+		return
+	}
+	buf.WriteString(" fields")
+	if node.TerminatedBy != nil {
+		buf.WriteString(" terminated by ")
+		buf.WriteString(encodeSQLString(*node.TerminatedBy))
+	}
+	if node.EnclosedBy != nil {
+		if node.OptionallyEnclosed {
+			buf.WriteString(" optionally")
+		}
+		buf.WriteString(" enclosed by ")
+		buf.WriteString(encodeSQLString(*node.EnclosedBy))
+	}
+	if node.EscapedBy != nil {
+		buf.WriteString(" escaped by ")
+		buf.WriteString(encodeSQLString(*node.EscapedBy))
+	}
+}
+
+// FormatFast formats the node.
+func (node *LoadLines) FormatFast(buf *TrackedBuffer) {
+	if node == nil {
+		return
+	}
+	buf.WriteString(" lines")
+	if node.StartingBy != nil {
+		buf.WriteString(" starting by ")
+		buf.WriteString(encodeSQLString(*node.StartingBy))
+	}
+	if node.TerminatedBy != nil {
+		buf.WriteString(" terminated by ")
+		buf.WriteString(encodeSQLString(*node.TerminatedBy))
+	}
 }
 
 // FormatFast formats the node.
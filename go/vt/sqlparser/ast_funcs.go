@@ -2203,6 +2203,10 @@ func ContainsAggregation(e SQLNode) bool {
 			// so we don't need to worry about aggregation in the original
 			return false, nil
 		case AggrFunc:
+			if IsWindowFunction(node) {
+				// windowed aggregations are evaluated after grouping, but their arguments can still aggregate
+				return true, nil
+			}
 			hasAggregates = true
 			return false, io.EOF
 		}
@@ -2212,6 +2216,83 @@ func ContainsAggregation(e SQLNode) bool {
 	return hasAggregates
 }
 
+// GetOverClause returns the OVER clause of a window function or windowed aggregation.
+// It returns nil for any other expression
+func GetOverClause(node SQLNode) *OverClause {
+	switch node := node.(type) {
+	case *ArgumentLessWindowExpr:
+		return node.OverClause
+	case *FirstOrLastValueExpr:
+		return node.OverClause
+	case *NtileExpr:
+		return node.OverClause
+	case *NTHValueExpr:
+		return node.OverClause
+	case *LagLeadExpr:
+		return node.OverClause
+	case *Count:
+		return node.OverClause
+	case *CountStar:
+		return node.OverClause
+	case *Avg:
+		return node.OverClause
+	case *Max:
+		return node.OverClause
+	case *Min:
+		return node.OverClause
+	case *Sum:
+		return node.OverClause
+	case *BitAnd:
+		return node.OverClause
+	case *BitOr:
+		return node.OverClause
+	case *BitXor:
+		return node.OverClause
+	case *Std:
+		return node.OverClause
+	case *StdDev:
+		return node.OverClause
+	case *StdPop:
+		return node.OverClause
+	case *StdSamp:
+		return node.OverClause
+	case *VarPop:
+		return node.OverClause
+	case *VarSamp:
+		return node.OverClause
+	case *Variance:
+		return node.OverClause
+	case *JSONArrayAgg:
+		return node.OverClause
+	case *JSONObjectAgg:
+		return node.OverClause
+	}
+	return nil
+}
+
+// IsWindowFunction returns true if the node is a function call with an OVER clause
+func IsWindowFunction(node SQLNode) bool {
+	return GetOverClause(node) != nil
+}
+
+// ContainsWindowFunction returns true if the expression contains a window function call.
+// Subqueries are not searched
+func ContainsWindowFunction(e SQLNode) bool {
+	hasWindow := false
+	_ = Walk(func(node SQLNode) (kontinue bool, err error) {
+		switch node.(type) {
+		case *Offset, *Subquery:
+			return false, nil
+		}
+		if IsWindowFunction(node) {
+			hasWindow = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, e)
+	return hasWindow
+}
+
 // setFuncArgs sets the arguments for the aggregation function, while checking that there is only one argument
 func setFuncArgs(aggr AggrFunc, exprs Exprs, name string) error {
 	if len(exprs) != 1 {
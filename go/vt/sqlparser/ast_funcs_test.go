@@ -219,3 +219,41 @@ func TestExtractTables(t *testing.T) {
 		})
 	}
 }
+
+// TestContainsWindowFunction verifies that windowed aggregations are told apart from plain aggregations.
+func TestContainsWindowFunction(t *testing.T) {
+	tcases := []struct {
+		expr        string
+		window      bool
+		aggregation bool
+	}{{
+		expr: "a + 1",
+	}, {
+		expr:   "row_number() over (partition by a order by b)",
+		window: true,
+	}, {
+		expr:   "lag(a, 2) over (order by b) + 1",
+		window: true,
+	}, {
+		expr:   "sum(a) over (partition by b)",
+		window: true,
+	}, {
+		expr:        "sum(a)",
+		aggregation: true,
+	}, {
+		expr:        "sum(count(a)) over (partition by b)",
+		window:      true,
+		aggregation: true,
+	}, {
+		expr: "(select row_number() over () from t)",
+	}}
+	parser := NewTestParser()
+	for _, tcase := range tcases {
+		t.Run(tcase.expr, func(t *testing.T) {
+			expr, err := parser.ParseExpr(tcase.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.window, ContainsWindowFunction(expr))
+			assert.Equal(t, tcase.aggregation, ContainsAggregation(expr))
+		})
+	}
+}
@@ -28,6 +28,10 @@ import (
 
 var HasValueSubQueryBaseName = []byte("__sq_has_values")
 
+// BatchSubQueryBaseName is the base name of the tuple bind variable used
+// to send the outer rows of a correlated subquery to the subquery all at once
+var BatchSubQueryBaseName = []byte("__sq_batch")
+
 // SQLSelectLimitUnset default value for sql_select_limit not set.
 const SQLSelectLimitUnset = -1
 
@@ -175,6 +179,8 @@ func (er *astRewriter) rewriteDown(node SQLNode, _ SQLNode) bool {
 		er.visitSelect(node)
 	case *PrepareStmt, *ExecuteStmt:
 		return false // nothing to rewrite here.
+	case *Load:
+		return false // the user variables of LOAD DATA stand for fields of the file, not for session variables.
 	}
 	return true
 }
@@ -400,6 +406,7 @@ func (er *astRewriter) sysVarRewrite(cursor *Cursor, node *Variable) {
 		sysvars.Version.Name,
 		sysvars.VersionComment.Name,
 		sysvars.QueryTimeout.Name,
+		sysvars.MaxExecutionTime.Name,
 		sysvars.Workload.Name:
 		found = true
 	}
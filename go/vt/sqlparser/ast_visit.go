@@ -306,6 +306,10 @@ func VisitSQLNode(in SQLNode, f Visit) error {
 		return VisitRefOfLiteral(in, f)
 	case *Load:
 		return VisitRefOfLoad(in, f)
+	case *LoadFields:
+		return VisitRefOfLoadFields(in, f)
+	case *LoadLines:
+		return VisitRefOfLoadLines(in, f)
 	case *LocateExpr:
 		return VisitRefOfLocateExpr(in, f)
 	case *LockOption:
@@ -2629,6 +2633,42 @@ func VisitRefOfLiteral(in *Literal, f Visit) error {
 	return nil
 }
 func VisitRefOfLoad(in *Load, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitTableName(in.Table, f); err != nil {
+		return err
+	}
+	if err := VisitPartitions(in.Partitions, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLoadFields(in.Fields, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLoadLines(in.Lines, f); err != nil {
+		return err
+	}
+	if err := VisitExprs(in.Columns, f); err != nil {
+		return err
+	}
+	if err := VisitUpdateExprs(in.SetExprs, f); err != nil {
+		return err
+	}
+	return nil
+}
+func VisitRefOfLoadFields(in *LoadFields, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	return nil
+}
+func VisitRefOfLoadLines(in *LoadLines, f Visit) error {
 	if in == nil {
 		return nil
 	}
@@ -65,6 +65,8 @@ const (
 
 	// OptimizerHintSetVar is the optimizer hint used in MySQL to set the value of a specific session variable for a query.
 	OptimizerHintSetVar = "SET_VAR"
+	// OptimizerHintMaxExecutionTime is the optimizer hint used in MySQL to limit the execution time of a SELECT, in milliseconds.
+	OptimizerHintMaxExecutionTime = "MAX_EXECUTION_TIME"
 )
 
 var ErrInvalidPriority = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Invalid priority value specified in query")
@@ -318,6 +320,43 @@ func (c *ParsedComments) GetMySQLSetVarValue(key string) string {
 	return ""
 }
 
+// GetMySQLMaxExecutionTime gets the value, in milliseconds, of a /*+ MAX_EXECUTION_TIME() */ MySQL optimizer hint.
+// It returns nil if the hint is not present, if its value is not a valid positive integer, or if it is 0:
+// like in MySQL, a zero hint means there is no hint-level limit, not that other limits are lifted.
+func (c *ParsedComments) GetMySQLMaxExecutionTime() *int {
+	if c == nil {
+		return nil
+	}
+	for _, commentStr := range c.comments {
+		// Skip all the comments that don't start with the query optimizer prefix.
+		if commentStr[0:3] != queryOptimizerPrefix {
+			continue
+		}
+
+		pos := 4
+		for pos < len(commentStr) {
+			finalPos, ohNameStart, ohNameEnd, ohContentStart, ohContentEnd := getOptimizerHint(pos, commentStr)
+			pos = finalPos + 1
+			// If we didn't find an optimizer hint or if it was malformed, we skip it.
+			if ohContentEnd == -1 {
+				break
+			}
+			if !strings.EqualFold(strings.TrimSpace(commentStr[ohNameStart:ohNameEnd]), OptimizerHintMaxExecutionTime) {
+				continue
+			}
+			timeout, err := strconv.Atoi(strings.TrimSpace(commentStr[ohContentStart:ohContentEnd]))
+			if err != nil || timeout <= 0 {
+				return nil
+			}
+			return &timeout
+		}
+
+		// MySQL only parses the first comment that has the optimizer hint prefix. The following ones are ignored.
+		return nil
+	}
+	return nil
+}
+
 // SetMySQLSetVarValue updates or sets the value of the given variable as part of a /*+ SET_VAR() */ MySQL optimizer hint.
 func (c *ParsedComments) SetMySQLSetVarValue(key string, value string) (newComments Comments) {
 	if c == nil {
@@ -569,6 +608,7 @@ type QueryHints struct {
 	ForeignKeyChecks    *bool
 	Priority            string
 	Timeout             *int
+	MaxExecutionTime    *int
 }
 
 func BuildQueryHints(stmt Statement) (qh QueryHints, err error) {
@@ -590,6 +630,7 @@ func BuildQueryHints(stmt Statement) (qh QueryHints, err error) {
 	qh.Workload = getWorkload(directives)
 	qh.ForeignKeyChecks = getForeignKeyChecksState(comment)
 	qh.Timeout = getQueryTimeout(directives)
+	qh.MaxExecutionTime = getMaxExecutionTime(stmt, comment)
 
 	return qh, nil
 }
@@ -662,3 +703,12 @@ func getQueryTimeout(directives *CommentDirectives) *int {
 	}
 	return &timeout
 }
+
+// getMaxExecutionTime returns the timeout from the MAX_EXECUTION_TIME optimizer hint.
+// Like MySQL, the hint is only honored for SELECT statements.
+func getMaxExecutionTime(stmt Statement, comment Commented) *int {
+	if _, isSelect := stmt.(SelectStatement); !isSelect {
+		return nil
+	}
+	return comment.GetParsedComments().GetMySQLMaxExecutionTime()
+}
@@ -682,6 +682,12 @@ func TestQueryTimeout(t *testing.T) {
 	}, {
 		query:     "select /*vt+ PRIORITY=-42 */ * from another_table",
 		noTimeout: true,
+	}, {
+		query:      "select /*+ MAX_EXECUTION_TIME(1500) */ /*vt+ QUERY_TIMEOUT_MS=21 */ * from a_table",
+		expTimeout: 21,
+	}, {
+		query:     "select /*+ MAX_EXECUTION_TIME(1500) */ * from a_table",
+		noTimeout: true,
 	}}
 
 	parser := NewTestParser()
@@ -698,3 +704,56 @@ func TestQueryTimeout(t *testing.T) {
 		})
 	}
 }
+
+// TestMaxExecutionTime tests the extraction of the MAX_EXECUTION_TIME optimizer hint from the comments.
+func TestMaxExecutionTime(t *testing.T) {
+	testCases := []struct {
+		query               string
+		expMaxExecutionTime int
+		noMaxExecutionTime  bool
+	}{{
+		query:              "select * from a_table",
+		noMaxExecutionTime: true,
+	}, {
+		query:               "select /*+ MAX_EXECUTION_TIME(1500) */ * from a_table",
+		expMaxExecutionTime: 1500,
+	}, {
+		query:               "select /*+ SET_VAR(sql_mode='') max_execution_time( 30 ) */ * from a_table",
+		expMaxExecutionTime: 30,
+	}, {
+		query:               "select /*+ MAX_EXECUTION_TIME(1500) */ /*vt+ QUERY_TIMEOUT_MS=21 */ * from a_table",
+		expMaxExecutionTime: 1500,
+	}, {
+		query:               "select /*+ MAX_EXECUTION_TIME(1500) */ * from a_table union select * from b_table",
+		expMaxExecutionTime: 1500,
+	}, {
+		query:              "select /*+ NO_RANGE_OPTIMIZER(t1) */ /*+ MAX_EXECUTION_TIME(1500) */ * from a_table",
+		noMaxExecutionTime: true,
+	}, {
+		query:              "select /*+ MAX_EXECUTION_TIME(0) */ * from a_table",
+		noMaxExecutionTime: true,
+	}, {
+		query:              "select /*+ MAX_EXECUTION_TIME(-5) */ * from a_table",
+		noMaxExecutionTime: true,
+	}, {
+		query:              "select /*+ MAX_EXECUTION_TIME(abc) */ * from a_table",
+		noMaxExecutionTime: true,
+	}, {
+		query:              "update /*+ MAX_EXECUTION_TIME(1500) */ a_table set a = 1",
+		noMaxExecutionTime: true,
+	}}
+
+	parser := NewTestParser()
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			stmt, err := parser.Parse(tc.query)
+			assert.NoError(t, err)
+			qh, _ := BuildQueryHints(stmt)
+			if tc.noMaxExecutionTime {
+				assert.Nil(t, qh.MaxExecutionTime)
+			} else {
+				assert.Equal(t, tc.expMaxExecutionTime, *qh.MaxExecutionTime)
+			}
+		})
+	}
+}
@@ -4750,7 +4750,7 @@ func TestLoadData(t *testing.T) {
 		"load data from s3 'x.txt'",
 		"load data from s3 manifest 'x.txt'",
 		"load data from s3 file 'x.txt'",
-		"load data infile 'x.txt' into table 'c'",
+		"load data infile 'x.txt' into table c",
 		"load data from s3 'x.txt' into table x"}
 
 	parser := NewTestParser()
@@ -4760,6 +4760,61 @@ func TestLoadData(t *testing.T) {
 	}
 }
 
+func TestLoadDataFormat(t *testing.T) {
+	testcases := []struct {
+		input  string
+		output string
+	}{{
+		input:  "load data infile 'x.txt' into table c",
+		output: "load data infile 'x.txt' into table c",
+	}, {
+		input:  "LOAD DATA LOCAL INFILE '/tmp/x.csv' REPLACE INTO TABLE ks.t PARTITION (p0, p1) CHARACTER SET utf8mb4",
+		output: "load data local infile '/tmp/x.csv' replace into table ks.t partition (p0, p1) character set utf8mb4",
+	}, {
+		input:  "load data local infile 'x.csv' ignore into table t fields terminated by ',' optionally enclosed by '\"' escaped by '\\\\' lines starting by 'x' terminated by '\\r\\n' ignore 1 lines (a, @b, c) set d = @b + 1, e = default",
+		output: "load data local infile 'x.csv' ignore into table t fields terminated by ',' optionally enclosed by '\"' escaped by '\\\\' lines starting by 'x' terminated by '\\r\\n' ignore 1 lines (a, @b, c) set d = @b + 1, e = default",
+	}, {
+		input:  "load data infile 'x.txt' into table t columns escaped by '' enclosed by '\"' terminated by '\\t' ignore 10 rows",
+		output: "load data infile 'x.txt' into table t fields terminated by '\\t' enclosed by '\"' escaped by '' ignore 10 lines",
+	}, {
+		input:  "load data infile 'x.txt' into table t lines terminated by ';' ()",
+		output: "load data infile 'x.txt' into table t lines terminated by ';'",
+	}}
+
+	parser := NewTestParser()
+	for _, tcase := range testcases {
+		t.Run(tcase.input, func(t *testing.T) {
+			tree, err := parser.Parse(tcase.input)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.output, String(tree))
+			assert.Equal(t, tcase.output, String(CloneStatement(tree)))
+		})
+	}
+
+	stmt, err := parser.Parse(`load data local infile 'x.csv' into table t fields terminated by '\t' escaped by '' lines terminated by '\r\n' ignore 2 lines (a, @b) set c = @b`)
+	require.NoError(t, err)
+	load := stmt.(*Load)
+	assert.True(t, load.Local)
+	assert.Equal(t, "x.csv", load.Infile)
+	assert.Equal(t, "\t", *load.Fields.TerminatedBy)
+	assert.Nil(t, load.Fields.EnclosedBy)
+	assert.Equal(t, "", *load.Fields.EscapedBy)
+	assert.Nil(t, load.Lines.StartingBy)
+	assert.Equal(t, "\r\n", *load.Lines.TerminatedBy)
+	assert.Equal(t, 2, load.IgnoreLines)
+	assert.Equal(t, Exprs{NewColName("a"), NewVariableExpression("b", SingleAt)}, load.Columns)
+	assert.Equal(t, "c = @b", String(load.SetExprs))
+
+	for _, sql := range []string{
+		"load data infile 'x.txt' into table 'c'",
+		"load data infile 'x.txt' into table t ignore lines",
+		"load data infile 'x.txt' into table t set",
+	} {
+		_, err := parser.Parse(sql)
+		assert.Error(t, err, sql)
+	}
+}
+
 func TestCreateTable(t *testing.T) {
 	createTableQueries := []struct {
 		input, output string
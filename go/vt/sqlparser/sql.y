@@ -70,6 +70,9 @@ func markBindVariable(yylex yyLexer, bvar string) {
   alterOption      AlterOption
 
   ins           *Insert
+  load          *Load
+  loadFields    *LoadFields
+  loadLines     *LoadLines
   colName       *ColName
   colNames      []*ColName
   indexHint    *IndexHint
@@ -255,7 +258,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %token <str> DISTINCT AS EXISTS ASC DESC INTO DUPLICATE DEFAULT SET LOCK UNLOCK KEYS DO CALL
 %left <str> ALL ANY SOME
 %token <str> DISTINCTROW PARSER GENERATED ALWAYS
-%token <str> OUTFILE S3 DATA LOAD LINES TERMINATED ESCAPED ENCLOSED
+%token <str> OUTFILE S3 DATA LOAD LINES TERMINATED ESCAPED ENCLOSED INFILE
 %token <str> DUMPFILE CSV HEADER MANIFEST OVERWRITE STARTING OPTIONALLY
 %token <str> VALUES LAST_INSERT_ID
 %token <str> NEXT VALUE SHARE MODE
@@ -512,6 +515,14 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %type <boolVal> boolean_value
 %type <comparisonExprOperator> compare any_all_compare
 %type <ins> insert_data
+%type <load> load_duplicate_opt
+%type <boolean> load_local_opt
+%type <loadFields> load_fields_opt load_fields_list
+%type <loadLines> load_lines_opt load_lines_list
+%type <integer> load_ignore_lines_opt
+%type <exprs> load_column_list load_columns_opt
+%type <expr> load_column
+%type <updateExprs> load_set_opt
 %type <expr> num_val
 %type <expr> function_call_keyword function_call_nonkeyword function_call_generic function_call_conflict
 %type <isExprOperator> is_suffix
@@ -718,11 +729,163 @@ do_statement:
   }
 
 load_statement:
-  LOAD DATA skip_to_end
+  LOAD DATA load_local_opt INFILE STRING load_duplicate_opt INTO TABLE table_name opt_partition_clause charset_opt load_fields_opt load_lines_opt load_ignore_lines_opt load_columns_opt load_set_opt
+  {
+    // load_duplicate_opt returns a *Load pre-filled with Action & Ignore
+    load := $6
+    load.Local = $3
+    load.Infile = $5
+    load.Table = $9
+    load.Partitions = $10
+    load.Charset = $11
+    load.Fields = $12
+    load.Lines = $13
+    load.IgnoreLines = $14
+    load.Columns = $15
+    load.SetExprs = $16
+    $$ = load
+  }
+| LOAD DATA FROM skip_to_end
   {
     $$ = &Load{}
   }
 
+load_local_opt:
+  {
+    $$ = false
+  }
+| LOCAL
+  {
+    $$ = true
+  }
+
+load_duplicate_opt:
+  {
+    $$ = &Load{Action: InsertAct}
+  }
+| REPLACE
+  {
+    $$ = &Load{Action: ReplaceAct}
+  }
+| IGNORE
+  {
+    $$ = &Load{Action: InsertAct, Ignore: true}
+  }
+
+load_fields_opt:
+  {
+    $$ = nil
+  }
+| columns_or_fields load_fields_list
+  {
+    $$ = $2
+  }
+
+load_fields_list:
+  {
+    $$ = &LoadFields{}
+  }
+| load_fields_list TERMINATED BY STRING
+  {
+    $1.TerminatedBy = ptr.Of($4)
+    $$ = $1
+  }
+| load_fields_list ENCLOSED BY STRING
+  {
+    $1.EnclosedBy = ptr.Of($4)
+    $$ = $1
+  }
+| load_fields_list OPTIONALLY ENCLOSED BY STRING
+  {
+    $1.EnclosedBy = ptr.Of($5)
+    $1.OptionallyEnclosed = true
+    $$ = $1
+  }
+| load_fields_list ESCAPED BY STRING
+  {
+    $1.EscapedBy = ptr.Of($4)
+    $$ = $1
+  }
+
+load_lines_opt:
+  {
+    $$ = nil
+  }
+| LINES load_lines_list
+  {
+    $$ = $2
+  }
+
+load_lines_list:
+  {
+    $$ = &LoadLines{}
+  }
+| load_lines_list STARTING BY STRING
+  {
+    $1.StartingBy = ptr.Of($4)
+    $$ = $1
+  }
+| load_lines_list TERMINATED BY STRING
+  {
+    $1.TerminatedBy = ptr.Of($4)
+    $$ = $1
+  }
+
+load_ignore_lines_opt:
+  {
+    $$ = 0
+  }
+| IGNORE INTEGRAL LINES
+  {
+    $$ = convertStringToInt($2)
+  }
+| IGNORE INTEGRAL ROWS
+  {
+    $$ = convertStringToInt($2)
+  }
+
+load_columns_opt:
+  {
+    $$ = nil
+  }
+| openb closeb
+  {
+    $$ = nil
+  }
+| openb load_column_list closeb
+  {
+    $$ = $2
+  }
+
+load_column_list:
+  load_column
+  {
+    $$ = Exprs{$1}
+  }
+| load_column_list ',' load_column
+  {
+    $$ = append($1, $3)
+  }
+
+load_column:
+  sql_id
+  {
+    $$ = &ColName{Name: $1}
+  }
+| user_defined_variable
+  {
+    $$ = $1
+  }
+
+load_set_opt:
+  {
+    $$ = nil
+  }
+| SET update_list
+  {
+    $$ = $2
+  }
+
 with_clause:
   WITH with_list
   {
@@ -8140,6 +8303,7 @@ reserved_keyword:
 | IGNORE
 | IN
 | INDEX
+| INFILE
 | INNER
 | INSERT
 | INTERVAL
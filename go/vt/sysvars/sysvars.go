@@ -57,7 +57,9 @@ var (
 	off     = "0"
 	utf8mb4 = "'utf8mb4'"
 
-	ForeignKeyChecks = "foreign_key_checks"
+	BlockEncryptionMode = "block_encryption_mode"
+	ForeignKeyChecks    = "foreign_key_checks"
+	GroupConcatMaxLen   = "group_concat_max_len"
 
 	Autocommit                  = SystemVariable{Name: "autocommit", IsBoolean: true, Default: on}
 	Charset                     = SystemVariable{Name: "charset", Default: utf8mb4, IdentifierAsString: true}
@@ -73,6 +75,7 @@ var (
 	TxReadOnly                  = SystemVariable{Name: "tx_read_only", IsBoolean: true, Default: off}
 	Workload                    = SystemVariable{Name: "workload", IdentifierAsString: true}
 	QueryTimeout                = SystemVariable{Name: "query_timeout"}
+	MaxExecutionTime            = SystemVariable{Name: "max_execution_time"}
 
 	// Online DDL
 	DDLStrategy      = SystemVariable{Name: "ddl_strategy", IdentifierAsString: true}
@@ -106,6 +109,7 @@ var (
 		ReadAfterWriteTimeOut,
 		SessionTrackGTIDs,
 		QueryTimeout,
+		MaxExecutionTime,
 	}
 
 	ReadOnly = []SystemVariable{
@@ -184,12 +188,13 @@ var (
 		{Name: "transaction_write_set_extraction"},
 	}
 	UseReservedConn = []SystemVariable{
+		{Name: BlockEncryptionMode},
 		{Name: "default_week_format"},
 		{Name: "end_markers_in_json", IsBoolean: true, SupportSetVar: true},
 		{Name: "eq_range_index_dive_limit", SupportSetVar: true},
 		{Name: "explicit_defaults_for_timestamp"},
 		{Name: ForeignKeyChecks, IsBoolean: true, SupportSetVar: true},
-		{Name: "group_concat_max_len", SupportSetVar: true},
+		{Name: GroupConcatMaxLen, SupportSetVar: true},
 		{Name: "information_schema_stats_expiry"},
 		{Name: "innodb_lock_wait_timeout"},
 		{Name: "max_heap_table_size", SupportSetVar: true},
@@ -235,7 +240,6 @@ var (
 		// Until then, SET statements against these settings are allowed
 		// as long as they have the same value as the underlying database
 		{Name: "binlog_format"},
-		{Name: "block_encryption_mode"},
 		{Name: "character_set_client"},
 		{Name: "character_set_connection"},
 		{Name: "character_set_database"},
@@ -252,7 +256,6 @@ var (
 		{Name: "lock_wait_timeout", SupportSetVar: true},
 		{Name: "max_allowed_packet"},
 		{Name: "max_error_count", SupportSetVar: true},
-		{Name: "max_execution_time", SupportSetVar: true},
 		{Name: "max_join_size", SupportSetVar: true},
 		{Name: "max_length_for_sort_data", SupportSetVar: true},
 		{Name: "max_sort_length", SupportSetVar: true},
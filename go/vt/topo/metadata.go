@@ -88,6 +88,11 @@ func (ts *Server) DeleteMetadata(ctx context.Context, key string) error {
 	return nil
 }
 
+// WatchMetadata watches the value of the key in the metadata
+func (ts *Server) WatchMetadata(ctx context.Context, key string) (*WatchData, <-chan *WatchData, error) {
+	return ts.globalCell.Watch(ctx, path.Join(MetadataPath, key))
+}
+
 func (ts *Server) getMetadata(ctx context.Context, key string) (string, error) {
 	keyPath := path.Join(MetadataPath, key)
 	contents, _, err := ts.globalCell.Get(ctx, keyPath)
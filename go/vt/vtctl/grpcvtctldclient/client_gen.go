@@ -560,6 +560,15 @@ func (client *gRPCVtctldClient) LaunchSchemaMigration(ctx context.Context, in *v
 	return client.c.LaunchSchemaMigration(ctx, in, opts...)
 }
 
+// LookupVindexCheck is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) LookupVindexCheck(ctx context.Context, in *vtctldatapb.LookupVindexCheckRequest, opts ...grpc.CallOption) (*vtctldatapb.LookupVindexCheckResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.LookupVindexCheck(ctx, in, opts...)
+}
+
 // LookupVindexCreate is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) LookupVindexCreate(ctx context.Context, in *vtctldatapb.LookupVindexCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.LookupVindexCreateResponse, error) {
 	if client.c == nil {
@@ -2802,6 +2802,24 @@ func (s *VtctldServer) LaunchSchemaMigration(ctx context.Context, req *vtctldata
 	return resp, nil
 }
 
+// LookupVindexCheck is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) LookupVindexCheck(ctx context.Context, req *vtctldatapb.LookupVindexCheckRequest) (resp *vtctldatapb.LookupVindexCheckResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.LookupVindexCheck")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("name", req.Name)
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("table_keyspace", req.TableKeyspace)
+	span.Annotate("cells", req.Cells)
+	span.Annotate("tablet_types", req.TabletTypes)
+	span.Annotate("repair", req.Repair)
+
+	resp, err = s.ws.LookupVindexCheck(ctx, req)
+	return resp, err
+}
+
 // LookupVindexCreate is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) LookupVindexCreate(ctx context.Context, req *vtctldatapb.LookupVindexCreateRequest) (resp *vtctldatapb.LookupVindexCreateResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.LookupVindexCreate")
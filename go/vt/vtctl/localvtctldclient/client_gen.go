@@ -416,6 +416,11 @@ func (client *localVtctldClient) LaunchSchemaMigration(ctx context.Context, in *
 	return client.s.LaunchSchemaMigration(ctx, in)
 }
 
+// LookupVindexCheck is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) LookupVindexCheck(ctx context.Context, in *vtctldatapb.LookupVindexCheckRequest, opts ...grpc.CallOption) (*vtctldatapb.LookupVindexCheckResponse, error) {
+	return client.s.LookupVindexCheck(ctx, in)
+}
+
 // LookupVindexCreate is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) LookupVindexCreate(ctx context.Context, in *vtctldatapb.LookupVindexCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.LookupVindexCreateResponse, error) {
 	return client.s.LookupVindexCreate(ctx, in)
@@ -38,6 +38,7 @@ import (
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vdiff"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vdiff/diffutil"
 	"vitess.io/vitess/go/vt/wrangler"
 
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
@@ -657,24 +658,11 @@ func displayVDiff2ActionStatusResponse(wr *wrangler.Wrangler, format, uuid strin
 
 func buildProgressReport(summary *vdiffSummary, rowsToCompare int64) {
 	report := &vdiff.ProgressReport{}
-	if summary.RowsCompared >= 1 {
-		// Round to 2 decimal points
-		report.Percentage = math.Round(math.Min((float64(summary.RowsCompared)/float64(rowsToCompare))*100, 100.00)*100) / 100
-	}
-	if math.IsNaN(report.Percentage) {
-		report.Percentage = 0
-	}
-	pctToGo := math.Abs(report.Percentage - 100.00)
 	startTime, _ := time.Parse(vdiff.TimestampFormat, summary.StartedAt)
-	curTime := time.Now().UTC()
-	runTime := curTime.Unix() - startTime.Unix()
-	if report.Percentage >= 1 {
-		// calculate how long 1% took, on avg, and multiply that by the % left
-		eta := time.Unix(((int64(runTime)/int64(report.Percentage))*int64(pctToGo))+curTime.Unix(), 1).UTC()
-		// cap the ETA at 1 year out to prevent providing nonsensical ETAs
-		if eta.Before(time.Now().UTC().AddDate(1, 0, 0)) {
-			report.ETA = eta.Format(vdiff.TimestampFormat)
-		}
+	percentage, eta := diffutil.EstimateProgress(summary.RowsCompared, rowsToCompare, startTime, time.Now().UTC())
+	report.Percentage = percentage
+	if !eta.IsZero() {
+		report.ETA = eta.UTC().Format(vdiff.TimestampFormat)
 	}
 	summary.Progress = report
 }
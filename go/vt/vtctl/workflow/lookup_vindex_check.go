@@ -0,0 +1,890 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vdiff/diffutil"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	// defaultLookupVindexCheckRepairBatchSize is used when the request does
+	// not set a repair batch size.
+	defaultLookupVindexCheckRepairBatchSize = 1000
+	// lookupVindexCheckVerifyMaxRows is the maximum number of owner rows
+	// that can share the from values of an entry that is repaired.
+	lookupVindexCheckVerifyMaxRows = 10000
+	// lookupVindexCheckProgressInterval is the number of owner rows after
+	// which the progress of the check is logged.
+	lookupVindexCheckProgressInterval = 10000
+)
+
+// lookupVindexCheckThrottleRetryInterval is how long the repair waits before
+// checking the throttler of a lookup shard again.
+var lookupVindexCheckThrottleRetryInterval = 1 * time.Second
+
+// checkableLookupVindexTypes are the vindex types whose lookup table stores
+// the keyspace ids of the rows of the owner table.
+var checkableLookupVindexTypes = map[string]bool{
+	"lookup":                   true,
+	"lookup_unique":            true,
+	"consistent_lookup":        true,
+	"consistent_lookup_unique": true,
+}
+
+// LookupVindexCheck compares an owned lookup vindex with its owner table. It
+// streams the owner table and the lookup table, both sorted by the from
+// columns of the vindex, and reports the entries of the lookup table that do
+// not match any row of the owner table (orphaned), and the entries that the
+// rows of the owner table need but the lookup table misses (missing). When
+// requested, it repairs the lookup table in throttled batches. The progress
+// of the check is logged every lookupVindexCheckProgressInterval owner rows.
+func (s *Server) LookupVindexCheck(ctx context.Context, req *vtctldatapb.LookupVindexCheckRequest) (*vtctldatapb.LookupVindexCheckResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.LookupVindexCheck")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("name", req.Name)
+	span.Annotate("table_keyspace", req.TableKeyspace)
+	span.Annotate("repair", req.Repair)
+
+	lvc, err := s.newLookupVindexChecker(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return lvc.check(ctx)
+}
+
+// lookupVindexChecker holds the state of a single LookupVindexCheck.
+type lookupVindexChecker struct {
+	ts           *topo.Server
+	tmc          tmclient.TabletManagerClient
+	collationEnv *collations.Environment
+	req          *vtctldatapb.LookupVindexCheckRequest
+	tabletTypes  string
+	batchSize    int
+
+	ownerTable     string
+	ownerColumns   []string
+	ownerPVColumns []string
+	ownerVindex    vindexes.Vindex
+	ownerShards    []*topo.ShardInfo
+
+	lookupTable string
+	fromColumns []string
+	toColumn    string
+	ignoreNulls bool
+	// lookupVindex is the primary vindex of the lookup table, and
+	// lookupVindexColumns are the positions of its columns in the rows of
+	// the lookup table. lookupVindex is nil if the lookup table lives in an
+	// unsharded keyspace.
+	lookupVindex        vindexes.Vindex
+	lookupVindexColumns []int
+	lookupShards        []*topo.ShardInfo
+
+	// collations are the collations used to compare the from values.
+	collations []collations.ID
+
+	// stream streams the result of query from a tablet of shard. It calls
+	// send with the fields first, and then with the rows.
+	stream func(ctx context.Context, keyspace string, shard *topo.ShardInfo, query string, send func(*sqltypes.Result) error) error
+
+	startedAt time.Time
+	// logProgress logs the progress of the check.
+	logProgress func(format string, args ...any)
+
+	primaries map[string]*topodatapb.Tablet
+	// repairs are the pending repairs, by lookup shard.
+	repairs map[string]*lookupVindexRepairs
+	resp    *vtctldatapb.LookupVindexCheckResponse
+}
+
+// lookupVindexRepairs are the lookup entries, made of the from values and
+// the keyspace id, to delete from and insert into a lookup shard.
+type lookupVindexRepairs struct {
+	deletes [][]sqltypes.Value
+	inserts [][]sqltypes.Value
+}
+
+func (s *Server) newLookupVindexChecker(ctx context.Context, req *vtctldatapb.LookupVindexCheckRequest) (*lookupVindexChecker, error) {
+	if req.TableKeyspace == "" {
+		req.TableKeyspace = req.Keyspace
+	}
+	lvc := &lookupVindexChecker{
+		ts:           s.ts,
+		tmc:          s.tmc,
+		collationEnv: s.env.CollationEnv(),
+		req:          req,
+		tabletTypes:  discovery.BuildTabletTypesString(req.TabletTypes, req.TabletSelectionPreference),
+		batchSize:    int(req.RepairBatchSize),
+		primaries:    make(map[string]*topodatapb.Tablet),
+		repairs:      make(map[string]*lookupVindexRepairs),
+		resp:         &vtctldatapb.LookupVindexCheckResponse{},
+	}
+	lvc.stream = lvc.streamFromTablet
+	lvc.logProgress = log.Infof
+	if lvc.batchSize <= 0 {
+		lvc.batchSize = defaultLookupVindexCheckRepairBatchSize
+	}
+
+	ownerVSchema, err := s.ts.GetVSchema(ctx, req.Keyspace)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to get vschema for the %s keyspace", req.Keyspace)
+	}
+	vindex := ownerVSchema.Vindexes[req.Name]
+	if vindex == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "vindex %s not found in the %s keyspace", req.Name, req.Keyspace)
+	}
+	if !checkableLookupVindexTypes[vindex.Type] {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "vindex %s is a %s vindex, which cannot be checked", req.Name, vindex.Type)
+	}
+	if vindex.Owner == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "vindex %s has no owner table to check it against", req.Name)
+	}
+	if !ownerVSchema.Sharded {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the %s keyspace of the owner table of vindex %s is not sharded", req.Keyspace, req.Name)
+	}
+
+	// Parse the lookup table of the vindex.
+	lvc.lookupTable = vindex.Params["table"]
+	if ks, table, ok := strings.Cut(lvc.lookupTable, "."); ok {
+		if ks != req.TableKeyspace {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the lookup table %s of vindex %s is not in the %s keyspace", lvc.lookupTable, req.Name, req.TableKeyspace)
+		}
+		lvc.lookupTable = table
+	}
+	for _, from := range strings.Split(vindex.Params["from"], ",") {
+		if from = strings.TrimSpace(from); from != "" {
+			lvc.fromColumns = append(lvc.fromColumns, from)
+		}
+	}
+	lvc.toColumn = strings.TrimSpace(vindex.Params["to"])
+	if lvc.lookupTable == "" || len(lvc.fromColumns) == 0 || lvc.toColumn == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "vindex %s must define the table, from and to params", req.Name)
+	}
+	lvc.ignoreNulls = vindex.Params["ignore_nulls"] == "true"
+
+	// Find the columns of the owner table that feed the vindex, and the
+	// primary vindex that gives the keyspace ids of its rows.
+	lvc.ownerTable = vindex.Owner
+	ownerTable := ownerVSchema.Tables[lvc.ownerTable]
+	if ownerTable == nil || len(ownerTable.ColumnVindexes) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "owner table %s of vindex %s has no primary vindex", lvc.ownerTable, req.Name)
+	}
+	for _, cv := range ownerTable.ColumnVindexes {
+		if cv.Name == req.Name {
+			lvc.ownerColumns = columnVindexColumns(cv)
+			break
+		}
+	}
+	if len(lvc.ownerColumns) != len(lvc.fromColumns) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "owner table %s does not use vindex %s on %d columns", lvc.ownerTable, req.Name, len(lvc.fromColumns))
+	}
+	lvc.ownerVindex, lvc.ownerPVColumns, err = keyspaceIDVindex(ownerVSchema, ownerTable)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "owner table %s", lvc.ownerTable)
+	}
+
+	// Find how to route the entries of the lookup table to its shards.
+	lookupVSchema, err := s.ts.GetVSchema(ctx, req.TableKeyspace)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to get vschema for the %s keyspace", req.TableKeyspace)
+	}
+	if lookupVSchema.Sharded {
+		lookupTable := lookupVSchema.Tables[lvc.lookupTable]
+		if lookupTable == nil || len(lookupTable.ColumnVindexes) == 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "lookup table %s has no primary vindex in the %s keyspace", lvc.lookupTable, req.TableKeyspace)
+		}
+		var columns []string
+		lvc.lookupVindex, columns, err = keyspaceIDVindex(lookupVSchema, lookupTable)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "lookup table %s", lvc.lookupTable)
+		}
+		lookupColumns := append(append([]string{}, lvc.fromColumns...), lvc.toColumn)
+		for _, column := range columns {
+			pos := -1
+			for i, lookupColumn := range lookupColumns {
+				if strings.EqualFold(column, lookupColumn) {
+					pos = i
+					break
+				}
+			}
+			if pos == -1 {
+				return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the primary vindex of lookup table %s uses column %s, which is not a column of vindex %s", lvc.lookupTable, column, req.Name)
+			}
+			lvc.lookupVindexColumns = append(lvc.lookupVindexColumns, pos)
+		}
+	}
+
+	if lvc.ownerShards, err = s.ts.GetServingShards(ctx, req.Keyspace); err != nil {
+		return nil, err
+	}
+	if lvc.lookupShards, err = s.ts.GetServingShards(ctx, req.TableKeyspace); err != nil {
+		return nil, err
+	}
+	if err := lvc.buildCollations(ctx); err != nil {
+		return nil, err
+	}
+	lvc.estimateOwnerRows(ctx)
+	return lvc, nil
+}
+
+// columnVindexColumns returns the columns of a column vindex.
+func columnVindexColumns(cv *vschemapb.ColumnVindex) []string {
+	if len(cv.Columns) != 0 {
+		return cv.Columns
+	}
+	return []string{cv.Column}
+}
+
+// keyspaceIDVindex creates the primary vindex of a table, which must give
+// the keyspace ids of the rows of the table without a vcursor.
+func keyspaceIDVindex(ks *vschemapb.Keyspace, table *vschemapb.Table) (vindexes.Vindex, []string, error) {
+	cv := table.ColumnVindexes[0]
+	vindex := ks.Vindexes[cv.Name]
+	if vindex == nil {
+		return nil, nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "primary vindex %s not found", cv.Name)
+	}
+	vdx, err := vindexes.CreateVindex(vindex.Type, cv.Name, vindex.Params)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !vdx.IsUnique() || vdx.NeedsVCursor() {
+		return nil, nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "primary vindex %s must be unique and must not need a vcursor", cv.Name)
+	}
+	return vdx, columnVindexColumns(cv), nil
+}
+
+// buildCollations finds the collations to compare the from values with,
+// and makes sure that the from columns of the owner and lookup tables are
+// compared the same way.
+func (lvc *lookupVindexChecker) buildCollations(ctx context.Context) error {
+	ownerFields, err := lvc.tableFields(ctx, lvc.req.Keyspace, lvc.ownerShards[0], lvc.ownerTable)
+	if err != nil {
+		return err
+	}
+	lookupFields, err := lvc.tableFields(ctx, lvc.req.TableKeyspace, lvc.lookupShards[0], lvc.lookupTable)
+	if err != nil {
+		return err
+	}
+	for i := range lvc.fromColumns {
+		ownerField := ownerFields[strings.ToLower(lvc.ownerColumns[i])]
+		if ownerField == nil {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "column %s not found in owner table %s", lvc.ownerColumns[i], lvc.ownerTable)
+		}
+		lookupField := lookupFields[strings.ToLower(lvc.fromColumns[i])]
+		if lookupField == nil {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "column %s not found in lookup table %s", lvc.fromColumns[i], lvc.lookupTable)
+		}
+		if sqltypes.IsText(ownerField.Type) != sqltypes.IsText(lookupField.Type) ||
+			sqltypes.IsNumber(ownerField.Type) != sqltypes.IsNumber(lookupField.Type) ||
+			(sqltypes.IsText(ownerField.Type) && ownerField.Charset != lookupField.Charset) {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "column %s.%s (%v) and column %s.%s (%v) are not compared the same way",
+				lvc.ownerTable, lvc.ownerColumns[i], ownerField.Type, lvc.lookupTable, lvc.fromColumns[i], lookupField.Type)
+		}
+		// If the collation is unknown, use the binary collation to compare as bytes.
+		collation := collations.ID(lookupField.Charset)
+		if collation == collations.Unknown {
+			collation = collations.CollationBinaryID
+		}
+		lvc.collations = append(lvc.collations, collation)
+	}
+	return nil
+}
+
+// tableFields returns the fields of a table, by lower case column name.
+func (lvc *lookupVindexChecker) tableFields(ctx context.Context, keyspace string, shard *topo.ShardInfo, table string) (map[string]*querypb.Field, error) {
+	primary, err := lvc.primary(ctx, shard)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := lvc.tmc.GetSchema(ctx, primary, &tabletmanagerdatapb.GetSchemaRequest{Tables: []string{table}})
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil || len(schema.TableDefinitions) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found in the %s keyspace", table, keyspace)
+	}
+	fields := make(map[string]*querypb.Field)
+	for _, field := range schema.TableDefinitions[0].Fields {
+		fields[strings.ToLower(field.Name)] = field
+	}
+	return fields, nil
+}
+
+// estimateOwnerRows sums the row counts that the owner shards report for the
+// owner table. The estimate is only used to report progress, so a shard that
+// cannot report its row count is left out of it.
+func (lvc *lookupVindexChecker) estimateOwnerRows(ctx context.Context) {
+	for _, shard := range lvc.ownerShards {
+		primary, err := lvc.primary(ctx, shard)
+		if err != nil {
+			log.Warningf("LookupVindexCheck could not estimate the rows of owner table %s on shard %s/%s: %v", lvc.ownerTable, shard.Keyspace(), shard.ShardName(), err)
+			continue
+		}
+		schema, err := lvc.tmc.GetSchema(ctx, primary, &tabletmanagerdatapb.GetSchemaRequest{Tables: []string{lvc.ownerTable}})
+		if err != nil {
+			log.Warningf("LookupVindexCheck could not estimate the rows of owner table %s on shard %s/%s: %v", lvc.ownerTable, shard.Keyspace(), shard.ShardName(), err)
+			continue
+		}
+		if schema != nil && len(schema.TableDefinitions) > 0 {
+			lvc.resp.OwnerRowsEstimate += int64(schema.TableDefinitions[0].RowCount)
+		}
+	}
+}
+
+// reportProgress records in the response how far the check has got through
+// the estimated rows of the owner table, and logs it with the number of rows
+// compared so far.
+func (lvc *lookupVindexChecker) reportProgress() {
+	percentage, eta := diffutil.EstimateProgress(lvc.resp.OwnerRows, lvc.resp.OwnerRowsEstimate, lvc.startedAt, time.Now())
+	lvc.resp.ProgressPercentage = percentage
+	etaText := "unknown"
+	if !eta.IsZero() {
+		etaText = eta.UTC().Format(time.DateTime)
+	}
+	lvc.logProgress("LookupVindexCheck of vindex %s.%s: compared %d owner rows and %d lookup rows, %.2f%% of about %d owner rows, ETA %s",
+		lvc.req.Keyspace, lvc.req.Name, lvc.resp.OwnerRows, lvc.resp.LookupRows, percentage, lvc.resp.OwnerRowsEstimate, etaText)
+}
+
+// primary returns the primary tablet of a shard.
+func (lvc *lookupVindexChecker) primary(ctx context.Context, shard *topo.ShardInfo) (*topodatapb.Tablet, error) {
+	key := shard.Keyspace() + "/" + shard.ShardName()
+	if tablet := lvc.primaries[key]; tablet != nil {
+		return tablet, nil
+	}
+	if shard.PrimaryAlias == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard %s has no primary", key)
+	}
+	ti, err := lvc.ts.GetTablet(ctx, shard.PrimaryAlias)
+	if err != nil {
+		return nil, err
+	}
+	lvc.primaries[key] = ti.Tablet
+	return ti.Tablet, nil
+}
+
+func (lvc *lookupVindexChecker) check(ctx context.Context) (*vtctldatapb.LookupVindexCheckResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	n := len(lvc.fromColumns)
+	ownerQuery := fmt.Sprintf("select %s from %s order by %s",
+		escapeColumns(append(append([]string{}, lvc.ownerColumns...), lvc.ownerPVColumns...)),
+		sqlescape.EscapeID(lvc.ownerTable),
+		escapeColumns(lvc.ownerColumns))
+	lookupQuery := fmt.Sprintf("select %s from %s order by %s",
+		escapeColumns(append(append([]string{}, lvc.fromColumns...), lvc.toColumn)),
+		sqlescape.EscapeID(lvc.lookupTable),
+		escapeColumns(append(append([]string{}, lvc.fromColumns...), lvc.toColumn)))
+	owner := diffutil.NewPrimitiveExecutor(ctx, lvc.newMergeSorter(lvc.req.Keyspace, lvc.ownerShards, ownerQuery), "owner")
+	lookup := diffutil.NewPrimitiveExecutor(ctx, lvc.newMergeSorter(lvc.req.TableKeyspace, lvc.lookupShards, lookupQuery), "lookup")
+
+	lvc.startedAt = time.Now()
+	nextProgress := int64(lookupVindexCheckProgressInterval)
+	ownerRow, err := owner.Next()
+	if err != nil {
+		return nil, err
+	}
+	lookupRow, err := lookup.Next()
+	if err != nil {
+		return nil, err
+	}
+	for ownerRow != nil || lookupRow != nil {
+		// The next group of rows is the one with the smallest from values.
+		var from []sqltypes.Value
+		switch {
+		case lookupRow == nil:
+			from = ownerRow[:n]
+		case ownerRow == nil:
+			from = lookupRow[:n]
+		default:
+			c, err := lvc.compareFrom(ownerRow, lookupRow)
+			if err != nil {
+				return nil, err
+			}
+			if c <= 0 {
+				from = ownerRow[:n]
+			} else {
+				from = lookupRow[:n]
+			}
+		}
+
+		ownerKeyspaceIDs := make(map[string]bool)
+		for ownerRow != nil {
+			if c, err := lvc.compareFrom(ownerRow, from); err != nil {
+				return nil, err
+			} else if c != 0 {
+				break
+			}
+			lvc.resp.OwnerRows++
+			if !lvc.ignoreNulls || !hasNull(ownerRow[:n]) {
+				ksid, err := lvc.ownerKeyspaceID(ctx, ownerRow[n:])
+				if err != nil {
+					return nil, err
+				}
+				ownerKeyspaceIDs[ksid] = true
+			}
+			if ownerRow, err = owner.Next(); err != nil {
+				return nil, err
+			}
+		}
+		lookupKeyspaceIDs := make(map[string]bool)
+		for lookupRow != nil {
+			if c, err := lvc.compareFrom(lookupRow, from); err != nil {
+				return nil, err
+			} else if c != 0 {
+				break
+			}
+			lvc.resp.LookupRows++
+			lookupKeyspaceIDs[string(lookupRow[n].Raw())] = true
+			if lookupRow, err = lookup.Next(); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, ksid := range sortedKeys(lookupKeyspaceIDs) {
+			if !ownerKeyspaceIDs[ksid] {
+				lvc.resp.OrphanedEntries++
+				lvc.resp.OrphanedSample = lvc.appendSample(lvc.resp.OrphanedSample, from, ksid)
+				if err := lvc.queueRepair(ctx, from, ksid, true); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, ksid := range sortedKeys(ownerKeyspaceIDs) {
+			if !lookupKeyspaceIDs[ksid] {
+				lvc.resp.MissingEntries++
+				lvc.resp.MissingSample = lvc.appendSample(lvc.resp.MissingSample, from, ksid)
+				if err := lvc.queueRepair(ctx, from, ksid, false); err != nil {
+					return nil, err
+				}
+			}
+		}
+		// Only flush the repairs of full groups, so that a group is never
+		// half repaired.
+		if err := lvc.flushRepairs(ctx, false); err != nil {
+			return nil, err
+		}
+		if lvc.resp.OwnerRows >= nextProgress {
+			lvc.reportProgress()
+			nextProgress = (lvc.resp.OwnerRows/lookupVindexCheckProgressInterval + 1) * lookupVindexCheckProgressInterval
+		}
+	}
+	if err := lvc.flushRepairs(ctx, true); err != nil {
+		return nil, err
+	}
+	lvc.reportProgress()
+	return lvc.resp, nil
+}
+
+// compareFrom compares the from values at the start of two rows.
+func (lvc *lookupVindexChecker) compareFrom(a, b []sqltypes.Value) (int, error) {
+	for i, collation := range lvc.collations {
+		c, err := evalengine.NullsafeCompare(a[i], b[i], lvc.collationEnv, collation, nil)
+		if err != nil {
+			return 0, err
+		}
+		if c != 0 {
+			return c, nil
+		}
+	}
+	return 0, nil
+}
+
+// ownerKeyspaceID returns the keyspace id of a row of the owner table, given
+// the values of the columns of its primary vindex.
+func (lvc *lookupVindexChecker) ownerKeyspaceID(ctx context.Context, values []sqltypes.Value) (string, error) {
+	return keyspaceID(ctx, lvc.ownerVindex, values)
+}
+
+func keyspaceID(ctx context.Context, vindex vindexes.Vindex, values []sqltypes.Value) (string, error) {
+	destinations, err := vindexes.Map(ctx, vindex, nil, [][]sqltypes.Value{values})
+	if err != nil {
+		return "", err
+	}
+	ksid, ok := destinations[0].(key.DestinationKeyspaceID)
+	if !ok {
+		return "", vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "vindex %s maps %v to %v instead of a keyspace id", vindex.String(), values, destinations[0])
+	}
+	return string(ksid), nil
+}
+
+func (lvc *lookupVindexChecker) appendSample(sample []*vtctldatapb.LookupVindexCheckResponse_Entry, from []sqltypes.Value, ksid string) []*vtctldatapb.LookupVindexCheckResponse_Entry {
+	if int64(len(sample)) >= lvc.req.MaxReportSampleRows {
+		return sample
+	}
+	entry := &vtctldatapb.LookupVindexCheckResponse_Entry{KeyspaceId: hex.EncodeToString([]byte(ksid))}
+	for _, value := range from {
+		entry.From = append(entry.From, formatLookupValue(value))
+	}
+	return append(sample, entry)
+}
+
+// formatLookupValue formats a from value for the report.
+func formatLookupValue(value sqltypes.Value) string {
+	if value.IsNull() {
+		return "null"
+	}
+	if value.IsQuoted() {
+		return fmt.Sprintf("%q", value.Raw())
+	}
+	return value.ToString()
+}
+
+// queueRepair queues the deletion of an orphaned entry, or the insertion of
+// a missing entry, if the check repairs the lookup table.
+func (lvc *lookupVindexChecker) queueRepair(ctx context.Context, from []sqltypes.Value, ksid string, orphaned bool) error {
+	if !lvc.req.Repair {
+		return nil
+	}
+	entry := append(append([]sqltypes.Value{}, from...), sqltypes.MakeTrusted(sqltypes.VarBinary, []byte(ksid)))
+	shard, err := lvc.lookupShard(ctx, entry)
+	if err != nil {
+		return err
+	}
+	repairs := lvc.repairs[shard.ShardName()]
+	if repairs == nil {
+		repairs = &lookupVindexRepairs{}
+		lvc.repairs[shard.ShardName()] = repairs
+	}
+	if orphaned {
+		repairs.deletes = append(repairs.deletes, entry)
+	} else {
+		repairs.inserts = append(repairs.inserts, entry)
+	}
+	return nil
+}
+
+// lookupShard returns the lookup shard of an entry.
+func (lvc *lookupVindexChecker) lookupShard(ctx context.Context, entry []sqltypes.Value) (*topo.ShardInfo, error) {
+	if lvc.lookupVindex == nil {
+		return lvc.lookupShards[0], nil
+	}
+	values := make([]sqltypes.Value, 0, len(lvc.lookupVindexColumns))
+	for _, pos := range lvc.lookupVindexColumns {
+		values = append(values, entry[pos])
+	}
+	ksid, err := keyspaceID(ctx, lvc.lookupVindex, values)
+	if err != nil {
+		return nil, err
+	}
+	return shardForKeyspaceID(lvc.lookupShards, ksid)
+}
+
+func shardForKeyspaceID(shards []*topo.ShardInfo, ksid string) (*topo.ShardInfo, error) {
+	for _, shard := range shards {
+		if key.KeyRangeContains(shard.KeyRange, []byte(ksid)) {
+			return shard, nil
+		}
+	}
+	return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no serving shard for keyspace id %x", ksid)
+}
+
+// flushRepairs applies the pending repairs of the lookup shards that have
+// at least a batch of them, or of all the lookup shards if all is set.
+func (lvc *lookupVindexChecker) flushRepairs(ctx context.Context, all bool) error {
+	for _, shard := range lvc.lookupShards {
+		repairs := lvc.repairs[shard.ShardName()]
+		if repairs == nil || (!all && len(repairs.deletes)+len(repairs.inserts) < lvc.batchSize) {
+			continue
+		}
+		delete(lvc.repairs, shard.ShardName())
+		if err := lvc.repairShard(ctx, shard, repairs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// repairShard applies repairs to a lookup shard. The owner and lookup tables
+// were streamed from tablets that may lag behind, and the owner table may
+// have changed since: each entry is checked against the primaries of the
+// owner table before it is deleted or inserted.
+func (lvc *lookupVindexChecker) repairShard(ctx context.Context, shard *topo.ShardInfo, repairs *lookupVindexRepairs) error {
+	primary, err := lvc.primary(ctx, shard)
+	if err != nil {
+		return err
+	}
+	var deletes, inserts [][]sqltypes.Value
+	for _, entry := range repairs.deletes {
+		exists, err := lvc.ownerHasEntry(ctx, entry)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			deletes = append(deletes, entry)
+		}
+	}
+	for _, entry := range repairs.inserts {
+		exists, err := lvc.ownerHasEntry(ctx, entry)
+		if err != nil {
+			return err
+		}
+		if exists {
+			inserts = append(inserts, entry)
+		}
+	}
+
+	for len(deletes) > 0 {
+		batch := deletes[:min(len(deletes), lvc.batchSize)]
+		deletes = deletes[len(batch):]
+		if err := lvc.throttle(ctx, primary); err != nil {
+			return err
+		}
+		rowsAffected, err := lvc.execute(ctx, primary, lvc.deleteQuery(batch))
+		if err != nil {
+			return err
+		}
+		lvc.resp.DeletedEntries += int64(rowsAffected)
+	}
+	for len(inserts) > 0 {
+		batch := inserts[:min(len(inserts), lvc.batchSize)]
+		inserts = inserts[len(batch):]
+		if err := lvc.throttle(ctx, primary); err != nil {
+			return err
+		}
+		rowsAffected, err := lvc.execute(ctx, primary, lvc.insertQuery(batch))
+		if err != nil {
+			return err
+		}
+		lvc.resp.InsertedEntries += int64(rowsAffected)
+	}
+	return nil
+}
+
+// ownerHasEntry tells whether the owner table has a row with the from values
+// and the keyspace id of entry, on the primary of its shard.
+func (lvc *lookupVindexChecker) ownerHasEntry(ctx context.Context, entry []sqltypes.Value) (bool, error) {
+	n := len(lvc.fromColumns)
+	ksid := string(entry[n].Raw())
+	shard, err := shardForKeyspaceID(lvc.ownerShards, ksid)
+	if err != nil {
+		return false, err
+	}
+	primary, err := lvc.primary(ctx, shard)
+	if err != nil {
+		return false, err
+	}
+	query := fmt.Sprintf("select %s from %s where %s",
+		escapeColumns(lvc.ownerPVColumns), sqlescape.EscapeID(lvc.ownerTable), nullsafeEquals(lvc.ownerColumns, entry[:n]))
+	qr, err := lvc.tmc.ExecuteFetchAsApp(ctx, primary, true, &tabletmanagerdatapb.ExecuteFetchAsAppRequest{
+		Query:   []byte(query),
+		MaxRows: lookupVindexCheckVerifyMaxRows,
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, row := range sqltypes.Proto3ToResult(qr).Rows {
+		rowKeyspaceID, err := lvc.ownerKeyspaceID(ctx, row)
+		if err != nil {
+			return false, err
+		}
+		if rowKeyspaceID == ksid {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (lvc *lookupVindexChecker) deleteQuery(entries [][]sqltypes.Value) string {
+	columns := append(append([]string{}, lvc.fromColumns...), lvc.toColumn)
+	var conditions []string
+	for _, entry := range entries {
+		conditions = append(conditions, "("+nullsafeEquals(columns, entry)+")")
+	}
+	return fmt.Sprintf("delete from %s where %s", sqlescape.EscapeID(lvc.lookupTable), strings.Join(conditions, " or "))
+}
+
+func (lvc *lookupVindexChecker) insertQuery(entries [][]sqltypes.Value) string {
+	columns := append(append([]string{}, lvc.fromColumns...), lvc.toColumn)
+	var rows []string
+	for _, entry := range entries {
+		values := make([]string, 0, len(entry))
+		for _, value := range entry {
+			values = append(values, encodeValue(value))
+		}
+		rows = append(rows, "("+strings.Join(values, ", ")+")")
+	}
+	return fmt.Sprintf("insert ignore into %s(%s) values %s", sqlescape.EscapeID(lvc.lookupTable), escapeColumns(columns), strings.Join(rows, ", "))
+}
+
+func (lvc *lookupVindexChecker) execute(ctx context.Context, tablet *topodatapb.Tablet, query string) (uint64, error) {
+	qr, err := lvc.tmc.ExecuteFetchAsApp(ctx, tablet, true, &tabletmanagerdatapb.ExecuteFetchAsAppRequest{
+		Query: []byte(query),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return qr.RowsAffected, nil
+}
+
+// throttle waits until the throttler of tablet lets the repair write to it.
+func (lvc *lookupVindexChecker) throttle(ctx context.Context, tablet *topodatapb.Tablet) error {
+	for {
+		resp, err := lvc.tmc.CheckThrottler(ctx, tablet, &tabletmanagerdatapb.CheckThrottlerRequest{
+			AppName: throttlerapp.LookupVindexCheckName.String(),
+		})
+		if err != nil {
+			return err
+		}
+		if resp.ResponseCode == tabletmanagerdatapb.CheckThrottlerResponseCode_OK ||
+			(resp.ResponseCode == tabletmanagerdatapb.CheckThrottlerResponseCode_UNDEFINED && resp.StatusCode == http.StatusOK) {
+			return nil
+		}
+		log.Infof("LookupVindexCheck of %s.%s throttled by %v: %s", lvc.req.Keyspace, lvc.req.Name, tablet.Alias, resp.Message)
+		select {
+		case <-ctx.Done():
+			return vterrors.Wrap(ctx.Err(), "waiting for the throttler")
+		case <-time.After(lookupVindexCheckThrottleRetryInterval):
+		}
+	}
+}
+
+// newMergeSorter creates an engine.MergeSort that merges the rows of query
+// from all the shards, which are sorted by the from values.
+func (lvc *lookupVindexChecker) newMergeSorter(keyspace string, shards []*topo.ShardInfo, query string) *engine.MergeSort {
+	prims := make([]engine.StreamExecutor, 0, len(shards))
+	for _, shard := range shards {
+		prims = append(prims, &lookupVindexCheckStreamer{lvc: lvc, keyspace: keyspace, shard: shard, query: query})
+	}
+	sortColumns := make([]diffutil.SortColumn, 0, len(lvc.collations))
+	for i, collation := range lvc.collations {
+		sortColumns = append(sortColumns, diffutil.SortColumn{Col: i, Collation: collation})
+	}
+	return diffutil.NewMergeSorter(prims, sortColumns, lvc.collationEnv)
+}
+
+// streamFromTablet streams the result of query from a tablet of shard that
+// matches the cells and tablet types of the request.
+func (lvc *lookupVindexChecker) streamFromTablet(ctx context.Context, keyspace string, shard *topo.ShardInfo, query string, send func(*sqltypes.Result) error) error {
+	cells := lvc.req.Cells
+	if len(cells) == 0 {
+		cells = []string{shard.PrimaryAlias.Cell}
+	}
+	tp, err := discovery.NewTabletPicker(ctx, lvc.ts, cells, shard.PrimaryAlias.Cell, keyspace, shard.ShardName(), lvc.tabletTypes, discovery.TabletPickerOptions{})
+	if err != nil {
+		return err
+	}
+	tablet, err := tp.PickForStreaming(ctx)
+	if err != nil {
+		return err
+	}
+	conn, err := tabletconn.GetDialer()(ctx, tablet, grpcclient.FailFast(false))
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	target := &querypb.Target{
+		Keyspace:   keyspace,
+		Shard:      shard.ShardName(),
+		TabletType: tablet.Type,
+	}
+	return conn.StreamExecute(ctx, target, query, nil, 0, 0, nil, send)
+}
+
+// lookupVindexCheckStreamer streams the rows of a query from one shard.
+// It satisfies engine.StreamExecutor, and can be added to Primitives of
+// engine.MergeSort.
+type lookupVindexCheckStreamer struct {
+	lvc      *lookupVindexChecker
+	keyspace string
+	shard    *topo.ShardInfo
+	query    string
+}
+
+func (ls *lookupVindexCheckStreamer) StreamExecute(ctx context.Context, vcursor engine.VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	return ls.lvc.stream(ctx, ls.keyspace, ls.shard, ls.query, callback)
+}
+
+func escapeColumns(columns []string) string {
+	escaped := make([]string, 0, len(columns))
+	for _, column := range columns {
+		escaped = append(escaped, sqlescape.EscapeID(column))
+	}
+	return strings.Join(escaped, ", ")
+}
+
+// nullsafeEquals returns a condition that matches the rows whose columns
+// have the given values, including NULL values.
+func nullsafeEquals(columns []string, values []sqltypes.Value) string {
+	conditions := make([]string, 0, len(columns))
+	for i, column := range columns {
+		conditions = append(conditions, fmt.Sprintf("%s <=> %s", sqlescape.EscapeID(column), encodeValue(values[i])))
+	}
+	return strings.Join(conditions, " and ")
+}
+
+// encodeValue encodes a value for a query. Binary values, like keyspace
+// ids, are encoded as hex literals.
+func encodeValue(value sqltypes.Value) string {
+	if value.IsBinary() {
+		return fmt.Sprintf("X'%x'", value.Raw())
+	}
+	var b strings.Builder
+	value.EncodeSQLStringBuilder(&b)
+	return b.String()
+}
+
+func hasNull(values []sqltypes.Value) bool {
+	for _, value := range values {
+		if value.IsNull() {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,414 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// newLookupVindexCheckEnv creates an environment with a t1 owner table in
+// the sharded sourceks keyspace, and its v lookup vindex, whose lkp lookup
+// table lives in the unsharded targetks keyspace.
+func newLookupVindexCheckEnv(t *testing.T, ctx context.Context, vindexType string) *testMaterializerEnv {
+	ms := &vtctldatapb.MaterializeSettings{
+		SourceKeyspace: "sourceks",
+		TargetKeyspace: "targetks",
+	}
+	env := newTestMaterializerEnv(t, ctx, ms, []string{"-80", "80-"}, []string{"0"})
+
+	require.NoError(t, env.topoServ.SaveVSchema(ctx, "sourceks", &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"hash": {
+				Type: "hash",
+			},
+			"v": {
+				Type: vindexType,
+				Params: map[string]string{
+					"table": "targetks.lkp",
+					"from":  "c2",
+					"to":    "keyspace_id",
+				},
+				Owner: "t1",
+			},
+		},
+		Tables: map[string]*vschemapb.Table{
+			"t1": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Name:   "hash",
+					Column: "c1",
+				}, {
+					Name:   "v",
+					Column: "c2",
+				}},
+			},
+		},
+	}))
+	utf8mb4 := uint32(collations.MySQL8().DefaultConnectionCharset())
+	env.tmc.schema["sourceks.t1"] = &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+			Name: "t1",
+			Fields: []*querypb.Field{
+				{Name: "c1", Type: sqltypes.Int64, Charset: collations.CollationBinaryID},
+				{Name: "c2", Type: sqltypes.VarChar, Charset: utf8mb4},
+			},
+		}},
+	}
+	env.tmc.schema["targetks.lkp"] = &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+			Name: "lkp",
+			Fields: []*querypb.Field{
+				{Name: "c2", Type: sqltypes.VarChar, Charset: utf8mb4},
+				{Name: "keyspace_id", Type: sqltypes.VarBinary, Charset: collations.CollationBinaryID},
+			},
+		}},
+	}
+	return env
+}
+
+// hashKeyspaceID returns the keyspace id that the hash vindex maps id to.
+func hashKeyspaceID(t *testing.T, id int64) []byte {
+	hash, err := vindexes.CreateVindex("hash", "hash", nil)
+	require.NoError(t, err)
+	destinations, err := vindexes.Map(context.Background(), hash, nil, [][]sqltypes.Value{{sqltypes.NewInt64(id)}})
+	require.NoError(t, err)
+	return destinations[0].(key.DestinationKeyspaceID)
+}
+
+// fakeLookupVindexCheckStream streams the rows of the owner table from the
+// shards that own them, and the rows of the lookup table from its single
+// shard. The rows must be sorted by the from column.
+func fakeLookupVindexCheckStream(t *testing.T, ownerRows [][2]any, lookupRows [][2]any) func(ctx context.Context, keyspace string, shard *topo.ShardInfo, query string, send func(*sqltypes.Result) error) error {
+	return func(ctx context.Context, keyspace string, shard *topo.ShardInfo, query string, send func(*sqltypes.Result) error) error {
+		result := &sqltypes.Result{}
+		switch keyspace {
+		case "sourceks":
+			require.Equal(t, "select `c2`, `c1` from `t1` order by `c2`", query)
+			result.Fields = []*querypb.Field{
+				{Name: "c2", Type: sqltypes.VarChar},
+				{Name: "c1", Type: sqltypes.Int64},
+			}
+			for _, row := range ownerRows {
+				id := row[1].(int64)
+				if key.KeyRangeContains(shard.KeyRange, hashKeyspaceID(t, id)) {
+					result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewVarChar(row[0].(string)), sqltypes.NewInt64(id)})
+				}
+			}
+		case "targetks":
+			require.Equal(t, "select `c2`, `keyspace_id` from `lkp` order by `c2`, `keyspace_id`", query)
+			result.Fields = []*querypb.Field{
+				{Name: "c2", Type: sqltypes.VarChar},
+				{Name: "keyspace_id", Type: sqltypes.VarBinary},
+			}
+			for _, row := range lookupRows {
+				result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewVarChar(row[0].(string)), sqltypes.NewVarBinary(string(hashKeyspaceID(t, row[1].(int64))))})
+			}
+		default:
+			return fmt.Errorf("unexpected keyspace %s", keyspace)
+		}
+		if err := send(&sqltypes.Result{Fields: result.Fields}); err != nil {
+			return err
+		}
+		return send(&sqltypes.Result{Rows: result.Rows})
+	}
+}
+
+func TestLookupVindexCheck(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The owner rows and lookup entries are (c2, c1) pairs, where the
+	// keyspace id of an entry is the hash of c1.
+	ownerRows := [][2]any{{"a", int64(1)}, {"b", int64(2)}, {"c", int64(3)}, {"d", int64(4)}}
+	lookupRows := [][2]any{
+		{"a", int64(1)},
+		// Orphaned: the owner row of b has the keyspace id of 2.
+		{"b", int64(3)},
+		// Matches the d owner row with the case insensitive collation.
+		{"D", int64(4)},
+		// Orphaned: there is no e owner row.
+		{"e", int64(5)},
+	}
+	oldRetryInterval := lookupVindexCheckThrottleRetryInterval
+	lookupVindexCheckThrottleRetryInterval = time.Millisecond
+	defer func() {
+		lookupVindexCheckThrottleRetryInterval = oldRetryInterval
+	}()
+	hexKeyspaceID := func(id int64) string {
+		return fmt.Sprintf("%x", hashKeyspaceID(t, id))
+	}
+
+	testcases := []struct {
+		name            string
+		repair          bool
+		sampleRows      int64
+		throttledChecks int
+		// The owner rows that the repair finds on the primaries.
+		verifyRows map[string][]int64
+		want       *vtctldatapb.LookupVindexCheckResponse
+	}{
+		{
+			name:       "report",
+			sampleRows: 10,
+			want: &vtctldatapb.LookupVindexCheckResponse{
+				OwnerRows:       4,
+				LookupRows:      4,
+				OrphanedEntries: 2,
+				MissingEntries:  2,
+				OrphanedSample: []*vtctldatapb.LookupVindexCheckResponse_Entry{
+					{From: []string{`"b"`}, KeyspaceId: hexKeyspaceID(3)},
+					{From: []string{`"e"`}, KeyspaceId: hexKeyspaceID(5)},
+				},
+				MissingSample: []*vtctldatapb.LookupVindexCheckResponse_Entry{
+					{From: []string{`"b"`}, KeyspaceId: hexKeyspaceID(2)},
+					{From: []string{`"c"`}, KeyspaceId: hexKeyspaceID(3)},
+				},
+			},
+		},
+		{
+			name:       "limited sample",
+			sampleRows: 1,
+			want: &vtctldatapb.LookupVindexCheckResponse{
+				OwnerRows:       4,
+				LookupRows:      4,
+				OrphanedEntries: 2,
+				MissingEntries:  2,
+				OrphanedSample: []*vtctldatapb.LookupVindexCheckResponse_Entry{
+					{From: []string{`"b"`}, KeyspaceId: hexKeyspaceID(3)},
+				},
+				MissingSample: []*vtctldatapb.LookupVindexCheckResponse_Entry{
+					{From: []string{`"b"`}, KeyspaceId: hexKeyspaceID(2)},
+				},
+			},
+		},
+		{
+			name:            "repair",
+			repair:          true,
+			throttledChecks: 1,
+			verifyRows: map[string][]int64{
+				"b": {2},
+				"c": {3},
+			},
+			want: &vtctldatapb.LookupVindexCheckResponse{
+				OwnerRows:       4,
+				LookupRows:      4,
+				OrphanedEntries: 2,
+				MissingEntries:  2,
+				DeletedEntries:  2,
+				InsertedEntries: 2,
+			},
+		},
+		{
+			// The c owner row was deleted after it was streamed, so the
+			// repair does not insert its entry.
+			name:   "repair skips stale entries",
+			repair: true,
+			verifyRows: map[string][]int64{
+				"b": {2},
+			},
+			want: &vtctldatapb.LookupVindexCheckResponse{
+				OwnerRows:       4,
+				LookupRows:      4,
+				OrphanedEntries: 2,
+				MissingEntries:  2,
+				DeletedEntries:  2,
+				InsertedEntries: 1,
+			},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newLookupVindexCheckEnv(t, ctx, "consistent_lookup_unique")
+			defer env.close()
+			env.tmc.throttledChecks = tc.throttledChecks
+
+			if tc.repair {
+				ownerTabletID := func(id int64) int {
+					if key.KeyRangeContains(env.tablets[100].KeyRange, hashKeyspaceID(t, id)) {
+						return 100
+					}
+					return 110
+				}
+				verify := func(id int64, from string) {
+					result := sqltypes.MakeTestResult(sqltypes.MakeTestFields("c1", "int64"))
+					for _, c1 := range tc.verifyRows[from] {
+						result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewInt64(c1)})
+					}
+					env.tmc.expectVRQuery(ownerTabletID(id), fmt.Sprintf("select `c1` from `t1` where `c2` <=> '%s'", from), result)
+				}
+				// The orphaned entries are checked first, then the missing ones.
+				verify(3, "b")
+				verify(5, "e")
+				verify(2, "b")
+				verify(3, "c")
+				env.tmc.expectVRQuery(200, fmt.Sprintf("delete from `lkp` where (`c2` <=> 'b' and `keyspace_id` <=> X'%s') or (`c2` <=> 'e' and `keyspace_id` <=> X'%s')",
+					hexKeyspaceID(3), hexKeyspaceID(5)), &sqltypes.Result{RowsAffected: 2})
+				insert := fmt.Sprintf("insert ignore into `lkp`(`c2`, `keyspace_id`) values ('b', X'%s')", hexKeyspaceID(2))
+				if len(tc.verifyRows["c"]) > 0 {
+					insert += fmt.Sprintf(", ('c', X'%s')", hexKeyspaceID(3))
+				}
+				env.tmc.expectVRQuery(200, insert, &sqltypes.Result{RowsAffected: uint64(1 + len(tc.verifyRows["c"]))})
+			}
+
+			lvc, err := env.ws.newLookupVindexChecker(ctx, &vtctldatapb.LookupVindexCheckRequest{
+				Keyspace:            "sourceks",
+				Name:                "v",
+				TableKeyspace:       "targetks",
+				Repair:              tc.repair,
+				MaxReportSampleRows: tc.sampleRows,
+			})
+			require.NoError(t, err)
+			lvc.stream = fakeLookupVindexCheckStream(t, ownerRows, lookupRows)
+			resp, err := lvc.check(ctx)
+			require.NoError(t, err)
+			require.EqualValues(t, tc.want, resp)
+			env.tmc.verifyQueries(t)
+			require.Zero(t, env.tmc.throttledChecks)
+		})
+	}
+}
+
+func TestLookupVindexCheckRepairBatches(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env := newLookupVindexCheckEnv(t, ctx, "lookup")
+	defer env.close()
+
+	// All the entries are missing.
+	ownerRows := [][2]any{{"a", int64(1)}, {"b", int64(2)}, {"c", int64(3)}}
+	for _, row := range ownerRows {
+		id := row[1].(int64)
+		tabletID := 110
+		if key.KeyRangeContains(env.tablets[100].KeyRange, hashKeyspaceID(t, id)) {
+			tabletID = 100
+		}
+		env.tmc.expectVRQuery(tabletID, fmt.Sprintf("select `c1` from `t1` where `c2` <=> '%s'", row[0]),
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("c1", "int64"), fmt.Sprintf("%d", id)))
+	}
+	// Every group is a batch of its own, which is flushed before the next
+	// group is read.
+	for _, row := range ownerRows {
+		env.tmc.expectVRQuery(200, fmt.Sprintf("insert ignore into `lkp`(`c2`, `keyspace_id`) values ('%s', X'%x')", row[0], hashKeyspaceID(t, row[1].(int64))),
+			&sqltypes.Result{RowsAffected: 1})
+	}
+
+	lvc, err := env.ws.newLookupVindexChecker(ctx, &vtctldatapb.LookupVindexCheckRequest{
+		Keyspace:        "sourceks",
+		Name:            "v",
+		TableKeyspace:   "targetks",
+		Repair:          true,
+		RepairBatchSize: 1,
+	})
+	require.NoError(t, err)
+	lvc.stream = fakeLookupVindexCheckStream(t, ownerRows, nil)
+	resp, err := lvc.check(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, &vtctldatapb.LookupVindexCheckResponse{
+		OwnerRows:       3,
+		MissingEntries:  3,
+		InsertedEntries: 3,
+	}, resp)
+	env.tmc.verifyQueries(t)
+}
+
+func TestLookupVindexCheckProgress(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env := newLookupVindexCheckEnv(t, ctx, "lookup")
+	defer env.close()
+	// Both owner shards report 2 rows.
+	env.tmc.schema["sourceks.t1"].TableDefinitions[0].RowCount = 2
+
+	rows := [][2]any{{"a", int64(1)}, {"b", int64(2)}, {"c", int64(3)}}
+
+	lvc, err := env.ws.newLookupVindexChecker(ctx, &vtctldatapb.LookupVindexCheckRequest{
+		Keyspace:      "sourceks",
+		Name:          "v",
+		TableKeyspace: "targetks",
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 4, lvc.resp.OwnerRowsEstimate)
+	var reports []string
+	lvc.logProgress = func(format string, args ...any) {
+		reports = append(reports, fmt.Sprintf(format, args...))
+	}
+	lvc.stream = fakeLookupVindexCheckStream(t, rows, rows)
+	resp, err := lvc.check(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 4, resp.OwnerRowsEstimate)
+	require.EqualValues(t, 75, resp.ProgressPercentage)
+	require.Len(t, reports, 1)
+	require.Contains(t, reports[0], "compared 3 owner rows and 3 lookup rows, 75.00% of about 4 owner rows, ETA ")
+}
+
+func TestLookupVindexCheckErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	testcases := []struct {
+		name       string
+		vindexType string
+		req        *vtctldatapb.LookupVindexCheckRequest
+		wantErr    string
+	}{
+		{
+			name:       "vindex not found",
+			vindexType: "lookup",
+			req:        &vtctldatapb.LookupVindexCheckRequest{Keyspace: "sourceks", Name: "nope", TableKeyspace: "targetks"},
+			wantErr:    "vindex nope not found in the sourceks keyspace",
+		},
+		{
+			name:       "not a lookup vindex",
+			vindexType: "lookup",
+			req:        &vtctldatapb.LookupVindexCheckRequest{Keyspace: "sourceks", Name: "hash", TableKeyspace: "targetks"},
+			wantErr:    "vindex hash is a hash vindex, which cannot be checked",
+		},
+		{
+			name:       "lookup table in another keyspace",
+			vindexType: "lookup_unique",
+			req:        &vtctldatapb.LookupVindexCheckRequest{Keyspace: "sourceks", Name: "v"},
+			wantErr:    "the lookup table targetks.lkp of vindex v is not in the sourceks keyspace",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newLookupVindexCheckEnv(t, ctx, tc.vindexType)
+			defer env.close()
+
+			_, err := env.ws.LookupVindexCheck(ctx, tc.req)
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
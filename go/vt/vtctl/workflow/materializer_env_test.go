@@ -206,6 +206,9 @@ type testMaterializerTMClient struct {
 
 	// Used to override the response to ReadVReplicationWorkflow.
 	readVReplicationWorkflow readVReplicationWorkflowFunc
+
+	// Used to throttle the first throttledChecks CheckThrottler calls.
+	throttledChecks int
 }
 
 func newTestMaterializerTMClient(keyspace string, sourceShards []string, tableSettings []*vtctldatapb.TableMaterializeSettings) *testMaterializerTMClient {
@@ -368,6 +371,28 @@ func (tmc *testMaterializerTMClient) ExecuteFetchAsAllPrivs(ctx context.Context,
 	return nil, nil
 }
 
+func (tmc *testMaterializerTMClient) ExecuteFetchAsApp(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteFetchAsAppRequest) (*querypb.QueryResult, error) {
+	// Reuse VReplicationExec
+	return tmc.VReplicationExec(ctx, tablet, string(req.Query))
+}
+
+func (tmc *testMaterializerTMClient) CheckThrottler(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.CheckThrottlerRequest) (*tabletmanagerdatapb.CheckThrottlerResponse, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+
+	if tmc.throttledChecks > 0 {
+		tmc.throttledChecks--
+		return &tabletmanagerdatapb.CheckThrottlerResponse{
+			ResponseCode: tabletmanagerdatapb.CheckThrottlerResponseCode_THRESHOLD_EXCEEDED,
+			AppName:      req.AppName,
+		}, nil
+	}
+	return &tabletmanagerdatapb.CheckThrottlerResponse{
+		ResponseCode: tabletmanagerdatapb.CheckThrottlerResponseCode_OK,
+		AppName:      req.AppName,
+	}, nil
+}
+
 // Note: ONLY breaks up change.SQL into individual statements and executes it. Does NOT fully implement ApplySchema.
 func (tmc *testMaterializerTMClient) ApplySchema(ctx context.Context, tablet *topodatapb.Tablet, change *tmutils.SchemaChange) (*tabletmanagerdatapb.SchemaChangeResult, error) {
 	stmts := strings.Split(change.SQL, ";")
@@ -124,6 +124,7 @@ const (
 
 	CharacterSetMismatch
 	WrongParametersToNativeFct
+	AESInvalidIV
 
 	VectorConversion
 
@@ -18,9 +18,13 @@ package engine
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/collations/charset"
+	"vitess.io/vitess/go/mysql/collations/colldata"
 	"vitess.io/vitess/go/slice"
 	"vitess.io/vitess/go/sqltypes"
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
@@ -50,6 +54,12 @@ type AggregateParams struct {
 	// not what we use to aggregate at the engine primitive level.
 	OrigOpcode AggregateOpcode
 
+	// These are used only for GROUP_CONCAT, when it is computed from the individual values
+	// of the group instead of from partial results. Args holds the input columns of all
+	// the arguments, and OrderBy the ORDER BY clause of the function.
+	Args    evalengine.Comparison
+	OrderBy evalengine.Comparison
+
 	CollationEnv *collations.Environment
 }
 
@@ -79,6 +89,9 @@ func (ap *AggregateParams) String() string {
 	if sqltypes.IsText(ap.Type.Type()) && ap.CollationEnv.IsSupported(ap.Type.Collation()) {
 		keyCol += " COLLATE " + ap.CollationEnv.LookupName(ap.Type.Collation())
 	}
+	if len(ap.Args) > 0 {
+		keyCol = ap.groupConcatString()
+	}
 	dispOrigOp := ""
 	if ap.OrigOpcode != AggregateUnassigned && ap.OrigOpcode != ap.Opcode {
 		dispOrigOp = "_" + ap.OrigOpcode.String()
@@ -89,6 +102,25 @@ func (ap *AggregateParams) String() string {
 	return fmt.Sprintf("%s%s(%s)", ap.Opcode.String(), dispOrigOp, keyCol)
 }
 
+func (ap *AggregateParams) groupConcatString() string {
+	var args []string
+	for _, arg := range ap.Args {
+		if arg.WeightStringCol == -1 {
+			args = append(args, strconv.Itoa(arg.Col))
+		} else {
+			args = append(args, fmt.Sprintf("(%d|%d)", arg.Col, arg.WeightStringCol))
+		}
+	}
+	out := strings.Join(args, ", ")
+	if gcFunc, ok := ap.Func.(*sqlparser.GroupConcatExpr); ok && gcFunc.Distinct {
+		out = "distinct " + out
+	}
+	if len(ap.OrderBy) > 0 {
+		out += " order by " + GenericJoin(ap.OrderBy, orderByParamsToString)
+	}
+	return out
+}
+
 func (ap *AggregateParams) typ(inputType querypb.Type) querypb.Type {
 	if ap.OrigOpcode != AggregateUnassigned {
 		return ap.OrigOpcode.SQLType(inputType)
@@ -98,7 +130,7 @@ func (ap *AggregateParams) typ(inputType querypb.Type) querypb.Type {
 
 type aggregator interface {
 	add(row []sqltypes.Value) error
-	finish() sqltypes.Value
+	finish() (sqltypes.Value, error)
 	reset()
 }
 
@@ -151,8 +183,8 @@ func (a *aggregatorCount) add(row []sqltypes.Value) error {
 	return nil
 }
 
-func (a *aggregatorCount) finish() sqltypes.Value {
-	return sqltypes.NewInt64(a.n)
+func (a *aggregatorCount) finish() (sqltypes.Value, error) {
+	return sqltypes.NewInt64(a.n), nil
 }
 
 func (a *aggregatorCount) reset() {
@@ -169,8 +201,8 @@ func (a *aggregatorCountStar) add(_ []sqltypes.Value) error {
 	return nil
 }
 
-func (a *aggregatorCountStar) finish() sqltypes.Value {
-	return sqltypes.NewInt64(a.n)
+func (a *aggregatorCountStar) finish() (sqltypes.Value, error) {
+	return sqltypes.NewInt64(a.n), nil
 }
 
 func (a *aggregatorCountStar) reset() {
@@ -198,8 +230,8 @@ func (a *aggregatorMax) add(row []sqltypes.Value) (err error) {
 	return a.minmax.Max(row[a.from])
 }
 
-func (a *aggregatorMinMax) finish() sqltypes.Value {
-	return a.minmax.Result()
+func (a *aggregatorMinMax) finish() (sqltypes.Value, error) {
+	return a.minmax.Result(), nil
 }
 
 func (a *aggregatorMinMax) reset() {
@@ -222,8 +254,8 @@ func (a *aggregatorSum) add(row []sqltypes.Value) error {
 	return a.sum.Add(row[a.from])
 }
 
-func (a *aggregatorSum) finish() sqltypes.Value {
-	return a.sum.Result()
+func (a *aggregatorSum) finish() (sqltypes.Value, error) {
+	return a.sum.Result(), nil
 }
 
 func (a *aggregatorSum) reset() {
@@ -245,8 +277,8 @@ func (a *aggregatorScalar) add(row []sqltypes.Value) error {
 	return nil
 }
 
-func (a *aggregatorScalar) finish() sqltypes.Value {
-	return a.current
+func (a *aggregatorScalar) finish() (sqltypes.Value, error) {
+	return a.current, nil
 }
 
 func (a *aggregatorScalar) reset() {
@@ -259,6 +291,11 @@ type aggregatorGroupConcat struct {
 	type_     sqltypes.Type
 	separator []byte
 
+	// maxLen is the group_concat_max_len of the session. The result is truncated
+	// to this number of bytes, without splitting a character of charset.
+	maxLen  uint64
+	charset colldata.Charset
+
 	concat []byte
 	n      int
 }
@@ -267,19 +304,43 @@ func (a *aggregatorGroupConcat) add(row []sqltypes.Value) error {
 	if row[a.from].IsNull() {
 		return nil
 	}
+	a.append(row[a.from].Raw())
+	return nil
+}
+
+func (a *aggregatorGroupConcat) append(values ...[]byte) {
 	if a.n > 0 {
 		a.concat = append(a.concat, a.separator...)
 	}
-	a.concat = append(a.concat, row[a.from].Raw()...)
+	for _, v := range values {
+		a.concat = append(a.concat, v...)
+	}
 	a.n++
-	return nil
 }
 
-func (a *aggregatorGroupConcat) finish() sqltypes.Value {
+func (a *aggregatorGroupConcat) finish() (sqltypes.Value, error) {
 	if a.n == 0 {
-		return sqltypes.NULL
+		return sqltypes.NULL, nil
+	}
+	return sqltypes.MakeTrusted(a.type_, a.truncate()), nil
+}
+
+func (a *aggregatorGroupConcat) truncate() []byte {
+	if uint64(len(a.concat)) <= a.maxLen {
+		return a.concat
 	}
-	return sqltypes.MakeTrusted(a.type_, a.concat)
+	if a.charset == nil {
+		return a.concat[:a.maxLen]
+	}
+	end := 0
+	for {
+		r, size := a.charset.DecodeRune(a.concat[end:])
+		if (r == charset.RuneError && size < 2) || uint64(end+size) > a.maxLen {
+			break
+		}
+		end += size
+	}
+	return a.concat[:end]
 }
 
 func (a *aggregatorGroupConcat) reset() {
@@ -287,6 +348,60 @@ func (a *aggregatorGroupConcat) reset() {
 	a.concat = nil // not safe to reuse this byte slice as it's returned as MakeTrusted
 }
 
+// aggregatorGroupConcatValues computes GROUP_CONCAT from the individual values of the group,
+// which is needed for DISTINCT, for ORDER BY and for multiple arguments.
+type aggregatorGroupConcatValues struct {
+	aggregatorGroupConcat
+
+	// cols are the input columns of the arguments. They are kept apart from args,
+	// since comparing can switch the columns of args to their weight strings.
+	cols     []int
+	args     evalengine.Comparison
+	orderBy  evalengine.Comparison
+	distinct bool
+
+	rows []sqltypes.Row
+}
+
+func (a *aggregatorGroupConcatValues) add(row []sqltypes.Value) error {
+	for _, col := range a.cols {
+		if row[col].IsNull() {
+			return nil
+		}
+	}
+	a.rows = append(a.rows, row)
+	return nil
+}
+
+func (a *aggregatorGroupConcatValues) finish() (_ sqltypes.Value, err error) {
+	defer evalengine.PanicHandler(&err)
+
+	rows := a.rows
+	if a.distinct {
+		a.args.Sort(rows)
+		rows = slices.CompactFunc(rows, func(r1, r2 sqltypes.Row) bool {
+			return a.args.Compare(r1, r2) == 0
+		})
+	}
+	if len(a.orderBy) > 0 {
+		slices.SortStableFunc(rows, a.orderBy.Compare)
+	}
+
+	values := make([][]byte, len(a.cols))
+	for _, row := range rows {
+		for i, col := range a.cols {
+			values[i] = row[col].Raw()
+		}
+		a.append(values...)
+	}
+	return a.aggregatorGroupConcat.finish()
+}
+
+func (a *aggregatorGroupConcatValues) reset() {
+	a.aggregatorGroupConcat.reset()
+	a.rows = nil
+}
+
 type aggregatorGtid struct {
 	from   int
 	shards []*binlogdatapb.ShardGtid
@@ -301,9 +416,9 @@ func (a *aggregatorGtid) add(row []sqltypes.Value) error {
 	return nil
 }
 
-func (a *aggregatorGtid) finish() sqltypes.Value {
+func (a *aggregatorGtid) finish() (sqltypes.Value, error) {
 	gtid := binlogdatapb.VGtid{ShardGtids: a.shards}
-	return sqltypes.NewVarChar(gtid.String())
+	return sqltypes.NewVarChar(gtid.String()), nil
 }
 
 func (a *aggregatorGtid) reset() {
@@ -321,12 +436,16 @@ func (a aggregationState) add(row []sqltypes.Value) error {
 	return nil
 }
 
-func (a aggregationState) finish() (row []sqltypes.Value) {
-	row = make([]sqltypes.Value, 0, len(a))
+func (a aggregationState) finish() ([]sqltypes.Value, error) {
+	row := make([]sqltypes.Value, 0, len(a))
 	for _, st := range a {
-		row = append(row, st.finish())
+		v, err := st.finish()
+		if err != nil {
+			return nil, err
+		}
+		row = append(row, v)
 	}
-	return
+	return row, nil
 }
 
 func (a aggregationState) reset() {
@@ -354,7 +473,7 @@ func isComparable(typ sqltypes.Type) bool {
 	return false
 }
 
-func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (aggregationState, []*querypb.Field, error) {
+func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams, groupConcatMaxLen uint64) (aggregationState, []*querypb.Field, error) {
 	fields = slice.Map(fields, func(from *querypb.Field) *querypb.Field { return from.CloneVT() })
 
 	agstate := make([]aggregator, len(fields))
@@ -437,11 +556,33 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (agg
 
 		case AggregateGroupConcat:
 			gcFunc := aggr.Func.(*sqlparser.GroupConcatExpr)
-			separator := []byte(gcFunc.Separator)
-			ag = &aggregatorGroupConcat{
+			for _, arg := range aggr.Args {
+				// the result is binary as soon as one of the arguments is binary
+				if sqltypes.IsBinary(fields[arg.Col].Type) {
+					targetType = sqltypes.Blob
+				}
+			}
+			gc := aggregatorGroupConcat{
 				from:      aggr.Col,
 				type_:     targetType,
-				separator: separator,
+				separator: []byte(gcFunc.Separator),
+				maxLen:    groupConcatMaxLen,
+			}
+			if sqltypes.IsText(targetType) {
+				if coll := colldata.Lookup(collations.ID(fields[aggr.Col].Charset)); coll != nil {
+					gc.charset = coll.Charset()
+				}
+			}
+			if len(aggr.Args) == 0 {
+				ag = &gc
+				break
+			}
+			ag = &aggregatorGroupConcatValues{
+				aggregatorGroupConcat: gc,
+				cols:                  slice.Map(aggr.Args, func(from evalengine.OrderByParams) int { return from.Col }),
+				args:                  slices.Clone(aggr.Args),
+				orderBy:               slices.Clone(aggr.OrderBy),
+				distinct:              gcFunc.Distinct,
 			}
 
 		default:
@@ -35,7 +35,7 @@ func (cached *AggregateParams) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(112)
+		size += int64(160)
 	}
 	// field Type vitess.io/vitess/go/vt/vtgate/evalengine.Type
 	size += cached.Type.CachedSize(false)
@@ -47,6 +47,20 @@ func (cached *AggregateParams) CachedSize(alloc bool) int64 {
 	}
 	// field Original *vitess.io/vitess/go/vt/sqlparser.AliasedExpr
 	size += cached.Original.CachedSize(true)
+	// field Args vitess.io/vitess/go/vt/vtgate/evalengine.Comparison
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Args)) * int64(56))
+		for _, elem := range cached.Args {
+			size += elem.CachedSize(false)
+		}
+	}
+	// field OrderBy vitess.io/vitess/go/vt/vtgate/evalengine.Comparison
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.OrderBy)) * int64(56))
+		for _, elem := range cached.OrderBy {
+			size += elem.CachedSize(false)
+		}
+	}
 	// field CollationEnv *vitess.io/vitess/go/mysql/collations.Environment
 	size += cached.CollationEnv.CachedSize(true)
 	return size
@@ -113,6 +127,64 @@ func (cached *Concatenate) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+
+//go:nocheckptr
+func (cached *CorrelatedSubquery) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(176)
+	}
+	// field SubqueryResult string
+	size += hack.RuntimeAllocSize(int64(len(cached.SubqueryResult)))
+	// field HasValues string
+	size += hack.RuntimeAllocSize(int64(len(cached.HasValues)))
+	// field Vars map[string]int
+	if cached.Vars != nil {
+		size += int64(48)
+		hmap := reflect.ValueOf(cached.Vars)
+		numBuckets := int(math.Pow(2, float64((*(*uint8)(unsafe.Pointer(hmap.Pointer() + uintptr(9)))))))
+		numOldBuckets := (*(*uint16)(unsafe.Pointer(hmap.Pointer() + uintptr(10))))
+		size += hack.RuntimeAllocSize(int64(numOldBuckets * 208))
+		if len(cached.Vars) > 0 || numBuckets > 1 {
+			size += hack.RuntimeAllocSize(int64(numBuckets * 208))
+		}
+		for k := range cached.Vars {
+			size += hack.RuntimeAllocSize(int64(len(k)))
+		}
+	}
+	// field Predicate vitess.io/vitess/go/vt/vtgate/evalengine.Expr
+	if cc, ok := cached.Predicate.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field ASTPredicate vitess.io/vitess/go/vt/sqlparser.Expr
+	if cc, ok := cached.ASTPredicate.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field BatchVar string
+	size += hack.RuntimeAllocSize(int64(len(cached.BatchVar)))
+	// field CollationEnv *vitess.io/vitess/go/mysql/collations.Environment
+	size += cached.CollationEnv.CachedSize(true)
+	// field Values *vitess.io/vitess/go/vt/vtgate/evalengine.EnumSetValues
+	if cached.Values != nil {
+		size += int64(24)
+		size += hack.RuntimeAllocSize(int64(cap(*cached.Values)) * int64(16))
+		for _, elem := range *cached.Values {
+			size += hack.RuntimeAllocSize(int64(len(elem)))
+		}
+	}
+	// field Outer vitess.io/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Outer.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Subquery vitess.io/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Subquery.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	return size
+}
 func (cached *DBDDL) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -389,8 +461,10 @@ func (cached *Generate) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(48)
+		size += int64(64)
 	}
+	// field Mode string
+	size += hack.RuntimeAllocSize(int64(len(cached.Mode)))
 	// field Keyspace *vitess.io/vitess/go/vt/vtgate/vindexes.Keyspace
 	size += cached.Keyspace.CachedSize(true)
 	// field Query string
@@ -425,7 +499,7 @@ func (cached *HashJoin) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(128)
+		size += int64(160)
 	}
 	// field Left vitess.io/vitess/go/vt/vtgate/engine.Primitive
 	if cc, ok := cached.Left.(cachedObject); ok {
@@ -439,12 +513,37 @@ func (cached *HashJoin) CachedSize(alloc bool) int64 {
 	{
 		size += hack.RuntimeAllocSize(int64(cap(cached.Cols)) * int64(8))
 	}
+	// field Keys []vitess.io/vitess/go/vt/vtgate/engine.HashJoinKey
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Keys)) * int64(32))
+		for _, elem := range cached.Keys {
+			size += elem.CachedSize(false)
+		}
+	}
+	// field Residual vitess.io/vitess/go/vt/vtgate/evalengine.Expr
+	if cc, ok := cached.Residual.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field ResidualCols []int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.ResidualCols)) * int64(8))
+	}
 	// field ASTPred vitess.io/vitess/go/vt/sqlparser.Expr
 	if cc, ok := cached.ASTPred.(cachedObject); ok {
 		size += cc.CachedSize(true)
 	}
 	// field CollationEnv *vitess.io/vitess/go/mysql/collations.Environment
 	size += cached.CollationEnv.CachedSize(true)
+	return size
+}
+func (cached *HashJoinKey) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(32)
+	}
 	// field Values *vitess.io/vitess/go/vt/vtgate/evalengine.EnumSetValues
 	if cached.Values != nil {
 		size += int64(24)
@@ -542,7 +641,7 @@ func (cached *InsertSelect) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(176)
+		size += int64(208)
 	}
 	// field InsertCommon vitess.io/vitess/go/vt/vtgate/engine.InsertCommon
 	size += cached.InsertCommon.CachedSize(false)
@@ -559,6 +658,13 @@ func (cached *InsertSelect) CachedSize(alloc bool) int64 {
 			}
 		}
 	}
+	// field DeleteBeforeInsert []*vitess.io/vitess/go/vt/vtgate/engine.FkChild
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.DeleteBeforeInsert)) * int64(8))
+		for _, elem := range cached.DeleteBeforeInsert {
+			size += elem.CachedSize(true)
+		}
+	}
 	return size
 }
 
@@ -621,6 +727,47 @@ func (cached *Limit) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *Load) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(192)
+	}
+	// field Keyspace *vitess.io/vitess/go/vt/vtgate/vindexes.Keyspace
+	size += cached.Keyspace.CachedSize(true)
+	// field TableName string
+	size += hack.RuntimeAllocSize(int64(len(cached.TableName)))
+	// field Infile string
+	size += hack.RuntimeAllocSize(int64(len(cached.Infile)))
+	// field Insert *vitess.io/vitess/go/vt/sqlparser.Insert
+	size += cached.Insert.CachedSize(true)
+	// field Row vitess.io/vitess/go/vt/sqlparser.ValTuple
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Row)) * int64(16))
+		for _, elem := range cached.Row {
+			if cc, ok := elem.(cachedObject); ok {
+				size += cc.CachedSize(true)
+			}
+		}
+	}
+	// field FieldTypes []vitess.io/vitess/go/vt/proto/query.Type
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.FieldTypes)) * int64(4))
+	}
+	// field FieldsTerminatedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.FieldsTerminatedBy)))
+	// field FieldsEnclosedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.FieldsEnclosedBy)))
+	// field FieldsEscapedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.FieldsEscapedBy)))
+	// field LinesStartingBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.LinesStartingBy)))
+	// field LinesTerminatedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.LinesTerminatedBy)))
+	return size
+}
 func (cached *Lock) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -1410,15 +1557,40 @@ func (cached *Upsert) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(24)
+		size += int64(96)
 	}
 	// field Upserts []vitess.io/vitess/go/vt/vtgate/engine.upsert
 	{
-		size += hack.RuntimeAllocSize(int64(cap(cached.Upserts)) * int64(32))
+		size += hack.RuntimeAllocSize(int64(cap(cached.Upserts)) * int64(48))
 		for _, elem := range cached.Upserts {
 			size += elem.CachedSize(false)
 		}
 	}
+	// field Rows [][]vitess.io/vitess/go/vt/vtgate/evalengine.Expr
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Rows)) * int64(24))
+		for _, elem := range cached.Rows {
+			{
+				size += hack.RuntimeAllocSize(int64(cap(elem)) * int64(16))
+				for _, elem := range elem {
+					if cc, ok := elem.(cachedObject); ok {
+						size += cc.CachedSize(true)
+					}
+				}
+			}
+		}
+	}
+	// field Input vitess.io/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Input.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field BindVars []string
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.BindVars)) * int64(16))
+		for _, elem := range cached.BindVars {
+			size += hack.RuntimeAllocSize(int64(len(elem)))
+		}
+	}
 	return size
 }
 func (cached *UserDefinedVariable) CachedSize(alloc bool) int64 {
@@ -1597,6 +1769,55 @@ func (cached *VitessMetadata) CachedSize(alloc bool) int64 {
 	size += hack.RuntimeAllocSize(int64(len(cached.Value)))
 	return size
 }
+func (cached *Window) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(96)
+	}
+	// field Functions []*vitess.io/vitess/go/vt/vtgate/engine.WindowFuncParams
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Functions)) * int64(8))
+		for _, elem := range cached.Functions {
+			size += elem.CachedSize(true)
+		}
+	}
+	// field PartitionBy []*vitess.io/vitess/go/vt/vtgate/engine.GroupByParams
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.PartitionBy)) * int64(8))
+		for _, elem := range cached.PartitionBy {
+			size += elem.CachedSize(true)
+		}
+	}
+	// field OrderBy []*vitess.io/vitess/go/vt/vtgate/engine.GroupByParams
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.OrderBy)) * int64(8))
+		for _, elem := range cached.OrderBy {
+			size += elem.CachedSize(true)
+		}
+	}
+	// field Input vitess.io/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Input.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	return size
+}
+func (cached *WindowFuncParams) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(64)
+	}
+	// field Alias string
+	size += hack.RuntimeAllocSize(int64(len(cached.Alias)))
+	// field Original *vitess.io/vitess/go/vt/sqlparser.AliasedExpr
+	size += cached.Original.CachedSize(true)
+	return size
+}
 func (cached *percentBasedMirror) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -1656,7 +1877,11 @@ func (cached *upsert) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(32)
+		size += int64(48)
+	}
+	// field Check vitess.io/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Check.(cachedObject); ok {
+		size += cc.CachedSize(true)
 	}
 	// field Insert vitess.io/vitess/go/vt/vtgate/engine.Primitive
 	if cc, ok := cached.Insert.(cachedObject); ok {
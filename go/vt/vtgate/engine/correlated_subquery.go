@@ -0,0 +1,376 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vthash"
+)
+
+var _ Primitive = (*CorrelatedSubquery)(nil)
+
+// CorrelatedSubquery evaluates a subquery that depends on the rows of its outer query.
+// The subquery result for every outer row is exposed through the SubqueryResult and
+// HasValues bind variables, the same way UncorrelatedSubquery does it.
+//
+// When Predicate is set, it is evaluated for every outer row and only the rows for
+// which it is true are returned. Otherwise, the subquery value is returned as the
+// first column of every outer row.
+//
+// When BatchVar is set, the subquery is not executed once per outer row. Instead, the
+// distinct OuterKey values of the outer rows are sent in a single tuple bind variable,
+// and the rows of the subquery are matched back to the outer rows using SubqueryKey.
+type CorrelatedSubquery struct {
+	Opcode PulloutOpcode
+
+	// SubqueryResult and HasValues are the bindvars the subquery result is exposed through
+	SubqueryResult string
+	HasValues      string
+
+	// Vars defines the bindvars that need to be built from each outer row
+	// before invoking the subquery.
+	Vars map[string]int
+
+	// Predicate is evaluated for every outer row, together with the subquery result.
+	Predicate    evalengine.Expr
+	ASTPredicate sqlparser.Expr
+
+	// BatchVar is the tuple bindvar used to send all outer keys at once.
+	BatchVar string
+	// OuterKey and SubqueryKey are the offsets of the columns used to correlate
+	// the outer rows with the subquery rows when batching.
+	OuterKey, SubqueryKey int
+
+	// collation and type are used to hash the keys correctly when batching
+	Collation      collations.ID
+	ComparisonType querypb.Type
+	CollationEnv   *collations.Environment
+	Values         *evalengine.EnumSetValues
+
+	Outer    Primitive
+	Subquery Primitive
+}
+
+// Inputs returns the input primitives for this primitive
+func (cs *CorrelatedSubquery) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{cs.Outer, cs.Subquery}, []map[string]any{{
+		inputName: "Outer",
+	}, {
+		inputName: "SubQuery",
+	}}
+}
+
+// RouteType returns a description of the query routing type used by the primitive
+func (cs *CorrelatedSubquery) RouteType() string {
+	return cs.Opcode.String()
+}
+
+// GetKeyspaceName specifies the Keyspace that this primitive routes to.
+func (cs *CorrelatedSubquery) GetKeyspaceName() string {
+	return cs.Outer.GetKeyspaceName()
+}
+
+// GetTableName specifies the table that this primitive routes to.
+func (cs *CorrelatedSubquery) GetTableName() string {
+	return cs.Outer.GetTableName()
+}
+
+// TryExecute satisfies the Primitive interface.
+func (cs *CorrelatedSubquery) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	result, err := vcursor.ExecutePrimitive(ctx, cs.Outer, bindVars, wantfields)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := cs.apply(ctx, vcursor, bindVars, result.Rows)
+	if err != nil {
+		return nil, err
+	}
+	out := &sqltypes.Result{Rows: rows}
+	if wantfields {
+		out.Fields, err = cs.fields(ctx, vcursor, bindVars, result.Fields)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// TryStreamExecute performs a streaming exec.
+func (cs *CorrelatedSubquery) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	var mu sync.Mutex
+	var sendFields atomic.Bool
+	sendFields.Store(wantfields)
+
+	return vcursor.StreamExecutePrimitive(ctx, cs.Outer, bindVars, wantfields, func(result *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		rows, err := cs.apply(ctx, vcursor, bindVars, result.Rows)
+		if err != nil {
+			return err
+		}
+		out := &sqltypes.Result{Rows: rows}
+		if len(result.Fields) != 0 && sendFields.CompareAndSwap(true, false) {
+			out.Fields, err = cs.fields(ctx, vcursor, bindVars, result.Fields)
+			if err != nil {
+				return err
+			}
+		}
+		if len(out.Rows) == 0 && len(out.Fields) == 0 {
+			return nil
+		}
+		return callback(out)
+	})
+}
+
+// GetFields fetches the field info.
+func (cs *CorrelatedSubquery) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	result, err := cs.Outer.GetFields(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := cs.fields(ctx, vcursor, bindVars, result.Fields)
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{Fields: fields}, nil
+}
+
+// NeedsTransaction implements the Primitive interface
+func (cs *CorrelatedSubquery) NeedsTransaction() bool {
+	return cs.Subquery.NeedsTransaction() || cs.Outer.NeedsTransaction()
+}
+
+// fields returns the fields of the outer query, with the field
+// of the subquery prepended when the subquery is used as a value
+func (cs *CorrelatedSubquery) fields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, outer []*querypb.Field) ([]*querypb.Field, error) {
+	if cs.Predicate != nil {
+		return outer, nil
+	}
+	if cs.Opcode == PulloutExists {
+		// EXISTS used as a value returns 1 or 0, without needing to look at the subquery
+		field := &querypb.Field{Name: cs.SubqueryResult, Type: sqltypes.Int64}
+		return append([]*querypb.Field{field}, outer...), nil
+	}
+	joinVars := make(map[string]*querypb.BindVariable, len(cs.Vars)+1)
+	for k := range cs.Vars {
+		joinVars[k] = sqltypes.NullBindVariable
+	}
+	if cs.BatchVar != "" {
+		joinVars[cs.BatchVar] = &querypb.BindVariable{
+			Type:   querypb.Type_TUPLE,
+			Values: []*querypb.Value{sqltypes.ValueToProto(sqltypes.NULL)},
+		}
+	}
+	result, err := cs.Subquery.GetFields(ctx, vcursor, combineVars(bindVars, joinVars))
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Fields) == 0 {
+		return nil, errSqColumn
+	}
+	return append([]*querypb.Field{result.Fields[0]}, outer...), nil
+}
+
+// apply runs the subquery for the given outer rows and returns the rows to send on
+func (cs *CorrelatedSubquery) apply(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, rows []sqltypes.Row) ([]sqltypes.Row, error) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	var subqueryRows [][]sqltypes.Row
+	var err error
+	if cs.BatchVar != "" {
+		subqueryRows, err = cs.execBatch(ctx, vcursor, bindVars, rows)
+	} else {
+		subqueryRows, err = cs.execPerRow(ctx, vcursor, bindVars, rows)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	out := make([]sqltypes.Row, 0, len(rows))
+	for i, row := range rows {
+		if cs.Predicate == nil {
+			value, err := cs.value(subqueryRows[i])
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, append(sqltypes.Row{value}, row...))
+			continue
+		}
+
+		vars := make(map[string]*querypb.BindVariable, len(bindVars)+2)
+		for k, v := range bindVars {
+			vars[k] = v
+		}
+		if err := setPulloutVars(cs.Opcode, cs.SubqueryResult, cs.HasValues, subqueryRows[i], vars); err != nil {
+			return nil, err
+		}
+		env.BindVars = vars
+		env.Row = row
+		evalResult, err := env.Evaluate(cs.Predicate)
+		if err != nil {
+			return nil, err
+		}
+		if evalResult.ToBoolean() {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+// value returns the value of a scalar subquery given its rows
+func (cs *CorrelatedSubquery) value(rows []sqltypes.Row) (sqltypes.Value, error) {
+	if cs.Opcode == PulloutExists {
+		if len(rows) > 0 {
+			return sqltypes.NewInt64(1), nil
+		}
+		return sqltypes.NewInt64(0), nil
+	}
+	switch len(rows) {
+	case 0:
+		return sqltypes.NULL, nil
+	case 1:
+		return rows[0][0], nil
+	default:
+		return sqltypes.NULL, errSqRow
+	}
+}
+
+// execPerRow executes the subquery once for every outer row
+func (cs *CorrelatedSubquery) execPerRow(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, rows []sqltypes.Row) ([][]sqltypes.Row, error) {
+	out := make([][]sqltypes.Row, len(rows))
+	joinVars := make(map[string]*querypb.BindVariable, len(cs.Vars))
+	for i, row := range rows {
+		for k, col := range cs.Vars {
+			joinVars[k] = sqltypes.ValueBindVariable(row[col])
+		}
+		result, err := vcursor.ExecutePrimitive(ctx, cs.Subquery, combineVars(bindVars, joinVars), false)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = result.Rows
+	}
+	return out, nil
+}
+
+// execBatch executes the subquery once for all the outer rows,
+// and matches the subquery rows back to the outer rows using their keys
+func (cs *CorrelatedSubquery) execBatch(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, rows []sqltypes.Row) ([][]sqltypes.Row, error) {
+	hasher := vthash.New()
+	hash := func(val sqltypes.Value) (vthash.Hash, error) {
+		err := evalengine.NullsafeHashcode128(&hasher, val, cs.Collation, cs.ComparisonType, 0, cs.Values)
+		if err != nil {
+			return vthash.Hash{}, err
+		}
+		res := hasher.Sum128()
+		hasher.Reset()
+		return res, nil
+	}
+
+	// NULL keys can never match, so they are not sent to the subquery
+	keys := make([]vthash.Hash, len(rows))
+	seen := map[vthash.Hash]bool{}
+	batch := &querypb.BindVariable{Type: querypb.Type_TUPLE}
+	for i, row := range rows {
+		val := row[cs.OuterKey]
+		if val.IsNull() {
+			continue
+		}
+		h, err := hash(val)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = h
+		if !seen[h] {
+			seen[h] = true
+			batch.Values = append(batch.Values, sqltypes.ValueToProto(val))
+		}
+	}
+
+	out := make([][]sqltypes.Row, len(rows))
+	if len(batch.Values) == 0 {
+		return out, nil
+	}
+
+	result, err := vcursor.ExecutePrimitive(ctx, cs.Subquery, combineVars(bindVars, map[string]*querypb.BindVariable{cs.BatchVar: batch}), false)
+	if err != nil {
+		return nil, err
+	}
+	matches := map[vthash.Hash][]sqltypes.Row{}
+	for _, row := range result.Rows {
+		val := row[cs.SubqueryKey]
+		if val.IsNull() {
+			continue
+		}
+		h, err := hash(val)
+		if err != nil {
+			return nil, err
+		}
+		matches[h] = append(matches[h], row)
+	}
+	for i, row := range rows {
+		if row[cs.OuterKey].IsNull() {
+			continue
+		}
+		out[i] = matches[keys[i]]
+	}
+	return out, nil
+}
+
+func (cs *CorrelatedSubquery) description() PrimitiveDescription {
+	other := map[string]any{}
+	var pulloutVars []string
+	if cs.HasValues != "" {
+		pulloutVars = append(pulloutVars, cs.HasValues)
+	}
+	if cs.SubqueryResult != "" {
+		pulloutVars = append(pulloutVars, cs.SubqueryResult)
+	}
+	if len(pulloutVars) > 0 {
+		other["PulloutVars"] = pulloutVars
+	}
+	if len(cs.Vars) > 0 {
+		other["JoinVars"] = orderedStringIntMap(cs.Vars)
+	}
+	if cs.Predicate != nil {
+		other["Predicate"] = sqlparser.String(cs.ASTPredicate)
+	}
+	if cs.BatchVar != "" {
+		other["BatchVar"] = cs.BatchVar
+		other["OuterKey"] = cs.OuterKey
+		other["SubqueryKey"] = cs.SubqueryKey
+		other["ComparisonType"] = cs.ComparisonType.String()
+		if cs.Collation != collations.Unknown {
+			other["Collation"] = cs.CollationEnv.LookupName(cs.Collation)
+		}
+	}
+	return PrimitiveDescription{
+		OperatorType: "CorrelatedSubquery",
+		Variant:      cs.Opcode.String(),
+		Other:        other,
+	}
+}
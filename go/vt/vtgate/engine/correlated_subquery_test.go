@@ -0,0 +1,306 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+func correlatedPredicate(t *testing.T, expr string, fields []*querypb.Field) (evalengine.Expr, sqlparser.Expr) {
+	ast, err := sqlparser.NewTestParser().ParseExpr(expr)
+	require.NoError(t, err)
+	pred, err := evalengine.Translate(ast, &evalengine.Config{
+		Collation:     collations.MySQL8().DefaultConnectionCharset(),
+		ResolveColumn: evalengine.FieldResolver(fields).Column,
+		Environment:   vtenv.NewTestEnv(),
+	})
+	require.NoError(t, err)
+	return pred, ast
+}
+
+func TestCorrelatedSubqueryInPerRow(t *testing.T) {
+	outerFields := sqltypes.MakeTestFields("id|col", "int64|varchar")
+	outer := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(outerFields, "1|a", "2|b", "3|c"),
+		},
+	}
+	sqFields := sqltypes.MakeTestFields("val", "int64")
+	subquery := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqFields, "1", "5"),
+			sqltypes.MakeTestResult(sqFields),
+			sqltypes.MakeTestResult(sqFields, "4"),
+		},
+	}
+	pred, ast := correlatedPredicate(t, ":__sq_has_values and id in ::__sq1", outerFields)
+
+	cs := &CorrelatedSubquery{
+		Opcode:         PulloutIn,
+		SubqueryResult: "__sq1",
+		HasValues:      "__sq_has_values",
+		Vars:           map[string]int{"col": 1},
+		Predicate:      pred,
+		ASTPredicate:   ast,
+		Outer:          outer,
+		Subquery:       subquery,
+	}
+
+	r, err := cs.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	outer.ExpectLog(t, []string{
+		`Execute  true`,
+	})
+	subquery.ExpectLog(t, []string{
+		`Execute col: type:VARCHAR value:"a" false`,
+		`Execute col: type:VARCHAR value:"b" false`,
+		`Execute col: type:VARCHAR value:"c" false`,
+	})
+	utils.MustMatch(t, sqltypes.MakeTestResult(outerFields, "1|a"), r)
+}
+
+func TestCorrelatedSubqueryNotExists(t *testing.T) {
+	outerFields := sqltypes.MakeTestFields("id|col", "int64|varchar")
+	outer := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(outerFields, "1|a", "2|b"),
+		},
+	}
+	sqFields := sqltypes.MakeTestFields("val", "int64")
+	subquery := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqFields, "1"),
+			sqltypes.MakeTestResult(sqFields),
+		},
+	}
+	pred, ast := correlatedPredicate(t, "not :__sq_has_values", outerFields)
+
+	cs := &CorrelatedSubquery{
+		Opcode:       PulloutExists,
+		HasValues:    "__sq_has_values",
+		Vars:         map[string]int{"col": 1},
+		Predicate:    pred,
+		ASTPredicate: ast,
+		Outer:        outer,
+		Subquery:     subquery,
+	}
+
+	r, err := cs.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	utils.MustMatch(t, []sqltypes.Row{{sqltypes.NewInt64(2), sqltypes.NewVarChar("b")}}, r.Rows)
+}
+
+func TestCorrelatedSubqueryBatchedValue(t *testing.T) {
+	outerFields := sqltypes.MakeTestFields("id|col", "int64|varchar")
+	outer := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(outerFields, "1|a", "2|b", "3|null", "4|a"),
+		},
+	}
+	subquery := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("val|col", "int64|varchar"), "10|a", "20|b"),
+		},
+	}
+
+	cs := &CorrelatedSubquery{
+		Opcode:         PulloutValue,
+		SubqueryResult: "__sq1",
+		BatchVar:       "__sq_batch",
+		OuterKey:       1,
+		SubqueryKey:    1,
+		Collation:      collations.CollationBinaryID,
+		ComparisonType: sqltypes.VarBinary,
+		CollationEnv:   collations.MySQL8(),
+		Outer:          outer,
+		Subquery:       subquery,
+	}
+
+	r, err := cs.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	subquery.ExpectLog(t, []string{
+		`Execute __sq_batch: type:TUPLE values:{type:VARCHAR value:"a"} values:{type:VARCHAR value:"b"} false`,
+	})
+	utils.MustMatch(t, []sqltypes.Row{
+		{sqltypes.NewInt64(10), sqltypes.NewInt64(1), sqltypes.NewVarChar("a")},
+		{sqltypes.NewInt64(20), sqltypes.NewInt64(2), sqltypes.NewVarChar("b")},
+		{sqltypes.NULL, sqltypes.NewInt64(3), sqltypes.NULL},
+		{sqltypes.NewInt64(10), sqltypes.NewInt64(4), sqltypes.NewVarChar("a")},
+	}, r.Rows)
+}
+
+func TestCorrelatedSubqueryBatchedNoKeys(t *testing.T) {
+	outerFields := sqltypes.MakeTestFields("id|col", "int64|varchar")
+	outer := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(outerFields, "1|null"),
+		},
+	}
+	subquery := &fakePrimitive{}
+	pred, ast := correlatedPredicate(t, "not :__sq_has_values", outerFields)
+
+	cs := &CorrelatedSubquery{
+		Opcode:         PulloutExists,
+		HasValues:      "__sq_has_values",
+		Predicate:      pred,
+		ASTPredicate:   ast,
+		BatchVar:       "__sq_batch",
+		OuterKey:       1,
+		Collation:      collations.CollationBinaryID,
+		ComparisonType: sqltypes.VarBinary,
+		CollationEnv:   collations.MySQL8(),
+		Outer:          outer,
+		Subquery:       subquery,
+	}
+
+	r, err := cs.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	subquery.ExpectLog(t, nil)
+	utils.MustMatch(t, []sqltypes.Row{{sqltypes.NewInt64(1), sqltypes.NULL}}, r.Rows)
+}
+
+func TestCorrelatedSubqueryValueBadRows(t *testing.T) {
+	outer := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("id|col", "int64|varchar"), "1|a"),
+		},
+	}
+	subquery := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("val", "int64"), "1", "2"),
+		},
+	}
+
+	cs := &CorrelatedSubquery{
+		Opcode:         PulloutValue,
+		SubqueryResult: "__sq1",
+		Vars:           map[string]int{"col": 1},
+		Outer:          outer,
+		Subquery:       subquery,
+	}
+
+	_, err := cs.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.EqualError(t, err, "subquery returned more than one row")
+}
+
+func TestCorrelatedSubqueryStream(t *testing.T) {
+	outerFields := sqltypes.MakeTestFields("id|col", "int64|varchar")
+	outer := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(outerFields, "1|a"),
+			sqltypes.MakeTestResult(outerFields, "2|b"),
+		},
+		allResultsInOneCall: true,
+	}
+	sqFields := sqltypes.MakeTestFields("val", "int64")
+	subquery := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqFields, "10"),
+			sqltypes.MakeTestResult(sqFields, "20"),
+		},
+	}
+
+	cs := &CorrelatedSubquery{
+		Opcode:         PulloutValue,
+		SubqueryResult: "__sq1",
+		Vars:           map[string]int{"col": 1},
+		Outer:          outer,
+		Subquery:       subquery,
+	}
+
+	r, err := wrapStreamExecute(cs, &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	subquery.ExpectLog(t, []string{
+		`Execute col: type:VARCHAR value:"a" false`,
+		`Execute col: type:VARCHAR value:"b" false`,
+	})
+	utils.MustMatch(t, []sqltypes.Row{
+		{sqltypes.NewInt64(10), sqltypes.NewInt64(1), sqltypes.NewVarChar("a")},
+		{sqltypes.NewInt64(20), sqltypes.NewInt64(2), sqltypes.NewVarChar("b")},
+	}, r.Rows)
+}
+
+func TestCorrelatedSubqueryGetFields(t *testing.T) {
+	outer := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("id|col", "int64|varchar")),
+		},
+	}
+	subquery := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("val|col", "int64|varchar")),
+		},
+	}
+
+	cs := &CorrelatedSubquery{
+		Opcode:         PulloutValue,
+		SubqueryResult: "__sq1",
+		BatchVar:       "__sq_batch",
+		OuterKey:       1,
+		SubqueryKey:    1,
+		Outer:          outer,
+		Subquery:       subquery,
+	}
+
+	r, err := cs.GetFields(context.Background(), &noopVCursor{}, nil)
+	require.NoError(t, err)
+	subquery.ExpectLog(t, []string{
+		`GetFields __sq_batch: type:TUPLE values:{}`,
+		`Execute __sq_batch: type:TUPLE values:{} true`,
+	})
+	utils.MustMatch(t, sqltypes.MakeTestFields("val|id|col", "int64|int64|varchar"), r.Fields)
+}
+
+func TestCorrelatedSubqueryExistsValue(t *testing.T) {
+	outerFields := sqltypes.MakeTestFields("id|col", "int64|varchar")
+	outer := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(outerFields, "1|a", "2|b"),
+		},
+	}
+	sqFields := sqltypes.MakeTestFields("1", "int64")
+	subquery := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqFields, "1"),
+			sqltypes.MakeTestResult(sqFields),
+		},
+	}
+
+	cs := &CorrelatedSubquery{
+		Opcode:         PulloutExists,
+		SubqueryResult: "__sq1",
+		Vars:           map[string]int{"col": 1},
+		Outer:          outer,
+		Subquery:       subquery,
+	}
+
+	r, err := cs.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, sqltypes.MakeTestResult(sqltypes.MakeTestFields("__sq1|id|col", "int64|int64|varchar"), "1|1|a", "0|2|b"), r)
+}
@@ -67,6 +67,18 @@ func (pt *probeTable) exists(inputRow sqltypes.Row) (sqltypes.Row, error) {
 	return inputRow, nil
 }
 
+// spill writes the row to disk, unless we have already seen it
+func (pt *probeTable) spill(partitions *hashPartitions, inputRow sqltypes.Row) error {
+	code, err := pt.hashCodeForRow(inputRow)
+	if err != nil {
+		return err
+	}
+	if _, found := pt.seenRows[code]; found {
+		return nil
+	}
+	return partitions.add(code, inputRow)
+}
+
 func (pt *probeTable) hashCodeForRow(inputRow sqltypes.Row) (vthash.Hash, error) {
 	hasher := vthash.New()
 	for i, checkCol := range pt.checkCols {
@@ -102,6 +114,10 @@ func newProbeTable(checkCols []CheckCol, collationEnv *collations.Environment) *
 
 // TryExecute implements the Primitive interface
 func (d *Distinct) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if vcursor.SpillDir() != "" {
+		return executeSpilling(ctx, vcursor, bindVars, wantfields, d.TryStreamExecute)
+	}
+
 	input, err := vcursor.ExecutePrimitive(ctx, d.Source, bindVars, wantfields)
 	if err != nil {
 		return nil, err
@@ -133,6 +149,15 @@ func (d *Distinct) TryExecute(ctx context.Context, vcursor VCursor, bindVars map
 func (d *Distinct) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
 	var mu sync.Mutex
 
+	// once the probe table is full, the rows we have not seen yet are spilled to disk,
+	// and de-duplicated one partition at a time when the input is done
+	var spilled *hashPartitions
+	defer func() {
+		if spilled != nil {
+			spilled.close()
+		}
+	}()
+
 	pt := newProbeTable(d.CheckCols, vcursor.Environment().CollationEnv())
 	err := vcursor.StreamExecutePrimitive(ctx, d.Source, bindVars, wantfields, func(input *sqltypes.Result) error {
 		result := &sqltypes.Result{
@@ -142,6 +167,12 @@ func (d *Distinct) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVa
 		mu.Lock()
 		defer mu.Unlock()
 		for _, row := range input.Rows {
+			if spilled != nil {
+				if err := pt.spill(spilled, row); err != nil {
+					return err
+				}
+				continue
+			}
 			appendRow, err := pt.exists(row)
 			if err != nil {
 				return err
@@ -149,11 +180,68 @@ func (d *Distinct) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVa
 			if appendRow != nil {
 				result.Rows = append(result.Rows, appendRow)
 			}
+			if canSpill(vcursor, len(pt.seenRows)) {
+				spilled = newHashPartitions(vcursor.SpillDir())
+			}
 		}
 		return callback(result.Truncate(len(d.CheckCols)))
 	})
+	if err != nil || spilled == nil {
+		return err
+	}
 
-	return err
+	stats := spilled.stats()
+	stats.report(vcursor)
+	return d.dedupPartitions(vcursor, spilled, pt, callback)
+}
+
+// dedupPartitions de-duplicates the spilled rows one partition at a time. A partition that
+// holds too many rows to be de-duplicated in memory is split again first.
+func (d *Distinct) dedupPartitions(vcursor VCursor, spilled *hashPartitions, pt *probeTable, callback func(*sqltypes.Result) error) error {
+	for i := 0; i < spillPartitions; i++ {
+		if spilled.needsSplit(vcursor, i) {
+			sub, err := spilled.split(vcursor, i, pt.hashCodeForRow)
+			if err != nil {
+				return err
+			}
+			err = d.dedupPartitions(vcursor, sub, pt, callback)
+			sub.close()
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		partPT := newProbeTable(pt.checkCols, pt.collationEnv)
+		result := &sqltypes.Result{}
+		err := spilled.forEach(i, func(row sqltypes.Row) error {
+			appendRow, err := partPT.exists(row)
+			if err != nil || appendRow == nil {
+				return err
+			}
+			// only rows whose hashes share all the bits used by the partitions get here,
+			// so this can only happen with a huge number of distinct rows
+			if vcursor.ExceedsMaxMemoryRows(len(partPT.seenRows)) {
+				return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+			}
+			result.Rows = append(result.Rows, appendRow)
+			if len(result.Rows) < spillBatchRows {
+				return nil
+			}
+			err = callback(result.Truncate(len(d.CheckCols)))
+			result = &sqltypes.Result{}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if len(result.Rows) > 0 {
+			if err := callback(result.Truncate(len(d.CheckCols))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 // RouteType implements the Primitive interface
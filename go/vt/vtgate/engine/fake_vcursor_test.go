@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"reflect"
 	"slices"
 	"sort"
@@ -49,6 +50,8 @@ import (
 var (
 	testMaxMemoryRows       = 100
 	testIgnoreMaxMemoryRows = false
+	testSpillDir            = ""
+	testSpilledRows         = 0
 )
 
 var (
@@ -116,6 +119,10 @@ func (t *noopVCursor) GetWarmingReadsChannel() chan bool {
 	panic("implement me")
 }
 
+func (t *noopVCursor) SnowflakeGenerator() *SnowflakeGenerator {
+	panic("implement me")
+}
+
 func (t *noopVCursor) CloneForReplicaWarming(ctx context.Context) VCursor {
 	panic("implement me")
 }
@@ -167,6 +174,14 @@ func (t *noopVCursor) SQLMode() string {
 	return config.DefaultSQLMode
 }
 
+func (t *noopVCursor) GroupConcatMaxLen() uint64 {
+	return config.DefaultGroupConcatMaxLen
+}
+
+func (t *noopVCursor) BlockEncryptionMode() string {
+	return config.DefaultBlockEncryptionMode
+}
+
 func (t *noopVCursor) ExecutePrimitive(ctx context.Context, primitive Primitive, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
 	return primitive.TryExecute(ctx, t, bindVars, wantfields)
 }
@@ -309,6 +324,9 @@ func (t *noopVCursor) SetClientFoundRows(context.Context, bool) error {
 func (t *noopVCursor) SetQueryTimeout(maxExecutionTime int64) {
 }
 
+func (t *noopVCursor) SetMaxExecutionTime(int64) {
+}
+
 func (t *noopVCursor) SetSkipQueryPlanCache(context.Context, bool) error {
 	panic("implement me")
 }
@@ -353,6 +371,14 @@ func (t *noopVCursor) ExceedsMaxMemoryRows(numRows int) bool {
 	return !testIgnoreMaxMemoryRows && numRows > testMaxMemoryRows
 }
 
+func (t *noopVCursor) SpillDir() string {
+	return testSpillDir
+}
+
+func (t *noopVCursor) RecordSpill(rows, bytes int) {
+	testSpilledRows += rows
+}
+
 func (t *noopVCursor) GetKeyspace() string {
 	return "test_ks"
 }
@@ -364,6 +390,10 @@ func (t *noopVCursor) Execute(ctx context.Context, method string, query string,
 	panic("unimplemented")
 }
 
+func (t *noopVCursor) ReadLocalInfile(ctx context.Context, fileName string) (io.ReadCloser, error) {
+	panic("unimplemented")
+}
+
 func (t *noopVCursor) ExecuteMultiShard(ctx context.Context, primitive Primitive, rss []*srvtopo.ResolvedShard, queries []*querypb.BoundQuery, rollbackOnError, canAutocommit bool) (*sqltypes.Result, []error) {
 	panic("unimplemented")
 }
@@ -434,11 +464,18 @@ type loggingVCursor struct {
 	systemVariables map[string]string
 	disableSetVar   bool
 
+	groupConcatMaxLen uint64
+
+	// localInfile is the content of the file the client sends for LOAD DATA LOCAL INFILE
+	localInfile string
+
 	// map different shards to keyspaces in the test.
 	ksShardMap map[string][]string
 
 	shardSession []*srvtopo.ResolvedShard
 
+	snowflake *SnowflakeGenerator
+
 	parser *sqlparser.Parser
 
 	handleMirrorClonesFn   func(context.Context) VCursor
@@ -455,6 +492,13 @@ func (f *loggingVCursor) Commit(_ context.Context) error {
 	return nil
 }
 
+func (f *loggingVCursor) GroupConcatMaxLen() uint64 {
+	if f.groupConcatMaxLen == 0 {
+		return f.noopVCursor.GroupConcatMaxLen()
+	}
+	return f.groupConcatMaxLen
+}
+
 func (f *loggingVCursor) GetUDV(key string) *querypb.BindVariable {
 	// TODO implement me
 	panic("implement me")
@@ -555,6 +599,10 @@ func (f *loggingVCursor) GetWarmingReadsChannel() chan bool {
 	return make(chan bool)
 }
 
+func (f *loggingVCursor) SnowflakeGenerator() *SnowflakeGenerator {
+	return f.snowflake
+}
+
 func (f *loggingVCursor) CloneForReplicaWarming(ctx context.Context) VCursor {
 	return f
 }
@@ -566,6 +614,11 @@ func (f *loggingVCursor) CloneForMirroring(ctx context.Context) VCursor {
 	panic("no mirror clones available")
 }
 
+func (f *loggingVCursor) ReadLocalInfile(ctx context.Context, fileName string) (io.ReadCloser, error) {
+	f.log = append(f.log, "ReadLocalInfile "+fileName)
+	return io.NopCloser(strings.NewReader(f.localInfile)), nil
+}
+
 func (f *loggingVCursor) Execute(ctx context.Context, method string, query string, bindvars map[string]*querypb.BindVariable, rollbackOnError bool, co vtgatepb.CommitOrder) (*sqltypes.Result, error) {
 	name := "Unknown"
 	switch co {
@@ -103,7 +103,7 @@ func (fkc *FkCascade) TryExecute(ctx context.Context, vcursor VCursor, bindVars
 		if len(child.NonLiteralInfo) > 0 {
 			err = fkc.executeNonLiteralExprFkChild(ctx, vcursor, bindVars, wantfields, selectionRes, child)
 		} else {
-			err = fkc.executeLiteralExprFkChild(ctx, vcursor, bindVars, wantfields, selectionRes, child, false)
+			err = executeLiteralExprFkChild(ctx, vcursor, bindVars, wantfields, selectionRes, child, false)
 		}
 		if err != nil {
 			return nil, err
@@ -114,7 +114,7 @@ func (fkc *FkCascade) TryExecute(ctx context.Context, vcursor VCursor, bindVars
 	return vcursor.ExecutePrimitive(ctx, fkc.Parent, bindVars, wantfields)
 }
 
-func (fkc *FkCascade) executeLiteralExprFkChild(ctx context.Context, vcursor VCursor, in map[string]*querypb.BindVariable, wantfields bool, selectionRes *sqltypes.Result, child *FkChild, isStreaming bool) error {
+func executeLiteralExprFkChild(ctx context.Context, vcursor VCursor, in map[string]*querypb.BindVariable, wantfields bool, selectionRes *sqltypes.Result, child *FkChild, isStreaming bool) error {
 	bindVars := maps.Clone(in)
 	// We create a bindVariable that stores the tuple of columns involved in the fk constraint.
 	bv := &querypb.BindVariable{
@@ -36,9 +36,9 @@ var _ Primitive = (*HashJoin)(nil)
 type (
 	// HashJoin specifies the parameters for a join primitive
 	// Hash joins work by fetch all the input from the LHS, and building a hash map, known as the probe table, for this input.
-	// The key to the map is the hashcode of the value for column that we are joining by.
+	// The key to the map is the hashcode of the values for the columns that we are joining by.
 	// Then the RHS is fetched, and we can check if the rows from the RHS matches any from the LHS.
-	// When they match by hash code, we double-check that we are not working with a false positive by comparing the values.
+	// When they match by hash code, the residual predicate, if any, decides if the two rows should be joined.
 	HashJoin struct {
 		Opcode JoinOpcode
 
@@ -55,19 +55,31 @@ type (
 		// the returned result will be {Left0, Left1, Right0, Right1}.
 		Cols []int
 
-		// The keys correspond to the column offset in the inputs where
-		// the join columns can be found
-		LHSKey, RHSKey int
+		// Keys are the column pairs that are hashed together. Two rows
+		// can only be joined if all their keys compare equal.
+		Keys []HashJoinKey
+
+		// Residual is an optional predicate that has to be true for two rows
+		// with matching keys to be joined. It is evaluated against a row built
+		// from ResidualCols, which uses the same encoding as Cols.
+		Residual     evalengine.Expr
+		ResidualCols []int
 
 		// The join condition. Used for plan descriptions
 		ASTPred sqlparser.Expr
 
+		CollationEnv *collations.Environment
+	}
+
+	// HashJoinKey is a pair of columns, one from each side, that a HashJoin compares by
+	HashJoinKey struct {
+		// LHS and RHS are the column offsets in the inputs where the join columns can be found
+		LHS, RHS int
+
 		// collation and type are used to hash the incoming values correctly
 		Collation      collations.ID
 		ComparisonType querypb.Type
 
-		CollationEnv *collations.Environment
-
 		// Values for enum and set types
 		Values *evalengine.EnumSetValues
 	}
@@ -75,13 +87,23 @@ type (
 	hashJoinProbeTable struct {
 		innerMap map[vthash.Hash]*probeTableEntry
 
-		coll           collations.ID
-		typ            querypb.Type
-		lhsKey, rhsKey int
-		cols           []int
-		hasher         vthash.Hasher
-		sqlmode        evalengine.SQLMode
-		values         *evalengine.EnumSetValues
+		keys         []HashJoinKey
+		cols         []int
+		residual     evalengine.Expr
+		residualCols []int
+		rightJoin    bool
+		env          *evalengine.ExpressionEnv
+		hasher       vthash.Hasher
+		sqlmode      evalengine.SQLMode
+
+		// rows is the number of LHS rows in the probe table
+		rows int
+	}
+
+	// hashJoinSpill holds the rows of both sides of a hash join that didn't fit in memory,
+	// partitioned on the hash of the join keys
+	hashJoinSpill struct {
+		left, right *hashPartitions
 	}
 
 	probeTableEntry struct {
@@ -93,12 +115,16 @@ type (
 
 // TryExecute implements the Primitive interface
 func (hj *HashJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if vcursor.SpillDir() != "" {
+		return executeSpilling(ctx, vcursor, bindVars, wantfields, hj.TryStreamExecute)
+	}
+
 	lresult, err := vcursor.ExecutePrimitive(ctx, hj.Left, bindVars, wantfields)
 	if err != nil {
 		return nil, err
 	}
 
-	pt := newHashJoinProbeTable(hj.Collation, hj.ComparisonType, hj.LHSKey, hj.RHSKey, hj.Cols, hj.Values)
+	pt := hj.newProbeTable(evalengine.NewExpressionEnv(ctx, bindVars, vcursor))
 	// build the probe table from the LHS result
 	for _, row := range lresult.Rows {
 		err := pt.addLeftRow(row)
@@ -134,9 +160,20 @@ func (hj *HashJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars ma
 // TryStreamExecute implements the Primitive interface
 func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
 	// build the probe table from the LHS result
-	pt := newHashJoinProbeTable(hj.Collation, hj.ComparisonType, hj.LHSKey, hj.RHSKey, hj.Cols, hj.Values)
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	pt := hj.newProbeTable(env)
 	var lfields []*querypb.Field
 	var mu sync.Mutex
+
+	// if the LHS doesn't fit in memory, both sides are partitioned on disk using
+	// the hash of the join keys, and we join one partition at a time
+	var spilled *hashJoinSpill
+	defer func() {
+		if spilled != nil {
+			spilled.close()
+		}
+	}()
+
 	err := vcursor.StreamExecutePrimitive(ctx, hj.Left, bindVars, wantfields, func(result *sqltypes.Result) error {
 		mu.Lock()
 		defer mu.Unlock()
@@ -144,10 +181,22 @@ func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 			lfields = result.Fields
 		}
 		for _, current := range result.Rows {
+			if spilled != nil {
+				if err := spilled.addLeft(pt, current); err != nil {
+					return err
+				}
+				continue
+			}
 			err := pt.addLeftRow(current)
 			if err != nil {
 				return err
 			}
+			if canSpill(vcursor, pt.rows) {
+				spilled, err = pt.spill(vcursor.SpillDir())
+				if err != nil {
+					return err
+				}
+			}
 		}
 		return nil
 	})
@@ -167,6 +216,15 @@ func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 			res.Fields = joinFields(lfields, result.Fields, hj.Cols)
 		}
 		for _, currentRHSRow := range result.Rows {
+			if spilled != nil {
+				// rows with NULL keys can't match anything, so we don't need to spill them
+				if pt.hasNullKey(currentRHSRow) {
+					res.Rows = append(res.Rows, pt.unmatched(currentRHSRow, nil)...)
+				} else if err := spilled.addRight(pt, currentRHSRow); err != nil {
+					return err
+				}
+				continue
+			}
 			results, err := pt.get(currentRHSRow)
 			if err != nil {
 				return err
@@ -182,6 +240,19 @@ func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 		return err
 	}
 
+	if spilled != nil {
+		if sendFields.CompareAndSwap(true, false) {
+			rres, err := hj.Right.GetFields(ctx, vcursor, bindVars)
+			if err != nil {
+				return err
+			}
+			if err := callback(&sqltypes.Result{Fields: joinFields(lfields, rres.Fields, hj.Cols)}); err != nil {
+				return err
+			}
+		}
+		return hj.joinSpilledPartitions(vcursor, env, spilled, callback)
+	}
+
 	if hj.Opcode == LeftJoin {
 		res := &sqltypes.Result{}
 		if sendFields.CompareAndSwap(true, false) {
@@ -251,11 +322,25 @@ func (hj *HashJoin) description() PrimitiveDescription {
 		"TableName":         hj.GetTableName(),
 		"JoinColumnIndexes": strings.Trim(strings.Join(strings.Fields(fmt.Sprint(hj.Cols)), ","), "[]"),
 		"Predicate":         sqlparser.String(hj.ASTPred),
-		"ComparisonType":    hj.ComparisonType.String(),
 	}
-	coll := hj.Collation
-	if coll != collations.Unknown {
-		other["Collation"] = hj.CollationEnv.LookupName(coll)
+	var types, colls []string
+	var hasColl bool
+	for _, key := range hj.Keys {
+		types = append(types, key.ComparisonType.String())
+		coll := "-"
+		if key.Collation != collations.Unknown {
+			coll = hj.CollationEnv.LookupName(key.Collation)
+			hasColl = true
+		}
+		colls = append(colls, coll)
+	}
+	other["ComparisonType"] = strings.Join(types, ", ")
+	if hasColl {
+		other["Collation"] = strings.Join(colls, ", ")
+	}
+	if hj.Residual != nil {
+		other["ResidualColumnIndexes"] = strings.Trim(strings.Join(strings.Fields(fmt.Sprint(hj.ResidualCols)), ","), "[]")
+		other["Residual"] = sqlparser.String(hj.Residual)
 	}
 	return PrimitiveDescription{
 		OperatorType: "Join",
@@ -264,21 +349,21 @@ func (hj *HashJoin) description() PrimitiveDescription {
 	}
 }
 
-func newHashJoinProbeTable(coll collations.ID, typ querypb.Type, lhsKey, rhsKey int, cols []int, values *evalengine.EnumSetValues) *hashJoinProbeTable {
+func (hj *HashJoin) newProbeTable(env *evalengine.ExpressionEnv) *hashJoinProbeTable {
 	return &hashJoinProbeTable{
-		innerMap: map[vthash.Hash]*probeTableEntry{},
-		coll:     coll,
-		typ:      typ,
-		lhsKey:   lhsKey,
-		rhsKey:   rhsKey,
-		cols:     cols,
-		hasher:   vthash.New(),
-		values:   values,
+		innerMap:     map[vthash.Hash]*probeTableEntry{},
+		keys:         hj.Keys,
+		cols:         hj.Cols,
+		residual:     hj.Residual,
+		residualCols: hj.ResidualCols,
+		rightJoin:    hj.Opcode == RightJoin,
+		env:          env,
+		hasher:       vthash.New(),
 	}
 }
 
 func (pt *hashJoinProbeTable) addLeftRow(r sqltypes.Row) error {
-	hash, err := pt.hash(r[pt.lhsKey])
+	hash, err := pt.hash(r, true)
 	if err != nil {
 		return err
 	}
@@ -286,38 +371,290 @@ func (pt *hashJoinProbeTable) addLeftRow(r sqltypes.Row) error {
 		row:  r,
 		next: pt.innerMap[hash],
 	}
+	pt.rows++
 
 	return nil
 }
 
-func (pt *hashJoinProbeTable) hash(val sqltypes.Value) (vthash.Hash, error) {
-	err := evalengine.NullsafeHashcode128(&pt.hasher, val, pt.coll, pt.typ, pt.sqlmode, pt.values)
+// spill moves all the rows in the probe table to disk
+func (pt *hashJoinProbeTable) spill(dir string) (*hashJoinSpill, error) {
+	spilled := &hashJoinSpill{
+		left:  newHashPartitions(dir),
+		right: newHashPartitions(dir),
+	}
+	for hash, e := range pt.innerMap {
+		for ; e != nil; e = e.next {
+			if err := spilled.left.add(hash, e.row); err != nil {
+				return nil, err
+			}
+		}
+	}
+	pt.innerMap = map[vthash.Hash]*probeTableEntry{}
+	pt.rows = 0
+	return spilled, nil
+}
+
+func (hs *hashJoinSpill) addLeft(pt *hashJoinProbeTable, row sqltypes.Row) error {
+	hash, err := pt.hash(row, true)
 	if err != nil {
-		return vthash.Hash{}, err
+		return err
 	}
+	return hs.left.add(hash, row)
+}
 
-	res := pt.hasher.Sum128()
-	pt.hasher.Reset()
-	return res, nil
+func (hs *hashJoinSpill) addRight(pt *hashJoinProbeTable, row sqltypes.Row) error {
+	hash, err := pt.hash(row, false)
+	if err != nil {
+		return err
+	}
+	return hs.right.add(hash, row)
 }
 
-func (pt *hashJoinProbeTable) get(rrow sqltypes.Row) (result []sqltypes.Row, err error) {
-	val := rrow[pt.rhsKey]
-	if val.IsNull() {
-		return
+// split splits the given partition of both sides into the partitions of the next level
+func (hs *hashJoinSpill) split(vcursor VCursor, pt *hashJoinProbeTable, idx int) (*hashJoinSpill, error) {
+	left, err := hs.left.split(vcursor, idx, func(row sqltypes.Row) (vthash.Hash, error) {
+		return pt.hash(row, true)
+	})
+	if err != nil {
+		return nil, err
 	}
+	right, err := hs.right.split(vcursor, idx, func(row sqltypes.Row) (vthash.Hash, error) {
+		return pt.hash(row, false)
+	})
+	if err != nil {
+		left.close()
+		return nil, err
+	}
+	return &hashJoinSpill{left: left, right: right}, nil
+}
+
+func (hs *hashJoinSpill) close() {
+	hs.left.close()
+	hs.right.close()
+}
 
-	hash, err := pt.hash(val)
+// joinSpilledPartitions joins the spilled rows one partition at a time. Matching rows
+// always end up in the same partition, so each partition can be joined on its own.
+func (hj *HashJoin) joinSpilledPartitions(vcursor VCursor, env *evalengine.ExpressionEnv, spilled *hashJoinSpill, callback func(*sqltypes.Result) error) error {
+	stats := spilled.left.stats()
+	rstats := spilled.right.stats()
+	stats.rows += rstats.rows
+	stats.bytes += rstats.bytes
+	stats.report(vcursor)
+
+	var rows []sqltypes.Row
+	emit := func(matches []sqltypes.Row) error {
+		rows = append(rows, matches...)
+		if len(rows) < spillBatchRows {
+			return nil
+		}
+		err := callback(&sqltypes.Result{Rows: rows})
+		rows = nil
+		return err
+	}
+	if err := hj.joinPartitions(vcursor, env, spilled, emit); err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return callback(&sqltypes.Result{Rows: rows})
+}
+
+// joinPartitions joins the partitions of the spill one after the other. When the LHS of a
+// partition holds too many rows to fit in memory, both sides of the partition are split
+// again, and when they cannot be split any further, the partition is joined in chunks.
+func (hj *HashJoin) joinPartitions(vcursor VCursor, env *evalengine.ExpressionEnv, spilled *hashJoinSpill, emit func([]sqltypes.Row) error) error {
+	for i := 0; i < spillPartitions; i++ {
+		if spilled.left.needsSplit(vcursor, i) {
+			sub, err := spilled.split(vcursor, hj.newProbeTable(env), i)
+			if err != nil {
+				return err
+			}
+			err = hj.joinPartitions(vcursor, env, sub, emit)
+			sub.close()
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if vcursor.ExceedsMaxMemoryRows(spilled.left.rows(i)) {
+			if err := hj.joinPartitionInChunks(vcursor, env, spilled, i, emit); err != nil {
+				return err
+			}
+			continue
+		}
+
+		pt := hj.newProbeTable(env)
+		if err := spilled.left.forEach(i, pt.addLeftRow); err != nil {
+			return err
+		}
+		err := spilled.right.forEach(i, func(row sqltypes.Row) error {
+			matches, err := pt.get(row)
+			if err != nil {
+				return err
+			}
+			return emit(matches)
+		})
+		if err != nil {
+			return err
+		}
+		if hj.Opcode == LeftJoin {
+			if err := emit(pt.notFetched()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// joinPartitionInChunks joins a partition whose LHS rows cannot be split any further, which
+// happens when too many of them share the same join keys. The LHS rows are loaded in memory
+// a chunk at a time, and the RHS rows of the partition are read again for every chunk.
+func (hj *HashJoin) joinPartitionInChunks(vcursor VCursor, env *evalengine.ExpressionEnv, spilled *hashJoinSpill, idx int, emit func([]sqltypes.Row) error) error {
+	// matched keeps track of the RHS rows that matched any chunk, so that a right join
+	// only returns the ones that matched none of them
+	var matched []bool
+	if hj.Opcode == RightJoin {
+		matched = make([]bool, spilled.right.rows(idx))
+	}
+
+	chunk := hj.newProbeTable(env)
+	joinChunk := func() error {
+		pos := 0
+		err := spilled.right.forEach(idx, func(row sqltypes.Row) error {
+			matches, err := chunk.matches(row)
+			if err != nil {
+				return err
+			}
+			if matched != nil && len(matches) > 0 {
+				matched[pos] = true
+			}
+			pos++
+			return emit(matches)
+		})
+		if err != nil {
+			return err
+		}
+		if hj.Opcode == LeftJoin {
+			if err := emit(chunk.notFetched()); err != nil {
+				return err
+			}
+		}
+		chunk = hj.newProbeTable(env)
+		return nil
+	}
+
+	err := spilled.left.forEach(idx, func(row sqltypes.Row) error {
+		if err := chunk.addLeftRow(row); err != nil {
+			return err
+		}
+		if vcursor.ExceedsMaxMemoryRows(chunk.rows) {
+			return joinChunk()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if chunk.rows > 0 {
+		if err := joinChunk(); err != nil {
+			return err
+		}
+	}
+	if matched == nil {
+		return nil
+	}
+
+	pos := 0
+	return spilled.right.forEach(idx, func(row sqltypes.Row) error {
+		pos++
+		if matched[pos-1] {
+			return nil
+		}
+		return emit(chunk.unmatched(row, nil))
+	})
+}
+
+// hash calculates the hash of all the key columns of the row
+func (pt *hashJoinProbeTable) hash(row sqltypes.Row, left bool) (vthash.Hash, error) {
+	defer pt.hasher.Reset()
+	for _, key := range pt.keys {
+		val := row[key.RHS]
+		if left {
+			val = row[key.LHS]
+		}
+		err := evalengine.NullsafeHashcode128(&pt.hasher, val, key.Collation, key.ComparisonType, pt.sqlmode, key.Values)
+		if err != nil {
+			return vthash.Hash{}, err
+		}
+	}
+
+	return pt.hasher.Sum128(), nil
+}
+
+func (pt *hashJoinProbeTable) get(rrow sqltypes.Row) ([]sqltypes.Row, error) {
+	result, err := pt.matches(rrow)
+	if err != nil {
+		return nil, err
+	}
+	return pt.unmatched(rrow, result), nil
+}
+
+// matches returns the RHS row joined with every LHS row of the probe table that it matches
+func (pt *hashJoinProbeTable) matches(rrow sqltypes.Row) (result []sqltypes.Row, err error) {
+	if pt.hasNullKey(rrow) {
+		return nil, nil
+	}
+
+	hash, err := pt.hash(rrow, false)
 	if err != nil {
 		return nil, err
 	}
 
 	for e := pt.innerMap[hash]; e != nil; e = e.next {
+		ok, err := pt.matchesResidual(e.row, rrow)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
 		e.seen = true
 		result = append(result, joinRows(e.row, rrow, pt.cols))
 	}
 
-	return
+	return result, nil
+}
+
+// unmatched adds the RHS row with nulls for the LHS columns when doing
+// a right join and the row did not match anything in the probe table
+func (pt *hashJoinProbeTable) unmatched(rrow sqltypes.Row, result []sqltypes.Row) []sqltypes.Row {
+	if !pt.rightJoin || len(result) > 0 {
+		return result
+	}
+	return []sqltypes.Row{joinRows(nil, rrow, pt.cols)}
+}
+
+func (pt *hashJoinProbeTable) hasNullKey(rrow sqltypes.Row) bool {
+	for _, key := range pt.keys {
+		if rrow[key.RHS].IsNull() {
+			return true
+		}
+	}
+	return false
+}
+
+func (pt *hashJoinProbeTable) matchesResidual(lrow, rrow sqltypes.Row) (bool, error) {
+	if pt.residual == nil {
+		return true, nil
+	}
+	pt.env.Row = joinRows(lrow, rrow, pt.residualCols)
+	res, err := pt.env.Evaluate(pt.residual)
+	if err != nil {
+		return false, err
+	}
+	return res.ToBoolean(), nil
 }
 
 func (pt *hashJoinProbeTable) notFetched() (rows []sqltypes.Row) {
@@ -136,13 +136,15 @@ func TestHashJoinVariations(t *testing.T) {
 		require.NoError(t, err)
 
 		jn := &HashJoin{
-			Opcode:         tc.typ,
-			Cols:           []int{-1, -2, 1, 2},
-			LHSKey:         tc.lhs,
-			RHSKey:         tc.rhs,
-			Collation:      typ.Collation(),
-			ComparisonType: typ.Type(),
-			CollationEnv:   collations.MySQL8(),
+			Opcode: tc.typ,
+			Cols:   []int{-1, -2, 1, 2},
+			Keys: []HashJoinKey{{
+				LHS:            tc.lhs,
+				RHS:            tc.rhs,
+				Collation:      typ.Collation(),
+				ComparisonType: typ.Type(),
+			}},
+			CollationEnv: collations.MySQL8(),
 		}
 
 		t.Run(tc.name, func(t *testing.T) {
@@ -172,3 +174,116 @@ func typeForOffset(i int) evalengine.Type {
 		panic(i)
 	}
 }
+
+func TestHashJoinCompositeKeysAndResidual(t *testing.T) {
+	lhs := func() Primitive {
+		return &fakePrimitive{
+			results: []*sqltypes.Result{
+				sqltypes.MakeTestResult(
+					sqltypes.MakeTestFields(
+						"a|b|lval",
+						"int64|varchar|int64",
+					),
+					"1|x|10",
+					"1|y|20",
+					"2|x|30",
+					"null|x|40",
+				),
+			},
+		}
+	}
+	rhs := func() Primitive {
+		return &fakePrimitive{
+			results: []*sqltypes.Result{
+				sqltypes.MakeTestResult(
+					sqltypes.MakeTestFields(
+						"c|d|rval",
+						"int64|varchar|int64",
+					),
+					"1|x|15",
+					"1|y|5",
+					"2|y|100",
+					"3|x|1",
+				),
+			},
+		}
+	}
+
+	rows := func(r ...string) []string { return r }
+	residual, ast := correlatedPredicate(t, "lval < rval", sqltypes.MakeTestFields("lval|rval", "int64|int64"))
+
+	tests := []struct {
+		name     string
+		typ      JoinOpcode
+		residual bool
+		expected []string
+	}{{
+		name:     "inner join",
+		typ:      InnerJoin,
+		expected: rows("1|x|1|x", "1|y|1|y"),
+	}, {
+		name:     "inner join with residual",
+		typ:      InnerJoin,
+		residual: true,
+		expected: rows("1|x|1|x"),
+	}, {
+		name:     "left join with residual",
+		typ:      LeftJoin,
+		residual: true,
+		expected: rows("1|x|1|x", "1|y|null|null", "2|x|null|null", "null|x|null|null"),
+	}, {
+		name:     "right join",
+		typ:      RightJoin,
+		expected: rows("1|x|1|x", "1|y|1|y", "null|null|2|y", "null|null|3|x"),
+	}, {
+		name:     "right join with residual",
+		typ:      RightJoin,
+		residual: true,
+		expected: rows("1|x|1|x", "null|null|1|y", "null|null|2|y", "null|null|3|x"),
+	}}
+
+	fields := sqltypes.MakeTestFields(
+		"a|b|c|d",
+		"int64|varchar|int64|varchar",
+	)
+	for _, tc := range tests {
+		jn := &HashJoin{
+			Opcode: tc.typ,
+			Cols:   []int{-1, -2, 1, 2},
+			Keys: []HashJoinKey{{
+				LHS:            0,
+				RHS:            0,
+				Collation:      collations.CollationBinaryID,
+				ComparisonType: sqltypes.Int64,
+			}, {
+				LHS:            1,
+				RHS:            1,
+				Collation:      collations.MySQL8().DefaultConnectionCharset(),
+				ComparisonType: sqltypes.VarChar,
+			}},
+			CollationEnv: collations.MySQL8(),
+		}
+		if tc.residual {
+			jn.Residual = residual
+			jn.ResidualCols = []int{-3, 3}
+			jn.ASTPred = ast
+		}
+
+		expected := sqltypes.MakeTestResult(fields, tc.expected...)
+
+		t.Run(tc.name, func(t *testing.T) {
+			jn.Left = lhs()
+			jn.Right = rhs()
+			r, err := jn.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+			require.NoError(t, err)
+			expectResultAnyOrder(t, r, expected)
+		})
+		t.Run("Streaming "+tc.name, func(t *testing.T) {
+			jn.Left = lhs()
+			jn.Right = rhs()
+			r, err := wrapStreamExecute(jn, &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+			require.NoError(t, err)
+			expectResultAnyOrder(t, r, expected)
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
@@ -30,6 +31,7 @@ import (
 	"vitess.io/vitess/go/vt/key"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
@@ -87,6 +89,9 @@ type (
 	// Generate represents the instruction to generate
 	// a value from a sequence.
 	Generate struct {
+		// Mode is the vindexes.AutoIncrement mode of the column.
+		// It is empty for the default sequence mode.
+		Mode     string
 		Keyspace *vindexes.Keyspace
 		Query    string
 		// Values are the supplied values for the column, which
@@ -371,25 +376,25 @@ func (ic *InsertCommon) processGenerateFromSelect(
 		return 0, nil
 	}
 
-	insertID, err = ic.execGenerate(ctx, vcursor, loggingPrimitive, count)
+	ids, err := ic.execGenerate(ctx, vcursor, loggingPrimitive, count)
 	if err != nil {
 		return 0, err
 	}
 
-	used := insertID
+	used := 0
 	for idx, val := range rows {
 		if genColPresent {
 			if shouldGenerate(val[offset], evalengine.ParseSQLMode(vcursor.SQLMode())) {
-				val[offset] = sqltypes.NewInt64(used)
+				val[offset] = sqltypes.NewInt64(ids[used])
 				used++
 			}
 		} else {
-			rows[idx] = append(val, sqltypes.NewInt64(used))
+			rows[idx] = append(val, sqltypes.NewInt64(ids[used]))
 			used++
 		}
 	}
 
-	return insertID, nil
+	return ids[0], nil
 }
 
 // processGenerateFromValues generates new values using a sequence if necessary.
@@ -421,19 +426,21 @@ func (ic *InsertCommon) processGenerateFromValues(
 	}
 
 	// If generation is needed, generate the requested number of values (as one call).
+	var ids []int64
 	if count != 0 {
-		insertID, err = ic.execGenerate(ctx, vcursor, loggingPrimitive, count)
+		ids, err = ic.execGenerate(ctx, vcursor, loggingPrimitive, count)
 		if err != nil {
 			return 0, err
 		}
+		insertID = ids[0]
 	}
 
 	// Fill the holes where no value was supplied.
-	cur := insertID
+	used := 0
 	for i, v := range values {
 		if shouldGenerate(v, evalengine.ParseSQLMode(vcursor.SQLMode())) {
-			bindVars[SeqVarName+strconv.Itoa(i)] = sqltypes.Int64BindVariable(cur)
-			cur++
+			bindVars[SeqVarName+strconv.Itoa(i)] = sqltypes.Int64BindVariable(ids[used])
+			used++
 		} else {
 			bindVars[SeqVarName+strconv.Itoa(i)] = sqltypes.ValueBindVariable(v)
 		}
@@ -441,17 +448,85 @@ func (ic *InsertCommon) processGenerateFromValues(
 	return insertID, nil
 }
 
-func (ic *InsertCommon) execGenerate(ctx context.Context, vcursor VCursor, loggingPrimitive Primitive, count int64) (int64, error) {
+// execGenerate generates count values, and returns them in the order in which
+// they should be used.
+func (ic *InsertCommon) execGenerate(ctx context.Context, vcursor VCursor, loggingPrimitive Primitive, count int64) ([]int64, error) {
+	switch ic.Generate.Mode {
+	case vindexes.AutoIncrementSnowflake, vindexes.AutoIncrementShardedSequence:
+		// Both modes rely on the node id to keep the values of different vtgates apart,
+		// so we refuse to generate anything until it was set explicitly.
+		if vcursor.SnowflakeGenerator() == nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the %s auto_increment mode needs a node id that is unique to this vtgate, set it with --sequence-node-id", ic.Generate.Mode)
+		}
+	}
+	switch ic.Generate.Mode {
+	case vindexes.AutoIncrementSnowflake:
+		return vcursor.SnowflakeGenerator().Next(count)
+	case vindexes.AutoIncrementShardedSequence:
+		return ic.execShardedSequence(ctx, vcursor, loggingPrimitive, count)
+	}
+
 	// If generation is needed, generate the requested number of values (as one call).
 	rss, _, err := vcursor.ResolveDestinations(ctx, ic.Generate.Keyspace.Name, nil, []key.Destination{key.DestinationAnyShard{}})
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	if len(rss) != 1 {
-		return 0, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "auto sequence generation can happen through single shard only, it is getting routed to %d shards", len(rss))
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "auto sequence generation can happen through single shard only, it is getting routed to %d shards", len(rss))
+	}
+	start, err := ic.nextSequenceValue(ctx, vcursor, loggingPrimitive, rss[0], count)
+	if err != nil {
+		return nil, err
+	}
+	return sequenceValues(start, 1, count), nil
+}
+
+// sequenceValues returns the count values that start at start and are step apart.
+func sequenceValues(start, step, count int64) []int64 {
+	ids := make([]int64, count)
+	for i := range ids {
+		ids[i] = start + int64(i)*step
+	}
+	return ids
+}
+
+// shardedSequenceStride is the step between two values of a sharded sequence generated by
+// the same vtgate: the values of node id n are v*shardedSequenceStride+n.
+const shardedSequenceStride = SnowflakeMaxNodeID + 1
+
+// execShardedSequence generates count values from a sequence table that has
+// a row on every shard of a sharded keyspace. Every vtgate sticks to the shard
+// picked by its node id, which spreads the load of the vtgates over the shards,
+// and turns each value v handed out by the shard into v*1024+nodeID. Each node
+// id thus owns its own stripe of values, whatever the number of shards, and a
+// vtgate can only repeat a value if it gets the same v twice.
+//
+// That never happens while the shards are unchanged, since each shard hands out
+// growing values. After a reshard, the vtgates can pick a different shard, so
+// the sequence rows of the new shards have to start above the highest next_id
+// of the old shards, which keeps the values of every vtgate unique and growing.
+func (ic *InsertCommon) execShardedSequence(ctx context.Context, vcursor VCursor, loggingPrimitive Primitive, count int64) ([]int64, error) {
+	rss, _, err := vcursor.ResolveDestinations(ctx, ic.Generate.Keyspace.Name, nil, []key.Destination{key.DestinationAllShards{}})
+	if err != nil {
+		return nil, err
+	}
+	if len(rss) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "no shard found in keyspace %s to generate a sharded sequence value", ic.Generate.Keyspace.Name)
 	}
+	nodeID := vcursor.SnowflakeGenerator().NodeID()
+	val, err := ic.nextSequenceValue(ctx, vcursor, loggingPrimitive, rss[nodeID%int64(len(rss))], count)
+	if err != nil {
+		return nil, err
+	}
+	if val < 0 || val+count > math.MaxInt64/shardedSequenceStride {
+		return nil, vterrors.Errorf(vtrpcpb.Code_OUT_OF_RANGE, "sharded sequence value %d is out of range", val)
+	}
+	return sequenceValues(val*shardedSequenceStride+nodeID, shardedSequenceStride, count), nil
+}
+
+func (ic *InsertCommon) nextSequenceValue(ctx context.Context, vcursor VCursor, loggingPrimitive Primitive, rs *srvtopo.ResolvedShard, count int64) (int64, error) {
 	bindVars := map[string]*querypb.BindVariable{nextValBV: sqltypes.Int64BindVariable(count)}
-	qr, err := vcursor.ExecuteStandalone(ctx, loggingPrimitive, ic.Generate.Query, bindVars, rss[0])
+	qr, err := vcursor.ExecuteStandalone(ctx, loggingPrimitive, ic.Generate.Query, bindVars, rs)
 	if err != nil {
 		return 0, err
 	}
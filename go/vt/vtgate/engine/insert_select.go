@@ -48,6 +48,10 @@ type (
 		// VindexValueOffset stores the offset for each column in the ColumnVindex
 		// that will appear in the result set of the select query.
 		VindexValueOffset [][]int
+
+		// DeleteBeforeInsert are executed with the rows from the select query before they are inserted.
+		// REPLACE uses them to delete the existing rows that clash with the new rows on a primary or unique key.
+		DeleteBeforeInsert []*FkChild
 	}
 )
 
@@ -84,7 +88,20 @@ func newInsertSelect(
 }
 
 func (ins *InsertSelect) Inputs() ([]Primitive, []map[string]any) {
-	return []Primitive{ins.Input}, nil
+	if len(ins.DeleteBeforeInsert) == 0 {
+		return []Primitive{ins.Input}, nil
+	}
+	inputs := []Primitive{ins.Input}
+	inputsMap := []map[string]any{{inputName: "Selection"}}
+	for idx, del := range ins.DeleteBeforeInsert {
+		inputs = append(inputs, del.Exec)
+		inputsMap = append(inputsMap, map[string]any{
+			inputName: fmt.Sprintf("DeleteBeforeInsert-%d", idx+1),
+			"BvName":  del.BVName,
+			"Cols":    del.Cols,
+		})
+	}
+	return inputs, inputsMap
 }
 
 // RouteType returns a description of the query routing type used by the primitive
@@ -154,6 +171,9 @@ func (ins *InsertSelect) execInsertUnsharded(ctx context.Context, vcursor VCurso
 	if len(irr.rows) == 0 {
 		return &sqltypes.Result{}, nil
 	}
+	if err := ins.deleteBeforeInsert(ctx, vcursor, bindVars, irr.rows); err != nil {
+		return nil, err
+	}
 	return ins.insertIntoUnshardedTable(ctx, vcursor, bindVars, irr)
 }
 
@@ -202,7 +222,7 @@ func (ins *InsertSelect) executeInsertQueries(
 	queries []*querypb.BoundQuery,
 	insertID uint64,
 ) (*sqltypes.Result, error) {
-	autocommit := (len(rss) == 1 || ins.MultiShardAutocommit) && vcursor.AutocommitApproval()
+	autocommit := (len(rss) == 1 || ins.MultiShardAutocommit) && !ins.PreventAutoCommit && vcursor.AutocommitApproval()
 	err := allowOnlyPrimary(rss...)
 	if err != nil {
 		return nil, err
@@ -314,10 +334,24 @@ func (ins *InsertSelect) execInsertSharded(ctx context.Context, vcursor VCursor,
 	if len(result.rows) == 0 {
 		return &sqltypes.Result{}, nil
 	}
+	if err := ins.deleteBeforeInsert(ctx, vcursor, bindVars, result.rows); err != nil {
+		return nil, err
+	}
 
 	return ins.insertIntoShardedTable(ctx, vcursor, bindVars, result)
 }
 
+// deleteBeforeInsert runs the deletes that need the rows to insert, before they are inserted
+func (ins *InsertSelect) deleteBeforeInsert(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, rows []sqltypes.Row) error {
+	rowsRes := &sqltypes.Result{Rows: rows}
+	for _, del := range ins.DeleteBeforeInsert {
+		if err := executeLiteralExprFkChild(ctx, vcursor, bindVars, false, rowsRes, del, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (ins *InsertSelect) description() PrimitiveDescription {
 	other := ins.commonDesc()
 	other["TableName"] = ins.GetTableName()
@@ -354,10 +388,17 @@ func (ic *InsertCommon) commonDesc() map[string]any {
 	}
 
 	if ic.Generate != nil {
+		generate := ic.Generate.Query
+		switch {
+		case ic.Generate.Query == "":
+			generate = ic.Generate.Mode
+		case ic.Generate.Mode != "":
+			generate = ic.Generate.Mode + ":" + generate
+		}
 		if ic.Generate.Values == nil {
-			other["AutoIncrement"] = fmt.Sprintf("%s:Offset(%d)", ic.Generate.Query, ic.Generate.Offset)
+			other["AutoIncrement"] = fmt.Sprintf("%s:Offset(%d)", generate, ic.Generate.Offset)
 		} else {
-			other["AutoIncrement"] = fmt.Sprintf("%s:Values::%s", ic.Generate.Query, sqlparser.String(ic.Generate.Values))
+			other["AutoIncrement"] = fmt.Sprintf("%s:Values::%s", generate, sqlparser.String(ic.Generate.Values))
 		}
 	}
 	return other
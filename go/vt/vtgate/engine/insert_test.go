@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -173,6 +174,177 @@ func TestInsertUnshardedGenerate_Zeros(t *testing.T) {
 	expectResult(t, result, &sqltypes.Result{InsertID: 4})
 }
 
+func TestInsertUnshardedGenerateSnowflake(t *testing.T) {
+	ins := newQueryInsert(
+		InsertUnsharded,
+		&vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: false,
+		},
+		"dummy_insert",
+	)
+	ins.Generate = &Generate{
+		Mode: vindexes.AutoIncrementSnowflake,
+		Values: evalengine.NewTupleExpr(
+			evalengine.NewLiteralInt(1),
+			evalengine.NullExpr,
+			evalengine.NullExpr,
+		),
+	}
+
+	vc := newDMLTestVCursor("0")
+	vc.snowflake = newTestSnowflakeGenerator(t, 5, snowflakeEpoch.Add(time.Second))
+	vc.results = []*sqltypes.Result{{InsertID: 1}}
+
+	result, err := ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	// The ids are made of 1000ms since the epoch, node id 5, and counters 0 and 1.
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.0: dummy_insert {__seq0: type:INT64 value:"1" __seq1: type:INT64 value:"4194324480" __seq2: type:INT64 value:"4194324481"} true true`,
+	})
+	expectResult(t, result, &sqltypes.Result{InsertID: 4194324480})
+}
+
+func TestInsertSelectGenerateSnowflakeLargeBatch(t *testing.T) {
+	ic := &InsertCommon{
+		Generate: &Generate{
+			Mode:   vindexes.AutoIncrementSnowflake,
+			Offset: 1,
+		},
+	}
+
+	// More rows than a millisecond's worth of snowflake ids, half of which need one.
+	count := 2*SnowflakeMaxCount + 100
+	rows := make([]sqltypes.Row, 0, 2*count)
+	for i := range 2 * count {
+		id := sqltypes.NULL
+		if i%2 == 1 {
+			id = sqltypes.NewInt64(int64(i))
+		}
+		rows = append(rows, sqltypes.Row{sqltypes.NewVarChar("a"), id})
+	}
+
+	vc := newDMLTestVCursor("0")
+	vc.snowflake = newTestSnowflakeGenerator(t, 5, snowflakeEpoch.Add(time.Millisecond))
+	insertID, err := ic.processGenerateFromSelect(context.Background(), vc, nil, rows)
+	require.NoError(t, err)
+	require.EqualValues(t, 1<<22|5<<12, insertID)
+
+	prev := int64(0)
+	for i, row := range rows {
+		id, err := row[1].ToInt64()
+		require.NoError(t, err)
+		if i%2 == 1 {
+			require.EqualValues(t, i, id)
+			continue
+		}
+		require.Greater(t, id, prev)
+		prev = id
+	}
+	// The last generated id was borrowed from the third millisecond.
+	require.EqualValues(t, 3<<22|5<<12|99, prev)
+}
+
+func TestInsertUnshardedGenerateShardedSequence(t *testing.T) {
+	newInsert := func() *Insert {
+		ins := newQueryInsert(
+			InsertUnsharded,
+			&vindexes.Keyspace{
+				Name:    "ks",
+				Sharded: false,
+			},
+			"dummy_insert",
+		)
+		ins.Generate = &Generate{
+			Mode: vindexes.AutoIncrementShardedSequence,
+			Keyspace: &vindexes.Keyspace{
+				Name:    "ks2",
+				Sharded: true,
+			},
+			Query: "dummy_generate",
+			Values: evalengine.NewTupleExpr(
+				evalengine.NullExpr,
+				evalengine.NewLiteralInt(2),
+				evalengine.NullExpr,
+			),
+		}
+		return ins
+	}
+	nextval := func(val string) *sqltypes.Result {
+		return sqltypes.MakeTestResult(sqltypes.MakeTestFields("nextval", "int64"), val)
+	}
+
+	vc := newDMLTestVCursor("0")
+	vc.ksShardMap = map[string][]string{"ks2": {"-80", "80-"}}
+	vc.snowflake = newTestSnowflakeGenerator(t, 3, snowflakeEpoch)
+	vc.results = []*sqltypes.Result{nextval("5"), {InsertID: 1}}
+
+	result, err := newInsert().TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		// Node id 3 picks the second of the two shards, and its values are v*1024+3.
+		`ResolveDestinations ks2 [] Destinations:DestinationAllShards()`,
+		`ExecuteStandalone dummy_generate n: type:INT64 value:"2" ks2 80-`,
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.0: dummy_insert {__seq0: type:INT64 value:"5123" __seq1: type:INT64 value:"2" __seq2: type:INT64 value:"6147"} true true`,
+	})
+	expectResult(t, result, &sqltypes.Result{InsertID: 5123})
+
+	// After a reshard to four shards, node id 3 picks the last shard, whose sequence
+	// starts above the next_id of the old shards, so its values keep growing.
+	vc = newDMLTestVCursor("0")
+	vc.ksShardMap = map[string][]string{"ks2": {"-40", "40-80", "80-c0", "c0-"}}
+	vc.snowflake = newTestSnowflakeGenerator(t, 3, snowflakeEpoch)
+	vc.results = []*sqltypes.Result{nextval("7"), {InsertID: 1}}
+
+	result, err = newInsert().TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks2 [] Destinations:DestinationAllShards()`,
+		`ExecuteStandalone dummy_generate n: type:INT64 value:"2" ks2 c0-`,
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.0: dummy_insert {__seq0: type:INT64 value:"7171" __seq1: type:INT64 value:"2" __seq2: type:INT64 value:"8195"} true true`,
+	})
+	expectResult(t, result, &sqltypes.Result{InsertID: 7171})
+
+	// Node id 1 gets the same values from the same shard, but in its own stripe.
+	vc = newDMLTestVCursor("0")
+	vc.ksShardMap = map[string][]string{"ks2": {"-80", "80-"}}
+	vc.snowflake = newTestSnowflakeGenerator(t, 1, snowflakeEpoch)
+	vc.results = []*sqltypes.Result{nextval("5"), {InsertID: 1}}
+
+	result, err = newInsert().TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	expectResult(t, result, &sqltypes.Result{InsertID: 5121})
+}
+
+func TestInsertGenerateWithoutNodeID(t *testing.T) {
+	for _, mode := range []string{vindexes.AutoIncrementSnowflake, vindexes.AutoIncrementShardedSequence} {
+		t.Run(mode, func(t *testing.T) {
+			ins := newQueryInsert(
+				InsertUnsharded,
+				&vindexes.Keyspace{
+					Name:    "ks",
+					Sharded: false,
+				},
+				"dummy_insert",
+			)
+			ins.Generate = &Generate{
+				Mode:     mode,
+				Keyspace: &vindexes.Keyspace{Name: "ks2", Sharded: true},
+				Query:    "dummy_generate",
+				Values:   evalengine.NewTupleExpr(evalengine.NullExpr),
+			}
+
+			vc := newDMLTestVCursor("0")
+			_, err := ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+			require.EqualError(t, err, "the "+mode+" auto_increment mode needs a node id that is unique to this vtgate, set it with --sequence-node-id")
+			vc.ExpectLog(t, nil)
+		})
+	}
+}
+
 func TestInsertShardedSimple(t *testing.T) {
 	invschema := &vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
@@ -1743,6 +1915,71 @@ func TestInsertSelectSimple(t *testing.T) {
 			` {_c1_0: type:VARCHAR value:"a" _c1_1: type:INT64 value:"3"} true false`})
 }
 
+// TestInsertSelectDeleteBeforeInsert tests that the rows clashing with the selected rows are deleted before the insert.
+func TestInsertSelectDeleteBeforeInsert(t *testing.T) {
+	invschema := &vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"sharded": {
+				Sharded: true,
+				Vindexes: map[string]*vschemapb.Vindex{
+					"hash": {Type: "hash"}},
+				Tables: map[string]*vschemapb.Table{
+					"t1": {
+						ColumnVindexes: []*vschemapb.ColumnVindex{{
+							Name:    "hash",
+							Columns: []string{"id"}}}}}}}}
+
+	vs := vindexes.BuildVSchema(invschema, sqlparser.NewTestParser())
+	ks := vs.Keyspaces["sharded"]
+
+	rb := &Route{
+		Query:      "dummy_select",
+		FieldQuery: "dummy_field_query",
+		RoutingParameters: &RoutingParameters{
+			Opcode:   Scatter,
+			Keyspace: ks.Keyspace}}
+	del := &Delete{
+		DML: &DML{
+			Query: "dummy_delete",
+			RoutingParameters: &RoutingParameters{
+				Opcode:   Scatter,
+				Keyspace: ks.Keyspace,
+			},
+		},
+	}
+	ins := newInsertSelect(false, ks.Keyspace, ks.Tables["t1"], "prefix ", nil, [][]int{{1}}, rb)
+	ins.DeleteBeforeInsert = []*FkChild{{BVName: "replace_vals", Cols: []int{1}, Exec: del}}
+
+	vc := newDMLTestVCursor("-20", "20-")
+	vc.shardForKsid = []string{"20-", "-20"}
+	vc.results = []*sqltypes.Result{
+		sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"name|id",
+				"varchar|int64"),
+			"a|1",
+			"b|2")}
+
+	_, err := ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations sharded [] Destinations:DestinationAllShards()`,
+
+		// the select query
+		`ExecuteMultiShard sharded.-20: dummy_select {} sharded.20-: dummy_select {} false false`,
+
+		// the delete of the clashing rows
+		`ResolveDestinations sharded [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard sharded.-20: dummy_delete {replace_vals: type:TUPLE values:{type:TUPLE value:"\x89\x02\x011"} values:{type:TUPLE value:"\x89\x02\x012"}} ` +
+			`sharded.20-: dummy_delete {replace_vals: type:TUPLE values:{type:TUPLE value:"\x89\x02\x011"} values:{type:TUPLE value:"\x89\x02\x012"}} true false`,
+
+		// the insert
+		`ResolveDestinations sharded [value:"0" value:"1"] Destinations:DestinationKeyspaceID(166b40b44aba4bd6),DestinationKeyspaceID(06e7ea22ce92708f)`,
+		`ExecuteMultiShard ` +
+			`sharded.20-: prefix values (:_c0_0, :_c0_1) {_c0_0: type:VARCHAR value:"a" _c0_1: type:INT64 value:"1"} ` +
+			`sharded.-20: prefix values (:_c1_0, :_c1_1) {_c1_0: type:VARCHAR value:"b" _c1_1: type:INT64 value:"2"} true false`})
+}
+
 func TestInsertSelectOwned(t *testing.T) {
 	invschema := &vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
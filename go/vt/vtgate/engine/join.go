@@ -232,7 +232,10 @@ func joinRows(lrow, rrow sqltypes.Row, cols []int) sqltypes.Row {
 	row := make([]sqltypes.Value, len(cols))
 	for i, index := range cols {
 		if index < 0 {
-			row[i] = lrow[-index-1]
+			// lrow can be nil on right joins
+			if lrow != nil {
+				row[i] = lrow[-index-1]
+			}
 			continue
 		}
 		// rrow can be nil on left joins
@@ -251,11 +254,17 @@ type JoinOpcode int
 const (
 	InnerJoin = JoinOpcode(iota)
 	LeftJoin
+	// RightJoin keeps all the rows from the RHS. It is only used by HashJoin,
+	// where it allows the probe table to be built from the outer side of the join.
+	RightJoin
 )
 
 func (code JoinOpcode) String() string {
-	if code == InnerJoin {
+	switch code {
+	case InnerJoin:
 		return "Join"
+	case RightJoin:
+		return "RightJoin"
 	}
 	return "LeftJoin"
 }
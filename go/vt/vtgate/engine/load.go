@@ -0,0 +1,463 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/collations/charset"
+	"vitess.io/vitess/go/mysql/collations/colldata"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// LoadFieldPrefix is the prefix of the arguments that stand for the fields of a line in Load.Row.
+// The prefix is followed by the position of the field in the line.
+const LoadFieldPrefix = "__ld"
+
+const (
+	// loadBatchRows is the maximum number of rows inserted by a single statement
+	loadBatchRows = 1000
+	// loadBatchBytes caps the size of the values inserted by a single statement
+	loadBatchBytes = 4 * 1024 * 1024
+)
+
+var _ Primitive = (*Load)(nil)
+
+// Load is the primitive for LOAD DATA LOCAL INFILE. It reads the file from the client,
+// and inserts its lines in batches through the executor, so every row is routed through
+// the vindexes of the table just like the rows of any other insert.
+type Load struct {
+	noInputs
+
+	// Keyspace and TableName are the table the rows are loaded into.
+	Keyspace  *vindexes.Keyspace
+	TableName string
+
+	// Infile is the name of the file on the client.
+	Infile string
+
+	// Insert is the INSERT, INSERT IGNORE or REPLACE statement the rows are sent with.
+	// Its rows are filled in for every batch.
+	Insert *sqlparser.Insert
+
+	// Row is the row inserted for every line, where the arguments starting with LoadFieldPrefix
+	// stand for the fields of the line. When it is nil, every field is inserted as is.
+	Row sqlparser.ValTuple
+
+	// FieldTypes is the type the fields are bound as, by position. Fields without a type are bound as VARCHAR.
+	FieldTypes []querypb.Type
+
+	// Charset is the default collation of the character set of the file,
+	// or collations.Unknown when the file does not need converting.
+	Charset collations.ID
+
+	FieldsTerminatedBy string
+	FieldsEnclosedBy   string
+	FieldsEscapedBy    string
+	LinesStartingBy    string
+	LinesTerminatedBy  string
+	IgnoreLines        int
+}
+
+// loadField is a single field read from the file
+type loadField struct {
+	val  []byte
+	null bool
+}
+
+// RouteType implements the Primitive interface
+func (l *Load) RouteType() string {
+	return "Load"
+}
+
+// GetKeyspaceName implements the Primitive interface
+func (l *Load) GetKeyspaceName() string {
+	return l.Keyspace.Name
+}
+
+// GetTableName implements the Primitive interface
+func (l *Load) GetTableName() string {
+	return l.TableName
+}
+
+// NeedsTransaction implements the Primitive interface
+func (l *Load) NeedsTransaction() bool {
+	return true
+}
+
+// TryExecute implements the Primitive interface
+func (l *Load) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (result *sqltypes.Result, err error) {
+	file, err := vcursor.ReadLocalInfile(ctx, l.Infile)
+	if err != nil {
+		return nil, err
+	}
+	// the client sends the whole file no matter what, and we have to read all of it before replying
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	r := l.newReader(file)
+	for i := 0; i < l.IgnoreLines; i++ {
+		if _, err := r.next(); err != nil {
+			if err == io.EOF {
+				return &sqltypes.Result{}, nil
+			}
+			return nil, err
+		}
+	}
+
+	result = &sqltypes.Result{}
+	var (
+		rows      sqlparser.Values
+		batchVars map[string]*querypb.BindVariable
+		size      int
+	)
+	flush := func() error {
+		if len(rows) == 0 {
+			return nil
+		}
+		qr, err := l.insertRows(ctx, vcursor, rows, batchVars)
+		if err != nil {
+			return err
+		}
+		result.RowsAffected += qr.RowsAffected
+		rows, batchVars, size = nil, nil, 0
+		return nil
+	}
+
+	for {
+		fields, err := r.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if batchVars == nil {
+			batchVars = make(map[string]*querypb.BindVariable)
+		}
+		rows = append(rows, l.makeRow(len(rows), fields, batchVars))
+		for _, field := range fields {
+			size += len(field.val)
+		}
+		if len(rows) >= loadBatchRows || size >= loadBatchBytes {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// insertRows sends the rows through the executor, which routes them to the right shards
+func (l *Load) insertRows(ctx context.Context, vcursor VCursor, rows sqlparser.Values, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	ins := *l.Insert
+	ins.Rows = rows
+	return vcursor.Execute(ctx, "Load", sqlparser.String(&ins), bindVars, true /* rollbackOnError */, vtgatepb.CommitOrder_NORMAL)
+}
+
+// makeRow returns the row for the given line, adding the values of its fields to bindVars
+func (l *Load) makeRow(rowNum int, fields []loadField, bindVars map[string]*querypb.BindVariable) sqlparser.ValTuple {
+	argFor := func(idx int) *sqlparser.Argument {
+		name := "ld" + strconv.Itoa(rowNum) + "_" + strconv.Itoa(idx)
+		if _, ok := bindVars[name]; !ok {
+			bindVars[name] = l.bindField(idx, fields[idx])
+		}
+		return sqlparser.NewArgument(name)
+	}
+
+	if l.Row == nil {
+		row := make(sqlparser.ValTuple, 0, len(fields))
+		for idx := range fields {
+			row = append(row, argFor(idx))
+		}
+		return row
+	}
+
+	row := make(sqlparser.ValTuple, 0, len(l.Row))
+	for _, expr := range l.Row {
+		if idx, ok := loadFieldIndex(expr); ok {
+			if idx >= len(fields) {
+				// columns without a field in the line are set to their default value
+				row = append(row, &sqlparser.Default{})
+			} else {
+				row = append(row, argFor(idx))
+			}
+			continue
+		}
+		expr = sqlparser.CopyOnRewrite(expr, nil, func(cursor *sqlparser.CopyOnWriteCursor) {
+			idx, ok := loadFieldIndex(cursor.Node())
+			if !ok {
+				return
+			}
+			if idx >= len(fields) {
+				// user variables without a field in the line are NULL
+				cursor.Replace(&sqlparser.NullVal{})
+			} else {
+				cursor.Replace(argFor(idx))
+			}
+		}, nil).(sqlparser.Expr)
+		row = append(row, expr)
+	}
+	return row
+}
+
+// loadFieldIndex returns the position of the field the node stands for, if it is one
+func loadFieldIndex(node sqlparser.SQLNode) (int, bool) {
+	arg, ok := node.(*sqlparser.Argument)
+	if !ok || !strings.HasPrefix(arg.Name, LoadFieldPrefix) {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(arg.Name[len(LoadFieldPrefix):])
+	return idx, err == nil
+}
+
+func (l *Load) bindField(idx int, field loadField) *querypb.BindVariable {
+	if field.null {
+		return sqltypes.NullBindVariable
+	}
+	if idx < len(l.FieldTypes) && l.FieldTypes[idx] != sqltypes.Null {
+		// values that don't fit the type of their column are left for mysql to convert
+		if val, err := sqltypes.NewValue(l.FieldTypes[idx], field.val); err == nil {
+			return sqltypes.ValueBindVariable(val)
+		}
+	}
+	return sqltypes.ValueBindVariable(sqltypes.MakeTrusted(sqltypes.VarChar, field.val))
+}
+
+// TryStreamExecute implements the Primitive interface
+func (l *Load) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	res, err := l.TryExecute(ctx, vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	return callback(res)
+}
+
+// GetFields implements the Primitive interface
+func (l *Load) GetFields(context.Context, VCursor, map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return &sqltypes.Result{}, nil
+}
+
+func (l *Load) description() PrimitiveDescription {
+	// the row is empty when every field of a line is inserted as is
+	ins := *l.Insert
+	ins.Rows = sqlparser.Values{l.Row}
+	other := map[string]any{
+		"Table":  l.GetTableName(),
+		"Infile": l.Infile,
+		"Query":  sqlparser.String(&ins),
+	}
+	if l.FieldsTerminatedBy != "\t" {
+		other["FieldsTerminatedBy"] = l.FieldsTerminatedBy
+	}
+	if l.FieldsEnclosedBy != "" {
+		other["FieldsEnclosedBy"] = l.FieldsEnclosedBy
+	}
+	if l.FieldsEscapedBy != "\\" {
+		other["FieldsEscapedBy"] = l.FieldsEscapedBy
+	}
+	if l.LinesStartingBy != "" {
+		other["LinesStartingBy"] = l.LinesStartingBy
+	}
+	if l.LinesTerminatedBy != "\n" {
+		other["LinesTerminatedBy"] = l.LinesTerminatedBy
+	}
+	if l.IgnoreLines > 0 {
+		other["IgnoreLines"] = l.IgnoreLines
+	}
+	if l.Charset != collations.Unknown {
+		other["Charset"] = l.Charset
+	}
+	return PrimitiveDescription{
+		OperatorType: "Load",
+		Keyspace:     l.Keyspace,
+		Other:        other,
+	}
+}
+
+// loadReader splits the file sent by the client into lines and fields,
+// following the FIELDS and LINES options of the statement
+type loadReader struct {
+	r *bufio.Reader
+
+	fieldTerm, lineTerm, lineStart []byte
+	enclosed, escaped              byte
+	hasEnclosed, hasEscaped        bool
+
+	// cs is the character set the file is converted from, or nil
+	cs charset.Charset
+}
+
+func (l *Load) newReader(file io.Reader) *loadReader {
+	lr := &loadReader{
+		r:         bufio.NewReader(file),
+		fieldTerm: []byte(l.FieldsTerminatedBy),
+		lineTerm:  []byte(l.LinesTerminatedBy),
+		lineStart: []byte(l.LinesStartingBy),
+	}
+	if l.FieldsEnclosedBy != "" {
+		lr.enclosed, lr.hasEnclosed = l.FieldsEnclosedBy[0], true
+	}
+	if l.FieldsEscapedBy != "" {
+		lr.escaped, lr.hasEscaped = l.FieldsEscapedBy[0], true
+	}
+	if l.Charset != collations.Unknown {
+		lr.cs = colldata.Lookup(l.Charset).Charset()
+	}
+	return lr
+}
+
+// next returns the fields of the next line, or io.EOF once the whole file was read
+func (lr *loadReader) next() ([]loadField, error) {
+	if len(lr.lineStart) > 0 {
+		// everything up to the prefix is skipped, including lines without the prefix
+		for !lr.peek(lr.lineStart) {
+			if _, err := lr.r.ReadByte(); err != nil {
+				return nil, err
+			}
+		}
+		_, _ = lr.r.Discard(len(lr.lineStart))
+	} else if _, err := lr.r.Peek(1); err != nil {
+		return nil, err
+	}
+
+	var fields []loadField
+	for {
+		field, lineEnd, err := lr.readField()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+		if lineEnd {
+			return fields, nil
+		}
+	}
+}
+
+// readField reads a single field, and reports whether it was the last one of the line
+func (lr *loadReader) readField() (field loadField, lineEnd bool, err error) {
+	enclosed := lr.hasEnclosed && lr.peek([]byte{lr.enclosed})
+	wasEnclosed := enclosed
+	if enclosed {
+		_, _ = lr.r.Discard(1)
+	}
+
+	var val []byte
+	escapedN := false
+	for {
+		if !enclosed {
+			if lr.peek(lr.fieldTerm) {
+				_, _ = lr.r.Discard(len(lr.fieldTerm))
+				break
+			}
+			if lr.peek(lr.lineTerm) {
+				_, _ = lr.r.Discard(len(lr.lineTerm))
+				lineEnd = true
+				break
+			}
+		}
+
+		c, err := lr.r.ReadByte()
+		if err == io.EOF {
+			lineEnd = true
+			break
+		}
+		if err != nil {
+			return loadField{}, false, err
+		}
+
+		switch {
+		case enclosed && c == lr.enclosed:
+			switch {
+			case lr.peek([]byte{lr.enclosed}):
+				// a doubled enclosing character stands for itself
+				_, _ = lr.r.Discard(1)
+				val = append(val, c)
+			case lr.atFieldEnd():
+				enclosed = false
+			default:
+				val = append(val, c)
+			}
+		case lr.hasEscaped && c == lr.escaped:
+			next, err := lr.r.ReadByte()
+			if err == io.EOF {
+				val = append(val, c)
+				continue
+			}
+			if err != nil {
+				return loadField{}, false, err
+			}
+			if next == 'N' && len(val) == 0 && !wasEnclosed {
+				escapedN = true
+			}
+			if decoded := sqltypes.SQLDecodeMap[next]; decoded != sqltypes.DontEscape {
+				next = decoded
+			}
+			val = append(val, next)
+		default:
+			val = append(val, c)
+		}
+	}
+
+	switch {
+	case escapedN && len(val) == 1:
+		// \N is NULL
+		return loadField{null: true}, lineEnd, nil
+	case lr.hasEnclosed && !wasEnclosed && string(val) == "NULL":
+		// so is a NULL word that is not enclosed, when fields can be enclosed
+		return loadField{null: true}, lineEnd, nil
+	}
+	if lr.cs != nil {
+		if val, err = charset.Convert(nil, charset.Charset_utf8mb4{}, val, lr.cs); err != nil {
+			return loadField{}, false, err
+		}
+	}
+	return loadField{val: val}, lineEnd, nil
+}
+
+// peek reports whether the input continues with the given bytes
+func (lr *loadReader) peek(s []byte) bool {
+	if len(s) == 0 {
+		return false
+	}
+	buf, _ := lr.r.Peek(len(s))
+	return bytes.Equal(buf, s)
+}
+
+// atFieldEnd reports whether the input is at the end of the file or of a field
+func (lr *loadReader) atFieldEnd() bool {
+	if _, err := lr.r.Peek(1); err != nil {
+		return true
+	}
+	return lr.peek(lr.fieldTerm) || lr.peek(lr.lineTerm)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func newTestLoad(t *testing.T, insert string, row ...string) *Load {
+	stmt, err := sqlparser.NewTestParser().Parse(insert)
+	require.NoError(t, err)
+	load := &Load{
+		Keyspace:           &vindexes.Keyspace{Name: "ks", Sharded: true},
+		TableName:          "t",
+		Infile:             "data.txt",
+		Insert:             stmt.(*sqlparser.Insert),
+		FieldsTerminatedBy: "\t",
+		FieldsEscapedBy:    "\\",
+		LinesTerminatedBy:  "\n",
+	}
+	for _, field := range row {
+		expr, err := sqlparser.NewTestParser().ParseExpr(field)
+		require.NoError(t, err)
+		load.Row = append(load.Row, expr)
+	}
+	return load
+}
+
+func TestLoadExecute(t *testing.T) {
+	load := newTestLoad(t, "insert ignore into t(a, b) values ()", ":__ld0", ":__ld1")
+	load.FieldTypes = []querypb.Type{sqltypes.Int64, sqltypes.VarChar}
+
+	vc := &loggingVCursor{
+		localInfile: "1\tfoo\n2\t\\N\nx\tb\\tar\n3\n",
+		results:     []*sqltypes.Result{{RowsAffected: 4}},
+	}
+	qr, err := load.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, qr.RowsAffected)
+	vc.ExpectLog(t, []string{
+		`ReadLocalInfile data.txt`,
+		"Execute insert ignore into t(a, b) values (:ld0_0, :ld0_1), (:ld1_0, :ld1_1), (:ld2_0, :ld2_1), (:ld3_0, default) " +
+			`ld0_0: type:INT64 value:"1" ld0_1: type:VARCHAR value:"foo" ` +
+			`ld1_0: type:INT64 value:"2" ld1_1:  ` +
+			`ld2_0: type:VARCHAR value:"x" ld2_1: type:VARCHAR value:"b\tar" ` +
+			`ld3_0: type:INT64 value:"3" true`,
+	})
+}
+
+func TestLoadExecuteFormat(t *testing.T) {
+	load := newTestLoad(t, "replace into t(a, b, c) values ()", ":__ld0", ":__ld2", "upper(:__ld1)")
+	load.FieldsTerminatedBy = ","
+	load.FieldsEnclosedBy = `"`
+	load.LinesStartingBy = "> "
+	load.LinesTerminatedBy = "\r\n"
+	load.IgnoreLines = 1
+
+	vc := &loggingVCursor{
+		localInfile: "> a,b,c\r\nskipped\r\n> \"x,\"\"y\"\"\",NULL,\"NULL\"\r\n> 2,\"z\"",
+	}
+	_, err := load.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ReadLocalInfile data.txt`,
+		"Execute replace into t(a, b, c) values (:ld0_0, :ld0_2, upper(:ld0_1)), (:ld1_0, default, upper(:ld1_1)) " +
+			`ld0_0: type:VARCHAR value:"x,\"y\"" ld0_1:  ld0_2: type:VARCHAR value:"NULL" ` +
+			`ld1_0: type:VARCHAR value:"2" ld1_1: type:VARCHAR value:"z" true`,
+	})
+}
+
+func TestLoadExecuteAsIs(t *testing.T) {
+	load := newTestLoad(t, "insert ignore into t values ()")
+	load.Charset = collations.ID(8) // latin1_swedish_ci
+
+	vc := &loggingVCursor{localInfile: "1\t\xe9t\xe9\n"}
+	_, err := load.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ReadLocalInfile data.txt`,
+		`Execute insert ignore into t values (:ld0_0, :ld0_1) ld0_0: type:VARCHAR value:"1" ld0_1: type:VARCHAR value:"été" true`,
+	})
+}
+
+func TestLoadExecuteBatches(t *testing.T) {
+	load := newTestLoad(t, "insert ignore into t(a) values ()", ":__ld0")
+
+	var file strings.Builder
+	for i := 0; i < loadBatchRows+1; i++ {
+		file.WriteString("1\n")
+	}
+	vc := &loggingVCursor{
+		localInfile: file.String(),
+		results:     []*sqltypes.Result{{RowsAffected: loadBatchRows}, {RowsAffected: 1}},
+	}
+	qr, err := load.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	assert.EqualValues(t, loadBatchRows+1, qr.RowsAffected)
+	require.Len(t, vc.log, 3)
+	assert.True(t, strings.HasPrefix(vc.log[2], "Execute insert ignore into t(a) values (:ld0_0) ld0_0: "), vc.log[2])
+}
+
+func TestLoadExecuteError(t *testing.T) {
+	load := newTestLoad(t, "insert ignore into t(a) values ()", ":__ld0")
+
+	vc := &loggingVCursor{
+		localInfile: "1\n",
+		resultErr:   sqlparser.ErrEmpty,
+	}
+	_, err := load.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.ErrorIs(t, err, sqlparser.ErrEmpty)
+}
@@ -61,6 +61,10 @@ func (ms *MemorySort) GetTableName() string {
 
 // TryExecute satisfies the Primitive interface.
 func (ms *MemorySort) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if vcursor.SpillDir() != "" {
+		return executeSpilling(ctx, vcursor, bindVars, wantfields, ms.TryStreamExecute)
+	}
+
 	count, err := ms.fetchCount(ctx, vcursor, bindVars)
 	if err != nil {
 		return nil, err
@@ -98,6 +102,11 @@ func (ms *MemorySort) TryStreamExecute(ctx context.Context, vcursor VCursor, bin
 		Limit:   count,
 	}
 
+	// once we have too many rows in memory, we write them out as sorted runs,
+	// and merge them all together at the end
+	runs := newSortedRuns(vcursor.SpillDir(), ms.OrderBy)
+	defer runs.close()
+
 	var mu sync.Mutex
 	err = vcursor.StreamExecutePrimitive(ctx, ms.Input, bindVars, wantfields, func(qr *sqltypes.Result) error {
 		mu.Lock()
@@ -110,6 +119,16 @@ func (ms *MemorySort) TryStreamExecute(ctx context.Context, vcursor VCursor, bin
 		for _, row := range qr.Rows {
 			sorter.Push(row)
 		}
+		if canSpill(vcursor, sorter.Len()) {
+			if err := runs.add(sorter.Sorted()); err != nil {
+				return err
+			}
+			sorter = &evalengine.Sorter{
+				Compare: ms.OrderBy,
+				Limit:   count,
+			}
+			return nil
+		}
 		if vcursor.ExceedsMaxMemoryRows(sorter.Len()) {
 			return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
 		}
@@ -118,7 +137,15 @@ func (ms *MemorySort) TryStreamExecute(ctx context.Context, vcursor VCursor, bin
 	if err != nil {
 		return err
 	}
-	return cb(&sqltypes.Result{Rows: sorter.Sorted()})
+	if len(runs.runs) == 0 {
+		return cb(&sqltypes.Result{Rows: sorter.Sorted()})
+	}
+
+	stats := runs.stats()
+	stats.report(vcursor)
+	return runs.merge(sorter.Sorted(), count, func(rows []sqltypes.Row) error {
+		return cb(&sqltypes.Result{Rows: rows})
+	})
 }
 
 // GetFields satisfies the Primitive interface.
@@ -200,3 +200,73 @@ func (code AggregateOpcode) IsDistinct() bool {
 		return false
 	}
 }
+
+// WindowOpcode is the opcode for window functions evaluated at the vtgate.
+type WindowOpcode int
+
+// These constants list the window functions that the vtgate can evaluate.
+const (
+	WindowUnassigned = WindowOpcode(iota)
+	WindowRowNumber
+	WindowRank
+	WindowDenseRank
+	WindowLag
+	WindowLead
+	WindowSum
+	WindowCount
+	WindowCountStar
+	WindowAvg
+)
+
+var WindowName = map[WindowOpcode]string{
+	WindowRowNumber: "row_number",
+	WindowRank:      "rank",
+	WindowDenseRank: "dense_rank",
+	WindowLag:       "lag",
+	WindowLead:      "lead",
+	WindowSum:       "sum",
+	WindowCount:     "count",
+	WindowCountStar: "count_star",
+	WindowAvg:       "avg",
+}
+
+func (code WindowOpcode) String() string {
+	name := WindowName[code]
+	if name == "" {
+		name = "ERROR"
+	}
+	return name
+}
+
+// MarshalJSON serializes the WindowOpcode as a JSON string.
+// It's used for testing and diagnostics.
+func (code WindowOpcode) MarshalJSON() ([]byte, error) {
+	return ([]byte)(fmt.Sprintf("\"%s\"", code.String())), nil
+}
+
+// SQLType returns the type of the values produced by the window function
+func (code WindowOpcode) SQLType(typ querypb.Type) querypb.Type {
+	switch code {
+	case WindowRowNumber, WindowRank, WindowDenseRank, WindowCount, WindowCountStar:
+		return sqltypes.Int64
+	case WindowLag, WindowLead:
+		return typ
+	case WindowSum:
+		return AggregateSum.SQLType(typ)
+	case WindowAvg:
+		return AggregateAvg.SQLType(typ)
+	default:
+		return sqltypes.Null
+	}
+}
+
+// IsAggregation returns true for the windowed versions of the aggregation functions.
+// These are the only window functions that take the window frame into account.
+func (code WindowOpcode) IsAggregation() bool {
+	switch code {
+	case WindowSum, WindowCount, WindowCountStar, WindowAvg:
+		return true
+	default:
+		return false
+	}
+}
@@ -44,6 +44,10 @@ type OrderedAggregate struct {
 	// the aggregation key.
 	GroupByKeys []*GroupByParams
 
+	// WithRollup is true when the super-aggregate rows of GROUP BY ... WITH ROLLUP
+	// have to be produced by this primitive.
+	WithRollup bool
+
 	// TruncateColumnCount specifies the number of columns to return
 	// in the final result. Rest of the columns are truncated
 	// from the result received. If 0, no truncation happens.
@@ -95,7 +99,12 @@ func (oa *OrderedAggregate) GetTableName() string {
 }
 
 // TryExecute is a Primitive function.
-func (oa *OrderedAggregate) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
+func (oa *OrderedAggregate) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if vcursor.SpillDir() != "" {
+		// the sort feeding us can spill to disk, as long as we stream its output
+		// and only keep one row per group in memory
+		return executeSpilling(ctx, vcursor, bindVars, wantfields, oa.TryStreamExecute)
+	}
 	qr, err := oa.execute(ctx, vcursor, bindVars)
 	if err != nil {
 		return nil, err
@@ -142,11 +151,15 @@ func (oa *OrderedAggregate) execute(ctx context.Context, vcursor VCursor, bindVa
 	if err != nil {
 		return nil, err
 	}
-	if len(oa.Aggregates) == 0 {
+	if len(oa.Aggregates) == 0 && !oa.WithRollup {
 		return oa.executeGroupBy(result)
 	}
 
-	agg, fields, err := newAggregation(result.Fields, oa.Aggregates)
+	agg, fields, err := newAggregation(result.Fields, oa.Aggregates, vcursor.GroupConcatMaxLen())
+	if err != nil {
+		return nil, err
+	}
+	rollup, err := oa.newRollup(result.Fields, vcursor.GroupConcatMaxLen())
 	if err != nil {
 		return nil, err
 	}
@@ -160,23 +173,47 @@ func (oa *OrderedAggregate) execute(ctx context.Context, vcursor VCursor, bindVa
 	for _, row := range result.Rows {
 		var nextGroup bool
 
+		lastKey := currentKey
 		currentKey, nextGroup, err = oa.nextGroupBy(currentKey, row)
 		if err != nil {
 			return nil, err
 		}
 
 		if nextGroup {
-			out.Rows = append(out.Rows, agg.finish())
+			groupRow, err := agg.finish()
+			if err != nil {
+				return nil, err
+			}
+			out.Rows = append(out.Rows, groupRow)
 			agg.reset()
+
+			superRows, err := rollup.finish(lastKey, row)
+			if err != nil {
+				return nil, err
+			}
+			out.Rows = append(out.Rows, superRows...)
 		}
 
 		if err := agg.add(row); err != nil {
 			return nil, err
 		}
+		if err := rollup.add(row); err != nil {
+			return nil, err
+		}
 	}
 
 	if currentKey != nil {
-		out.Rows = append(out.Rows, agg.finish())
+		row, err := agg.finish()
+		if err != nil {
+			return nil, err
+		}
+		out.Rows = append(out.Rows, row)
+
+		superRows, err := rollup.finish(currentKey, nil)
+		if err != nil {
+			return nil, err
+		}
+		out.Rows = append(out.Rows, superRows...)
 	}
 
 	return out, nil
@@ -235,7 +272,7 @@ func (oa *OrderedAggregate) executeStreamGroupBy(ctx context.Context, vcursor VC
 
 // TryStreamExecute is a Primitive function.
 func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool, callback func(*sqltypes.Result) error) error {
-	if len(oa.Aggregates) == 0 {
+	if len(oa.Aggregates) == 0 && !oa.WithRollup {
 		return oa.executeStreamGroupBy(ctx, vcursor, bindVars, callback)
 	}
 
@@ -244,6 +281,7 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 	}
 
 	var agg aggregationState
+	var rollup *rollupAggregation
 	var fields []*querypb.Field
 	var currentKey []sqltypes.Value
 
@@ -251,7 +289,11 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 		var err error
 
 		if agg == nil && len(qr.Fields) != 0 {
-			agg, fields, err = newAggregation(qr.Fields, oa.Aggregates)
+			agg, fields, err = newAggregation(qr.Fields, oa.Aggregates, vcursor.GroupConcatMaxLen())
+			if err != nil {
+				return err
+			}
+			rollup, err = oa.newRollup(qr.Fields, vcursor.GroupConcatMaxLen())
 			if err != nil {
 				return err
 			}
@@ -264,6 +306,7 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 		for _, row := range qr.Rows {
 			var nextGroup bool
 
+			lastKey := currentKey
 			currentKey, nextGroup, err = oa.nextGroupBy(currentKey, row)
 			if err != nil {
 				return err
@@ -271,7 +314,16 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 
 			if nextGroup {
 				// this is a new grouping. let's yield the old one, and start a new
-				if err := cb(&sqltypes.Result{Rows: [][]sqltypes.Value{agg.finish()}}); err != nil {
+				groupRow, err := agg.finish()
+				if err != nil {
+					return err
+				}
+				rows := [][]sqltypes.Value{groupRow}
+				superRows, err := rollup.finish(lastKey, row)
+				if err != nil {
+					return err
+				}
+				if err := cb(&sqltypes.Result{Rows: append(rows, superRows...)}); err != nil {
 					return err
 				}
 
@@ -281,6 +333,9 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 			if err := agg.add(row); err != nil {
 				return err
 			}
+			if err := rollup.add(row); err != nil {
+				return err
+			}
 		}
 		return nil
 	}
@@ -292,7 +347,16 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 	}
 
 	if currentKey != nil {
-		if err := cb(&sqltypes.Result{Rows: [][]sqltypes.Value{agg.finish()}}); err != nil {
+		row, err := agg.finish()
+		if err != nil {
+			return err
+		}
+		rows := [][]sqltypes.Value{row}
+		superRows, err := rollup.finish(currentKey, nil)
+		if err != nil {
+			return err
+		}
+		if err := cb(&sqltypes.Result{Rows: append(rows, superRows...)}); err != nil {
 			return err
 		}
 	}
@@ -306,7 +370,8 @@ func (oa *OrderedAggregate) GetFields(ctx context.Context, vcursor VCursor, bind
 		return nil, err
 	}
 
-	_, fields, err := newAggregation(qr.Fields, oa.Aggregates)
+	// only the fields are needed, so the group_concat_max_len of the session does not matter
+	_, fields, err := newAggregation(qr.Fields, oa.Aggregates, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -330,30 +395,137 @@ func (oa *OrderedAggregate) nextGroupBy(currentKey, nextRow []sqltypes.Value) (n
 		return nextRow, false, nil
 	}
 
-	for _, gb := range oa.GroupByKeys {
-		v1 := currentKey[gb.KeyCol]
-		v2 := nextRow[gb.KeyCol]
+	equal, err := equalGroupingKeys(oa.GroupByKeys, currentKey, nextRow)
+	if err != nil {
+		return nil, false, err
+	}
+	if !equal {
+		return nextRow, true, nil
+	}
+	return currentKey, false, nil
+}
+
+// equalGroupingKeys returns true if the two rows have the same values for all the grouping keys
+func equalGroupingKeys(keys []*GroupByParams, row1, row2 sqltypes.Row) (bool, error) {
+	idx, err := firstDifferentGroupingKey(keys, row1, row2)
+	if err != nil {
+		return false, err
+	}
+	return idx == len(keys), nil
+}
+
+// firstDifferentGroupingKey returns the index of the first grouping key that has different values in the two rows,
+// or the number of keys if the two rows belong to the same group
+func firstDifferentGroupingKey(keys []*GroupByParams, row1, row2 sqltypes.Row) (int, error) {
+	for idx, gb := range keys {
+		v1 := row1[gb.KeyCol]
+		v2 := row2[gb.KeyCol]
 		if v1.TinyWeightCmp(v2) != 0 {
-			return nextRow, true, nil
+			return idx, nil
 		}
 
 		cmp, err := evalengine.NullsafeCompare(v1, v2, gb.CollationEnv, gb.Type.Collation(), gb.Type.Values())
 		if err != nil {
 			_, isCollationErr := err.(evalengine.UnsupportedCollationError)
 			if !isCollationErr || gb.WeightStringCol == -1 {
-				return nil, false, err
+				return 0, err
 			}
 			gb.KeyCol = gb.WeightStringCol
-			cmp, err = evalengine.NullsafeCompare(currentKey[gb.WeightStringCol], nextRow[gb.WeightStringCol], gb.CollationEnv, gb.Type.Collation(), gb.Type.Values())
+			cmp, err = evalengine.NullsafeCompare(row1[gb.WeightStringCol], row2[gb.WeightStringCol], gb.CollationEnv, gb.Type.Collation(), gb.Type.Values())
 			if err != nil {
-				return nil, false, err
+				return 0, err
 			}
 		}
 		if cmp != 0 {
-			return nextRow, true, nil
+			return idx, nil
 		}
 	}
-	return currentKey, false, nil
+	return len(keys), nil
+}
+
+// rollupAggregation computes the super-aggregate rows of GROUP BY ... WITH ROLLUP.
+// The input rows are fed to one aggregation state per grouping level,
+// so the totals are computed from the same rows as the regular groups are.
+type rollupAggregation struct {
+	keys []*GroupByParams
+
+	// levels[i] aggregates the rows that have the same values for the first i grouping keys.
+	// levels[0] is the grand total.
+	levels []aggregationState
+
+	// keyCols[i] are the columns that hold the value of the grouping key i.
+	// They are NULL in the super-aggregate rows that don't group by this key.
+	keyCols [][]int
+}
+
+// newRollup returns nil when the primitive doesn't need to produce super-aggregate rows
+func (oa *OrderedAggregate) newRollup(fields []*querypb.Field, groupConcatMaxLen uint64) (*rollupAggregation, error) {
+	if !oa.WithRollup {
+		return nil, nil
+	}
+
+	r := &rollupAggregation{keys: oa.GroupByKeys}
+	for range oa.GroupByKeys {
+		agg, _, err := newAggregation(fields, oa.Aggregates, groupConcatMaxLen)
+		if err != nil {
+			return nil, err
+		}
+		r.levels = append(r.levels, agg)
+	}
+	for _, gb := range oa.GroupByKeys {
+		cols := []int{gb.KeyCol}
+		if gb.WeightStringCol != -1 && gb.WeightStringCol != gb.KeyCol {
+			cols = append(cols, gb.WeightStringCol)
+		}
+		r.keyCols = append(r.keyCols, cols)
+	}
+	return r, nil
+}
+
+func (r *rollupAggregation) add(row []sqltypes.Value) error {
+	if r == nil {
+		return nil
+	}
+	for _, level := range r.levels {
+		if err := level.add(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finish returns the super-aggregate rows of all the levels that are complete
+// when the input moves from the group of current to the group of next.
+// A nil next row means the input is exhausted, and all the levels are complete.
+func (r *rollupAggregation) finish(current, next sqltypes.Row) ([]sqltypes.Row, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	first := 0
+	if next != nil {
+		idx, err := firstDifferentGroupingKey(r.keys, current, next)
+		if err != nil {
+			return nil, err
+		}
+		first = idx + 1
+	}
+
+	var rows []sqltypes.Row
+	for i := len(r.levels) - 1; i >= first; i-- {
+		row, err := r.levels[i].finish()
+		if err != nil {
+			return nil, err
+		}
+		for _, cols := range r.keyCols[i:] {
+			for _, col := range cols {
+				row[col] = sqltypes.NULL
+			}
+		}
+		rows = append(rows, row)
+		r.levels[i].reset()
+	}
+	return rows, nil
 }
 func aggregateParamsToString(in any) string {
 	return in.(*AggregateParams).String()
@@ -370,6 +542,9 @@ func (oa *OrderedAggregate) description() PrimitiveDescription {
 		"Aggregates": aggregates,
 		"GroupBy":    groupBy,
 	}
+	if oa.WithRollup {
+		other["WithRollup"] = true
+	}
 	if oa.TruncateColumnCount > 0 {
 		other["ResultColumns"] = oa.TruncateColumnCount
 	}
@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 
 	"github.com/stretchr/testify/assert"
@@ -1169,3 +1170,256 @@ func TestGroupConcat(t *testing.T) {
 		})
 	}
 }
+
+func TestGroupConcatFromValues(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"c1|c2|c3|c4",
+		"int64|varbinary|varbinary|int64",
+	)
+	input := sqltypes.MakeTestResult(fields,
+		"10|b|x|3", "10|a|y|1", "10|b|x|2", "10|null|z|0",
+		"20|c|x|1", "20|c|y|2", "20|c|x|3",
+		"30|null|x|1",
+	)
+
+	var tcases = []struct {
+		name     string
+		distinct bool
+		args     []int
+		orderBy  evalengine.Comparison
+		expected []string
+	}{{
+		name:     "distinct",
+		distinct: true,
+		args:     []int{1},
+		expected: []string{"10|a,b|x|3", "20|c|x|1", "30|null|x|1"},
+	}, {
+		name:     "distinct with multiple arguments",
+		distinct: true,
+		args:     []int{1, 2},
+		expected: []string{"10|ay,bx|x|3", "20|cx,cy|x|1", "30|null|x|1"},
+	}, {
+		name:     "order by",
+		args:     []int{1},
+		orderBy:  evalengine.Comparison{{Col: 3, WeightStringCol: -1}},
+		expected: []string{"10|a,b,b|x|3", "20|c,c,c|x|1", "30|null|x|1"},
+	}, {
+		name:     "order by desc with multiple arguments",
+		args:     []int{1, 2},
+		orderBy:  evalengine.Comparison{{Col: 3, WeightStringCol: -1, Desc: true}},
+		expected: []string{"10|bx,bx,ay|x|3", "20|cx,cy,cx|x|1", "30|null|x|1"},
+	}, {
+		name:     "distinct with order by",
+		distinct: true,
+		args:     []int{2},
+		orderBy:  evalengine.Comparison{{Col: 2, WeightStringCol: -1, Desc: true}},
+		expected: []string{"10|b|z,y,x|3", "20|c|y,x|1", "30|null|x|1"},
+	}}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			fp := &fakePrimitive{results: []*sqltypes.Result{input}}
+			agp := NewAggregateParam(AggregateGroupConcat, tcase.args[0], "", collations.MySQL8())
+			agp.Func = &sqlparser.GroupConcatExpr{Separator: ",", Distinct: tcase.distinct}
+			for _, col := range tcase.args {
+				agp.Args = append(agp.Args, evalengine.OrderByParams{Col: col, WeightStringCol: -1})
+			}
+			agp.OrderBy = tcase.orderBy
+			oa := &OrderedAggregate{
+				Aggregates:  []*AggregateParams{agp},
+				GroupByKeys: []*GroupByParams{{KeyCol: 0}},
+				Input:       fp,
+			}
+
+			qr, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+			require.NoError(t, err)
+			var got []string
+			for _, row := range qr.Rows {
+				got = append(got, fmt.Sprintf("%s|%s|%s|%s", row[0].ToString(), nullableString(row[1]), row[2].ToString(), row[3].ToString()))
+			}
+			assert.Equal(t, tcase.expected, got)
+		})
+	}
+}
+
+func nullableString(v sqltypes.Value) string {
+	if v.IsNull() {
+		return "null"
+	}
+	return v.ToString()
+}
+
+func TestOrderedAggregateWithRollup(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"a|b|count(*)|max(c)",
+		"varbinary|varbinary|int64|int64",
+	)
+	tcases := []struct {
+		name        string
+		aggregates  func() []*AggregateParams
+		inputResult *sqltypes.Result
+		expResult   *sqltypes.Result
+	}{{
+		name: "two grouping keys",
+		aggregates: func() []*AggregateParams {
+			count := NewAggregateParam(AggregateSum, 2, "", collations.MySQL8())
+			count.OrigOpcode = AggregateCountStar
+			return []*AggregateParams{count, NewAggregateParam(AggregateMax, 3, "", collations.MySQL8())}
+		},
+		inputResult: sqltypes.MakeTestResult(fields,
+			"x|1|2|10",
+			"x|1|1|30",
+			"x|2|4|20",
+			"y|1|3|5",
+			"y|3|1|50",
+			"z|1|7|1",
+		),
+		expResult: sqltypes.MakeTestResult(fields,
+			"x|1|3|30",
+			"x|2|4|20",
+			"x|null|7|30",
+			"y|1|3|5",
+			"y|3|1|50",
+			"y|null|4|50",
+			"z|1|7|1",
+			"z|null|7|1",
+			"null|null|18|50",
+		),
+	}, {
+		name:       "no aggregation functions",
+		aggregates: func() []*AggregateParams { return nil },
+		inputResult: sqltypes.MakeTestResult(fields,
+			"x|1|2|10",
+			"x|2|4|20",
+			"y|1|3|5",
+		),
+		expResult: sqltypes.MakeTestResult(fields,
+			"x|1|2|10",
+			"x|2|4|20",
+			"x|null|2|10",
+			"y|1|3|5",
+			"y|null|3|5",
+			"null|null|2|10",
+		),
+	}, {
+		name:        "empty result",
+		aggregates:  func() []*AggregateParams { return nil },
+		inputResult: sqltypes.MakeTestResult(fields),
+		expResult:   sqltypes.MakeTestResult(fields),
+	}}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			fp := &fakePrimitive{results: []*sqltypes.Result{tcase.inputResult}}
+			oa := &OrderedAggregate{
+				Aggregates:  tcase.aggregates(),
+				GroupByKeys: []*GroupByParams{{KeyCol: 0, WeightStringCol: -1}, {KeyCol: 1, WeightStringCol: -1}},
+				WithRollup:  true,
+				Input:       fp,
+			}
+			qr, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+			require.NoError(t, err)
+			if len(qr.Rows) == 0 {
+				qr.Rows = nil
+			}
+			utils.MustMatch(t, tcase.expResult, qr)
+
+			fp.rewind()
+			results := &sqltypes.Result{}
+			err = oa.TryStreamExecute(context.Background(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+				if qr.Fields != nil {
+					results.Fields = qr.Fields
+				}
+				results.Rows = append(results.Rows, qr.Rows...)
+				return nil
+			})
+			require.NoError(t, err)
+			utils.MustMatch(t, tcase.expResult, results)
+		})
+	}
+}
+
+// TestOrderedAggregateWithRollupProjection checks that an expression over a grouping key,
+// such as col+1 in `select col+1, count(*) from user group by col with rollup`, is NULL in
+// the super-aggregate rows when it is evaluated on top of the aggregation.
+func TestOrderedAggregateWithRollupProjection(t *testing.T) {
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"col|1|count(*)",
+				"int64|int64|int64",
+			),
+			"1|1|2",
+			"1|1|1",
+			"2|1|4",
+		)},
+	}
+
+	anyValue := NewAggregateParam(AggregateAnyValue, 1, "", collations.MySQL8())
+	count := NewAggregateParam(AggregateSum, 2, "count(*)", collations.MySQL8())
+	count.OrigOpcode = AggregateCountStar
+	oa := &OrderedAggregate{
+		Aggregates:  []*AggregateParams{anyValue, count},
+		GroupByKeys: []*GroupByParams{{KeyCol: 0, WeightStringCol: -1}},
+		WithRollup:  true,
+		Input:       fp,
+	}
+
+	plusOne, err := evalengine.Translate(&sqlparser.BinaryExpr{
+		Operator: sqlparser.PlusOp,
+		Left:     &sqlparser.Offset{V: 0},
+		Right:    sqlparser.NewIntLiteral("1"),
+	}, &evalengine.Config{
+		Environment: vtenv.NewTestEnv(),
+		Collation:   collations.MySQL8().DefaultConnectionCharset(),
+	})
+	require.NoError(t, err)
+	proj := &Projection{
+		Cols:  []string{"col + 1", "count(*)"},
+		Exprs: []evalengine.Expr{plusOne, evalengine.NewColumn(2, evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID), nil)},
+		Input: oa,
+	}
+
+	qr, err := proj.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, "[[INT64(2) INT64(3)] [INT64(3) INT64(4)] [NULL INT64(7)]]", fmt.Sprintf("%v", qr.Rows))
+}
+
+func TestOrderedAggregateWithRollupTruncate(t *testing.T) {
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"col|count(*)|weight_string(col)",
+				"varchar|int64|varbinary",
+			),
+			"a|1|A",
+			"A|1|A",
+			"b|2|B",
+		)},
+	}
+
+	aggr := NewAggregateParam(AggregateSum, 1, "", collations.MySQL8())
+	aggr.OrigOpcode = AggregateCountStar
+
+	oa := &OrderedAggregate{
+		Aggregates:          []*AggregateParams{aggr},
+		GroupByKeys:         []*GroupByParams{{KeyCol: 0, WeightStringCol: 2}},
+		WithRollup:          true,
+		TruncateColumnCount: 2,
+		Input:               fp,
+	}
+
+	result, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+
+	wantResult := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"col|count(*)",
+			"varchar|int64",
+		),
+		"a|2",
+		"b|2",
+		"null|4",
+	)
+	utils.MustMatch(t, wantResult, result)
+}
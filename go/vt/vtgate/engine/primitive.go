@@ -18,6 +18,7 @@ package engine
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"vitess.io/vitess/go/mysql/collations"
@@ -56,9 +57,21 @@ type (
 		// if the max memory rows override directive is set to true
 		ExceedsMaxMemoryRows(numRows int) bool
 
+		// SpillDir returns the directory where primitives can spill rows to disk
+		// instead of keeping more than MaxMemoryRows rows in memory.
+		// Spilling is disabled when it is empty.
+		SpillDir() string
+
+		// RecordSpill records how many rows and bytes a primitive spilled to disk
+		RecordSpill(rows, bytes int)
+
 		Execute(ctx context.Context, method string, query string, bindVars map[string]*querypb.BindVariable, rollbackOnError bool, co vtgatepb.CommitOrder) (*sqltypes.Result, error)
 		AutocommitApproval() bool
 
+		// ReadLocalInfile asks the client for the contents of the file of a LOAD DATA LOCAL INFILE statement.
+		// The reader has to be closed before the statement returns.
+		ReadLocalInfile(ctx context.Context, fileName string) (io.ReadCloser, error)
+
 		// Execute the given primitive
 		ExecutePrimitive(ctx context.Context, primitive Primitive, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error)
 		// Execute the given primitive in a new autocommit session
@@ -91,6 +104,8 @@ type (
 		Environment() *vtenv.Environment
 		TimeZone() *time.Location
 		SQLMode() string
+		GroupConcatMaxLen() uint64
+		BlockEncryptionMode() string
 
 		ExecuteLock(ctx context.Context, rs *srvtopo.ResolvedShard, query *querypb.BoundQuery, lockFuncType sqlparser.LockingFuncType) (*sqltypes.Result, error)
 
@@ -129,6 +144,9 @@ type (
 		// GetWarmingReadsChannel returns the channel for executing warming reads against replicas
 		GetWarmingReadsChannel() chan bool
 
+		// SnowflakeGenerator returns the generator of the snowflake auto_increment values
+		SnowflakeGenerator() *SnowflakeGenerator
+
 		// CloneForReplicaWarming clones the VCursor for re-use in warming queries to replicas
 		CloneForReplicaWarming(ctx context.Context) VCursor
 
@@ -219,6 +237,9 @@ type (
 		// SetQueryTimeout sets the query timeout
 		SetQueryTimeout(queryTimeout int64)
 
+		// SetMaxExecutionTime sets the max_execution_time of the session, which bounds SELECT statements
+		SetMaxExecutionTime(maxExecutionTime int64)
+
 		// InTransaction returns true if the session has already opened transaction or
 		// will start a transaction on the query execution.
 		InTransaction() bool
@@ -520,7 +520,7 @@ func orderByToString(in any) string {
 
 func (route *Route) executeWarmingReplicaRead(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, queries []*querypb.BoundQuery) {
 	switch route.Opcode {
-	case Unsharded, Scatter, Equal, EqualUnique, IN, MultiEqual:
+	case Unsharded, Scatter, Equal, EqualUnique, IN, MultiEqual, Range:
 		// no-op
 	default:
 		return
@@ -261,6 +261,46 @@ func TestSelectEqualUnique(t *testing.T) {
 	expectResult(t, result, defaultSelectResult)
 }
 
+func TestSelectRange(t *testing.T) {
+	vindex, _ := vindexes.CreateVindex("numeric", "", nil)
+	sel := NewRoute(
+		Range,
+		&vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: true,
+		},
+		"dummy_select",
+		"dummy_select_field",
+	)
+	sel.Vindex = vindex.(vindexes.SingleColumn)
+
+	sel.Values = []evalengine.Expr{
+		evalengine.NewLiteralInt(1),
+		evalengine.NullExpr,
+	}
+	vc := &loggingVCursor{
+		shards:       []string{"-20", "20-"},
+		shardForKsid: []string{"20-"},
+		results:      []*sqltypes.Result{defaultSelectResult},
+	}
+	result, err := sel.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationKeyRange(0000000000000001-)`,
+		`ExecuteMultiShard ks.20-: dummy_select {} false false`,
+	})
+	expectResult(t, result, defaultSelectResult)
+
+	vc.Rewind()
+	result, err = wrapStreamExecute(sel, vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationKeyRange(0000000000000001-)`,
+		`StreamExecuteMulti dummy_select ks.20-: {} `,
+	})
+	expectResult(t, result, defaultSelectResult)
+}
+
 func TestSelectNone(t *testing.T) {
 	vindex, _ := vindexes.CreateVindex("hash", "", nil)
 	sel := NewRoute(
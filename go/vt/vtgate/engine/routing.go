@@ -56,6 +56,9 @@ const (
 	MultiEqual
 	// SubShard is for when we are missing one or more columns from a composite vindex
 	SubShard
+	// Range is for routing a statement to the shards that hold a range of vindex values.
+	// Requires: A RangeMapper Vindex, and two Values: the start and the end of the range.
+	Range
 	// Scatter is for routing a scattered statement.
 	Scatter
 	// Next is for fetching from a sequence.
@@ -86,6 +89,7 @@ var opName = map[Opcode]string{
 	None:          "None",
 	ByDestination: "ByDestination",
 	SubShard:      "SubShard",
+	Range:         "Range",
 }
 
 // MarshalJSON serializes the Opcode as a JSON string.
@@ -164,6 +168,8 @@ func (rp *RoutingParameters) findRoute(ctx context.Context, vcursor VCursor, bin
 		default:
 			return rp.multiEqual(ctx, vcursor, bindVars)
 		}
+	case Range:
+		return rp.keyRange(ctx, vcursor, bindVars)
 	default:
 		// Unreachable.
 		return nil, nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unsupported opcode: %v", rp.Opcode)
@@ -413,6 +419,23 @@ func (rp *RoutingParameters) multiEqual(ctx context.Context, vcursor VCursor, bi
 	return rss, multiBindVars, nil
 }
 
+func (rp *RoutingParameters) keyRange(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) ([]*srvtopo.ResolvedShard, []map[string]*querypb.BindVariable, error) {
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	start, err := env.Evaluate(rp.Values[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	end, err := env.Evaluate(rp.Values[1])
+	if err != nil {
+		return nil, nil, err
+	}
+	destination, err := rp.Vindex.(vindexes.RangeMapper).MapRange(ctx, vcursor, start.Value(vcursor.ConnCollation()), end.Value(vcursor.ConnCollation()))
+	if err != nil {
+		return nil, nil, err
+	}
+	return rp.byDestination(ctx, vcursor, bindVars, destination)
+}
+
 func (rp *RoutingParameters) multiEqualMultiCol(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) ([]*srvtopo.ResolvedShard, []map[string]*querypb.BindVariable, error) {
 	var multiColValues [][]sqltypes.Value
 	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
@@ -64,7 +64,8 @@ func (sa *ScalarAggregate) GetFields(ctx context.Context, vcursor VCursor, bindV
 		return nil, err
 	}
 
-	_, fields, err := newAggregation(qr.Fields, sa.Aggregates)
+	// only the fields are needed, so the group_concat_max_len of the session does not matter
+	_, fields, err := newAggregation(qr.Fields, sa.Aggregates, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -80,12 +81,17 @@ func (sa *ScalarAggregate) NeedsTransaction() bool {
 
 // TryExecute implements the Primitive interface
 func (sa *ScalarAggregate) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if vcursor.SpillDir() != "" {
+		// stream the input, so that a sort or a distinct feeding us can spill to disk
+		return executeSpilling(ctx, vcursor, bindVars, true, sa.TryStreamExecute)
+	}
+
 	result, err := vcursor.ExecutePrimitive(ctx, sa.Input, bindVars, true)
 	if err != nil {
 		return nil, err
 	}
 
-	agg, fields, err := newAggregation(result.Fields, sa.Aggregates)
+	agg, fields, err := newAggregation(result.Fields, sa.Aggregates, vcursor.GroupConcatMaxLen())
 	if err != nil {
 		return nil, err
 	}
@@ -96,9 +102,13 @@ func (sa *ScalarAggregate) TryExecute(ctx context.Context, vcursor VCursor, bind
 		}
 	}
 
+	row, err := agg.finish()
+	if err != nil {
+		return nil, err
+	}
 	out := &sqltypes.Result{
 		Fields: fields,
-		Rows:   [][]sqltypes.Value{agg.finish()},
+		Rows:   [][]sqltypes.Value{row},
 	}
 	return out.Truncate(sa.TruncateColumnCount), nil
 }
@@ -123,7 +133,7 @@ func (sa *ScalarAggregate) TryStreamExecute(ctx context.Context, vcursor VCursor
 
 		if agg == nil && len(result.Fields) != 0 {
 			var err error
-			agg, fields, err = newAggregation(result.Fields, sa.Aggregates)
+			agg, fields, err = newAggregation(result.Fields, sa.Aggregates, vcursor.GroupConcatMaxLen())
 			if err != nil {
 				return err
 			}
@@ -146,7 +156,11 @@ func (sa *ScalarAggregate) TryStreamExecute(ctx context.Context, vcursor VCursor
 		return err
 	}
 
-	return cb(&sqltypes.Result{Rows: [][]sqltypes.Value{agg.finish()}})
+	row, err := agg.finish()
+	if err != nil {
+		return err
+	}
+	return cb(&sqltypes.Result{Rows: [][]sqltypes.Value{row}})
 }
 
 // Inputs implements the Primitive interface
@@ -27,6 +27,7 @@ import (
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/utils"
+	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/sqlparser"
 	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
 )
@@ -418,3 +419,65 @@ func TestScalarGroupConcat(t *testing.T) {
 		})
 	}
 }
+
+func TestScalarGroupConcatMaxLen(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"group_concat(c1)",
+		"varchar",
+	)
+	fields[0].Charset = uint32(collations.CollationUtf8mb4ID)
+	binaryFields := sqltypes.MakeTestFields(
+		"group_concat(c1)",
+		"varbinary",
+	)
+
+	var tcases = []struct {
+		name     string
+		fields   []*querypb.Field
+		maxLen   uint64
+		input    []string
+		expected string
+	}{{
+		name:     "shorter than the max length",
+		fields:   fields,
+		maxLen:   5,
+		input:    []string{"ab", "c"},
+		expected: "ab,c",
+	}, {
+		name:     "truncated to the max length",
+		fields:   fields,
+		maxLen:   5,
+		input:    []string{"ab", "cd", "ef"},
+		expected: "ab,cd",
+	}, {
+		name:     "does not split multibyte characters",
+		fields:   fields,
+		maxLen:   4,
+		input:    []string{"aé", "é"},
+		expected: "aé,",
+	}, {
+		name:     "binary values are truncated to the byte",
+		fields:   binaryFields,
+		maxLen:   5,
+		input:    []string{"aé", "é"},
+		expected: "aé,\xc3",
+	}}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			fp := &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(tcase.fields, tcase.input...)}}
+			oa := &ScalarAggregate{
+				Aggregates: []*AggregateParams{{
+					Opcode: AggregateGroupConcat,
+					Col:    0,
+					Func:   &sqlparser.GroupConcatExpr{Separator: ","},
+				}},
+				Input: fp,
+			}
+			qr, err := oa.TryExecute(context.Background(), &loggingVCursor{groupConcatMaxLen: tcase.maxLen}, nil, false)
+			require.NoError(t, err)
+			require.Len(t, qr.Rows, 1)
+			assert.Equal(t, tcase.expected, qr.Rows[0][0].ToString())
+		})
+	}
+}
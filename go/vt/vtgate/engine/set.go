@@ -500,6 +500,12 @@ func (svss *SysVarSetAware) Execute(ctx context.Context, vcursor VCursor, env *e
 			return err
 		}
 		vcursor.Session().SetQueryTimeout(queryTimeout)
+	case sysvars.MaxExecutionTime.Name:
+		maxExecutionTime, err := svss.evalAsInt64(env, vcursor)
+		if err != nil {
+			return err
+		}
+		vcursor.Session().SetMaxExecutionTime(maxExecutionTime)
 	case sysvars.SessionEnableSystemSettings.Name:
 		err = svss.setBoolSysVar(ctx, env, vcursor.Session().SetSessionEnableSystemSettings)
 	case sysvars.Charset.Name, sysvars.Names.Name:
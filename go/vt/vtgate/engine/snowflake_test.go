@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSnowflakeGenerator creates a SnowflakeGenerator whose clock is stopped at now.
+func newTestSnowflakeGenerator(t *testing.T, nodeID int64, now time.Time) *SnowflakeGenerator {
+	sg, err := NewSnowflakeGenerator(nodeID)
+	require.NoError(t, err)
+	sg.now = func() time.Time { return now }
+	return sg
+}
+
+func TestNewSnowflakeGenerator(t *testing.T) {
+	_, err := NewSnowflakeGenerator(SnowflakeMaxNodeID)
+	require.NoError(t, err)
+	_, err = NewSnowflakeGenerator(SnowflakeMaxNodeID + 1)
+	require.EqualError(t, err, "snowflake node id must be between 0 and 1023: 1024")
+	_, err = NewSnowflakeGenerator(-1)
+	require.EqualError(t, err, "snowflake node id must be between 0 and 1023: -1")
+}
+
+func TestSnowflakeGeneratorNext(t *testing.T) {
+	now := snowflakeEpoch.Add(time.Millisecond)
+	sg := newTestSnowflakeGenerator(t, 7, now)
+	// Let the test move the clock.
+	sg.now = func() time.Time { return now }
+
+	// 1ms since the epoch, node id 7, counter 0.
+	ids, err := sg.Next(10)
+	require.NoError(t, err)
+	require.Len(t, ids, 10)
+	assert.EqualValues(t, 1<<22|7<<12, ids[0])
+	assert.EqualValues(t, 1<<22|7<<12|9, ids[9])
+
+	// The next ids of the same millisecond follow the reserved ones.
+	next, err := sg.Next(1)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{ids[0] + 10}, next)
+
+	// Once the counter is exhausted, the generator moves on to the next millisecond.
+	next, err = sg.Next(SnowflakeMaxCount)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2<<22|7<<12, next[0])
+	assert.EqualValues(t, 2<<22|7<<12|(SnowflakeMaxCount-1), next[SnowflakeMaxCount-1])
+
+	// The ids keep growing when the clock goes back in time.
+	now = snowflakeEpoch
+	prev := next[len(next)-1]
+	for range 3 {
+		next, err = sg.Next(SnowflakeMaxCount / 2)
+		require.NoError(t, err)
+		assert.Greater(t, next[0], prev)
+		prev = next[len(next)-1]
+	}
+
+	// A new millisecond resets the counter.
+	now = snowflakeEpoch.Add(time.Second)
+	next, err = sg.Next(1)
+	require.NoError(t, err)
+	assert.EqualValues(t, []int64{1000<<22 | 7<<12}, next)
+
+	_, err = sg.Next(0)
+	require.EqualError(t, err, "cannot generate 0 snowflake ids")
+}
+
+func TestSnowflakeGeneratorNextLargeBatch(t *testing.T) {
+	now := snowflakeEpoch.Add(time.Millisecond)
+	sg := newTestSnowflakeGenerator(t, 7, now)
+
+	// A batch larger than a millisecond's counter spreads over the following milliseconds.
+	count := int64(2*SnowflakeMaxCount + 100)
+	ids, err := sg.Next(count)
+	require.NoError(t, err)
+	require.Len(t, ids, int(count))
+	assert.EqualValues(t, 1<<22|7<<12, ids[0])
+	assert.EqualValues(t, 1<<22|7<<12|(SnowflakeMaxCount-1), ids[SnowflakeMaxCount-1])
+	assert.EqualValues(t, 2<<22|7<<12, ids[SnowflakeMaxCount])
+	assert.EqualValues(t, 3<<22|7<<12|99, ids[count-1])
+	for i := 1; i < len(ids); i++ {
+		require.Greater(t, ids[i], ids[i-1])
+	}
+
+	// The next batch continues in the last borrowed millisecond.
+	next, err := sg.Next(1)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3<<22 | 7<<12 | 100}, next)
+}
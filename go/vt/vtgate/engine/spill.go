@@ -0,0 +1,445 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bufio"
+	"container/heap"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vthash"
+)
+
+const (
+	// spillPartitionBits is the number of bits of the hash of a row that pick its partition.
+	spillPartitionBits = 5
+
+	// spillPartitions is the number of files the hash based primitives spread their rows over once
+	// they run out of memory. Each partition is then processed on its own, so we need roughly
+	// this many times less memory than we would have needed without spilling.
+	spillPartitions = 1 << spillPartitionBits
+
+	// spillMaxLevel is the deepest level a partition that is still too big to be processed in
+	// memory can be split to, each level using the next bits of the first 64 bits of the hash.
+	spillMaxLevel = 64/spillPartitionBits - 1
+)
+
+// spillBatchRows is the number of rows we send in each callback when streaming rows back from disk
+const spillBatchRows = 1000
+
+type (
+	// spillFile is a temporary file holding rows that didn't fit in memory.
+	// Rows are written one after the other, and can be read back in the same order.
+	spillFile struct {
+		file *os.File
+		w    *bufio.Writer
+		buf  []byte
+
+		rows, bytes int
+	}
+
+	spillReader struct {
+		r *bufio.Reader
+	}
+
+	// spillStats keeps track of how much a primitive spilled to disk, so we can report it to the vcursor
+	spillStats struct {
+		rows, bytes int
+	}
+
+	// sortedRuns stores sorted runs of rows on disk, and merges them back in order
+	sortedRuns struct {
+		dir  string
+		cmp  evalengine.Comparison
+		runs []*spillFile
+	}
+
+	// hashPartitions spreads rows over spillPartitions files on disk based on their hash,
+	// so that all the rows with the same hash end up in the same partition. A partition that
+	// is too big can be split again into partitions of the next level, which use other bits
+	// of the hash.
+	hashPartitions struct {
+		dir   string
+		level int
+		parts [spillPartitions]*spillFile
+	}
+)
+
+func newSpillFile(dir string) (*spillFile, error) {
+	file, err := os.CreateTemp(dir, "vtgate-spill-")
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to create spill file")
+	}
+	return &spillFile{
+		file: file,
+		w:    bufio.NewWriter(file),
+	}, nil
+}
+
+// write encodes the row as the number of values, followed by the type, length and bytes of each value
+func (sf *spillFile) write(row sqltypes.Row) error {
+	buf := binary.AppendUvarint(sf.buf[:0], uint64(len(row)))
+	for _, val := range row {
+		raw := val.Raw()
+		buf = binary.AppendUvarint(buf, uint64(val.Type()))
+		buf = binary.AppendUvarint(buf, uint64(len(raw)))
+		buf = append(buf, raw...)
+	}
+	sf.buf = buf
+	sf.rows++
+	sf.bytes += len(buf)
+	_, err := sf.w.Write(buf)
+	return err
+}
+
+// reader flushes everything written so far and returns a reader that starts at the first row
+func (sf *spillFile) reader() (*spillReader, error) {
+	if err := sf.w.Flush(); err != nil {
+		return nil, err
+	}
+	if _, err := sf.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return &spillReader{r: bufio.NewReader(sf.file)}, nil
+}
+
+func (sf *spillFile) close() {
+	_ = sf.file.Close()
+	_ = os.Remove(sf.file.Name())
+}
+
+// next returns the next row in the file, or io.EOF once all rows have been read
+func (sr *spillReader) next() (sqltypes.Row, error) {
+	cols, err := binary.ReadUvarint(sr.r)
+	if err != nil {
+		return nil, err
+	}
+	row := make(sqltypes.Row, cols)
+	for i := range row {
+		typ, err := binary.ReadUvarint(sr.r)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		size, err := binary.ReadUvarint(sr.r)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		if querypb.Type(typ) == sqltypes.Null {
+			row[i] = sqltypes.NULL
+			continue
+		}
+		raw := make([]byte, size)
+		if _, err := io.ReadFull(sr.r, raw); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		row[i] = sqltypes.MakeTrusted(querypb.Type(typ), raw)
+	}
+	return row, nil
+}
+
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// forEach calls f for every row in the file
+func (sf *spillFile) forEach(f func(sqltypes.Row) error) error {
+	r, err := sf.reader()
+	if err != nil {
+		return err
+	}
+	for {
+		row, err := r.next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := f(row); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *spillStats) add(sf *spillFile) {
+	s.rows += sf.rows
+	s.bytes += sf.bytes
+}
+
+// report sends the stats to the vcursor, if anything was spilled
+func (s *spillStats) report(vcursor VCursor) {
+	if s.rows > 0 {
+		vcursor.RecordSpill(s.rows, s.bytes)
+	}
+}
+
+// canSpill returns true when the primitive should spill to disk instead of
+// keeping more than numRows rows in memory
+func canSpill(vcursor VCursor, numRows int) bool {
+	return vcursor.SpillDir() != "" && vcursor.ExceedsMaxMemoryRows(numRows)
+}
+
+// executeSpilling is used by the TryExecute of the primitives that can spill to disk.
+// It runs their streaming implementation and gathers the output, so that the input
+// is streamed through the primitive and only the final result is held in memory.
+// That result still has to fit in max_memory_rows.
+func executeSpilling(
+	ctx context.Context,
+	vcursor VCursor,
+	bindVars map[string]*querypb.BindVariable,
+	wantfields bool,
+	stream func(context.Context, VCursor, map[string]*querypb.BindVariable, bool, func(*sqltypes.Result) error) error,
+) (*sqltypes.Result, error) {
+	var mu sync.Mutex
+	result := &sqltypes.Result{}
+	err := stream(ctx, vcursor, bindVars, wantfields, func(qr *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if result.Fields == nil {
+			result.Fields = qr.Fields
+		}
+		if result.InsertID == 0 {
+			result.InsertID = qr.InsertID
+		}
+		result.Rows = append(result.Rows, qr.Rows...)
+		if vcursor.ExceedsMaxMemoryRows(len(result.Rows)) {
+			return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func newSortedRuns(dir string, cmp evalengine.Comparison) *sortedRuns {
+	return &sortedRuns{dir: dir, cmp: cmp}
+}
+
+// add writes already sorted rows to disk as a new run
+func (sr *sortedRuns) add(rows []sqltypes.Row) error {
+	run, err := newSpillFile(sr.dir)
+	if err != nil {
+		return err
+	}
+	sr.runs = append(sr.runs, run)
+	for _, row := range rows {
+		if err := run.write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// merge streams the rows from all the runs on disk together with the sorted in-memory rows,
+// in order, stopping after limit rows.
+func (sr *sortedRuns) merge(inMemory []sqltypes.Row, limit int, callback func([]sqltypes.Row) error) (err error) {
+	defer evalengine.PanicHandler(&err)
+
+	h := &runHeap{cmp: sr.cmp}
+	for i, run := range sr.runs {
+		r, err := run.reader()
+		if err != nil {
+			return err
+		}
+		h.readers = append(h.readers, r)
+		if err := h.pushNext(i); err != nil {
+			return err
+		}
+	}
+	memIdx := len(sr.runs)
+	h.readers = append(h.readers, nil)
+	if len(inMemory) > 0 {
+		heap.Push(h, runRow{row: inMemory[0], source: memIdx})
+		inMemory = inMemory[1:]
+	}
+
+	var batch []sqltypes.Row
+	for sent := 0; h.Len() > 0 && sent < limit; sent++ {
+		next := heap.Pop(h).(runRow)
+		batch = append(batch, next.row)
+		if next.source == memIdx {
+			if len(inMemory) > 0 {
+				heap.Push(h, runRow{row: inMemory[0], source: memIdx})
+				inMemory = inMemory[1:]
+			}
+		} else if err := h.pushNext(next.source); err != nil {
+			return err
+		}
+		if len(batch) == spillBatchRows {
+			if err := callback(batch); err != nil {
+				return err
+			}
+			batch = nil
+		}
+	}
+	if len(batch) > 0 {
+		return callback(batch)
+	}
+	return nil
+}
+
+func (sr *sortedRuns) stats() (s spillStats) {
+	for _, run := range sr.runs {
+		s.add(run)
+	}
+	return
+}
+
+func (sr *sortedRuns) close() {
+	for _, run := range sr.runs {
+		run.close()
+	}
+}
+
+type (
+	runRow struct {
+		row    sqltypes.Row
+		source int
+	}
+
+	runHeap struct {
+		cmp     evalengine.Comparison
+		rows    []runRow
+		readers []*spillReader
+	}
+)
+
+func (h *runHeap) pushNext(source int) error {
+	row, err := h.readers[source].next()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	heap.Push(h, runRow{row: row, source: source})
+	return nil
+}
+
+func (h *runHeap) Len() int { return len(h.rows) }
+
+func (h *runHeap) Less(i, j int) bool {
+	if c := h.cmp.Compare(h.rows[i].row, h.rows[j].row); c != 0 {
+		return c < 0
+	}
+	// keep the merge stable, earlier runs first
+	return h.rows[i].source < h.rows[j].source
+}
+
+func (h *runHeap) Swap(i, j int) { h.rows[i], h.rows[j] = h.rows[j], h.rows[i] }
+
+func (h *runHeap) Push(x any) { h.rows = append(h.rows, x.(runRow)) }
+
+func (h *runHeap) Pop() any {
+	n := len(h.rows)
+	x := h.rows[n-1]
+	h.rows = h.rows[:n-1]
+	return x
+}
+
+func newHashPartitions(dir string) *hashPartitions {
+	return &hashPartitions{dir: dir}
+}
+
+// add writes the row to the partition the hash belongs to
+func (hp *hashPartitions) add(hash vthash.Hash, row sqltypes.Row) error {
+	idx := (binary.LittleEndian.Uint64(hash[:8]) >> (spillPartitionBits * hp.level)) % spillPartitions
+	part := hp.parts[idx]
+	if part == nil {
+		var err error
+		part, err = newSpillFile(hp.dir)
+		if err != nil {
+			return err
+		}
+		hp.parts[idx] = part
+	}
+	return part.write(row)
+}
+
+// forEach calls f for every row in the given partition
+func (hp *hashPartitions) forEach(idx int, f func(sqltypes.Row) error) error {
+	if hp.parts[idx] == nil {
+		return nil
+	}
+	return hp.parts[idx].forEach(f)
+}
+
+// rows returns the number of rows in the given partition
+func (hp *hashPartitions) rows(idx int) int {
+	if hp.parts[idx] == nil {
+		return 0
+	}
+	return hp.parts[idx].rows
+}
+
+// needsSplit returns true if the given partition holds more rows than we can keep in memory,
+// and can still be split further.
+func (hp *hashPartitions) needsSplit(vcursor VCursor, idx int) bool {
+	return hp.level < spillMaxLevel && vcursor.ExceedsMaxMemoryRows(hp.rows(idx))
+}
+
+// split spreads the rows of the given partition over the partitions of the next level,
+// using the hash function that spread them over this level.
+func (hp *hashPartitions) split(vcursor VCursor, idx int, hash func(sqltypes.Row) (vthash.Hash, error)) (*hashPartitions, error) {
+	sub := &hashPartitions{dir: hp.dir, level: hp.level + 1}
+	err := hp.forEach(idx, func(row sqltypes.Row) error {
+		h, err := hash(row)
+		if err != nil {
+			return err
+		}
+		return sub.add(h, row)
+	})
+	if err != nil {
+		sub.close()
+		return nil, err
+	}
+	stats := sub.stats()
+	stats.report(vcursor)
+	return sub, nil
+}
+
+func (hp *hashPartitions) stats() (s spillStats) {
+	for _, part := range hp.parts {
+		if part != nil {
+			s.add(part)
+		}
+	}
+	return
+}
+
+func (hp *hashPartitions) close() {
+	for _, part := range hp.parts {
+		if part != nil {
+			part.close()
+		}
+	}
+}
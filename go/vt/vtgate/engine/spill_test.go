@@ -0,0 +1,415 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vthash"
+)
+
+// withSpilling makes the noopVCursor spill once more than maxRows rows are held in memory
+func withSpilling(t *testing.T, maxRows int) {
+	saveMax, saveIgnore, saveDir := testMaxMemoryRows, testIgnoreMaxMemoryRows, testSpillDir
+	testMaxMemoryRows, testIgnoreMaxMemoryRows, testSpillDir = maxRows, false, t.TempDir()
+	testSpilledRows = 0
+	t.Cleanup(func() {
+		testMaxMemoryRows, testIgnoreMaxMemoryRows, testSpillDir = saveMax, saveIgnore, saveDir
+		testSpilledRows = 0
+	})
+}
+
+// assertNoSpillFiles checks that all the temporary files were removed after the query
+func assertNoSpillFiles(t *testing.T) {
+	entries, err := os.ReadDir(testSpillDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestSpillFileRoundTrip(t *testing.T) {
+	sf, err := newSpillFile(t.TempDir())
+	require.NoError(t, err)
+	defer sf.close()
+
+	rows := []sqltypes.Row{
+		{sqltypes.NewInt64(1), sqltypes.NewVarChar("a"), sqltypes.NULL},
+		{sqltypes.NewInt64(-2), sqltypes.NewVarChar(""), sqltypes.NewFloat64(1.5)},
+		{},
+	}
+	for _, row := range rows {
+		require.NoError(t, sf.write(row))
+	}
+
+	var got []sqltypes.Row
+	require.NoError(t, sf.forEach(func(row sqltypes.Row) error {
+		got = append(got, row)
+		return nil
+	}))
+	assert.Equal(t, fmt.Sprint(rows), fmt.Sprint(got))
+	assert.Equal(t, 3, sf.rows)
+}
+
+func TestMemorySortSpill(t *testing.T) {
+	withSpilling(t, 2)
+
+	fields := sqltypes.MakeTestFields(
+		"c1|c2",
+		"varbinary|decimal",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			fields,
+			"a|5",
+			"g|2",
+			"a|1",
+			"c|4",
+			"c|3",
+			"d|1",
+			"e|6",
+		)},
+	}
+
+	ms := &MemorySort{
+		OrderBy: []evalengine.OrderByParams{{
+			WeightStringCol: -1,
+			Col:             1,
+		}},
+		Input: fp,
+	}
+
+	result, err := wrapStreamExecute(ms, &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	wantResult := sqltypes.MakeTestResult(
+		fields,
+		"a|1",
+		"d|1",
+		"g|2",
+		"c|3",
+		"c|4",
+		"a|5",
+		"e|6",
+	)
+	utils.MustMatch(t, wantResult, result)
+	assert.NotZero(t, testSpilledRows)
+	assertNoSpillFiles(t)
+
+	// the non-streaming path spills as well, but its result must still fit in memory
+	fp.rewind()
+	_, err = ms.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.EqualError(t, err, "in-memory row count exceeded allowed limit of 2")
+	assertNoSpillFiles(t)
+
+	fp.rewind()
+	ms.UpperLimit = evalengine.NewBindVar("__upper_limit", evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID))
+	bv := map[string]*querypb.BindVariable{"__upper_limit": sqltypes.Int64BindVariable(3)}
+	result, err = wrapStreamExecute(ms, &noopVCursor{}, bv, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, sqltypes.MakeTestResult(fields, "a|1", "d|1", "g|2"), result)
+}
+
+func TestDistinctSpill(t *testing.T) {
+	withSpilling(t, 2)
+
+	input := r("myid|id", "varchar|int64", "monkey|1", "horse|1", "Horse|1", "Monkey|1", "horses|1", "MONKEY|2", "horse|1", "null|null", "null|null")
+	fp := &fakePrimitive{results: []*sqltypes.Result{input}}
+	distinct := &Distinct{
+		Source: fp,
+		CheckCols: []CheckCol{{
+			Col:          0,
+			Type:         evalengine.NewType(sqltypes.VarChar, collations.CollationUtf8mb4ID),
+			CollationEnv: collations.MySQL8(),
+		}, {
+			Col:          1,
+			Type:         evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID),
+			CollationEnv: collations.MySQL8(),
+		}},
+	}
+
+	result, err := wrapStreamExecute(distinct, &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	want := r("myid|id", "varchar|int64", "monkey|1", "horse|1", "horses|1", "MONKEY|2", "null|null")
+	expectResultAnyOrder(t, result, want)
+	assert.NotZero(t, testSpilledRows)
+	assertNoSpillFiles(t)
+
+	fp.rewind()
+	_, err = distinct.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.EqualError(t, err, "in-memory row count exceeded allowed limit of 2")
+	assertNoSpillFiles(t)
+}
+
+func TestHashJoinSpill(t *testing.T) {
+	lhs := func() Primitive {
+		return &fakePrimitive{
+			results: []*sqltypes.Result{
+				sqltypes.MakeTestResult(
+					sqltypes.MakeTestFields(
+						"a|lval",
+						"int64|int64",
+					),
+					"1|10",
+					"1|20",
+					"2|30",
+					"null|40",
+					"4|50",
+					"5|60",
+				),
+			},
+		}
+	}
+	rhs := func() Primitive {
+		return &fakePrimitive{
+			results: []*sqltypes.Result{
+				sqltypes.MakeTestResult(
+					sqltypes.MakeTestFields(
+						"c|rval",
+						"int64|int64",
+					),
+					"1|15",
+					"2|5",
+					"3|1",
+					"null|7",
+					"5|70",
+				),
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		typ      JoinOpcode
+		expected []string
+	}{{
+		name:     "inner join",
+		typ:      InnerJoin,
+		expected: []string{"10|15", "20|15", "30|5", "60|70"},
+	}, {
+		name:     "left join",
+		typ:      LeftJoin,
+		expected: []string{"10|15", "20|15", "30|5", "40|null", "50|null", "60|70"},
+	}, {
+		name:     "right join",
+		typ:      RightJoin,
+		expected: []string{"10|15", "20|15", "30|5", "null|1", "null|7", "60|70"},
+	}}
+
+	fields := sqltypes.MakeTestFields("lval|rval", "int64|int64")
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			withSpilling(t, 2)
+			jn := &HashJoin{
+				Opcode: tc.typ,
+				Left:   lhs(),
+				Right:  rhs(),
+				Cols:   []int{-2, 2},
+				Keys: []HashJoinKey{{
+					LHS:            0,
+					RHS:            0,
+					Collation:      collations.CollationBinaryID,
+					ComparisonType: sqltypes.Int64,
+				}},
+				CollationEnv: collations.MySQL8(),
+			}
+			result, err := wrapStreamExecute(jn, &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+			require.NoError(t, err)
+			expectResultAnyOrder(t, result, sqltypes.MakeTestResult(fields, tc.expected...))
+			assert.NotZero(t, testSpilledRows)
+			assertNoSpillFiles(t)
+
+			jn.Left, jn.Right = lhs(), rhs()
+			_, err = jn.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+			require.EqualError(t, err, "in-memory row count exceeded allowed limit of 2")
+			assertNoSpillFiles(t)
+		})
+	}
+}
+
+func TestOrderedAggregateSpill(t *testing.T) {
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"col|count(*)",
+				"varbinary|decimal",
+			),
+			"c|3",
+			"a|1",
+			"b|2",
+			"c|4",
+			"a|1",
+			"b|2",
+		)},
+	}
+	// the aggregation needs its input sorted, which is where we run out of memory
+	oa := &OrderedAggregate{
+		Aggregates:  []*AggregateParams{NewAggregateParam(opcode.AggregateSum, 1, "", collations.MySQL8())},
+		GroupByKeys: []*GroupByParams{{KeyCol: 0}},
+		Input: &MemorySort{
+			OrderBy: []evalengine.OrderByParams{{
+				WeightStringCol: -1,
+				Col:             0,
+			}},
+			Input: fp,
+		},
+	}
+
+	want, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	require.Len(t, want.Rows, 3)
+
+	// the sort holds more rows than allowed, but the aggregated result fits
+	withSpilling(t, 3)
+	for _, execute := range []func() (*sqltypes.Result, error){
+		func() (*sqltypes.Result, error) {
+			return oa.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+		},
+		func() (*sqltypes.Result, error) {
+			return wrapStreamExecute(oa, &noopVCursor{}, nil, true)
+		},
+	} {
+		fp.rewind()
+		testSpilledRows = 0
+		result, err := execute()
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprint(want.Rows), fmt.Sprint(result.Rows))
+		assert.NotZero(t, testSpilledRows)
+		assertNoSpillFiles(t)
+	}
+}
+
+func TestHashPartitionsSplit(t *testing.T) {
+	withSpilling(t, 2)
+
+	// the hash of a row is its only value, so we know which partition it goes to
+	hash := func(row sqltypes.Row) (vthash.Hash, error) {
+		v, err := row[0].ToUint64()
+		var h vthash.Hash
+		binary.LittleEndian.PutUint64(h[:8], v)
+		return h, err
+	}
+	hp := newHashPartitions(testSpillDir)
+	defer hp.close()
+	for _, v := range []uint64{0, 1 << spillPartitionBits, 3 << spillPartitionBits, 3 << spillPartitionBits, 1} {
+		row := sqltypes.Row{sqltypes.NewUint64(v)}
+		h, err := hash(row)
+		require.NoError(t, err)
+		require.NoError(t, hp.add(h, row))
+	}
+	assert.Equal(t, 4, hp.rows(0))
+	assert.Equal(t, 1, hp.rows(1))
+	assert.True(t, hp.needsSplit(&noopVCursor{}, 0))
+	assert.False(t, hp.needsSplit(&noopVCursor{}, 1))
+
+	// the next level uses the next bits of the hash
+	sub, err := hp.split(&noopVCursor{}, 0, hash)
+	require.NoError(t, err)
+	defer sub.close()
+	assert.Equal(t, 1, sub.level)
+	assert.Equal(t, 1, sub.rows(0))
+	assert.Equal(t, 1, sub.rows(1))
+	assert.Equal(t, 2, sub.rows(3))
+	assert.EqualValues(t, 4, testSpilledRows)
+
+	// partitions of the last level are never split again
+	sub.level = spillMaxLevel
+	assert.False(t, sub.needsSplit(&noopVCursor{}, 3))
+}
+
+func TestDistinctSpillSplitsPartitions(t *testing.T) {
+	fields := sqltypes.MakeTestFields("id", "int64")
+	var rows, want []string
+	for i := range 500 {
+		rows = append(rows, fmt.Sprint(i), fmt.Sprint(i/2))
+		want = append(want, fmt.Sprint(i))
+	}
+	fp := &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, rows...)}}
+	distinct := &Distinct{
+		Source: fp,
+		CheckCols: []CheckCol{{
+			Col:          0,
+			Type:         evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID),
+			CollationEnv: collations.MySQL8(),
+		}},
+	}
+
+	// the partitions of the first level get about 15 rows each, more than fit in memory
+	withSpilling(t, 5)
+	result, err := wrapStreamExecute(distinct, &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	expectResultAnyOrder(t, result, sqltypes.MakeTestResult(fields, want...))
+	// the rows of the first level were written again to the partitions of the next one
+	assert.Greater(t, testSpilledRows, 1000)
+	assertNoSpillFiles(t)
+}
+
+func TestHashJoinSpillSkewedKeys(t *testing.T) {
+	// most of the LHS rows share the same key, so their partition can never be split
+	// small enough to fit in memory, and it is joined in chunks instead
+	lhs := func() Primitive {
+		rows := []string{"2|100", "null|200"}
+		for i := range 9 {
+			rows = append(rows, fmt.Sprintf("1|%d", i))
+		}
+		return &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("a|lval", "int64|int64"), rows...)}}
+	}
+	rhs := func() Primitive {
+		return &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("c|rval", "int64|int64"),
+			"1|10", "3|30", "1|11", "null|40", "1|12",
+		)}}
+	}
+
+	for _, typ := range []JoinOpcode{InnerJoin, LeftJoin, RightJoin} {
+		t.Run(typ.String(), func(t *testing.T) {
+			jn := &HashJoin{
+				Opcode: typ,
+				Left:   lhs(),
+				Right:  rhs(),
+				Cols:   []int{-2, 2},
+				Keys: []HashJoinKey{{
+					LHS:            0,
+					RHS:            0,
+					Collation:      collations.CollationBinaryID,
+					ComparisonType: sqltypes.Int64,
+				}},
+				CollationEnv: collations.MySQL8(),
+			}
+			want, err := jn.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+			require.NoError(t, err)
+
+			withSpilling(t, 2)
+			jn.Left, jn.Right = lhs(), rhs()
+			result, err := wrapStreamExecute(jn, &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+			require.NoError(t, err)
+			expectResultAnyOrder(t, result, want)
+			assert.NotZero(t, testSpilledRows)
+			assertNoSpillFiles(t)
+		})
+	}
+}
@@ -127,45 +127,54 @@ func (ps *UncorrelatedSubquery) execSubquery(ctx context.Context, vcursor VCurso
 	for k, v := range bindVars {
 		combinedVars[k] = v
 	}
-	switch ps.Opcode {
+	if err := setPulloutVars(ps.Opcode, ps.SubqueryResult, ps.HasValues, result.Rows, combinedVars); err != nil {
+		return nil, err
+	}
+	return combinedVars, nil
+}
+
+// setPulloutVars adds the bind variables that represent the result of a subquery
+// with the given opcode to vars. It's shared by the primitives that pull out subqueries.
+func setPulloutVars(opcode PulloutOpcode, resultName, hasValuesName string, rows []sqltypes.Row, vars map[string]*querypb.BindVariable) error {
+	switch opcode {
 	case PulloutValue:
-		switch len(result.Rows) {
+		switch len(rows) {
 		case 0:
-			combinedVars[ps.SubqueryResult] = sqltypes.NullBindVariable
+			vars[resultName] = sqltypes.NullBindVariable
 		case 1:
-			combinedVars[ps.SubqueryResult] = sqltypes.ValueBindVariable(result.Rows[0][0])
+			vars[resultName] = sqltypes.ValueBindVariable(rows[0][0])
 		default:
-			return nil, errSqRow
+			return errSqRow
 		}
 	case PulloutIn, PulloutNotIn:
-		switch len(result.Rows) {
+		switch len(rows) {
 		case 0:
-			combinedVars[ps.HasValues] = sqltypes.Int64BindVariable(0)
+			vars[hasValuesName] = sqltypes.Int64BindVariable(0)
 			// Add a bogus value. It will not be checked.
-			combinedVars[ps.SubqueryResult] = &querypb.BindVariable{
+			vars[resultName] = &querypb.BindVariable{
 				Type:   querypb.Type_TUPLE,
 				Values: []*querypb.Value{sqltypes.ValueToProto(sqltypes.NewInt64(0))},
 			}
 		default:
-			combinedVars[ps.HasValues] = sqltypes.Int64BindVariable(1)
+			vars[hasValuesName] = sqltypes.Int64BindVariable(1)
 			values := &querypb.BindVariable{
 				Type:   querypb.Type_TUPLE,
-				Values: make([]*querypb.Value, len(result.Rows)),
+				Values: make([]*querypb.Value, len(rows)),
 			}
-			for i, v := range result.Rows {
+			for i, v := range rows {
 				values.Values[i] = sqltypes.ValueToProto(v[0])
 			}
-			combinedVars[ps.SubqueryResult] = values
+			vars[resultName] = values
 		}
-	case PulloutExists:
-		switch len(result.Rows) {
+	case PulloutExists, PulloutNotExists:
+		switch len(rows) {
 		case 0:
-			combinedVars[ps.HasValues] = sqltypes.Int64BindVariable(0)
+			vars[hasValuesName] = sqltypes.Int64BindVariable(0)
 		default:
-			combinedVars[ps.HasValues] = sqltypes.Int64BindVariable(1)
+			vars[hasValuesName] = sqltypes.Int64BindVariable(1)
 		}
 	}
-	return combinedVars, nil
+	return nil
 }
 
 func (ps *UncorrelatedSubquery) description() PrimitiveDescription {
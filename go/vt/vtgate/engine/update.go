@@ -65,7 +65,7 @@ func (upd *Update) TryExecute(ctx context.Context, vcursor VCursor, bindVars map
 	switch upd.Opcode {
 	case Unsharded:
 		return upd.execUnsharded(ctx, upd, vcursor, bindVars, rss)
-	case Equal, EqualUnique, IN, Scatter, ByDestination, SubShard, MultiEqual:
+	case Equal, EqualUnique, IN, Scatter, ByDestination, SubShard, MultiEqual, Range:
 		return upd.execMultiDestination(ctx, upd, vcursor, bindVars, rss, upd.updateVindexEntries, bvs)
 	default:
 		// Unreachable.
@@ -120,6 +120,8 @@ func (upd *Update) updateVindexEntries(ctx context.Context, vcursor VCursor, bin
 	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
 
 	for _, row := range subQueryResult.Rows {
+		// new values that depend on the row are read from the owned vindex query
+		env.Row = row
 		ksid, err := resolveKeyspaceID(ctx, vcursor, upd.KsidVindex, row[0:upd.KsidLength])
 		if err != nil {
 			return err
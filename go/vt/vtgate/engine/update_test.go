@@ -21,6 +21,7 @@ import (
 	"errors"
 	"testing"
 
+	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 
@@ -665,6 +666,59 @@ func TestUpdateInMultiCol(t *testing.T) {
 	})
 }
 
+func TestUpdateEqualChangedVindexExpression(t *testing.T) {
+	ks := buildTestVSchema().Keyspaces["sharded"]
+	upd := &Update{
+		DML: &DML{
+			RoutingParameters: &RoutingParameters{
+				Opcode:   Equal,
+				Keyspace: ks.Keyspace,
+				Vindex:   ks.Vindexes["hash"],
+				Values:   []evalengine.Expr{evalengine.NewLiteralInt(1)},
+			},
+			Query:            "dummy_update",
+			TableNames:       []string{ks.Tables["t1"].Name.String()},
+			Vindexes:         ks.Tables["t1"].Owned,
+			OwnedVindexQuery: "dummy_subquery",
+			KsidVindex:       ks.Vindexes["hash"],
+			KsidLength:       1,
+		},
+		ChangedVindexValues: map[string]*VindexValues{
+			"onecol": {
+				// the new value of c3 is computed by the owned vindex query
+				EvalExprMap: map[string]evalengine.Expr{
+					"c3": evalengine.NewColumn(5, evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID), nil),
+				},
+				Offset: 4,
+			},
+		},
+	}
+
+	results := []*sqltypes.Result{sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"id|c1|c2|c3|onecol|c3_new",
+			"int64|int64|int64|int64|int64|int64",
+		),
+		"1|4|5|6|0|7",
+		"1|4|5|8|0|9",
+	)}
+	vc := newDMLTestVCursor("-20", "20-")
+	vc.results = results
+
+	_, err := upd.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations sharded [type:INT64 value:"1"] Destinations:DestinationKeyspaceID(166b40b44aba4bd6)`,
+		`ExecuteMultiShard sharded.-20: dummy_subquery {} false false`,
+		// each row gets its own new value for c3.
+		`Execute delete from lkp1 where from = :from and toc = :toc from: type:INT64 value:"6" toc: type:VARBINARY value:"\x16k@\xb4J\xbaK\xd6" true`,
+		`Execute insert into lkp1(from, toc) values(:from_0, :toc_0) from_0: type:INT64 value:"7" toc_0: type:VARBINARY value:"\x16k@\xb4J\xbaK\xd6" true`,
+		`Execute delete from lkp1 where from = :from and toc = :toc from: type:INT64 value:"8" toc: type:VARBINARY value:"\x16k@\xb4J\xbaK\xd6" true`,
+		`Execute insert into lkp1(from, toc) values(:from_0, :toc_0) from_0: type:INT64 value:"9" toc_0: type:VARBINARY value:"\x16k@\xb4J\xbaK\xd6" true`,
+		`ExecuteMultiShard sharded.-20: dummy_update {} true true`,
+	})
+}
+
 func TestUpdateInChangedVindex(t *testing.T) {
 	ks := buildTestVSchema().Keyspaces["sharded"]
 	upd := &Update{
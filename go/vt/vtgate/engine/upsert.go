@@ -19,12 +19,16 @@ package engine
 import (
 	"context"
 	"fmt"
+	"maps"
+	"strings"
 
 	"vitess.io/vitess/go/sqltypes"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 )
 
 var _ Primitive = (*Upsert)(nil)
@@ -34,9 +38,19 @@ var _ Primitive = (*Upsert)(nil)
 type Upsert struct {
 	txNeeded
 	Upserts []upsert
+
+	// Rows and Input are set when a single upsert is planned for all the rows.
+	// The values of each row are bound to BindVars before executing it.
+	// The rows are either the evaluated Rows expressions, or the rows returned by Input.
+	Rows     [][]evalengine.Expr
+	Input    Primitive
+	BindVars []string
 }
 
 type upsert struct {
+	// Check, if set, is executed first. If it returns any rows, the row already exists
+	// and the update is executed without trying the insert.
+	Check  Primitive
 	Insert Primitive
 	Update Primitive
 }
@@ -49,6 +63,15 @@ func (u *Upsert) AddUpsert(ins, upd Primitive) {
 	})
 }
 
+// AddCheckedUpsert appends to the Upsert Primitive, with a check for the existing row.
+func (u *Upsert) AddCheckedUpsert(check, ins, upd Primitive) {
+	u.Upserts = append(u.Upserts, upsert{
+		Check:  check,
+		Insert: ins,
+		Update: upd,
+	})
+}
+
 // RouteType implements Primitive interface type.
 func (u *Upsert) RouteType() string {
 	return "UPSERT"
@@ -77,6 +100,9 @@ func (u *Upsert) GetFields(ctx context.Context, vcursor VCursor, bindVars map[st
 
 // TryExecute implements Primitive interface type.
 func (u *Upsert) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if u.Rows != nil || u.Input != nil {
+		return u.execRows(ctx, vcursor, bindVars, wantfields)
+	}
 	result := &sqltypes.Result{}
 	for _, up := range u.Upserts {
 		qr, err := execOne(ctx, vcursor, bindVars, wantfields, up)
@@ -88,7 +114,70 @@ func (u *Upsert) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[s
 	return result, nil
 }
 
+// execRows executes the upsert once for every row, with the values of the row bound to BindVars.
+func (u *Upsert) execRows(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if len(u.Upserts) != 1 {
+		return nil, vterrors.VT13001(fmt.Sprintf("expected a single upsert for the rows, got %d", len(u.Upserts)))
+	}
+	rows, err := u.getRows(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &sqltypes.Result{}
+	for _, row := range rows {
+		if len(row) != len(u.BindVars) {
+			return nil, vterrors.VT03006()
+		}
+		rowBindVars := maps.Clone(bindVars)
+		for i, bvName := range u.BindVars {
+			rowBindVars[bvName] = sqltypes.ValueBindVariable(row[i])
+		}
+		qr, err := execOne(ctx, vcursor, rowBindVars, wantfields, u.Upserts[0])
+		if err != nil {
+			return nil, err
+		}
+		result.RowsAffected += qr.RowsAffected
+	}
+	return result, nil
+}
+
+// getRows returns the rows to upsert, either by evaluating the row expressions or by executing the input.
+func (u *Upsert) getRows(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) ([]sqltypes.Row, error) {
+	if u.Input != nil {
+		qr, err := vcursor.ExecutePrimitive(ctx, u.Input, bindVars, false)
+		if err != nil {
+			return nil, err
+		}
+		return qr.Rows, nil
+	}
+
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	rows := make([]sqltypes.Row, 0, len(u.Rows))
+	for _, exprs := range u.Rows {
+		row := make(sqltypes.Row, 0, len(exprs))
+		for _, expr := range exprs {
+			res, err := env.Evaluate(expr)
+			if err != nil {
+				return nil, err
+			}
+			row = append(row, res.Value(vcursor.ConnCollation()))
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
 func execOne(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, up upsert) (*sqltypes.Result, error) {
+	if up.Check != nil {
+		checkQr, err := vcursor.ExecutePrimitive(ctx, up.Check, bindVars, false)
+		if err != nil {
+			return nil, err
+		}
+		if len(checkQr.Rows) > 0 {
+			return execUpdate(ctx, vcursor, bindVars, wantfields, up)
+		}
+	}
 	insQr, err := vcursor.ExecutePrimitive(ctx, up.Insert, bindVars, wantfields)
 	if err == nil {
 		return insQr, nil
@@ -96,6 +185,10 @@ func execOne(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.
 	if vterrors.Code(err) != vtrpcpb.Code_ALREADY_EXISTS {
 		return nil, err
 	}
+	return execUpdate(ctx, vcursor, bindVars, wantfields, up)
+}
+
+func execUpdate(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, up upsert) (*sqltypes.Result, error) {
 	updQr, err := vcursor.ExecutePrimitive(ctx, up.Update, bindVars, wantfields)
 	if err != nil {
 		return nil, err
@@ -120,7 +213,15 @@ func (u *Upsert) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars
 func (u *Upsert) Inputs() ([]Primitive, []map[string]any) {
 	var inputs []Primitive
 	var inputsMap []map[string]any
+	if u.Input != nil {
+		inputs = append(inputs, u.Input)
+		inputsMap = append(inputsMap, map[string]any{inputName: "Rows"})
+	}
 	for i, up := range u.Upserts {
+		if up.Check != nil {
+			inputs = append(inputs, up.Check)
+			inputsMap = append(inputsMap, map[string]any{inputName: fmt.Sprintf("Check-%d", i+1)})
+		}
 		inputs = append(inputs, up.Insert, up.Update)
 		inputsMap = append(inputsMap,
 			map[string]any{inputName: fmt.Sprintf("Insert-%d", i+1)},
@@ -130,8 +231,24 @@ func (u *Upsert) Inputs() ([]Primitive, []map[string]any) {
 }
 
 func (u *Upsert) description() PrimitiveDescription {
+	var other map[string]any
+	if len(u.BindVars) > 0 {
+		other = map[string]any{"BindVars": u.BindVars}
+		if len(u.Rows) > 0 {
+			var rows []string
+			for _, row := range u.Rows {
+				var vals []string
+				for _, expr := range row {
+					vals = append(vals, sqlparser.String(expr))
+				}
+				rows = append(rows, "("+strings.Join(vals, ", ")+")")
+			}
+			other["Rows"] = rows
+		}
+	}
 	return PrimitiveDescription{
 		OperatorType:     "Upsert",
 		TargetTabletType: topodatapb.TabletType_PRIMARY,
+		Other:            other,
 	}
 }
@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+var errDupKey = vterrors.New(vtrpcpb.Code_ALREADY_EXISTS, "Duplicate entry '1' for key 'PRIMARY'")
+
+func TestUpsertRows(t *testing.T) {
+	ins := &fakePrimitive{
+		results: []*sqltypes.Result{{RowsAffected: 1}, nil},
+		sendErr: errDupKey,
+	}
+	upd := &fakePrimitive{results: []*sqltypes.Result{{RowsAffected: 1}}}
+	up := &Upsert{
+		Rows: [][]evalengine.Expr{
+			{evalengine.NewLiteralInt(1), evalengine.NewBindVar("v1", evalengine.Type{})},
+			{evalengine.NewLiteralInt(2), evalengine.NewLiteralInt(7)},
+		},
+		BindVars: []string{"upsert_id", "upsert_col"},
+	}
+	up.AddUpsert(ins, upd)
+
+	qr, err := up.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{
+		"v1": sqltypes.Int64BindVariable(10),
+	}, false)
+	require.NoError(t, err)
+	// the first row is inserted, the second one already exists and is updated instead.
+	assert.EqualValues(t, 3, qr.RowsAffected)
+	ins.ExpectLog(t, []string{
+		`Execute upsert_col: type:INT64 value:"10" upsert_id: type:INT64 value:"1" v1: type:INT64 value:"10" false`,
+		`Execute upsert_col: type:INT64 value:"7" upsert_id: type:INT64 value:"2" v1: type:INT64 value:"10" false`,
+	})
+	upd.ExpectLog(t, []string{
+		`Execute upsert_col: type:INT64 value:"7" upsert_id: type:INT64 value:"2" v1: type:INT64 value:"10" false`,
+	})
+}
+
+func TestUpsertInputWithCheck(t *testing.T) {
+	input := &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("id|col", "int64|varchar"),
+		"1|a",
+		"2|b",
+	)}}
+	check := &fakePrimitive{results: []*sqltypes.Result{
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("1", "int64"), "1"),
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("1", "int64")),
+	}}
+	ins := &fakePrimitive{results: []*sqltypes.Result{{RowsAffected: 1}}}
+	upd := &fakePrimitive{results: []*sqltypes.Result{{RowsAffected: 0}}}
+	up := &Upsert{
+		Input:    input,
+		BindVars: []string{"upsert_id", "upsert_col"},
+	}
+	up.AddCheckedUpsert(check, ins, upd)
+
+	qr, err := up.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	// the first row exists and is not changed by the update, the second one is inserted.
+	assert.EqualValues(t, 1, qr.RowsAffected)
+	check.ExpectLog(t, []string{
+		`Execute upsert_col: type:VARCHAR value:"a" upsert_id: type:INT64 value:"1" false`,
+		`Execute upsert_col: type:VARCHAR value:"b" upsert_id: type:INT64 value:"2" false`,
+	})
+	upd.ExpectLog(t, []string{
+		`Execute upsert_col: type:VARCHAR value:"a" upsert_id: type:INT64 value:"1" false`,
+	})
+	ins.ExpectLog(t, []string{
+		`Execute upsert_col: type:VARCHAR value:"b" upsert_id: type:INT64 value:"2" false`,
+	})
+
+	// the input returning rows of the wrong size is a column count mismatch.
+	input.rewind()
+	up.BindVars = []string{"upsert_id"}
+	_, err = up.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, false)
+	require.ErrorContains(t, err, "VT03006")
+}
+
+func TestUpsertDescription(t *testing.T) {
+	up := &Upsert{
+		Rows:     [][]evalengine.Expr{{evalengine.NewLiteralInt(1), evalengine.NewBindVar("v1", evalengine.Type{})}},
+		BindVars: []string{"upsert_id", "upsert_col"},
+	}
+	desc := up.description()
+	assert.Equal(t, []string{"upsert_id", "upsert_col"}, desc.Other["BindVars"])
+	assert.Equal(t, []string{"(1, :v1)"}, desc.Other["Rows"])
+}
@@ -0,0 +1,392 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+var _ Primitive = (*Window)(nil)
+
+// Window is a primitive that evaluates window functions at the vtgate.
+// It expects the underlying primitive to feed rows sorted by the PartitionBy
+// keys followed by the OrderBy keys, which is what a merge-sorted scatter
+// query produces. All functions evaluated by a single Window share the same
+// window specification, and use the default window frame: the whole partition
+// when there is no ORDER BY, and all rows up to the last peer of the current
+// row otherwise.
+type Window struct {
+	// Functions specifies the window functions to evaluate.
+	// The result of each function replaces the value of its input column.
+	Functions []*WindowFuncParams
+
+	// PartitionBy specifies the input columns of the PARTITION BY clause.
+	PartitionBy []*GroupByParams
+
+	// OrderBy specifies the input columns of the window ORDER BY clause.
+	// Rows that have the same values for all these columns are peers.
+	OrderBy []*GroupByParams
+
+	// TruncateColumnCount specifies the number of columns to return
+	// in the final result. Rest of the columns are truncated
+	// from the result received. If 0, no truncation happens.
+	TruncateColumnCount int
+
+	// Input is the primitive that will feed into this Primitive.
+	Input Primitive
+}
+
+// WindowFuncParams specify the parameters for each window function.
+type WindowFuncParams struct {
+	Opcode WindowOpcode
+	// Col is the input column holding the argument of the function.
+	// Functions that don't take an argument still own a column in the input,
+	// since this is where the result of the function is written.
+	Col int
+
+	// Offset is the number of rows LAG and LEAD look behind or ahead.
+	Offset int
+	// DefaultCol is the input column holding the default value of LAG and LEAD,
+	// or -1 if NULL should be used.
+	DefaultCol int
+
+	Alias    string
+	Original *sqlparser.AliasedExpr
+}
+
+// NewWindowFuncParam creates the parameters for a window function that uses no default value.
+func NewWindowFuncParam(opcode WindowOpcode, col int, alias string) *WindowFuncParams {
+	return &WindowFuncParams{
+		Opcode:     opcode,
+		Col:        col,
+		Offset:     1,
+		DefaultCol: -1,
+		Alias:      alias,
+	}
+}
+
+func (wf *WindowFuncParams) String() string {
+	args := strconv.Itoa(wf.Col)
+	if wf.Opcode == WindowLag || wf.Opcode == WindowLead {
+		args = fmt.Sprintf("%s, %d", args, wf.Offset)
+		if wf.DefaultCol >= 0 {
+			args = fmt.Sprintf("%s, %d", args, wf.DefaultCol)
+		}
+	}
+	if wf.Alias != "" {
+		return fmt.Sprintf("%s(%s) AS %s", wf.Opcode.String(), args, wf.Alias)
+	}
+	return fmt.Sprintf("%s(%s)", wf.Opcode.String(), args)
+}
+
+// RouteType returns a description of the query routing type used by the primitive
+func (w *Window) RouteType() string {
+	return w.Input.RouteType()
+}
+
+// GetKeyspaceName specifies the Keyspace that this primitive routes to.
+func (w *Window) GetKeyspaceName() string {
+	return w.Input.GetKeyspaceName()
+}
+
+// GetTableName specifies the table that this primitive routes to.
+func (w *Window) GetTableName() string {
+	return w.Input.GetTableName()
+}
+
+// TryExecute is a Primitive function.
+func (w *Window) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
+	result, err := vcursor.ExecutePrimitive(
+		ctx,
+		w.Input,
+		bindVars,
+		true, /*wantFields - we need the input fields types to correctly calculate the output types*/
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &sqltypes.Result{
+		Fields: w.fields(result.Fields),
+		Rows:   make([]sqltypes.Row, 0, len(result.Rows)),
+	}
+
+	var partition []sqltypes.Row
+	for _, row := range result.Rows {
+		if len(partition) > 0 {
+			samePartition, err := equalGroupingKeys(w.PartitionBy, partition[0], row)
+			if err != nil {
+				return nil, err
+			}
+			if !samePartition {
+				rows, err := w.evaluatePartition(out.Fields, partition)
+				if err != nil {
+					return nil, err
+				}
+				out.Rows = append(out.Rows, rows...)
+				partition = nil
+			}
+		}
+		partition = append(partition, row)
+	}
+
+	if len(partition) > 0 {
+		rows, err := w.evaluatePartition(out.Fields, partition)
+		if err != nil {
+			return nil, err
+		}
+		out.Rows = append(out.Rows, rows...)
+	}
+
+	return out.Truncate(w.TruncateColumnCount), nil
+}
+
+// TryStreamExecute is a Primitive function.
+func (w *Window) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool, callback func(*sqltypes.Result) error) error {
+	cb := func(qr *sqltypes.Result) error {
+		return callback(qr.Truncate(w.TruncateColumnCount))
+	}
+
+	var fields []*querypb.Field
+	var partition []sqltypes.Row
+
+	flush := func() error {
+		rows, err := w.evaluatePartition(fields, partition)
+		if err != nil {
+			return err
+		}
+		partition = nil
+		return cb(&sqltypes.Result{Rows: rows})
+	}
+
+	visitor := func(qr *sqltypes.Result) error {
+		if fields == nil && len(qr.Fields) > 0 {
+			fields = w.fields(qr.Fields)
+			if err := cb(&sqltypes.Result{Fields: fields}); err != nil {
+				return err
+			}
+		}
+
+		for _, row := range qr.Rows {
+			if len(partition) > 0 {
+				samePartition, err := equalGroupingKeys(w.PartitionBy, partition[0], row)
+				if err != nil {
+					return err
+				}
+				if !samePartition {
+					if err := flush(); err != nil {
+						return err
+					}
+				}
+			}
+			partition = append(partition, row)
+		}
+
+		if vcursor.ExceedsMaxMemoryRows(len(partition)) {
+			return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+		}
+		return nil
+	}
+
+	/* we need the input fields types to correctly calculate the output types */
+	err := vcursor.StreamExecutePrimitive(ctx, w.Input, bindVars, true, visitor)
+	if err != nil {
+		return err
+	}
+
+	if len(partition) > 0 {
+		return flush()
+	}
+	return nil
+}
+
+// evaluatePartition calculates all the window functions for a single partition.
+// The returned rows are copies of the input rows, with the function results in place.
+func (w *Window) evaluatePartition(fields []*querypb.Field, partition []sqltypes.Row) ([]sqltypes.Row, error) {
+	out := make([]sqltypes.Row, len(partition))
+	for i, row := range partition {
+		out[i] = slices.Clone(row)
+	}
+
+	// peerEnd holds, for the first row of every group of peers, the index right after the last peer
+	peerEnd := make(map[int]int)
+	start := 0
+	for i := 1; i < len(partition); i++ {
+		peers, err := equalGroupingKeys(w.OrderBy, partition[start], partition[i])
+		if err != nil {
+			return nil, err
+		}
+		if !peers {
+			peerEnd[start] = i
+			start = i
+		}
+	}
+	peerEnd[start] = len(partition)
+
+	for _, fn := range w.Functions {
+		switch fn.Opcode {
+		case WindowRowNumber:
+			for i := range out {
+				out[i][fn.Col] = sqltypes.NewInt64(int64(i + 1))
+			}
+		case WindowRank, WindowDenseRank:
+			group := 0
+			for start := 0; start < len(out); start = peerEnd[start] {
+				group++
+				rank := int64(group)
+				if fn.Opcode == WindowRank {
+					rank = int64(start + 1)
+				}
+				for i := start; i < peerEnd[start]; i++ {
+					out[i][fn.Col] = sqltypes.NewInt64(rank)
+				}
+			}
+		case WindowLag, WindowLead:
+			offset := fn.Offset
+			if fn.Opcode == WindowLag {
+				offset = -offset
+			}
+			for i := range out {
+				switch {
+				case i+offset >= 0 && i+offset < len(partition):
+					out[i][fn.Col] = partition[i+offset][fn.Col]
+				case fn.DefaultCol >= 0:
+					out[i][fn.Col] = partition[i][fn.DefaultCol]
+				default:
+					out[i][fn.Col] = sqltypes.NULL
+				}
+			}
+		case WindowSum, WindowCount, WindowCountStar, WindowAvg:
+			agg := newWindowAggregation(fn.Opcode, fields[fn.Col].Type)
+			for start := 0; start < len(out); start = peerEnd[start] {
+				for i := start; i < peerEnd[start]; i++ {
+					if err := agg.Add(partition[i][fn.Col]); err != nil {
+						return nil, err
+					}
+				}
+				result := agg.Result()
+				for i := start; i < peerEnd[start]; i++ {
+					out[i][fn.Col] = result
+				}
+			}
+		default:
+			panic("BUG: unexpected Window opcode")
+		}
+	}
+	return out, nil
+}
+
+// windowCount implements COUNT() and COUNT(*) using the same interface as the other windowed aggregations
+type windowCount struct {
+	n    int64
+	star bool
+}
+
+func (c *windowCount) Add(value sqltypes.Value) error {
+	if c.star || !value.IsNull() {
+		c.n++
+	}
+	return nil
+}
+
+func (c *windowCount) Result() sqltypes.Value {
+	return sqltypes.NewInt64(c.n)
+}
+
+func (c *windowCount) Reset() {
+	c.n = 0
+}
+
+func newWindowAggregation(code WindowOpcode, inputType querypb.Type) evalengine.Sum {
+	switch code {
+	case WindowSum:
+		return evalengine.NewAggregationSum(inputType)
+	case WindowAvg:
+		return evalengine.NewAggregationAvg(inputType)
+	case WindowCount:
+		return &windowCount{}
+	case WindowCountStar:
+		return &windowCount{star: true}
+	default:
+		panic("BUG: unexpected windowed aggregation opcode")
+	}
+}
+
+func (w *Window) fields(input []*querypb.Field) []*querypb.Field {
+	fields := slice.Map(input, func(from *querypb.Field) *querypb.Field { return from.CloneVT() })
+	for _, fn := range w.Functions {
+		fields[fn.Col].Type = fn.Opcode.SQLType(fields[fn.Col].Type)
+		if fn.Alias != "" {
+			fields[fn.Col].Name = fn.Alias
+		}
+	}
+	return fields
+}
+
+// GetFields is a Primitive function.
+func (w *Window) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	qr, err := w.Input.GetFields(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+
+	qr = &sqltypes.Result{Fields: w.fields(qr.Fields)}
+	return qr.Truncate(w.TruncateColumnCount), nil
+}
+
+// Inputs returns the Primitive input for this window
+func (w *Window) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{w.Input}, nil
+}
+
+// NeedsTransaction implements the Primitive interface
+func (w *Window) NeedsTransaction() bool {
+	return w.Input.NeedsTransaction()
+}
+
+func windowFuncParamsToString(in any) string {
+	return in.(*WindowFuncParams).String()
+}
+
+func (w *Window) description() PrimitiveDescription {
+	other := map[string]any{
+		"Functions": GenericJoin(w.Functions, windowFuncParamsToString),
+	}
+	if len(w.PartitionBy) > 0 {
+		other["PartitionBy"] = GenericJoin(w.PartitionBy, groupByParamsToString)
+	}
+	if len(w.OrderBy) > 0 {
+		other["OrderBy"] = GenericJoin(w.OrderBy, groupByParamsToString)
+	}
+	if w.TruncateColumnCount > 0 {
+		other["ResultColumns"] = w.TruncateColumnCount
+	}
+	return PrimitiveDescription{
+		OperatorType: "Window",
+		Other:        other,
+	}
+}
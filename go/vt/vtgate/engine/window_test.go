@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+func windowTestInput() *fakePrimitive {
+	return &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"dept|salary|rn|rnk|drnk|prev|next|total",
+				"varbinary|int64|int64|int64|int64|int64|int64|int64",
+			),
+			"a|10|1|1|1|10|10|10",
+			"a|20|1|1|1|20|20|20",
+			"a|20|1|1|1|20|20|20",
+			"a|30|1|1|1|30|30|30",
+			"b|5|1|1|1|5|5|5",
+			"c|7|1|1|1|7|7|7",
+			"c|8|1|1|1|8|8|8",
+		)},
+	}
+}
+
+func windowTestPrimitive(input Primitive) *Window {
+	lead := NewWindowFuncParam(WindowLead, 6, "")
+	lead.Offset = 2
+	return &Window{
+		Functions: []*WindowFuncParams{
+			NewWindowFuncParam(WindowRowNumber, 2, ""),
+			NewWindowFuncParam(WindowRank, 3, ""),
+			NewWindowFuncParam(WindowDenseRank, 4, ""),
+			NewWindowFuncParam(WindowLag, 5, ""),
+			lead,
+			NewWindowFuncParam(WindowSum, 7, ""),
+		},
+		PartitionBy: []*GroupByParams{{KeyCol: 0, WeightStringCol: -1, CollationEnv: collations.MySQL8()}},
+		OrderBy:     []*GroupByParams{{KeyCol: 1, WeightStringCol: -1, CollationEnv: collations.MySQL8()}},
+		Input:       input,
+	}
+}
+
+var windowTestResult = sqltypes.MakeTestResult(
+	sqltypes.MakeTestFields(
+		"dept|salary|rn|rnk|drnk|prev|next|total",
+		"varbinary|int64|int64|int64|int64|int64|int64|decimal",
+	),
+	"a|10|1|1|1|null|20|10",
+	"a|20|2|2|2|10|30|50",
+	"a|20|3|2|2|20|null|50",
+	"a|30|4|4|3|20|null|80",
+	"b|5|1|1|1|null|null|5",
+	"c|7|1|1|1|null|null|7",
+	"c|8|2|2|2|7|null|15",
+)
+
+func TestWindowExecute(t *testing.T) {
+	w := windowTestPrimitive(windowTestInput())
+
+	result, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, windowTestResult, result)
+}
+
+func TestWindowStreamExecute(t *testing.T) {
+	fp := windowTestInput()
+	fp.allResultsInOneCall = true
+	w := windowTestPrimitive(fp)
+
+	result, err := wrapStreamExecute(w, &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, windowTestResult, result)
+}
+
+func TestWindowWithoutOrderBy(t *testing.T) {
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"dept|salary|cnt|avg_salary|rnk",
+				"varbinary|int64|int64|int64|int64",
+			),
+			"a|10|10|10|0",
+			"a|null|null|null|0",
+			"a|20|20|20|0",
+			"b|5|5|5|0",
+		)},
+	}
+
+	// without ORDER BY, all rows in a partition are peers,
+	// so the aggregations are calculated over the whole partition
+	w := &Window{
+		Functions: []*WindowFuncParams{
+			NewWindowFuncParam(WindowCount, 2, ""),
+			NewWindowFuncParam(WindowAvg, 3, ""),
+			NewWindowFuncParam(WindowRank, 4, ""),
+		},
+		PartitionBy: []*GroupByParams{{KeyCol: 0, WeightStringCol: -1, CollationEnv: collations.MySQL8()}},
+		Input:       fp,
+	}
+
+	result, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"dept|salary|cnt|avg_salary|rnk",
+			"varbinary|int64|int64|decimal|int64",
+		),
+		"a|10|2|15.0000|1",
+		"a|null|2|15.0000|1",
+		"a|20|2|15.0000|1",
+		"b|5|1|5.0000|1",
+	), result)
+}
+
+func TestWindowLagWithDefault(t *testing.T) {
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"id|prev|default",
+				"int64|int64|int64",
+			),
+			"1|1|-1",
+			"2|2|-2",
+			"3|3|-3",
+		)},
+	}
+
+	lag := NewWindowFuncParam(WindowLag, 1, "prev")
+	lag.DefaultCol = 2
+	w := &Window{
+		Functions:           []*WindowFuncParams{lag},
+		OrderBy:             []*GroupByParams{{KeyCol: 0, WeightStringCol: -1, Type: evalengine.NewType(sqltypes.Int64, collations.Unknown), CollationEnv: collations.MySQL8()}},
+		TruncateColumnCount: 2,
+		Input:               fp,
+	}
+
+	result, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"id|prev",
+			"int64|int64",
+		),
+		"1|-1",
+		"2|1",
+		"3|2",
+	), result)
+}
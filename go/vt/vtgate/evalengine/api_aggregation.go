@@ -440,6 +440,59 @@ func NewAggregationSum(type_ sqltypes.Type) Sum {
 	}
 }
 
+// aggregationAvg implements AVG on top of a SUM aggregation.
+// Like in MySQL, the average of integral and DECIMAL values is a DECIMAL with
+// divPrecisionIncrement more fractional digits than the sum, and the average
+// of any other type is a DOUBLE. If no values have been aggregated, the result is NULL.
+type aggregationAvg struct {
+	sum Sum
+	n   int64
+}
+
+func (a *aggregationAvg) Add(value sqltypes.Value) error {
+	if value.IsNull() {
+		return nil
+	}
+	if err := a.sum.Add(value); err != nil {
+		return err
+	}
+	a.n++
+	return nil
+}
+
+func (a *aggregationAvg) Result() sqltypes.Value {
+	if a.n == 0 {
+		return sqltypes.NULL
+	}
+	sum := a.sum.Result()
+	switch sum.Type() {
+	case sqltypes.Decimal:
+		dec, err := decimal.NewFromMySQL(sum.Raw())
+		if err != nil {
+			return sqltypes.NULL
+		}
+		prec := -dec.Exponent() + divPrecisionIncrement
+		return sqltypes.MakeTrusted(sqltypes.Decimal, dec.Div(decimal.NewFromInt(a.n), divPrecisionIncrement).FormatMySQL(prec))
+	default:
+		f, err := fastparse.ParseFloat64(sum.RawStr())
+		if err != nil {
+			return sqltypes.NULL
+		}
+		return sqltypes.NewFloat64(f / float64(a.n))
+	}
+}
+
+func (a *aggregationAvg) Reset() {
+	a.sum.Reset()
+	a.n = 0
+}
+
+// NewAggregationAvg returns an aggregation that calculates the average of the values
+// added to it. The interface is the same as the one used for SUM
+func NewAggregationAvg(type_ sqltypes.Type) Sum {
+	return &aggregationAvg{sum: NewAggregationSum(type_)}
+}
+
 // aggregationMinMax implements MIN and MAX aggregations for all data types
 // that cannot be more efficiently handled by one of the numeric aggregators.
 // The aggregation is performed using the slow NullSafeComparison path of the
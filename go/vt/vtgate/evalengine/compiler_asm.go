@@ -51,6 +51,7 @@ import (
 	"vitess.io/vitess/go/sqltypes"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vthash"
 )
@@ -2279,6 +2280,24 @@ func (asm *assembler) Fn_JSON_KEYS(jp *json.Path) {
 	}
 }
 
+func (asm *assembler) Fn_JSON_MERGE(fname string, patch bool, args int) {
+	asm.adjustStack(-(args - 1))
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-args], env.vm.err = jsonMerge(fname, patch, env.vm.stack[env.vm.sp-args:env.vm.sp])
+		env.vm.sp -= args - 1
+		return 1
+	}, "FN %s (SP-%d)...(SP-1)", fname, args)
+}
+
+func (asm *assembler) Fn_JSON_MODIFY(fname string, t json.Transformation, args int) {
+	asm.adjustStack(-(args - 1))
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-args], env.vm.err = jsonModify(fname, t, env.vm.stack[env.vm.sp-args:env.vm.sp])
+		env.vm.sp -= args - 1
+		return 1
+	}, "FN %s (SP-%d)...(SP-1)", fname, args)
+}
+
 func (asm *assembler) Fn_JSON_OBJECT(args int) {
 	asm.adjustStack(-(args - 1))
 	asm.emit(func(env *ExpressionEnv) int {
@@ -2300,6 +2319,40 @@ func (asm *assembler) Fn_JSON_OBJECT(args int) {
 	}, "FN JSON_ARRAY (SP-%d)...(SP-1)", args)
 }
 
+func (asm *assembler) Fn_JSON_OVERLAPS() {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-2], env.vm.err = jsonOverlapsEval(env.vm.stack[env.vm.sp-2], env.vm.stack[env.vm.sp-1])
+		env.vm.sp--
+		return 1
+	}, "FN JSON_OVERLAPS (SP-2), (SP-1)")
+}
+
+func (asm *assembler) Fn_JSON_PRETTY() {
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-1], env.vm.err = jsonPretty(env.vm.stack[env.vm.sp-1])
+		return 1
+	}, "FN JSON_PRETTY (SP-1)")
+}
+
+func (asm *assembler) Fn_JSON_SCHEMA_VALID() {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-2], env.vm.err = jsonSchemaValid(env.vm.stack[env.vm.sp-2], env.vm.stack[env.vm.sp-1])
+		env.vm.sp--
+		return 1
+	}, "FN JSON_SCHEMA_VALID (SP-2), (SP-1)")
+}
+
+func (asm *assembler) Fn_JSON_SEARCH(args int, collate collations.ID) {
+	asm.adjustStack(-(args - 1))
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-args], env.vm.err = jsonSearch(env.vm.stack[env.vm.sp-args:env.vm.sp], collate)
+		env.vm.sp -= args - 1
+		return 1
+	}, "FN JSON_SEARCH (SP-%d)...(SP-1)", args)
+}
+
 func (asm *assembler) Fn_JSON_UNQUOTE() {
 	asm.emit(func(env *ExpressionEnv) int {
 		j := env.vm.stack[env.vm.sp-1].(*evalJSON)
@@ -2316,6 +2369,24 @@ func (asm *assembler) Fn_JSON_UNQUOTE() {
 	}, "FN JSON_UNQUOTE (SP-1)")
 }
 
+func (asm *assembler) Fn_JSON_VALUE(args int, onEmpty, onError sqlparser.JtOnResponseType, returning *ConvertExpr) {
+	asm.adjustStack(-(args - 1))
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-args], env.vm.err = jsonValue(env, env.vm.stack[env.vm.sp-args:env.vm.sp], onEmpty, onError, returning)
+		env.vm.sp -= args - 1
+		return 1
+	}, "FN JSON_VALUE (SP-%d)...(SP-1)", args)
+}
+
+func (asm *assembler) Fn_MEMBER_OF() {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-2], env.vm.err = memberOf(env.vm.stack[env.vm.sp-2], env.vm.stack[env.vm.sp-1])
+		env.vm.sp--
+		return 1
+	}, "FN MEMBER_OF (SP-2), (SP-1)")
+}
+
 func (asm *assembler) Fn_CHAR_LENGTH() {
 	asm.emit(func(env *ExpressionEnv) int {
 		arg := env.vm.stack[env.vm.sp-1].(*evalBytes)
@@ -3207,6 +3278,74 @@ func (asm *assembler) Replace() {
 	}, "REPLACE VARCHAR(SP-3), VARCHAR(SP-2) VARCHAR(SP-1)")
 }
 
+func (asm *assembler) Fn_SUBSTRING_INDEX(tt sqltypes.Type, tc collations.TypedCollation) {
+	asm.adjustStack(-2)
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-3], env.vm.err = substringIndex(env.vm.stack[env.vm.sp-3], env.vm.stack[env.vm.sp-2], env.vm.stack[env.vm.sp-1], tt, tc)
+		env.vm.sp -= 2
+		return 1
+	}, "FN SUBSTRING_INDEX VARCHAR(SP-3), VARCHAR(SP-2), INT64(SP-1)")
+}
+
+func (asm *assembler) Fn_FORMAT(locale bool, col collations.TypedCollation) {
+	if locale {
+		asm.adjustStack(-2)
+		asm.emit(func(env *ExpressionEnv) int {
+			env.vm.stack[env.vm.sp-3] = formatNumber(env.vm.stack[env.vm.sp-3], env.vm.stack[env.vm.sp-2], env.vm.stack[env.vm.sp-1], col)
+			env.vm.sp -= 2
+			return 1
+		}, "FN FORMAT NUMERIC(SP-3), INT64(SP-2), VARCHAR(SP-1)")
+		return
+	}
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-2] = formatNumber(env.vm.stack[env.vm.sp-2], env.vm.stack[env.vm.sp-1], nil, col)
+		env.vm.sp--
+		return 1
+	}, "FN FORMAT NUMERIC(SP-2), INT64(SP-1)")
+}
+
+func (asm *assembler) Fn_SOUNDEX(tt sqltypes.Type, tc collations.TypedCollation) {
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-1], env.vm.err = soundexEval(env.vm.stack[env.vm.sp-1], tt, tc)
+		return 1
+	}, "FN SOUNDEX VARCHAR(SP-1)")
+}
+
+func (asm *assembler) Fn_MAKE_SET(args int, tt sqltypes.Type, tc collations.TypedCollation) {
+	asm.adjustStack(-args + 1)
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-args], env.vm.err = makeSet(env.vm.stack[env.vm.sp-args], env.vm.stack[env.vm.sp-args+1:env.vm.sp], tt, tc)
+		env.vm.sp -= args - 1
+		return 1
+	}, "FN MAKE_SET INT64(SP-%d) VARCHAR(SP-%d)...VARCHAR(SP-1)", args, args-1)
+}
+
+func (asm *assembler) Fn_EXPORT_SET(args int, tt sqltypes.Type, tc collations.TypedCollation) {
+	asm.adjustStack(-args + 1)
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-args], env.vm.err = exportSet(env.vm.stack[env.vm.sp-args:env.vm.sp], tt, tc)
+		env.vm.sp -= args - 1
+		return 1
+	}, "FN EXPORT_SET INT64(SP-%d) VARCHAR(SP-%d)...(SP-1)", args, args-1)
+}
+
+func (asm *assembler) Fn_FIND_IN_SET(tc collations.TypedCollation) {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-2], env.vm.err = findInSet(env.vm.stack[env.vm.sp-2], env.vm.stack[env.vm.sp-1], tc)
+		env.vm.sp--
+		return 1
+	}, "FN FIND_IN_SET VARCHAR(SP-2), VARCHAR(SP-1)")
+}
+
+func (asm *assembler) Fn_QUOTE(tt sqltypes.Type, tc collations.TypedCollation) {
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-1], env.vm.err = quote(env.vm.stack[env.vm.sp-1], tt, tc)
+		return 1
+	}, "FN QUOTE VARCHAR(SP-1)")
+}
+
 func (asm *assembler) Strcmp(collation collations.TypedCollation) {
 	asm.adjustStack(-1)
 
@@ -3729,6 +3868,59 @@ func (asm *assembler) Fn_RandomBytes() {
 	}, "FN RANDOM_BYTES INT64(SP-1)")
 }
 
+func (asm *assembler) Fn_AES_ENCRYPT(args int) {
+	asm.adjustStack(-args + 1)
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-args], env.vm.err = aesCrypt("aes_encrypt", env.vm.stack[env.vm.sp-args:env.vm.sp], env.blockEncryptionMode(), false)
+		env.vm.sp -= args - 1
+		return 1
+	}, "FN AES_ENCRYPT VARBINARY(SP-%d)...VARBINARY(SP-1)", args)
+}
+
+func (asm *assembler) Fn_AES_DECRYPT(args int) {
+	asm.adjustStack(-args + 1)
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-args], env.vm.err = aesCrypt("aes_decrypt", env.vm.stack[env.vm.sp-args:env.vm.sp], env.blockEncryptionMode(), true)
+		env.vm.sp -= args - 1
+		return 1
+	}, "FN AES_DECRYPT VARBINARY(SP-%d)...VARBINARY(SP-1)", args)
+}
+
+func (asm *assembler) Fn_COMPRESS() {
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-1], env.vm.err = compress(env.vm.stack[env.vm.sp-1])
+		return 1
+	}, "FN COMPRESS VARBINARY(SP-1)")
+}
+
+func (asm *assembler) Fn_UNCOMPRESS() {
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-1], env.vm.err = uncompress(env.vm.stack[env.vm.sp-1])
+		return 1
+	}, "FN UNCOMPRESS VARBINARY(SP-1)")
+}
+
+func (asm *assembler) Fn_UNCOMPRESSED_LENGTH() {
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-1], env.vm.err = uncompressedLength(env.vm.stack[env.vm.sp-1])
+		return 1
+	}, "FN UNCOMPRESSED_LENGTH VARBINARY(SP-1)")
+}
+
+func (asm *assembler) Fn_STATEMENT_DIGEST(col collations.TypedCollation) {
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-1], env.vm.err = statementDigest(env.vc.Environment().Parser(), env.vm.stack[env.vm.sp-1], col, false)
+		return 1
+	}, "FN STATEMENT_DIGEST VARCHAR(SP-1)")
+}
+
+func (asm *assembler) Fn_STATEMENT_DIGEST_TEXT(col collations.TypedCollation) {
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-1], env.vm.err = statementDigest(env.vc.Environment().Parser(), env.vm.stack[env.vm.sp-1], col, true)
+		return 1
+	}, "FN STATEMENT_DIGEST_TEXT VARCHAR(SP-1)")
+}
+
 func (asm *assembler) Fn_DATE_FORMAT(col collations.TypedCollation) {
 	asm.adjustStack(-1)
 	asm.emit(func(env *ExpressionEnv) int {
@@ -4339,6 +4531,74 @@ func (asm *assembler) Fn_PERIOD_DIFF() {
 	}, "FN PERIOD_DIFF INT64(SP-2) INT64(SP-1)")
 }
 
+func (asm *assembler) Fn_STR_TO_DATE(t sqltypes.Type, prec uint8, allowZero bool) {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-2] = strToDate(env.vm.stack[env.vm.sp-2], env.vm.stack[env.vm.sp-1], t, prec, allowZero)
+		env.vm.sp--
+		return 1
+	}, "FN STR_TO_DATE VARCHAR(SP-2), VARCHAR(SP-1)")
+}
+
+func (asm *assembler) Fn_TIMESTAMPDIFF(unit datetime.IntervalType) {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-2] = timestampDiff(env.vm.stack[env.vm.sp-2], env.vm.stack[env.vm.sp-1], unit, env.now)
+		env.vm.sp--
+		return 1
+	}, "FN TIMESTAMPDIFF TEMPORAL(SP-2), TEMPORAL(SP-1)")
+}
+
+func (asm *assembler) Fn_DATEDIFF() {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-2] = dateDiff(env.vm.stack[env.vm.sp-2], env.vm.stack[env.vm.sp-1], env.now)
+		env.vm.sp--
+		return 1
+	}, "FN DATEDIFF DATE(SP-2), DATE(SP-1)")
+}
+
+func (asm *assembler) Fn_TIMEDIFF() {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-2] = timeDiff(env.vm.stack[env.vm.sp-2], env.vm.stack[env.vm.sp-1], env.now)
+		env.vm.sp--
+		return 1
+	}, "FN TIMEDIFF TEMPORAL(SP-2), TEMPORAL(SP-1)")
+}
+
+func (asm *assembler) Fn_ADDTIME(sub bool, col collations.TypedCollation) {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-2] = addTime(env.vm.stack[env.vm.sp-2], env.vm.stack[env.vm.sp-1], sub, col, env.now)
+		env.vm.sp--
+		return 1
+	}, "FN ADDTIME TEMPORAL(SP-2), TIME(SP-1)")
+}
+
+func (asm *assembler) Fn_GET_FORMAT(t sqltypes.Type, col collations.TypedCollation) {
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-1] = getFormat(t, env.vm.stack[env.vm.sp-1], col)
+		return 1
+	}, "FN GET_FORMAT VARCHAR(SP-1)")
+}
+
+func (asm *assembler) Fn_TIME_FORMAT(col collations.TypedCollation) {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-2] = timeFormat(env.vm.stack[env.vm.sp-2], env.vm.stack[env.vm.sp-1], col)
+		env.vm.sp--
+		return 1
+	}, "FN TIME_FORMAT TIME(SP-2), VARCHAR(SP-1)")
+}
+
+func (asm *assembler) Fn_EXTRACT(unit datetime.IntervalType) {
+	asm.emit(func(env *ExpressionEnv) int {
+		env.vm.stack[env.vm.sp-1] = extract(env.vm.stack[env.vm.sp-1], unit, env.now)
+		return 1
+	}, "FN EXTRACT TEMPORAL(SP-1)")
+}
+
 func (asm *assembler) Interval(l int) {
 	asm.adjustStack(-l)
 	asm.emit(func(env *ExpressionEnv) int {
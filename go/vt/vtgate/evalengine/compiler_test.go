@@ -723,6 +723,98 @@ func TestCompilerSingle(t *testing.T) {
 			expression: `cast(_utf32 0x0000FF as binary)`,
 			result:     `VARBINARY("\x00\x00\x00\xff")`,
 		},
+		{
+			expression: `substring_index('www.mysql.com', '.', -2)`,
+			result:     `VARCHAR("mysql.com")`,
+		},
+		{
+			expression: `format(12332.2, 2, 'de_DE')`,
+			result:     `VARCHAR("12.332,20")`,
+		},
+		{
+			expression: `soundex('Quadratically')`,
+			result:     `VARCHAR("Q36324")`,
+		},
+		{
+			expression: `make_set(1 | 4, 'hello', 'nice', NULL, 'world')`,
+			result:     `VARCHAR("hello")`,
+		},
+		{
+			expression: `export_set(5, 'Y', 'N', ',', 4)`,
+			result:     `VARCHAR("Y,N,Y,N")`,
+		},
+		{
+			expression: `find_in_set('B', 'a,b,c,d')`,
+			result:     `INT64(2)`,
+		},
+		{
+			expression: `quote(NULL)`,
+			result:     `VARCHAR("NULL")`,
+		},
+		{
+			expression: `json_array_append('["a", ["b", "c"], "d"]', '$[1]', 1, '$[0]', 2, '$[1][0]', 3)`,
+			result:     `JSON("[[\"a\", 2], [[\"b\", 3], \"c\", 1], \"d\"]")`,
+		},
+		{
+			expression: `json_merge_preserve('{ "a": 1, "b": 2 }', '{ "a": 3, "c": 4 }', '{ "a": 5, "d": 6 }')`,
+			result:     `JSON("{\"a\": [1, 3, 5], \"b\": 2, \"c\": 4, \"d\": 6}")`,
+		},
+		{
+			expression: `json_search('["abc", [{"k": "10"}, "def"], {"x":"abc"}, {"y":"bcd"}]', 'all', '%b%')`,
+			result:     `JSON("[\"$[0]\", \"$[2].x\", \"$[3].y\"]")`,
+		},
+		{
+			expression: `json_overlaps('{"a":1,"b":10,"d":10}', '{"c":1,"e":10,"f":1,"d":10}')`,
+			result:     `INT64(1)`,
+		},
+		{
+			expression: `json_value('{"item": "shoes", "price": "49.95"}', '$.price' returning decimal(4,2))`,
+			result:     `DECIMAL(49.95)`,
+		},
+		{
+			expression: `json_value('{"price": "123.456"}', '$.price' returning decimal(4,2))`,
+			result:     `NULL`,
+		},
+		{
+			expression: `json_value('{"price": "123.456"}', '$.price' returning decimal(4,2) default '1.5' on error)`,
+			result:     `DECIMAL(1.50)`,
+		},
+		{
+			expression: `json_value('{"a": "x"}', '$.a' returning signed)`,
+			result:     `NULL`,
+		},
+		{
+			expression: `json_value('{"a": "2.5"}', '$.a' returning signed error on error)`,
+			result:     `INT64(3)`,
+		},
+		{
+			expression: `json_value('{"a": true}', '$.a' returning unsigned)`,
+			result:     `UINT64(1)`,
+		},
+		{
+			expression: `json_value('{"a": "abcdef"}', '$.a' returning char(3))`,
+			result:     `NULL`,
+		},
+		{
+			expression: `cast('[4,5]' as json) member of ('[[3,4],[4,5]]')`,
+			result:     `INT64(1)`,
+		},
+		{
+			expression: `uncompress(compress('any string'))`,
+			result:     `BLOB("any string")`,
+		},
+		{
+			expression: `uncompressed_length(compress(repeat('a', 30)))`,
+			result:     `INT64(30)`,
+		},
+		{
+			expression: `statement_digest_text('SELECT * FROM t1 WHERE a = 2 AND b IN (1, 2, 3)')`,
+			result:     "TEXT(\"SELECT * FROM `t1` WHERE `a` = ? AND `b` IN (...)\")",
+		},
+		{
+			expression: `statement_digest('SELECT * FROM t1 WHERE a = 2 AND b IN (1, 2, 3)')`,
+			result:     `VARCHAR("eaea1bc0bc9de5b77ca6b9fee450483b10e910575d10b475b6e348f428e8c7eb")`,
+		},
 	}
 
 	tz, _ := time.LoadLocation("Europe/Madrid")
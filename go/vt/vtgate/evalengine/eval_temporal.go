@@ -340,6 +340,28 @@ func evalToTime(e eval, l int) *evalTemporal {
 	return nil
 }
 
+// evalToTimeOrDateTime converts e the same way MySQL converts the arguments of
+// functions that operate on TIME values: temporal values keep their type, and
+// strings and integers that contain a full datetime are returned as a DATETIME,
+// so that the caller can decide whether they are acceptable.
+func evalToTimeOrDateTime(e eval) *evalTemporal {
+	switch e := e.(type) {
+	case *evalTemporal:
+		return e
+	case *evalBytes:
+		if dt, l, ok := datetime.ParseDateTime(e.string(), -1); ok {
+			return newEvalDateTime(dt, l, true)
+		}
+	case *evalInt64:
+		if _, ok := datetime.ParseTimeInt64(e.i); !ok {
+			if dt, ok := datetime.ParseDateTimeInt64(e.i); ok {
+				return newEvalDateTime(dt, 0, true)
+			}
+		}
+	}
+	return evalToTime(e, -1)
+}
+
 func evalToDateTime(e eval, l int, now time.Time, allowZero bool) *evalTemporal {
 	switch e := e.(type) {
 	case *evalTemporal:
@@ -34,6 +34,7 @@ type VCursor interface {
 	TimeZone() *time.Location
 	GetKeyspace() string
 	SQLMode() string
+	BlockEncryptionMode() string
 	Environment() *vtenv.Environment
 }
 
@@ -86,6 +87,10 @@ func (env *ExpressionEnv) currentTimezone() *time.Location {
 	return env.vc.TimeZone()
 }
 
+func (env *ExpressionEnv) blockEncryptionMode() string {
+	return env.vc.BlockEncryptionMode()
+}
+
 func (env *ExpressionEnv) Evaluate(expr Expr) (EvalResult, error) {
 	if p, ok := expr.(*CompiledExpr); ok {
 		return env.EvaluateVM(p)
@@ -141,6 +146,10 @@ func (e *emptyVCursor) SQLMode() string {
 	return config.DefaultSQLMode
 }
 
+func (e *emptyVCursor) BlockEncryptionMode() string {
+	return config.DefaultBlockEncryptionMode
+}
+
 func NewEmptyVCursor(env *vtenv.Environment, tz *time.Location) VCursor {
 	return &emptyVCursor{env: env, tz: tz}
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package evalengine
 
 import (
+	"context"
 	"sync"
 	"testing"
 
@@ -101,3 +102,77 @@ func TestExpressionEnvTypeOf(t *testing.T) {
 		})
 	}
 }
+
+type blockEncryptionModeVCursor struct {
+	emptyVCursor
+	mode string
+}
+
+func (vc *blockEncryptionModeVCursor) BlockEncryptionMode() string {
+	return vc.mode
+}
+
+// TestBlockEncryptionMode tests that AES functions honor the block_encryption_mode of the session
+func TestBlockEncryptionMode(t *testing.T) {
+	tests := []struct {
+		mode   string
+		expr   string
+		result string
+		err    string
+	}{
+		{
+			mode:   "aes-128-ecb",
+			expr:   "hex(aes_encrypt('text', 'password'))",
+			result: `VARCHAR("F6BD0FA8DCB7F8CD4A2FAABC54668044")`,
+		},
+		{
+			mode:   "aes-256-cbc",
+			expr:   "hex(aes_encrypt('text', 'password', '1234567890abcdef'))",
+			result: `VARCHAR("ECAE7FFE3360C24A4AC7BC687B8C2A24")`,
+		},
+		{
+			mode:   "aes-128-ofb",
+			expr:   "hex(aes_encrypt('text', 'password', '1234567890abcdef'))",
+			result: `VARCHAR("FD0ABDF3")`,
+		},
+		{
+			mode:   "aes-192-cfb8",
+			expr:   "aes_decrypt(unhex('EC690A80'), 'password', '1234567890abcdef')",
+			result: `VARBINARY("text")`,
+		},
+		{
+			mode: "aes-256-cbc",
+			expr: "aes_encrypt('text', 'password')",
+			err:  "Incorrect parameter count in the call to native function 'aes_encrypt'",
+		},
+		{
+			mode: "aes-256-cbc",
+			expr: "aes_encrypt('text', 'password', 'short')",
+			err:  "The initialization vector supplied to aes_encrypt is too short. Must be at least 16 bytes long",
+		},
+	}
+
+	venv := vtenv.NewTestEnv()
+	for _, tt := range tests {
+		t.Run(tt.mode+" "+tt.expr, func(t *testing.T) {
+			expr, err := venv.Parser().ParseExpr(tt.expr)
+			require.NoError(t, err)
+
+			converted, err := Translate(expr, &Config{Environment: venv, NoConstantFolding: true})
+			require.NoError(t, err)
+
+			vc := &blockEncryptionModeVCursor{emptyVCursor: emptyVCursor{env: venv}, mode: tt.mode}
+			env := NewExpressionEnv(context.Background(), nil, vc)
+
+			for _, eval := range []func(Expr) (EvalResult, error){env.EvaluateAST, env.Evaluate} {
+				res, err := eval(converted)
+				if tt.err != "" {
+					require.ErrorContains(t, err, tt.err)
+					continue
+				}
+				require.NoError(t, err)
+				require.Equal(t, tt.result, res.String())
+			}
+		})
+	}
+}
@@ -17,15 +17,26 @@ limitations under the License.
 package evalengine
 
 import (
+	"bytes"
+	"compress/zlib"
+	"crypto/aes"
+	"crypto/cipher"
 	"crypto/md5"
 	"crypto/rand"
 	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
+	"encoding/binary"
 	"encoding/hex"
+	"io"
+	"strings"
 
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
 )
 
 type builtinMD5 struct {
@@ -246,3 +257,486 @@ func (call *builtinRandomBytes) compile(c *compiler) (ctype, error) {
 	c.asm.jumpDestination(skip)
 	return ctype{Type: sqltypes.VarBinary, Col: collationBinary, Flag: nullableFlags(arg.Flag) | flagNullable}, nil
 }
+
+type (
+	builtinAESEncrypt struct {
+		CallExpr
+	}
+
+	builtinAESDecrypt struct {
+		CallExpr
+	}
+
+	builtinCompress struct {
+		CallExpr
+	}
+
+	builtinUncompress struct {
+		CallExpr
+	}
+
+	builtinUncompressedLength struct {
+		CallExpr
+	}
+
+	builtinStatementDigest struct {
+		CallExpr
+		collate collations.ID
+	}
+
+	builtinStatementDigestText struct {
+		CallExpr
+		collate collations.ID
+	}
+)
+
+var _ IR = (*builtinAESEncrypt)(nil)
+var _ IR = (*builtinAESDecrypt)(nil)
+var _ IR = (*builtinCompress)(nil)
+var _ IR = (*builtinUncompress)(nil)
+var _ IR = (*builtinUncompressedLength)(nil)
+var _ IR = (*builtinStatementDigest)(nil)
+var _ IR = (*builtinStatementDigestText)(nil)
+
+// aesMode is a parsed value of the block_encryption_mode system variable,
+// which has the form aes-<keylen>-<mode>.
+type aesMode struct {
+	keylen int
+	mode   string
+}
+
+func parseAESMode(mode string) aesMode {
+	var m aesMode
+	if bits, name, ok := strings.Cut(strings.TrimPrefix(strings.ToLower(mode), "aes-"), "-"); ok {
+		switch bits {
+		case "128":
+			m.keylen = 16
+		case "192":
+			m.keylen = 24
+		case "256":
+			m.keylen = 32
+		}
+		switch name {
+		case "ecb", "cbc", "cfb1", "cfb8", "cfb128", "ofb":
+			m.mode = name
+		}
+	}
+	if m.keylen == 0 || m.mode == "" {
+		return aesMode{keylen: 16, mode: "ecb"}
+	}
+	return m
+}
+
+// aesKey folds the given key into a key of the given length the same way
+// MySQL does: every byte of the key is XOR'ed into the position of its
+// index modulo the key length.
+func aesKey(key []byte, keylen int) []byte {
+	rkey := make([]byte, keylen)
+	for i, b := range key {
+		rkey[i%keylen] ^= b
+	}
+	return rkey
+}
+
+// aesBlockCrypt encrypts or decrypts src using ECB (if iv is nil) or CBC with
+// PKCS#7 padding. It returns false if the input cannot be decrypted.
+func aesBlockCrypt(block cipher.Block, iv, src []byte, decrypt bool) ([]byte, bool) {
+	bs := block.BlockSize()
+	if decrypt {
+		if len(src) == 0 || len(src)%bs != 0 {
+			return nil, false
+		}
+		dst := make([]byte, len(src))
+		if iv == nil {
+			for i := 0; i < len(src); i += bs {
+				block.Decrypt(dst[i:], src[i:])
+			}
+		} else {
+			cipher.NewCBCDecrypter(block, iv).CryptBlocks(dst, src)
+		}
+		pad := int(dst[len(dst)-1])
+		if pad == 0 || pad > bs {
+			return nil, false
+		}
+		for _, b := range dst[len(dst)-pad:] {
+			if int(b) != pad {
+				return nil, false
+			}
+		}
+		return dst[:len(dst)-pad], true
+	}
+
+	pad := bs - len(src)%bs
+	dst := make([]byte, len(src)+pad)
+	copy(dst, src)
+	for i := len(src); i < len(dst); i++ {
+		dst[i] = byte(pad)
+	}
+	if iv == nil {
+		for i := 0; i < len(dst); i += bs {
+			block.Encrypt(dst[i:], dst[i:])
+		}
+	} else {
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(dst, dst)
+	}
+	return dst, true
+}
+
+// aesStreamCrypt encrypts or decrypts src using one of the CFB or OFB modes,
+// none of which use padding.
+func aesStreamCrypt(block cipher.Block, mode string, iv, src []byte, decrypt bool) []byte {
+	reg := bytes.Clone(iv)
+	out := make([]byte, len(reg))
+	dst := make([]byte, len(src))
+
+	switch mode {
+	case "ofb":
+		for i := range src {
+			k := i % len(reg)
+			if k == 0 {
+				block.Encrypt(reg, reg)
+			}
+			dst[i] = src[i] ^ reg[k]
+		}
+	case "cfb128":
+		for i := range src {
+			k := i % len(reg)
+			if k == 0 {
+				block.Encrypt(out, reg)
+			}
+			dst[i] = src[i] ^ out[k]
+			if decrypt {
+				reg[k] = src[i]
+			} else {
+				reg[k] = dst[i]
+			}
+		}
+	case "cfb8":
+		for i := range src {
+			block.Encrypt(out, reg)
+			dst[i] = src[i] ^ out[0]
+			copy(reg, reg[1:])
+			if decrypt {
+				reg[len(reg)-1] = src[i]
+			} else {
+				reg[len(reg)-1] = dst[i]
+			}
+		}
+	case "cfb1":
+		for i := range src {
+			for bit := 7; bit >= 0; bit-- {
+				block.Encrypt(out, reg)
+				in := src[i] >> bit & 1
+				o := in ^ out[0]>>7
+				dst[i] |= o << bit
+
+				feedback := o
+				if decrypt {
+					feedback = in
+				}
+				for j := 0; j < len(reg)-1; j++ {
+					reg[j] = reg[j]<<1 | reg[j+1]>>7
+				}
+				reg[len(reg)-1] = reg[len(reg)-1]<<1 | feedback
+			}
+		}
+	}
+	return dst
+}
+
+func aesCrypt(fname string, args []eval, blockEncryptionMode string, decrypt bool) (eval, error) {
+	if args[0] == nil || args[1] == nil {
+		return nil, nil
+	}
+
+	mode := parseAESMode(blockEncryptionMode)
+
+	var iv []byte
+	if mode.mode != "ecb" {
+		if len(args) < 3 {
+			return nil, argError(fname)
+		}
+		if args[2] != nil {
+			iv = evalToBinary(args[2]).bytes
+		}
+		if len(iv) < aes.BlockSize {
+			return nil, vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.AESInvalidIV, "The initialization vector supplied to %s is too short. Must be at least %d bytes long", fname, aes.BlockSize)
+		}
+		iv = iv[:aes.BlockSize]
+	}
+
+	block, err := aes.NewCipher(aesKey(evalToBinary(args[1]).bytes, mode.keylen))
+	if err != nil {
+		return nil, err
+	}
+
+	src := evalToBinary(args[0]).bytes
+	switch mode.mode {
+	case "ecb", "cbc":
+		dst, ok := aesBlockCrypt(block, iv, src, decrypt)
+		if !ok {
+			return nil, nil
+		}
+		return newEvalBinary(dst), nil
+	default:
+		return newEvalBinary(aesStreamCrypt(block, mode.mode, iv, src, decrypt)), nil
+	}
+}
+
+func (call *builtinAESEncrypt) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	return aesCrypt("aes_encrypt", args, env.blockEncryptionMode(), false)
+}
+
+// constant returns false because the result depends on the
+// block_encryption_mode of the session.
+func (call *builtinAESEncrypt) constant() bool {
+	return false
+}
+
+func (call *builtinAESEncrypt) compile(c *compiler) (ctype, error) {
+	for _, arg := range call.Arguments {
+		if _, err := arg.compile(c); err != nil {
+			return ctype{}, err
+		}
+	}
+	c.asm.Fn_AES_ENCRYPT(len(call.Arguments))
+	return ctype{Type: sqltypes.VarBinary, Col: collationBinary, Flag: flagNullable}, nil
+}
+
+func (call *builtinAESDecrypt) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	return aesCrypt("aes_decrypt", args, env.blockEncryptionMode(), true)
+}
+
+// constant returns false because the result depends on the
+// block_encryption_mode of the session.
+func (call *builtinAESDecrypt) constant() bool {
+	return false
+}
+
+func (call *builtinAESDecrypt) compile(c *compiler) (ctype, error) {
+	for _, arg := range call.Arguments {
+		if _, err := arg.compile(c); err != nil {
+			return ctype{}, err
+		}
+	}
+	c.asm.Fn_AES_DECRYPT(len(call.Arguments))
+	return ctype{Type: sqltypes.VarBinary, Col: collationBinary, Flag: flagNullable}, nil
+}
+
+// uncompressMaxLength is the default max_allowed_packet in MySQL, which is
+// the upper bound for the length of an uncompressed string.
+const uncompressMaxLength = 64 * 1024 * 1024
+
+// compress compresses the argument with zlib, prefixed with the length of
+// the uncompressed string, like MySQL's COMPRESS.
+func compress(arg eval) (eval, error) {
+	if arg == nil {
+		return nil, nil
+	}
+	src := evalToBinary(arg).bytes
+	if len(src) == 0 {
+		return newEvalBinary(nil), nil
+	}
+
+	var buf bytes.Buffer
+	buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(src))&0x3FFFFFFF))
+	w := zlib.NewWriter(&buf)
+	if _, err := w.Write(src); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	// MySQL appends a '.' so that trailing spaces are not
+	// stripped when the result is stored in a CHAR column.
+	if b := buf.Bytes(); b[len(b)-1] == ' ' {
+		buf.WriteByte('.')
+	}
+	return newEvalBinary(buf.Bytes()), nil
+}
+
+func uncompress(arg eval) (eval, error) {
+	if arg == nil {
+		return nil, nil
+	}
+	src := evalToBinary(arg).bytes
+	if len(src) == 0 {
+		return newEvalRaw(sqltypes.Blob, nil, collationBinary), nil
+	}
+	if len(src) <= 4 {
+		return nil, nil
+	}
+
+	size := binary.LittleEndian.Uint32(src) & 0x3FFFFFFF
+	if size > uncompressMaxLength {
+		return nil, nil
+	}
+	r, err := zlib.NewReader(bytes.NewReader(src[4:]))
+	if err != nil {
+		return nil, nil
+	}
+	dst, err := io.ReadAll(io.LimitReader(r, int64(size)+1))
+	if err != nil || len(dst) > int(size) {
+		return nil, nil
+	}
+	return newEvalRaw(sqltypes.Blob, dst, collationBinary), nil
+}
+
+func uncompressedLength(arg eval) (eval, error) {
+	if arg == nil {
+		return nil, nil
+	}
+	src := evalToBinary(arg).bytes
+	if len(src) <= 4 {
+		return newEvalInt64(0), nil
+	}
+	return newEvalInt64(int64(binary.LittleEndian.Uint32(src) & 0x3FFFFFFF)), nil
+}
+
+func (call *builtinCompress) eval(env *ExpressionEnv) (eval, error) {
+	arg, err := call.arg1(env)
+	if err != nil {
+		return nil, err
+	}
+	return compress(arg)
+}
+
+func (call *builtinCompress) compile(c *compiler) (ctype, error) {
+	str, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	c.asm.Fn_COMPRESS()
+	return ctype{Type: sqltypes.VarBinary, Col: collationBinary, Flag: nullableFlags(str.Flag)}, nil
+}
+
+func (call *builtinUncompress) eval(env *ExpressionEnv) (eval, error) {
+	arg, err := call.arg1(env)
+	if err != nil {
+		return nil, err
+	}
+	return uncompress(arg)
+}
+
+func (call *builtinUncompress) compile(c *compiler) (ctype, error) {
+	if _, err := call.Arguments[0].compile(c); err != nil {
+		return ctype{}, err
+	}
+	c.asm.Fn_UNCOMPRESS()
+	return ctype{Type: sqltypes.Blob, Col: collationBinary, Flag: flagNullable}, nil
+}
+
+func (call *builtinUncompressedLength) eval(env *ExpressionEnv) (eval, error) {
+	arg, err := call.arg1(env)
+	if err != nil {
+		return nil, err
+	}
+	return uncompressedLength(arg)
+}
+
+func (call *builtinUncompressedLength) compile(c *compiler) (ctype, error) {
+	str, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	c.asm.Fn_UNCOMPRESSED_LENGTH()
+	return ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: nullableFlags(str.Flag)}, nil
+}
+
+// formatDigest formats a normalized statement the way MySQL prints statement
+// digests: literals become '?', lists of literals become '(...)' and comments
+// are dropped.
+func formatDigest(buf *sqlparser.TrackedBuffer, node sqlparser.SQLNode) {
+	switch node.(type) {
+	case *sqlparser.ParsedComments:
+	case *sqlparser.Argument:
+		buf.WriteString("?")
+	case sqlparser.ListArg:
+		buf.WriteString("(...)")
+	default:
+		node.Format(buf)
+	}
+}
+
+// statementDigestText normalizes the given statement with the sqlparser
+// normalizer and formats it like MySQL's STATEMENT_DIGEST_TEXT.
+func statementDigestText(parser *sqlparser.Parser, sql string) (string, error) {
+	stmt, reservedVars, err := parser.Parse2(sql)
+	if err != nil {
+		return "", err
+	}
+	err = sqlparser.Normalize(stmt, sqlparser.NewReservedVars("digest", reservedVars), map[string]*querypb.BindVariable{})
+	if err != nil {
+		return "", err
+	}
+
+	buf := sqlparser.NewTrackedBuffer(formatDigest)
+	buf.SetUpperCase(true)
+	buf.SetEscapeAllIdentifiers()
+	stmt.Format(buf)
+	return buf.String(), nil
+}
+
+// statementDigest returns the STATEMENT_DIGEST_TEXT of arg if text is set, and its
+// STATEMENT_DIGEST otherwise, which MySQL computes as the SHA-256 of the digest text,
+// in hexadecimal.
+func statementDigest(parser *sqlparser.Parser, arg eval, col collations.TypedCollation, text bool) (eval, error) {
+	if arg == nil {
+		return nil, nil
+	}
+	digest, err := statementDigestText(parser, string(evalToBinary(arg).bytes))
+	if err != nil {
+		return nil, err
+	}
+	if text {
+		return newEvalRaw(sqltypes.Text, []byte(digest), col), nil
+	}
+	sum := sha256.Sum256([]byte(digest))
+	buf := make([]byte, hex.EncodedLen(len(sum)))
+	hex.Encode(buf, sum[:])
+	return newEvalText(buf, col), nil
+}
+
+func (call *builtinStatementDigest) eval(env *ExpressionEnv) (eval, error) {
+	arg, err := call.arg1(env)
+	if err != nil {
+		return nil, err
+	}
+	return statementDigest(env.vc.Environment().Parser(), arg, typedCoercionCollation(sqltypes.VarChar, call.collate), false)
+}
+
+func (call *builtinStatementDigest) compile(c *compiler) (ctype, error) {
+	str, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	col := typedCoercionCollation(sqltypes.VarChar, c.collation)
+	c.asm.Fn_STATEMENT_DIGEST(col)
+	return ctype{Type: sqltypes.VarChar, Col: col, Flag: nullableFlags(str.Flag)}, nil
+}
+
+func (call *builtinStatementDigestText) eval(env *ExpressionEnv) (eval, error) {
+	arg, err := call.arg1(env)
+	if err != nil {
+		return nil, err
+	}
+	return statementDigest(env.vc.Environment().Parser(), arg, typedCoercionCollation(sqltypes.Text, call.collate), true)
+}
+
+func (call *builtinStatementDigestText) compile(c *compiler) (ctype, error) {
+	str, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	col := typedCoercionCollation(sqltypes.Text, c.collation)
+	c.asm.Fn_STATEMENT_DIGEST_TEXT(col)
+	return ctype{Type: sqltypes.Text, Col: col, Flag: nullableFlags(str.Flag)}, nil
+}
@@ -17,11 +17,22 @@ limitations under the License.
 package evalengine
 
 import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"vitess.io/vitess/go/hack"
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/collations/charset"
+	"vitess.io/vitess/go/mysql/collations/colldata"
+	"vitess.io/vitess/go/mysql/decimal"
 	"vitess.io/vitess/go/mysql/json"
+	"vitess.io/vitess/go/ptr"
 	"vitess.io/vitess/go/slice"
 	"vitess.io/vitess/go/sqltypes"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
 )
 
@@ -57,6 +68,44 @@ type (
 	builtinJSONKeys struct {
 		CallExpr
 	}
+
+	builtinJSONModify struct {
+		CallExpr
+		transform json.Transformation
+	}
+
+	builtinJSONMerge struct {
+		CallExpr
+		patch bool
+	}
+
+	builtinJSONSearch struct {
+		CallExpr
+		collate collations.ID
+	}
+
+	builtinJSONOverlaps struct {
+		CallExpr
+	}
+
+	builtinJSONValue struct {
+		CallExpr
+		onEmpty   sqlparser.JtOnResponseType
+		onError   sqlparser.JtOnResponseType
+		returning *ConvertExpr
+	}
+
+	builtinJSONSchemaValid struct {
+		CallExpr
+	}
+
+	builtinMemberOf struct {
+		CallExpr
+	}
+
+	builtinJSONPretty struct {
+		CallExpr
+	}
 )
 
 var _ IR = (*builtinJSONExtract)(nil)
@@ -67,6 +116,14 @@ var _ IR = (*builtinJSONDepth)(nil)
 var _ IR = (*builtinJSONLength)(nil)
 var _ IR = (*builtinJSONContainsPath)(nil)
 var _ IR = (*builtinJSONKeys)(nil)
+var _ IR = (*builtinJSONModify)(nil)
+var _ IR = (*builtinJSONMerge)(nil)
+var _ IR = (*builtinJSONSearch)(nil)
+var _ IR = (*builtinJSONOverlaps)(nil)
+var _ IR = (*builtinJSONValue)(nil)
+var _ IR = (*builtinJSONSchemaValid)(nil)
+var _ IR = (*builtinMemberOf)(nil)
+var _ IR = (*builtinJSONPretty)(nil)
 
 var errInvalidPathForTransform = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "In this situation, path expressions may not contain the * and ** tokens or an array range.")
 
@@ -511,3 +568,738 @@ func (call *builtinJSONKeys) compile(c *compiler) (ctype, error) {
 	c.asm.Fn_JSON_KEYS(jp)
 	return ctype{Type: sqltypes.TypeJSON, Flag: flagNullable, Col: collationJSON}, nil
 }
+
+// jsonModify applies the transformation t to the JSON document in args[0]
+// for all the paths in the rest of args. Unless t is json.Remove, every
+// path is followed by the value that must be used for it.
+func jsonModify(fname string, t json.Transformation, args []eval) (eval, error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	doc, err := intoJSON(fname, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	step := 2
+	if t == json.Remove {
+		step = 1
+	}
+
+	paths := make([]*json.Path, 0, len(args)/step)
+	values := make([]*json.Value, 0, len(args)/step)
+	for i := 1; i < len(args); i += step {
+		if args[i] == nil {
+			return nil, nil
+		}
+		path, err := intoJSONPath(args[i])
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+
+		if t != json.Remove {
+			value, err := argToJSON(args[i+1])
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value.Clone())
+		}
+	}
+
+	res, err := json.ApplyTransform(t, doc.Clone(), paths, values)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (call *builtinJSONModify) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	return jsonModify(call.Method, call.transform, args)
+}
+
+func (call *builtinJSONModify) compile(c *compiler) (ctype, error) {
+	for i, arg := range call.Arguments {
+		a, err := arg.compile(c)
+		if err != nil {
+			return ctype{}, err
+		}
+		if call.transform != json.Remove && i > 0 && i%2 == 0 {
+			if _, err := c.compileArgToJSON(a, 1); err != nil {
+				return ctype{}, err
+			}
+		}
+	}
+	c.asm.Fn_JSON_MODIFY(call.Method, call.transform, len(call.Arguments))
+	return ctype{Type: sqltypes.TypeJSON, Flag: flagNullable, Col: collationJSON}, nil
+}
+
+// jsonMerge merges all the JSON documents in args, either following RFC 7396
+// like JSON_MERGE_PATCH, or preserving duplicate keys like JSON_MERGE_PRESERVE.
+func jsonMerge(fname string, patch bool, args []eval) (eval, error) {
+	docs := make([]*json.Value, 0, len(args))
+	for _, arg := range args {
+		if arg == nil {
+			docs = append(docs, nil)
+			continue
+		}
+		doc, err := intoJSON(fname, arg)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc.Clone())
+	}
+
+	if !patch {
+		for _, doc := range docs {
+			if doc == nil {
+				return nil, nil
+			}
+		}
+		res := docs[0]
+		for _, doc := range docs[1:] {
+			res = jsonMergePreserve(res, doc)
+		}
+		return res, nil
+	}
+
+	// A NULL document only makes the result NULL until a document
+	// that is not an object replaces the whole result.
+	res := docs[0]
+	for _, doc := range docs[1:] {
+		switch {
+		case doc == nil:
+			res = nil
+		case doc.Type() != json.TypeObject:
+			res = doc
+		case res != nil:
+			res = jsonMergePatch(res, doc)
+		}
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res, nil
+}
+
+func jsonMergePreserve(target, doc *json.Value) *json.Value {
+	tobj, tok := target.Object()
+	dobj, dok := doc.Object()
+	if tok && dok {
+		dobj.Visit(func(key string, value *json.Value) {
+			if prev := tobj.Get(key); prev != nil {
+				value = jsonMergePreserve(prev, value)
+			}
+			tobj.Set(key, value, json.Set)
+		})
+		return target
+	}
+
+	ary, ok := target.Array()
+	if !ok {
+		ary = []*json.Value{target}
+	}
+	if other, ok := doc.Array(); ok {
+		ary = append(ary, other...)
+	} else {
+		ary = append(ary, doc)
+	}
+	return json.NewArray(ary)
+}
+
+func jsonMergePatch(target, patch *json.Value) *json.Value {
+	pobj, ok := patch.Object()
+	if !ok {
+		return patch
+	}
+	if target == nil || target.Type() != json.TypeObject {
+		target = json.NewObject(json.Object{})
+	}
+	tobj, _ := target.Object()
+	pobj.Visit(func(key string, value *json.Value) {
+		if value.Type() == json.TypeNull {
+			tobj.Del(key)
+		} else {
+			tobj.Set(key, jsonMergePatch(tobj.Get(key), value), json.Set)
+		}
+	})
+	return target
+}
+
+func (call *builtinJSONMerge) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	return jsonMerge(call.Method, call.patch, args)
+}
+
+func (call *builtinJSONMerge) compile(c *compiler) (ctype, error) {
+	for _, arg := range call.Arguments {
+		if _, err := arg.compile(c); err != nil {
+			return ctype{}, err
+		}
+	}
+	c.asm.Fn_JSON_MERGE(call.Method, call.patch, len(call.Arguments))
+	return ctype{Type: sqltypes.TypeJSON, Flag: flagNullable, Col: collationJSON}, nil
+}
+
+var errJSONSearchEscape = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Incorrect arguments to ESCAPE")
+
+// jsonSearch returns the paths to the strings in a JSON document that match
+// a LIKE pattern. The arguments are the document, 'one' or 'all', the pattern,
+// and optionally the escape character followed by the paths to search in.
+// The pattern is matched with its own collation if it's a utf8mb4 one, since
+// that's the encoding of all JSON strings, or with collate otherwise.
+func jsonSearch(args []eval, collate collations.ID) (eval, error) {
+	for i, arg := range args {
+		if arg == nil && i != 3 {
+			return nil, nil
+		}
+	}
+
+	doc, err := intoJSON("JSON_SEARCH", args[0])
+	if err != nil {
+		return nil, err
+	}
+	match, err := intoOneOrAll("JSON_SEARCH", evalToBinary(args[1]).string())
+	if err != nil {
+		return nil, err
+	}
+
+	if b, ok := args[2].(*evalBytes); ok && sqltypes.IsText(b.SQLType()) {
+		if _, utf8mb4 := colldata.Lookup(b.col.Collation).Charset().(charset.Charset_utf8mb4); utf8mb4 {
+			collate = b.col.Collation
+		}
+	}
+	pattern, err := evalToVarchar(args[2], collate, true)
+	if err != nil {
+		return nil, err
+	}
+
+	escape := '\\'
+	if len(args) > 3 && args[3] != nil {
+		esc := args[3].ToRawBytes()
+		if len(esc) > 0 {
+			r, size := utf8.DecodeRune(esc)
+			if size != len(esc) {
+				return nil, errJSONSearchEscape
+			}
+			escape = r
+		}
+	}
+
+	var roots []*json.Path
+	if len(args) > 4 {
+		roots = make([]*json.Path, 0, len(args)-4)
+		for _, arg := range args[4:] {
+			path, err := intoJSONPath(arg)
+			if err != nil {
+				return nil, err
+			}
+			roots = append(roots, path)
+		}
+	}
+
+	wc := colldata.Lookup(collate).Wildcard(pattern.bytes, 0, 0, escape)
+	found := json.Search(doc, roots, match == jsonMatchOne, func(s string) bool {
+		return wc.Match(hack.StringBytes(s))
+	})
+
+	switch len(found) {
+	case 0:
+		return nil, nil
+	case 1:
+		return json.NewString(found[0]), nil
+	default:
+		paths := make([]*json.Value, 0, len(found))
+		for _, p := range found {
+			paths = append(paths, json.NewString(p))
+		}
+		return json.NewArray(paths), nil
+	}
+}
+
+func (call *builtinJSONSearch) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	return jsonSearch(args, call.collate)
+}
+
+func (call *builtinJSONSearch) compile(c *compiler) (ctype, error) {
+	for _, arg := range call.Arguments {
+		if _, err := arg.compile(c); err != nil {
+			return ctype{}, err
+		}
+	}
+	c.asm.Fn_JSON_SEARCH(len(call.Arguments), call.collate)
+	return ctype{Type: sqltypes.TypeJSON, Flag: flagNullable, Col: collationJSON}, nil
+}
+
+// jsonOverlaps returns whether two JSON documents have any array element,
+// or any key-value pair, in common. A scalar is compared against the elements
+// of an array as if it were a single-element array.
+func jsonOverlaps(a, b *json.Value) (bool, error) {
+	if b.Type() == json.TypeArray {
+		a, b = b, a
+	}
+
+	switch a.Type() {
+	case json.TypeArray:
+		ary, _ := a.Array()
+		others := []*json.Value{b}
+		if other, ok := b.Array(); ok {
+			others = other
+		}
+		for _, v := range ary {
+			for _, other := range others {
+				if cmp, err := compareJSONValue(v, other); err != nil || cmp == 0 {
+					return err == nil, err
+				}
+			}
+		}
+		return false, nil
+	case json.TypeObject:
+		aobj, _ := a.Object()
+		bobj, ok := b.Object()
+		if !ok {
+			return false, nil
+		}
+		for _, key := range aobj.Keys() {
+			other := bobj.Get(key)
+			if other == nil {
+				continue
+			}
+			if cmp, err := compareJSONValue(aobj.Get(key), other); err != nil || cmp == 0 {
+				return err == nil, err
+			}
+		}
+		return false, nil
+	default:
+		cmp, err := compareJSONValue(a, b)
+		return cmp == 0, err
+	}
+}
+
+func jsonOverlapsEval(l, r eval) (eval, error) {
+	if l == nil || r == nil {
+		return nil, nil
+	}
+	a, err := intoJSON("JSON_OVERLAPS", l)
+	if err != nil {
+		return nil, err
+	}
+	b, err := intoJSON("JSON_OVERLAPS", r)
+	if err != nil {
+		return nil, err
+	}
+	overlaps, err := jsonOverlaps(a, b)
+	if err != nil {
+		return nil, err
+	}
+	return newEvalBool(overlaps), nil
+}
+
+func (call *builtinJSONOverlaps) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	return jsonOverlapsEval(args[0], args[1])
+}
+
+func (call *builtinJSONOverlaps) compile(c *compiler) (ctype, error) {
+	for _, arg := range call.Arguments {
+		if _, err := arg.compile(c); err != nil {
+			return ctype{}, err
+		}
+	}
+	c.asm.Fn_JSON_OVERLAPS()
+	return ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: flagIsBoolean | flagNullable}, nil
+}
+
+var (
+	errJSONValueMissing   = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "No value was found by 'json_value' on the specified path.")
+	errJSONValueNotScalar = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Can't store an array or an object in the scalar column 'json_value'.")
+)
+
+// jsonValue extracts the scalar value at a path of a JSON document as text,
+// or as the RETURNING type when one is given. The arguments are the document
+// and the path, followed by the DEFAULT values for ON EMPTY and ON ERROR,
+// when any of them is used.
+func jsonValue(env *ExpressionEnv, args []eval, onEmpty, onError sqlparser.JtOnResponseType, returning *ConvertExpr) (eval, error) {
+	defaults := args[2:]
+	respond := func(t sqlparser.JtOnResponseType, err error) (eval, error) {
+		switch t {
+		case sqlparser.ErrorJSONType:
+			return nil, err
+		case sqlparser.DefaultJSONType:
+			def := defaults[0]
+			if def == nil {
+				return nil, nil
+			}
+			if returning != nil {
+				return jsonValueReturning(env, returning, def)
+			}
+			return evalToVarchar(def, collationJSON.Collation, true)
+		default:
+			return nil, nil
+		}
+	}
+
+	if args[0] == nil || args[1] == nil {
+		return nil, nil
+	}
+	doc, err := intoJSON("JSON_VALUE", args[0])
+	if err != nil {
+		return nil, err
+	}
+	path, err := intoJSONPath(args[1])
+	if err != nil {
+		return nil, err
+	}
+	if path.ContainsWildcards() {
+		return nil, errInvalidPathForTransform
+	}
+
+	var value *json.Value
+	path.Match(doc, true, func(v *json.Value) {
+		if value == nil {
+			value = v
+		}
+	})
+	if value == nil {
+		return respond(onEmpty, errJSONValueMissing)
+	}
+	if onEmpty == sqlparser.DefaultJSONType {
+		defaults = defaults[1:]
+	}
+
+	switch value.Type() {
+	case json.TypeNull:
+		return nil, nil
+	case json.TypeObject, json.TypeArray:
+		return respond(onError, errJSONValueNotScalar)
+	}
+	if returning != nil {
+		res, err := jsonValueReturning(env, returning, value)
+		if err != nil {
+			return respond(onError, err)
+		}
+		return res, nil
+	}
+	if b, ok := value.StringBytes(); ok {
+		return newEvalText(b, collationJSON), nil
+	}
+	return newEvalText(value.MarshalTo(nil), collationJSON), nil
+}
+
+// jsonValueReturning converts a value of JSON_VALUE to its RETURNING type.
+// Unlike CAST, a value that does not fit the type is not truncated or
+// clamped but is an error, which JSON_VALUE handles according to ON ERROR.
+func jsonValueReturning(env *ExpressionEnv, conv *ConvertExpr, e eval) (eval, error) {
+	if conv.Type == "JSON" {
+		return evalToJSON(e)
+	}
+
+	var text []byte
+	switch e := e.(type) {
+	case *evalJSON:
+		if b, ok := e.Bool(); ok {
+			switch conv.Type {
+			case "SIGNED", "SIGNED INTEGER", "UNSIGNED", "UNSIGNED INTEGER", "DECIMAL", "DOUBLE", "REAL":
+				text = []byte("0")
+				if b {
+					text = []byte("1")
+				}
+			default:
+				text = e.MarshalTo(nil)
+			}
+		} else if b, ok := e.StringBytes(); ok {
+			text = b
+		} else {
+			text = e.MarshalTo(nil)
+		}
+	default:
+		t, err := evalToVarchar(e, collationJSON.Collation, true)
+		if err != nil {
+			return nil, err
+		}
+		text = t.bytes
+	}
+
+	invalid := func(target string) error {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Invalid JSON value for CAST to %s from column json_value at row 1", target)
+	}
+	outOfRange := func(target string) error {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Out of range JSON value for CAST to %s from column json_value at row 1", target)
+	}
+	tooLong := vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Data too long for column 'json_value' at row 1")
+
+	switch conv.Type {
+	case "BINARY":
+		if conv.Length != nil && len(text) > *conv.Length {
+			return nil, tooLong
+		}
+		b := newEvalBinary(text)
+		b.tt = int16(conv.convertToBinaryType(sqltypes.VarChar))
+		return b, nil
+	case "CHAR", "NCHAR":
+		t, err := evalToVarchar(newEvalText(text, collationJSON), conv.Collation, true)
+		if err != nil {
+			return nil, invalid("CHAR")
+		}
+		if conv.Length != nil && charset.Length(colldata.Lookup(conv.Collation).Charset(), t.bytes) > *conv.Length {
+			return nil, tooLong
+		}
+		t.tt = int16(conv.convertToCharType(sqltypes.VarChar))
+		return t, nil
+	case "DECIMAL":
+		dec, err := decimal.NewFromString(string(text))
+		if err != nil {
+			return nil, invalid("DECIMAL")
+		}
+		m, d := conv.decimalPrecision()
+		dec = dec.Round(d)
+		if !dec.Clamp(m-d, d).Equal(dec) {
+			return nil, outOfRange("DECIMAL")
+		}
+		return newEvalDecimal(dec, m, d), nil
+	case "DOUBLE", "REAL":
+		f, err := strconv.ParseFloat(strings.TrimSpace(string(text)), 64)
+		if err != nil {
+			if errors.Is(err, strconv.ErrRange) {
+				return nil, outOfRange("DOUBLE")
+			}
+			return nil, invalid("DOUBLE")
+		}
+		return newEvalFloat(f), nil
+	case "SIGNED", "SIGNED INTEGER", "UNSIGNED", "UNSIGNED INTEGER":
+		dec, err := decimal.NewFromString(string(text))
+		if err != nil {
+			return nil, invalid("INTEGER")
+		}
+		dec = dec.Round(0)
+		if conv.Type == "SIGNED" || conv.Type == "SIGNED INTEGER" {
+			i, ok := dec.Int64()
+			if !ok {
+				return nil, outOfRange("INTEGER")
+			}
+			return newEvalInt64(i), nil
+		}
+		u, ok := dec.Uint64()
+		if !ok {
+			return nil, outOfRange("INTEGER")
+		}
+		return newEvalUint64(u), nil
+	case "DATE":
+		if d := evalToDate(newEvalText(text, collationJSON), env.now, env.sqlmode.AllowZeroDate()); d != nil {
+			return d, nil
+		}
+		return nil, invalid("DATE")
+	case "DATETIME":
+		p := ptr.Unwrap(conv.Length, 0)
+		if p > 6 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Too-big precision %d specified for 'JSON_VALUE'. Maximum is 6.", p)
+		}
+		if dt := evalToDateTime(newEvalText(text, collationJSON), p, env.now, env.sqlmode.AllowZeroDate()); dt != nil {
+			return dt, nil
+		}
+		return nil, invalid("DATETIME")
+	case "TIME":
+		p := ptr.Unwrap(conv.Length, 0)
+		if p > 6 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Too-big precision %d specified for 'JSON_VALUE'. Maximum is 6.", p)
+		}
+		if t := evalToTime(newEvalText(text, collationJSON), p); t != nil {
+			return t, nil
+		}
+		return nil, invalid("TIME")
+	default:
+		return nil, conv.returnUnsupportedError()
+	}
+}
+
+func (call *builtinJSONValue) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	return jsonValue(env, args, call.onEmpty, call.onError, call.returning)
+}
+
+func (call *builtinJSONValue) compile(c *compiler) (ctype, error) {
+	for _, arg := range call.Arguments {
+		if _, err := arg.compile(c); err != nil {
+			return ctype{}, err
+		}
+	}
+	c.asm.Fn_JSON_VALUE(len(call.Arguments), call.onEmpty, call.onError, call.returning)
+
+	conv := call.returning
+	if conv == nil {
+		return ctype{Type: sqltypes.VarChar, Flag: flagNullable, Col: collationJSON}, nil
+	}
+	switch conv.Type {
+	case "BINARY":
+		return ctype{Type: conv.convertToBinaryType(sqltypes.VarChar), Flag: flagNullable, Col: collationBinary}, nil
+	case "CHAR", "NCHAR":
+		return ctype{Type: conv.convertToCharType(sqltypes.VarChar), Flag: flagNullable, Col: collations.TypedCollation{Collation: conv.Collation}}, nil
+	case "DECIMAL":
+		m, d := conv.decimalPrecision()
+		return ctype{Type: sqltypes.Decimal, Flag: flagNullable, Col: collationNumeric, Size: m, Scale: d}, nil
+	case "DOUBLE", "REAL":
+		return ctype{Type: sqltypes.Float64, Flag: flagNullable, Col: collationNumeric}, nil
+	case "SIGNED", "SIGNED INTEGER":
+		return ctype{Type: sqltypes.Int64, Flag: flagNullable, Col: collationNumeric}, nil
+	case "UNSIGNED", "UNSIGNED INTEGER":
+		return ctype{Type: sqltypes.Uint64, Flag: flagNullable, Col: collationNumeric}, nil
+	case "JSON":
+		return ctype{Type: sqltypes.TypeJSON, Flag: flagNullable, Col: collationJSON}, nil
+	case "DATE":
+		return ctype{Type: sqltypes.Date, Flag: flagNullable, Col: collationBinary}, nil
+	case "DATETIME", "TIME":
+		p := ptr.Unwrap(conv.Length, 0)
+		if p > 6 {
+			return ctype{}, c.unsupported(call)
+		}
+		tt := sqltypes.Datetime
+		if conv.Type == "TIME" {
+			tt = sqltypes.Time
+		}
+		return ctype{Type: tt, Size: int32(p), Flag: flagNullable, Col: collationBinary}, nil
+	default:
+		return ctype{}, c.unsupported(call)
+	}
+}
+
+var errJSONSchemaNotObject = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Invalid JSON type in argument 1 to function json_schema_valid; an object is required.")
+
+func jsonSchemaValid(s, d eval) (eval, error) {
+	if s == nil || d == nil {
+		return nil, nil
+	}
+	schema, err := intoJSON("json_schema_valid", s)
+	if err != nil {
+		return nil, err
+	}
+	if schema.Type() != json.TypeObject {
+		return nil, errJSONSchemaNotObject
+	}
+	doc, err := intoJSON("json_schema_valid", d)
+	if err != nil {
+		return nil, err
+	}
+	valid, err := json.ValidateSchema(schema, doc)
+	if err != nil {
+		return nil, err
+	}
+	return newEvalBool(valid), nil
+}
+
+func (call *builtinJSONSchemaValid) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	return jsonSchemaValid(args[0], args[1])
+}
+
+func (call *builtinJSONSchemaValid) compile(c *compiler) (ctype, error) {
+	for _, arg := range call.Arguments {
+		if _, err := arg.compile(c); err != nil {
+			return ctype{}, err
+		}
+	}
+	c.asm.Fn_JSON_SCHEMA_VALID()
+	return ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: flagIsBoolean | flagNullable}, nil
+}
+
+// memberOf returns whether value is an element of the JSON array in ary,
+// or whether it equals the document itself if it's not an array.
+func memberOf(value, ary eval) (eval, error) {
+	if value == nil || ary == nil {
+		return nil, nil
+	}
+	v, err := argToJSON(value)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := intoJSON("MEMBER OF", ary)
+	if err != nil {
+		return nil, err
+	}
+
+	elements, ok := doc.Array()
+	if !ok {
+		elements = []*json.Value{doc}
+	}
+	for _, elem := range elements {
+		cmp, err := compareJSONValue(v, elem)
+		if err != nil {
+			return nil, err
+		}
+		if cmp == 0 {
+			return newEvalBool(true), nil
+		}
+	}
+	return newEvalBool(false), nil
+}
+
+func (call *builtinMemberOf) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	return memberOf(args[0], args[1])
+}
+
+func (call *builtinMemberOf) compile(c *compiler) (ctype, error) {
+	value, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	skip := c.compileNullCheck1(value)
+	if _, err := c.compileArgToJSON(value, 1); err != nil {
+		return ctype{}, err
+	}
+	if _, err := call.Arguments[1].compile(c); err != nil {
+		return ctype{}, err
+	}
+	c.asm.Fn_MEMBER_OF()
+	c.asm.jumpDestination(skip)
+	return ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: flagIsBoolean | flagNullable}, nil
+}
+
+func jsonPretty(arg eval) (eval, error) {
+	if arg == nil {
+		return nil, nil
+	}
+	doc, err := intoJSON("json_pretty", arg)
+	if err != nil {
+		return nil, err
+	}
+	return newEvalRaw(sqltypes.Blob, doc.MarshalPrettyTo(nil), collationJSON), nil
+}
+
+func (call *builtinJSONPretty) eval(env *ExpressionEnv) (eval, error) {
+	arg, err := call.arg1(env)
+	if err != nil {
+		return nil, err
+	}
+	return jsonPretty(arg)
+}
+
+func (call *builtinJSONPretty) compile(c *compiler) (ctype, error) {
+	if _, err := call.Arguments[0].compile(c); err != nil {
+		return ctype{}, err
+	}
+	c.asm.Fn_JSON_PRETTY()
+	return ctype{Type: sqltypes.Blob, Flag: flagNullable, Col: collationJSON}, nil
+}
@@ -19,6 +19,8 @@ package evalengine
 import (
 	"bytes"
 	"math"
+	"strconv"
+	"strings"
 
 	"vitess.io/vitess/go/mysql/capabilities"
 	"vitess.io/vitess/go/mysql/collations"
@@ -150,6 +152,41 @@ type (
 		CallExpr
 		collate collations.ID
 	}
+
+	builtinSubstringIndex struct {
+		CallExpr
+		collate collations.ID
+	}
+
+	builtinFormat struct {
+		CallExpr
+		collate collations.ID
+	}
+
+	builtinSoundex struct {
+		CallExpr
+		collate collations.ID
+	}
+
+	builtinMakeSet struct {
+		CallExpr
+		collate collations.ID
+	}
+
+	builtinExportSet struct {
+		CallExpr
+		collate collations.ID
+	}
+
+	builtinFindInSet struct {
+		CallExpr
+		collate collations.ID
+	}
+
+	builtinQuote struct {
+		CallExpr
+		collate collations.ID
+	}
 )
 
 var _ IR = (*builtinField)(nil)
@@ -176,6 +213,13 @@ var _ IR = (*builtinRepeat)(nil)
 var _ IR = (*builtinConcat)(nil)
 var _ IR = (*builtinConcatWs)(nil)
 var _ IR = (*builtinReplace)(nil)
+var _ IR = (*builtinSubstringIndex)(nil)
+var _ IR = (*builtinFormat)(nil)
+var _ IR = (*builtinSoundex)(nil)
+var _ IR = (*builtinMakeSet)(nil)
+var _ IR = (*builtinExportSet)(nil)
+var _ IR = (*builtinFindInSet)(nil)
+var _ IR = (*builtinQuote)(nil)
 
 func fieldSQLType(arg sqltypes.Type, tt sqltypes.Type) sqltypes.Type {
 	if sqltypes.IsNull(arg) {
@@ -2189,3 +2233,779 @@ func replace(str, from, to []byte) []byte {
 	end += copy(out[end:], str[start:])
 	return out[0:end]
 }
+
+// stringResultCollation returns the collation of a string result that has
+// been aggregated from the arguments in ca. When none of the arguments were
+// strings, the default collation is used instead.
+func stringResultCollation(ca *collationAggregation, tt sqltypes.Type, collate collations.ID) collations.TypedCollation {
+	tc := ca.result()
+	switch tc.Coercibility {
+	case collations.CoerceNumeric, collations.CoerceIgnorable:
+		return typedCoercionCollation(tt, collate)
+	}
+	return tc
+}
+
+func appendRune(dst []byte, cs charset.Charset, r rune) []byte {
+	var buf [4]byte
+	n := cs.EncodeRune(buf[:], r)
+	if n < 0 {
+		return dst
+	}
+	return append(dst, buf[:n]...)
+}
+
+func evalToCount(e eval) int64 {
+	if u, ok := e.(*evalUint64); ok && u.u > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return evalToInt64(e).i
+}
+
+// substringIndexScan returns the byte offset of the nth non-overlapping
+// occurrence of delim in str. When there are fewer than n occurrences,
+// it returns -1 and the total number of occurrences.
+func substringIndexScan(cs charset.Charset, str, delim []byte, n int64) (int, int64) {
+	var found int64
+	for pos := 0; pos <= len(str)-len(delim); {
+		if bytes.HasPrefix(str[pos:], delim) {
+			if found++; found == n {
+				return pos, found
+			}
+			pos += len(delim)
+			continue
+		}
+		_, size := cs.DecodeRune(str[pos:])
+		pos += max(size, 1)
+	}
+	return -1, found
+}
+
+func substringIndexBytes(cs charset.Charset, str, delim []byte, count int64) []byte {
+	if len(delim) == 0 || count == 0 {
+		return nil
+	}
+
+	if count > 0 {
+		pos, _ := substringIndexScan(cs, str, delim, count)
+		if pos < 0 {
+			return str
+		}
+		return str[:pos]
+	}
+
+	if cs.MaxWidth() == 1 {
+		for end := len(str); end > 0; {
+			pos := bytes.LastIndex(str[:end], delim)
+			if pos < 0 {
+				return str
+			}
+			if count++; count == 0 {
+				return str[pos+len(delim):]
+			}
+			end = pos
+		}
+		return str
+	}
+
+	// With multibyte charsets we can't safely search backwards, so count
+	// all the occurrences first and then find the right one from the start.
+	_, total := substringIndexScan(cs, str, delim, 0)
+	n := count + total + 1
+	if n <= 0 {
+		return str
+	}
+	pos, _ := substringIndexScan(cs, str, delim, n)
+	return str[pos+len(delim):]
+}
+
+func substringIndex(str, delim, count eval, tt sqltypes.Type, tc collations.TypedCollation) (eval, error) {
+	s, err := evalToVarchar(str, tc.Collation, true)
+	if err != nil {
+		return nil, err
+	}
+	d, err := evalToVarchar(delim, tc.Collation, true)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := colldata.Lookup(tc.Collation).Charset()
+	return newEvalRaw(tt, substringIndexBytes(cs, s.bytes, d.bytes, evalToCount(count)), tc), nil
+}
+
+func (call *builtinSubstringIndex) eval(env *ExpressionEnv) (eval, error) {
+	str, delim, count, err := call.arg3(env)
+	if err != nil {
+		return nil, err
+	}
+	if str == nil || delim == nil || count == nil {
+		return nil, nil
+	}
+
+	var ca collationAggregation
+	tt := concatSQLType(str.SQLType(), sqltypes.VarChar)
+	tt = concatSQLType(delim.SQLType(), tt)
+	if err := ca.add(evalCollation(str), env.collationEnv); err != nil {
+		return nil, err
+	}
+	if err := ca.add(evalCollation(delim), env.collationEnv); err != nil {
+		return nil, err
+	}
+
+	return substringIndex(str, delim, count, tt, stringResultCollation(&ca, tt, call.collate))
+}
+
+func (call *builtinSubstringIndex) compile(c *compiler) (ctype, error) {
+	str, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	delim, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	count, err := call.Arguments[2].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck3(str, delim, count)
+
+	var ca collationAggregation
+	tt := concatSQLType(str.Type, sqltypes.VarChar)
+	tt = concatSQLType(delim.Type, tt)
+	if err := ca.add(str.Col, c.env.CollationEnv()); err != nil {
+		return ctype{}, err
+	}
+	if err := ca.add(delim.Col, c.env.CollationEnv()); err != nil {
+		return ctype{}, err
+	}
+	tc := stringResultCollation(&ca, tt, call.collate)
+
+	c.asm.Fn_SUBSTRING_INDEX(tt, tc)
+	c.asm.jumpDestination(skip)
+	return ctype{Type: tt, Col: tc, Flag: flagNullable}, nil
+}
+
+// formatLocale describes how FORMAT prints numbers for a given locale.
+// The grouping gives the size of each group of integer digits, starting
+// from the decimal point; the last size is repeated for the rest of the
+// digits. An empty grouping means that the digits are not grouped.
+type formatLocale struct {
+	decimal   byte
+	thousands byte
+	grouping  []int
+}
+
+var (
+	formatLocaleEnUS = formatLocale{'.', ',', []int{3}}
+	formatLocaleEnIN = formatLocale{'.', ',', []int{3, 2}}
+	formatLocaleDeDE = formatLocale{',', '.', []int{3}}
+	formatLocaleDeCH = formatLocale{'.', '\'', []int{3}}
+	formatLocaleItCH = formatLocale{',', '\'', []int{3}}
+	formatLocaleRuRU = formatLocale{',', ' ', []int{3}}
+	formatLocaleFrFR = formatLocale{',', 0, nil}
+)
+
+// formatLocales contains the locales supported by FORMAT, keyed by their
+// lowercase name. Like MySQL, unknown locales fall back to en_US.
+var formatLocales = map[string]formatLocale{
+	"en_au": formatLocaleEnUS,
+	"en_ca": formatLocaleEnUS,
+	"en_gb": formatLocaleEnUS,
+	"en_in": formatLocaleEnIN,
+	"en_nz": formatLocaleEnUS,
+	"en_ph": formatLocaleEnUS,
+	"en_us": formatLocaleEnUS,
+	"en_za": formatLocaleEnUS,
+	"en_zw": formatLocaleEnUS,
+	"es_mx": formatLocaleEnUS,
+	"es_us": formatLocaleEnUS,
+	"he_il": formatLocaleEnUS,
+	"hi_in": formatLocaleEnIN,
+	"ja_jp": formatLocaleEnUS,
+	"ko_kr": formatLocaleEnUS,
+	"th_th": formatLocaleEnUS,
+	"zh_cn": formatLocaleEnUS,
+	"zh_hk": formatLocaleEnUS,
+	"zh_tw": formatLocaleEnUS,
+	"da_dk": formatLocaleDeDE,
+	"de_be": formatLocaleDeDE,
+	"de_de": formatLocaleDeDE,
+	"de_lu": formatLocaleDeDE,
+	"nl_be": formatLocaleDeDE,
+	"de_ch": formatLocaleDeCH,
+	"it_ch": formatLocaleItCH,
+	"cs_cz": formatLocaleRuRU,
+	"fi_fi": formatLocaleRuRU,
+	"nb_no": formatLocaleRuRU,
+	"ru_ru": formatLocaleRuRU,
+	"sk_sk": formatLocaleRuRU,
+	"sv_se": formatLocaleRuRU,
+	"uk_ua": formatLocaleRuRU,
+	"ca_es": formatLocaleFrFR,
+	"de_at": formatLocaleFrFR,
+	"es_es": formatLocaleFrFR,
+	"fr_be": formatLocaleFrFR,
+	"fr_ca": formatLocaleFrFR,
+	"fr_fr": formatLocaleFrFR,
+	"fr_lu": formatLocaleFrFR,
+	"it_it": formatLocaleFrFR,
+	"nl_nl": formatLocaleFrFR,
+	"pt_br": formatLocaleFrFR,
+	"pt_pt": formatLocaleFrFR,
+}
+
+func (loc *formatLocale) format(dst, num []byte, dec int) []byte {
+	if len(num) > 0 && num[0] == '-' {
+		dst = append(dst, '-')
+		num = num[1:]
+	}
+
+	integer, frac := num, []byte(nil)
+	if dec > 0 {
+		integer, frac = num[:len(num)-dec-1], num[len(num)-dec:]
+	}
+
+	if len(loc.grouping) == 0 {
+		dst = append(dst, integer...)
+	} else {
+		// Walk the integer digits from the decimal point to find where
+		// the groups start, and then write them from the left.
+		var starts []int
+		group := 0
+		for end := len(integer); ; {
+			end -= loc.grouping[group]
+			if end <= 0 {
+				break
+			}
+			starts = append(starts, end)
+			if group < len(loc.grouping)-1 {
+				group++
+			}
+		}
+		prev := 0
+		for i := len(starts) - 1; i >= 0; i-- {
+			dst = append(dst, integer[prev:starts[i]]...)
+			dst = append(dst, loc.thousands)
+			prev = starts[i]
+		}
+		dst = append(dst, integer[prev:]...)
+	}
+
+	if dec > 0 {
+		dst = append(dst, loc.decimal)
+		dst = append(dst, frac...)
+	}
+	return dst
+}
+
+func formatNumber(x, d, locale eval, col collations.TypedCollation) eval {
+	dec := evalToCount(d)
+	dec = max(0, min(dec, 30))
+
+	var num []byte
+	switch x := x.(type) {
+	case *evalInt64, *evalUint64, *evalDecimal:
+		num = []byte(evalToDecimal(x, 0, 0).dec.StringFixed(int32(dec)))
+	default:
+		f, _ := evalToFloat(x)
+		v := f.f * math.Pow10(int(dec))
+		if math.IsInf(v, 0) {
+			v = f.f
+		} else {
+			v = math.RoundToEven(v) / math.Pow10(int(dec))
+		}
+		num = strconv.AppendFloat(nil, v, 'f', int(dec), 64)
+	}
+
+	loc := formatLocaleEnUS
+	if locale != nil {
+		if l, ok := formatLocales[strings.ToLower(evalToBinary(locale).string())]; ok {
+			loc = l
+		}
+	}
+	return newEvalText(loc.format(nil, num, int(dec)), col)
+}
+
+func (call *builtinFormat) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	if args[0] == nil || args[1] == nil {
+		return nil, nil
+	}
+
+	var locale eval
+	if len(args) > 2 {
+		locale = args[2]
+	}
+	return formatNumber(args[0], args[1], locale, typedCoercionCollation(sqltypes.VarChar, call.collate)), nil
+}
+
+func (call *builtinFormat) compile(c *compiler) (ctype, error) {
+	x, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	d, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck2(x, d)
+
+	locale := len(call.Arguments) > 2
+	if locale {
+		if _, err := call.Arguments[2].compile(c); err != nil {
+			return ctype{}, err
+		}
+	}
+
+	col := typedCoercionCollation(sqltypes.VarChar, call.collate)
+	c.asm.Fn_FORMAT(locale, col)
+	c.asm.jumpDestination(skip)
+	return ctype{Type: sqltypes.VarChar, Col: col, Flag: nullableFlags(x.Flag | d.Flag)}, nil
+}
+
+const soundexMap = "01230120022455012623010202"
+
+func soundexIsAlpha(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r >= 0xC0
+}
+
+func soundexToUpper(r rune) rune {
+	if r >= 'a' && r <= 'z' {
+		return r - 'a' + 'A'
+	}
+	return r
+}
+
+func soundexCode(r rune) rune {
+	r = soundexToUpper(r)
+	if r < 'A' || r > 'Z' {
+		return '0'
+	}
+	return rune(soundexMap[r-'A'])
+}
+
+func soundex(cs charset.Charset, str []byte) []byte {
+	var dst []byte
+	var last rune
+	for {
+		if len(str) == 0 {
+			return dst
+		}
+		r, size := cs.DecodeRune(str)
+		if r == charset.RuneError && size < 2 {
+			return dst
+		}
+		str = str[size:]
+		if soundexIsAlpha(r) {
+			dst = appendRune(dst, cs, soundexToUpper(r))
+			last = soundexCode(r)
+			break
+		}
+	}
+
+	n := 1
+	for len(str) > 0 {
+		r, size := cs.DecodeRune(str)
+		if r == charset.RuneError && size < 2 {
+			break
+		}
+		str = str[size:]
+		if !soundexIsAlpha(r) {
+			continue
+		}
+		if code := soundexCode(r); code != '0' && code != last {
+			dst = appendRune(dst, cs, code)
+			last = code
+			n++
+		}
+	}
+
+	for ; n < 4; n++ {
+		dst = appendRune(dst, cs, '0')
+	}
+	return dst
+}
+
+func soundexEval(str eval, tt sqltypes.Type, tc collations.TypedCollation) (eval, error) {
+	s, err := evalToVarchar(str, tc.Collation, true)
+	if err != nil {
+		return nil, err
+	}
+	cs := colldata.Lookup(tc.Collation).Charset()
+	return newEvalRaw(tt, soundex(cs, s.bytes), tc), nil
+}
+
+func (call *builtinSoundex) eval(env *ExpressionEnv) (eval, error) {
+	str, err := call.arg1(env)
+	if err != nil || str == nil {
+		return nil, err
+	}
+
+	var ca collationAggregation
+	tt := concatSQLType(str.SQLType(), sqltypes.VarChar)
+	if err := ca.add(evalCollation(str), env.collationEnv); err != nil {
+		return nil, err
+	}
+	return soundexEval(str, tt, stringResultCollation(&ca, tt, call.collate))
+}
+
+func (call *builtinSoundex) compile(c *compiler) (ctype, error) {
+	str, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck1(str)
+
+	var ca collationAggregation
+	tt := concatSQLType(str.Type, sqltypes.VarChar)
+	if err := ca.add(str.Col, c.env.CollationEnv()); err != nil {
+		return ctype{}, err
+	}
+	tc := stringResultCollation(&ca, tt, call.collate)
+
+	c.asm.Fn_SOUNDEX(tt, tc)
+	c.asm.jumpDestination(skip)
+	return ctype{Type: tt, Col: tc, Flag: nullableFlags(str.Flag)}, nil
+}
+
+func makeSet(bits eval, strs []eval, tt sqltypes.Type, tc collations.TypedCollation) (eval, error) {
+	cs := colldata.Lookup(tc.Collation).Charset()
+	set := uint64(evalToInt64(bits).i)
+
+	var buf []byte
+	var first = true
+	for _, str := range strs {
+		if set == 0 {
+			break
+		}
+		if set&1 != 0 && str != nil {
+			s, err := evalToVarchar(str, tc.Collation, true)
+			if err != nil {
+				return nil, err
+			}
+			if !first {
+				buf = appendRune(buf, cs, ',')
+			}
+			buf = append(buf, s.bytes...)
+			first = false
+		}
+		set >>= 1
+	}
+	return newEvalRaw(tt, buf, tc), nil
+}
+
+func (call *builtinMakeSet) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	if args[0] == nil {
+		return nil, nil
+	}
+
+	var ca collationAggregation
+	tt := sqltypes.VarChar
+	for _, arg := range args[1:] {
+		if arg != nil {
+			tt = concatSQLType(arg.SQLType(), tt)
+		}
+		if err := ca.add(evalCollation(arg), env.collationEnv); err != nil {
+			return nil, err
+		}
+	}
+	return makeSet(args[0], args[1:], tt, stringResultCollation(&ca, tt, call.collate))
+}
+
+func (call *builtinMakeSet) compile(c *compiler) (ctype, error) {
+	var ca collationAggregation
+	tt := sqltypes.VarChar
+
+	var skip *jump
+	for i, arg := range call.Arguments {
+		a, err := arg.compile(c)
+		if err != nil {
+			return ctype{}, err
+		}
+		if i == 0 {
+			skip = c.compileNullCheck1(a)
+			continue
+		}
+		tt = concatSQLType(a.Type, tt)
+		if err := ca.add(a.Col, c.env.CollationEnv()); err != nil {
+			return ctype{}, err
+		}
+	}
+	tc := stringResultCollation(&ca, tt, call.collate)
+
+	c.asm.Fn_MAKE_SET(len(call.Arguments), tt, tc)
+	c.asm.jumpDestination(skip)
+	return ctype{Type: tt, Col: tc, Flag: flagNullable}, nil
+}
+
+func exportSet(args []eval, tt sqltypes.Type, tc collations.TypedCollation) (eval, error) {
+	for _, arg := range args {
+		if arg == nil {
+			return nil, nil
+		}
+	}
+
+	cs := colldata.Lookup(tc.Collation).Charset()
+	on, err := evalToVarchar(args[1], tc.Collation, true)
+	if err != nil {
+		return nil, err
+	}
+	off, err := evalToVarchar(args[2], tc.Collation, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var sep []byte
+	if len(args) > 3 {
+		s, err := evalToVarchar(args[3], tc.Collation, true)
+		if err != nil {
+			return nil, err
+		}
+		sep = s.bytes
+	} else {
+		sep = appendRune(nil, cs, ',')
+	}
+
+	n := uint64(64)
+	if len(args) > 4 {
+		n = min(n, uint64(evalToInt64(args[4]).i))
+	}
+
+	bits := uint64(evalToInt64(args[0]).i)
+	var buf []byte
+	for i := uint64(0); i < n; i++ {
+		if i > 0 {
+			buf = append(buf, sep...)
+		}
+		if bits&(1<<i) != 0 {
+			buf = append(buf, on.bytes...)
+		} else {
+			buf = append(buf, off.bytes...)
+		}
+	}
+	return newEvalRaw(tt, buf, tc), nil
+}
+
+func (call *builtinExportSet) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+
+	var ca collationAggregation
+	tt := sqltypes.VarChar
+	for _, arg := range args[1:min(len(args), 4)] {
+		if arg != nil {
+			tt = concatSQLType(arg.SQLType(), tt)
+		}
+		if err := ca.add(evalCollation(arg), env.collationEnv); err != nil {
+			return nil, err
+		}
+	}
+	return exportSet(args, tt, stringResultCollation(&ca, tt, call.collate))
+}
+
+func (call *builtinExportSet) compile(c *compiler) (ctype, error) {
+	var ca collationAggregation
+	tt := sqltypes.VarChar
+
+	for i, arg := range call.Arguments {
+		a, err := arg.compile(c)
+		if err != nil {
+			return ctype{}, err
+		}
+		if i == 0 || i > 3 {
+			continue
+		}
+		tt = concatSQLType(a.Type, tt)
+		if err := ca.add(a.Col, c.env.CollationEnv()); err != nil {
+			return ctype{}, err
+		}
+	}
+	tc := stringResultCollation(&ca, tt, call.collate)
+
+	c.asm.Fn_EXPORT_SET(len(call.Arguments), tt, tc)
+	return ctype{Type: tt, Col: tc, Flag: flagNullable}, nil
+}
+
+func findInSetPosition(coll colldata.Collation, str, list []byte) int64 {
+	if len(list) < len(str) {
+		return 0
+	}
+
+	cs := coll.Charset()
+	var position int64
+	var last rune
+	var begin, end int
+	for end < len(list) {
+		r, size := cs.DecodeRune(list[end:])
+		if r == charset.RuneError && size < 2 {
+			break
+		}
+		last = r
+		next := end + size
+		separator := r == ','
+		if separator || next == len(list) {
+			position++
+			if !separator {
+				end = next
+			}
+			if coll.Collate(list[begin:end], str, false) == 0 {
+				return position
+			}
+			begin = next
+		}
+		end = next
+	}
+	// An empty string matches a trailing empty element in the list
+	if end == begin && len(str) == 0 && last == ',' {
+		return position + 1
+	}
+	return 0
+}
+
+func findInSet(str, list eval, tc collations.TypedCollation) (eval, error) {
+	s, err := evalToVarchar(str, tc.Collation, true)
+	if err != nil {
+		return nil, err
+	}
+	l, err := evalToVarchar(list, tc.Collation, true)
+	if err != nil {
+		return nil, err
+	}
+	return newEvalInt64(findInSetPosition(colldata.Lookup(tc.Collation), s.bytes, l.bytes)), nil
+}
+
+func (call *builtinFindInSet) eval(env *ExpressionEnv) (eval, error) {
+	str, list, err := call.arg2(env)
+	if err != nil {
+		return nil, err
+	}
+	if str == nil || list == nil {
+		return nil, nil
+	}
+
+	var ca collationAggregation
+	if err := ca.add(evalCollation(str), env.collationEnv); err != nil {
+		return nil, err
+	}
+	if err := ca.add(evalCollation(list), env.collationEnv); err != nil {
+		return nil, err
+	}
+	return findInSet(str, list, stringResultCollation(&ca, sqltypes.VarChar, call.collate))
+}
+
+func (call *builtinFindInSet) compile(c *compiler) (ctype, error) {
+	str, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	list, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck2(str, list)
+
+	var ca collationAggregation
+	if err := ca.add(str.Col, c.env.CollationEnv()); err != nil {
+		return ctype{}, err
+	}
+	if err := ca.add(list.Col, c.env.CollationEnv()); err != nil {
+		return ctype{}, err
+	}
+	tc := stringResultCollation(&ca, sqltypes.VarChar, call.collate)
+
+	c.asm.Fn_FIND_IN_SET(tc)
+	c.asm.jumpDestination(skip)
+	return ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: nullableFlags(str.Flag | list.Flag)}, nil
+}
+
+func quote(str eval, tt sqltypes.Type, tc collations.TypedCollation) (eval, error) {
+	cs := colldata.Lookup(tc.Collation).Charset()
+	if str == nil {
+		var buf []byte
+		for _, r := range "NULL" {
+			buf = appendRune(buf, cs, r)
+		}
+		return newEvalRaw(tt, buf, tc), nil
+	}
+
+	s, err := evalToVarchar(str, tc.Collation, true)
+	if err != nil {
+		return nil, err
+	}
+
+	src := s.bytes
+	buf := make([]byte, 0, len(src)+2)
+	buf = appendRune(buf, cs, '\'')
+	for len(src) > 0 {
+		r, size := cs.DecodeRune(src)
+		if r == charset.RuneError && size < 2 {
+			size = max(size, 1)
+			buf = append(buf, src[:size]...)
+			src = src[size:]
+			continue
+		}
+		src = src[size:]
+
+		switch r {
+		case 0:
+			buf = appendRune(buf, cs, '\\')
+			r = '0'
+		case '\032':
+			buf = appendRune(buf, cs, '\\')
+			r = 'Z'
+		case '\'', '\\':
+			buf = appendRune(buf, cs, '\\')
+		}
+		buf = appendRune(buf, cs, r)
+	}
+	buf = appendRune(buf, cs, '\'')
+	return newEvalRaw(tt, buf, tc), nil
+}
+
+func (call *builtinQuote) eval(env *ExpressionEnv) (eval, error) {
+	str, err := call.arg1(env)
+	if err != nil {
+		return nil, err
+	}
+
+	var ca collationAggregation
+	tt := sqltypes.VarChar
+	if str != nil {
+		tt = concatSQLType(str.SQLType(), tt)
+	}
+	if err := ca.add(evalCollation(str), env.collationEnv); err != nil {
+		return nil, err
+	}
+	return quote(str, tt, stringResultCollation(&ca, tt, call.collate))
+}
+
+func (call *builtinQuote) compile(c *compiler) (ctype, error) {
+	str, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	var ca collationAggregation
+	tt := concatSQLType(str.Type, sqltypes.VarChar)
+	if err := ca.add(str.Col, c.env.CollationEnv()); err != nil {
+		return ctype{}, err
+	}
+	tc := stringResultCollation(&ca, tt, call.collate)
+
+	c.asm.Fn_QUOTE(tt, tc)
+	return ctype{Type: tt, Col: tc}, nil
+}
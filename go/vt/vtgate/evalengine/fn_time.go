@@ -18,6 +18,7 @@ package evalengine
 
 import (
 	"math"
+	"strings"
 	"time"
 
 	"vitess.io/vitess/go/hack"
@@ -191,6 +192,47 @@ type (
 		unit    datetime.IntervalType
 		collate collations.ID
 	}
+
+	builtinStrToDate struct {
+		CallExpr
+		t    sqltypes.Type
+		prec uint8
+	}
+
+	builtinTimestampDiff struct {
+		CallExpr
+		unit datetime.IntervalType
+	}
+
+	builtinDateDiff struct {
+		CallExpr
+	}
+
+	builtinTimeDiff struct {
+		CallExpr
+	}
+
+	builtinAddTime struct {
+		CallExpr
+		sub     bool
+		collate collations.ID
+	}
+
+	builtinGetFormat struct {
+		CallExpr
+		t       sqltypes.Type
+		collate collations.ID
+	}
+
+	builtinTimeFormat struct {
+		CallExpr
+		collate collations.ID
+	}
+
+	builtinExtract struct {
+		CallExpr
+		unit datetime.IntervalType
+	}
 )
 
 var _ IR = (*builtinNow)(nil)
@@ -227,6 +269,14 @@ var _ IR = (*builtinYear)(nil)
 var _ IR = (*builtinYearWeek)(nil)
 var _ IR = (*builtinPeriodAdd)(nil)
 var _ IR = (*builtinPeriodDiff)(nil)
+var _ IR = (*builtinStrToDate)(nil)
+var _ IR = (*builtinTimestampDiff)(nil)
+var _ IR = (*builtinDateDiff)(nil)
+var _ IR = (*builtinTimeDiff)(nil)
+var _ IR = (*builtinAddTime)(nil)
+var _ IR = (*builtinGetFormat)(nil)
+var _ IR = (*builtinTimeFormat)(nil)
+var _ IR = (*builtinExtract)(nil)
 
 func (call *builtinNow) eval(env *ExpressionEnv) (eval, error) {
 	now := env.time(call.utc)
@@ -2149,3 +2199,594 @@ func (call *builtinDateMath) compile(c *compiler) (ctype, error) {
 	}
 	return ret, nil
 }
+
+// strToDateType returns the type of the values returned by STR_TO_DATE. Like in MySQL,
+// it depends on the specifiers used in the format if it is a constant, and it is a
+// DATETIME with full precision otherwise.
+func strToDateType(format IR) (sqltypes.Type, uint8) {
+	lit, ok := format.(*Literal)
+	if !ok || lit.inner == nil {
+		return sqltypes.Datetime, datetime.DefaultPrecision
+	}
+
+	hasDate, hasTime, hasFrac := datetime.StrToDateParts(evalToBinary(lit.inner).string())
+	switch {
+	case hasDate && hasFrac:
+		return sqltypes.Datetime, datetime.DefaultPrecision
+	case hasFrac:
+		return sqltypes.Time, datetime.DefaultPrecision
+	case hasDate && hasTime:
+		return sqltypes.Datetime, 0
+	case hasTime:
+		return sqltypes.Time, 0
+	default:
+		return sqltypes.Date, 0
+	}
+}
+
+func strToDate(str, format eval, t sqltypes.Type, prec uint8, allowZero bool) eval {
+	s := evalToBinary(str)
+	f := evalToBinary(format)
+
+	dt, ok := datetime.StrToDate(s.string(), f.string(), t == sqltypes.Time)
+	if !ok {
+		return nil
+	}
+
+	// Like MySQL's NO_ZERO_IN_DATE, only a zero month or day is rejected: a zero year is a valid year.
+	if t != sqltypes.Time && !allowZero && (dt.Date.Month() == 0 || dt.Date.Day() == 0) {
+		return nil
+	}
+
+	switch t {
+	case sqltypes.Time:
+		return newEvalTime(dt.Time, int(prec))
+	case sqltypes.Date:
+		return newEvalDate(dt.Date, true)
+	default:
+		return newEvalDateTime(dt, int(prec), true)
+	}
+}
+
+func (call *builtinStrToDate) eval(env *ExpressionEnv) (eval, error) {
+	str, format, err := call.arg2(env)
+	if err != nil {
+		return nil, err
+	}
+	if str == nil || format == nil {
+		return nil, nil
+	}
+	return strToDate(str, format, call.t, call.prec, env.sqlmode.AllowZeroDate()), nil
+}
+
+func (call *builtinStrToDate) compile(c *compiler) (ctype, error) {
+	str, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	format, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck2(str, format)
+	c.asm.Fn_STR_TO_DATE(call.t, call.prec, c.sqlmode.AllowZeroDate())
+	c.asm.jumpDestination(skip)
+	return ctype{Type: call.t, Col: collationBinary, Size: int32(call.prec), Flag: flagNullable}, nil
+}
+
+// temporalMicros returns the number of microseconds in the given temporal value:
+// the signed duration for a TIME, and the time elapsed since day zero otherwise.
+func temporalMicros(t *evalTemporal) int64 {
+	if t.t == sqltypes.Time {
+		return t.dt.Time.ToDuration().Microseconds()
+	}
+	return t.dt.ToSeconds()*1e6 + int64(t.dt.Time.Nanosecond()/1e3)
+}
+
+const maxTimeMicros = (datetime.MaxHours*3600 + 59*60 + 59) * 1e6
+
+// timeFromMicros converts a signed number of microseconds into a TIME,
+// clamping the result to the range of valid TIME values.
+func timeFromMicros(us int64) datetime.Time {
+	us = max(min(us, maxTimeMicros), -maxTimeMicros)
+	return datetime.NewTimeFromSeconds(decimal.New(us, -6))
+}
+
+// monthsBetween returns the number of complete months between beg and end,
+// which must not be earlier than beg.
+func monthsBetween(beg, end datetime.DateTime) int64 {
+	yearBeg, monthBeg, dayBeg := beg.Date.Year(), beg.Date.Month(), beg.Date.Day()
+	yearEnd, monthEnd, dayEnd := end.Date.Year(), end.Date.Month(), end.Date.Day()
+
+	secondsBeg := beg.Time.Hour()*3600 + beg.Time.Minute()*60 + beg.Time.Second()
+	secondsEnd := end.Time.Hour()*3600 + end.Time.Minute()*60 + end.Time.Second()
+
+	months := 12*(yearEnd-yearBeg) + monthEnd - monthBeg
+	switch {
+	case dayEnd < dayBeg:
+		months--
+	case dayEnd == dayBeg && (secondsEnd < secondsBeg || (secondsEnd == secondsBeg && end.Time.Nanosecond() < beg.Time.Nanosecond())):
+		months--
+	}
+	return int64(months)
+}
+
+func timestampDiff(l, r eval, unit datetime.IntervalType, now time.Time) eval {
+	t1 := evalToDateTime(l, -1, now, false)
+	t2 := evalToDateTime(r, -1, now, false)
+	if t1 == nil || t2 == nil {
+		return nil
+	}
+
+	beg, end := t1.dt, t2.dt
+	diff := temporalMicros(t2) - temporalMicros(t1)
+	sign := int64(1)
+	if diff < 0 {
+		beg, end = end, beg
+		diff = -diff
+		sign = -1
+	}
+	seconds := diff / 1e6
+
+	var n int64
+	switch unit {
+	case datetime.IntervalYear:
+		n = monthsBetween(beg, end) / 12
+	case datetime.IntervalQuarter:
+		n = monthsBetween(beg, end) / 3
+	case datetime.IntervalMonth:
+		n = monthsBetween(beg, end)
+	case datetime.IntervalWeek:
+		n = seconds / (24 * 3600) / 7
+	case datetime.IntervalDay:
+		n = seconds / (24 * 3600)
+	case datetime.IntervalHour:
+		n = seconds / 3600
+	case datetime.IntervalMinute:
+		n = seconds / 60
+	case datetime.IntervalSecond:
+		n = seconds
+	case datetime.IntervalMicrosecond:
+		n = diff
+	default:
+		return nil
+	}
+	return newEvalInt64(n * sign)
+}
+
+func (call *builtinTimestampDiff) eval(env *ExpressionEnv) (eval, error) {
+	l, r, err := call.arg2(env)
+	if err != nil {
+		return nil, err
+	}
+	if l == nil || r == nil {
+		return nil, nil
+	}
+	return timestampDiff(l, r, call.unit, env.now), nil
+}
+
+func (call *builtinTimestampDiff) compile(c *compiler) (ctype, error) {
+	l, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	r, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck2(l, r)
+	c.asm.Fn_TIMESTAMPDIFF(call.unit)
+	c.asm.jumpDestination(skip)
+	return ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: flagNullable}, nil
+}
+
+func dateDiff(l, r eval, now time.Time) eval {
+	d1 := evalToDate(l, now, false)
+	d2 := evalToDate(r, now, false)
+	if d1 == nil || d2 == nil {
+		return nil
+	}
+
+	days1 := datetime.MysqlDayNumber(d1.dt.Date.Year(), d1.dt.Date.Month(), d1.dt.Date.Day())
+	days2 := datetime.MysqlDayNumber(d2.dt.Date.Year(), d2.dt.Date.Month(), d2.dt.Date.Day())
+	return newEvalInt64(int64(days1 - days2))
+}
+
+func (call *builtinDateDiff) eval(env *ExpressionEnv) (eval, error) {
+	l, r, err := call.arg2(env)
+	if err != nil {
+		return nil, err
+	}
+	if l == nil || r == nil {
+		return nil, nil
+	}
+	return dateDiff(l, r, env.now), nil
+}
+
+func (call *builtinDateDiff) compile(c *compiler) (ctype, error) {
+	l, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	r, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck2(l, r)
+	c.asm.Fn_DATEDIFF()
+	c.asm.jumpDestination(skip)
+	return ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: flagNullable}, nil
+}
+
+func isTemporalWithDate(e eval) bool {
+	t, ok := e.(*evalTemporal)
+	return ok && t.t != sqltypes.Time
+}
+
+func isTemporalTime(e eval) bool {
+	t, ok := e.(*evalTemporal)
+	return ok && t.t == sqltypes.Time
+}
+
+func timeDiff(l, r eval, now time.Time) eval {
+	var t1, t2 *evalTemporal
+	switch {
+	case isTemporalWithDate(l) && isTemporalTime(r), isTemporalWithDate(r) && isTemporalTime(l):
+		return nil
+	case isTemporalWithDate(l) || isTemporalWithDate(r):
+		t1 = evalToDateTime(l, -1, now, true)
+		t2 = evalToDateTime(r, -1, now, true)
+	default:
+		t1 = evalToTimeOrDateTime(l)
+		t2 = evalToTimeOrDateTime(r)
+	}
+	if t1 == nil || t2 == nil || t1.t != t2.t {
+		return nil
+	}
+
+	diff := temporalMicros(t1) - temporalMicros(t2)
+	return newEvalTime(timeFromMicros(diff), int(max(t1.prec, t2.prec)))
+}
+
+func (call *builtinTimeDiff) eval(env *ExpressionEnv) (eval, error) {
+	l, r, err := call.arg2(env)
+	if err != nil {
+		return nil, err
+	}
+	if l == nil || r == nil {
+		return nil, nil
+	}
+	return timeDiff(l, r, env.now), nil
+}
+
+func (call *builtinTimeDiff) compile(c *compiler) (ctype, error) {
+	l, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	r, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck2(l, r)
+	c.asm.Fn_TIMEDIFF()
+	c.asm.jumpDestination(skip)
+	return ctype{Type: sqltypes.Time, Col: collationBinary, Size: max(l.Size, r.Size), Flag: flagNullable}, nil
+}
+
+// addTime adds (or subtracts) the time r to the temporal value l. The result is a TIME if l is a TIME,
+// a DATETIME if l is any other temporal type, and a string otherwise.
+func addTime(l, r eval, sub bool, col collations.TypedCollation, now time.Time) eval {
+	var t1, t2 *evalTemporal
+	var onlyTime bool
+
+	if isTemporalWithDate(l) {
+		t1 = evalToDateTime(l, -1, now, true)
+		t2 = evalToTimeOrDateTime(r)
+	} else {
+		t1 = evalToTimeOrDateTime(l)
+		t2 = evalToTimeOrDateTime(r)
+		onlyTime = t1 != nil && t1.t == sqltypes.Time
+	}
+	if t1 == nil || t2 == nil || t2.t != sqltypes.Time {
+		return nil
+	}
+
+	itv := temporalMicros(t2)
+	if sub {
+		itv = -itv
+	}
+	us := temporalMicros(t1) + itv
+
+	var res datetime.DateTime
+	if onlyTime {
+		res.Time = timeFromMicros(us)
+	} else {
+		if us < 0 {
+			return nil
+		}
+		res.Date = datetime.DateFromDayNumber(int(us / (24 * 3600 * 1e6)))
+		if res.Date.Day() == 0 {
+			return nil
+		}
+		res.Time = timeFromMicros(us % (24 * 3600 * 1e6))
+	}
+
+	if _, ok := l.(*evalTemporal); ok {
+		prec := int(max(t1.prec, t2.prec))
+		if onlyTime {
+			return newEvalTime(res.Time, prec)
+		}
+		return newEvalDateTime(res, prec, true)
+	}
+
+	var b []byte
+	var prec uint8
+	if res.Time.Nanosecond() != 0 {
+		prec = datetime.DefaultPrecision
+	}
+	if onlyTime {
+		b = res.Time.Format(prec)
+	} else {
+		b = res.Format(prec)
+	}
+	return newEvalText(b, col)
+}
+
+func (call *builtinAddTime) eval(env *ExpressionEnv) (eval, error) {
+	l, r, err := call.arg2(env)
+	if err != nil {
+		return nil, err
+	}
+	if l == nil || r == nil {
+		return nil, nil
+	}
+	return addTime(l, r, call.sub, typedCoercionCollation(sqltypes.VarChar, call.collate), env.now), nil
+}
+
+func (call *builtinAddTime) compile(c *compiler) (ctype, error) {
+	l, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	r, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck2(l, r)
+
+	ret := ctype{Col: collationBinary, Size: max(l.Size, r.Size), Flag: flagNullable}
+	switch l.Type {
+	case sqltypes.Time:
+		ret.Type = sqltypes.Time
+	case sqltypes.Date, sqltypes.Datetime, sqltypes.Timestamp:
+		ret.Type = sqltypes.Datetime
+	default:
+		ret.Type = sqltypes.VarChar
+		ret.Col = typedCoercionCollation(sqltypes.VarChar, c.collation)
+		ret.Size = 0
+	}
+
+	c.asm.Fn_ADDTIME(call.sub, ret.Col)
+	c.asm.jumpDestination(skip)
+	return ret, nil
+}
+
+var getFormatDate = map[string]string{
+	"usa":      "%m.%d.%Y",
+	"jis":      "%Y-%m-%d",
+	"iso":      "%Y-%m-%d",
+	"eur":      "%d.%m.%Y",
+	"internal": "%Y%m%d",
+}
+
+var getFormatDatetime = map[string]string{
+	"usa":      "%Y-%m-%d %H.%i.%s",
+	"jis":      "%Y-%m-%d %H:%i:%s",
+	"iso":      "%Y-%m-%d %H:%i:%s",
+	"eur":      "%Y-%m-%d %H.%i.%s",
+	"internal": "%Y%m%d%H%i%s",
+}
+
+var getFormatTime = map[string]string{
+	"usa":      "%h:%i:%s %p",
+	"jis":      "%H:%i:%s",
+	"iso":      "%H:%i:%s",
+	"eur":      "%H.%i.%s",
+	"internal": "%H%i%s",
+}
+
+func getFormat(t sqltypes.Type, locale eval, col collations.TypedCollation) eval {
+	formats := getFormatDatetime
+	switch t {
+	case sqltypes.Date:
+		formats = getFormatDate
+	case sqltypes.Time:
+		formats = getFormatTime
+	}
+
+	f, ok := formats[strings.ToLower(evalToBinary(locale).string())]
+	if !ok {
+		return nil
+	}
+	return newEvalText(hack.StringBytes(f), col)
+}
+
+func (call *builtinGetFormat) eval(env *ExpressionEnv) (eval, error) {
+	locale, err := call.arg1(env)
+	if err != nil {
+		return nil, err
+	}
+	if locale == nil {
+		return nil, nil
+	}
+	return getFormat(call.t, locale, typedCoercionCollation(sqltypes.VarChar, call.collate)), nil
+}
+
+func (call *builtinGetFormat) compile(c *compiler) (ctype, error) {
+	locale, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck1(locale)
+
+	col := typedCoercionCollation(sqltypes.VarChar, c.collation)
+	c.asm.Fn_GET_FORMAT(call.t, col)
+	c.asm.jumpDestination(skip)
+	return ctype{Type: sqltypes.VarChar, Col: col, Flag: flagNullable}, nil
+}
+
+func timeFormat(t, format eval, col collations.TypedCollation) eval {
+	f := evalToBinary(format)
+	if len(f.bytes) == 0 {
+		return nil
+	}
+
+	tt := evalToTime(t, -1)
+	if tt == nil {
+		return nil
+	}
+
+	b, ok := datetime.FormatTime(f.string(), tt.dt.Time)
+	if !ok {
+		return nil
+	}
+	return newEvalText(b, col)
+}
+
+func (call *builtinTimeFormat) eval(env *ExpressionEnv) (eval, error) {
+	t, format, err := call.arg2(env)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil || format == nil {
+		return nil, nil
+	}
+	return timeFormat(t, format, typedCoercionCollation(sqltypes.VarChar, call.collate)), nil
+}
+
+func (call *builtinTimeFormat) compile(c *compiler) (ctype, error) {
+	t, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	format, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck2(t, format)
+
+	col := typedCoercionCollation(sqltypes.VarChar, c.collation)
+	c.asm.Fn_TIME_FORMAT(col)
+	c.asm.jumpDestination(skip)
+	return ctype{Type: sqltypes.VarChar, Col: col, Flag: flagNullable}, nil
+}
+
+func extract(e eval, unit datetime.IntervalType, now time.Time) eval {
+	switch unit {
+	case datetime.IntervalYear, datetime.IntervalYearMonth, datetime.IntervalQuarter,
+		datetime.IntervalMonth, datetime.IntervalWeek, datetime.IntervalDay:
+		t := evalToDateTime(e, -1, now, true)
+		if t == nil {
+			return nil
+		}
+
+		d := t.dt.Date
+		var n int
+		switch unit {
+		case datetime.IntervalYear:
+			n = d.Year()
+		case datetime.IntervalYearMonth:
+			n = d.Year()*100 + d.Month()
+		case datetime.IntervalQuarter:
+			n = (d.Month() + 2) / 3
+		case datetime.IntervalMonth:
+			n = d.Month()
+		case datetime.IntervalWeek:
+			n = d.Week(0)
+		case datetime.IntervalDay:
+			n = d.Day()
+		}
+		return newEvalInt64(int64(n))
+	}
+
+	t := evalToTimeOrDateTime(e)
+	if t == nil {
+		return nil
+	}
+
+	day := int64(t.dt.Date.Day())
+	hour := int64(t.dt.Time.Hour())
+	minute := int64(t.dt.Time.Minute())
+	second := int64(t.dt.Time.Second())
+	micros := int64(t.dt.Time.Nanosecond() / 1e3)
+	hms := hour*10000 + minute*100 + second
+
+	var n int64
+	switch unit {
+	case datetime.IntervalDayHour:
+		n = day*100 + hour
+	case datetime.IntervalDayMinute:
+		n = day*10000 + hour*100 + minute
+	case datetime.IntervalDaySecond:
+		n = day*1000000 + hms
+	case datetime.IntervalHour:
+		n = hour
+	case datetime.IntervalHourMinute:
+		n = hour*100 + minute
+	case datetime.IntervalHourSecond:
+		n = hms
+	case datetime.IntervalMinute:
+		n = minute
+	case datetime.IntervalMinuteSecond:
+		n = minute*100 + second
+	case datetime.IntervalSecond:
+		n = second
+	case datetime.IntervalMicrosecond:
+		n = micros
+	case datetime.IntervalDayMicrosecond:
+		n = (day*1000000+hms)*1000000 + micros
+	case datetime.IntervalHourMicrosecond:
+		n = hms*1000000 + micros
+	case datetime.IntervalMinuteMicrosecond:
+		n = (minute*100+second)*1000000 + micros
+	case datetime.IntervalSecondMicrosecond:
+		n = second*1000000 + micros
+	default:
+		return nil
+	}
+	if t.dt.Time.Neg() {
+		n = -n
+	}
+	return newEvalInt64(n)
+}
+
+func (call *builtinExtract) eval(env *ExpressionEnv) (eval, error) {
+	arg, err := call.arg1(env)
+	if err != nil {
+		return nil, err
+	}
+	if arg == nil {
+		return nil, nil
+	}
+	return extract(arg, call.unit, env.now), nil
+}
+
+func (call *builtinExtract) compile(c *compiler) (ctype, error) {
+	arg, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck1(arg)
+	c.asm.Fn_EXTRACT(call.unit)
+	c.asm.jumpDestination(skip)
+	return ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: flagNullable}, nil
+}
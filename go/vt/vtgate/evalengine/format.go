@@ -278,6 +278,48 @@ func (c *builtinWeightString) format(buf *sqlparser.TrackedBuffer) {
 	buf.WriteByte(')')
 }
 
+func (c *builtinMemberOf) format(buf *sqlparser.TrackedBuffer) {
+	formatExpr(buf, c, c.Arguments[0], true)
+	buf.WriteLiteral(" member of (")
+	formatExpr(buf, c, c.Arguments[1], true)
+	buf.WriteByte(')')
+}
+
+func (c *builtinJSONValue) format(buf *sqlparser.TrackedBuffer) {
+	buf.WriteLiteral("json_value(")
+	formatExpr(buf, c, c.Arguments[0], true)
+	buf.WriteString(", ")
+	formatExpr(buf, c, c.Arguments[1], true)
+
+	defaults := c.Arguments[2:]
+	formatResponse := func(t sqlparser.JtOnResponseType, on string) {
+		switch t {
+		case sqlparser.ErrorJSONType:
+			buf.WriteLiteral(" error")
+		case sqlparser.DefaultJSONType:
+			buf.WriteLiteral(" default ")
+			formatExpr(buf, c, defaults[0], true)
+			defaults = defaults[1:]
+		default:
+			return
+		}
+		buf.WriteLiteral(on)
+	}
+	if conv := c.returning; conv != nil {
+		switch {
+		case conv.Length != nil && conv.Scale != nil:
+			_, _ = fmt.Fprintf(buf, " returning %s(%d,%d)", conv.Type, *conv.Length, *conv.Scale)
+		case conv.Length != nil:
+			_, _ = fmt.Fprintf(buf, " returning %s(%d)", conv.Type, *conv.Length)
+		default:
+			_, _ = fmt.Fprintf(buf, " returning %s", conv.Type)
+		}
+	}
+	formatResponse(c.onEmpty, " on empty")
+	formatResponse(c.onError, " on error")
+	buf.WriteByte(')')
+}
+
 func (n *NegateExpr) format(buf *sqlparser.TrackedBuffer) {
 	buf.WriteByte('-')
 	formatExpr(buf, n, n.Inner, true)
@@ -221,6 +221,10 @@ func (vc *vcursor) SQLMode() string {
 	return config.DefaultSQLMode
 }
 
+func (vc *vcursor) BlockEncryptionMode() string {
+	return config.DefaultBlockEncryptionMode
+}
+
 func (vc *vcursor) Environment() *vtenv.Environment {
 	return vc.env
 }
@@ -33,6 +33,14 @@ var Cases = []TestCase{
 	{Run: JSONPathOperations},
 	{Run: JSONArray},
 	{Run: JSONObject},
+	{Run: JSONModify},
+	{Run: JSONMerge},
+	{Run: JSONSearch},
+	{Run: JSONOverlaps},
+	{Run: JSONValue},
+	{Run: JSONSchemaValid},
+	{Run: JSONMemberOf},
+	{Run: JSONPretty},
 	{Run: CharsetConversionOperators},
 	{Run: CaseExprWithPredicate},
 	{Run: CaseExprWithValue},
@@ -88,6 +96,13 @@ var Cases = []TestCase{
 	{Run: FnReplace},
 	{Run: FnConcat},
 	{Run: FnConcatWs},
+	{Run: FnSubstringIndex},
+	{Run: FnFormat},
+	{Run: FnSoundex},
+	{Run: FnMakeSet},
+	{Run: FnExportSet},
+	{Run: FnFindInSet},
+	{Run: FnQuote},
 	{Run: FnChar},
 	{Run: FnHex},
 	{Run: FnUnhex},
@@ -126,6 +141,9 @@ var Cases = []TestCase{
 	{Run: FnSHA1},
 	{Run: FnSHA2},
 	{Run: FnRandomBytes},
+	{Run: FnAESEncrypt},
+	{Run: FnCompress},
+	{Run: FnStatementDigest},
 	{Run: FnDateFormat},
 	{Run: FnConvertTz},
 	{Run: FnDate},
@@ -157,6 +175,15 @@ var Cases = []TestCase{
 	{Run: FnYearWeek},
 	{Run: FnPeriodAdd},
 	{Run: FnPeriodDiff},
+	{Run: FnStrToDate},
+	{Run: FnTimestampDiff},
+	{Run: FnDateDiff},
+	{Run: FnTimeDiff},
+	{Run: FnAddTime},
+	{Run: FnSubTime},
+	{Run: FnGetFormat},
+	{Run: FnTimeFormat},
+	{Run: FnExtract},
 	{Run: FnInetAton},
 	{Run: FnInetNtoa},
 	{Run: FnInet6Aton},
@@ -214,6 +241,152 @@ func JSONObject(yield Query) {
 	yield("JSON_OBJECT()", nil)
 }
 
+func JSONModify(yield Query) {
+	for _, obj := range inputJSONObjects {
+		for _, path := range inputJSONPaths {
+			for _, fn := range []string{"JSON_SET", "JSON_INSERT", "JSON_REPLACE", "JSON_ARRAY_APPEND", "JSON_ARRAY_INSERT"} {
+				yield(fmt.Sprintf("%s('%s', '%s', 1)", fn, obj, path), nil)
+				yield(fmt.Sprintf("%s('%s', '%s', 'foo', '$[1]', JSON_ARRAY(1, 2))", fn, obj, path), nil)
+			}
+			yield(fmt.Sprintf("JSON_REMOVE('%s', '%s')", obj, path), nil)
+			yield(fmt.Sprintf("JSON_REMOVE('%s', '%s', '$[0]')", obj, path), nil)
+		}
+		yield(fmt.Sprintf("JSON_SET('%s', NULL, 1)", obj), nil)
+		yield(fmt.Sprintf("JSON_SET('%s', '$.new', NULL)", obj), nil)
+		yield(fmt.Sprintf("JSON_ARRAY_APPEND('%s', '$', NULL)", obj), nil)
+		yield(fmt.Sprintf("JSON_ARRAY_INSERT('%s', '$[100]', true)", obj), nil)
+		yield(fmt.Sprintf("JSON_REMOVE('%s', NULL)", obj), nil)
+	}
+	for _, val := range inputJSONPrimitives {
+		yield(fmt.Sprintf("JSON_SET('[1]', '$[5]', %s)", val), nil)
+		yield(fmt.Sprintf("JSON_INSERT('{\"a\": 1}', '$.b', %s)", val), nil)
+		yield(fmt.Sprintf("JSON_REPLACE('\"x\"', '$', %s)", val), nil)
+	}
+	yield("JSON_SET(NULL, '$.a', 1)", nil)
+}
+
+func JSONMerge(yield Query) {
+	docs := []string{
+		`'{"a": 1, "b": 2}'`, `'{"a": 3, "c": {"d": 4}}'`, `'{"a": null, "c": {"e": 5}}'`,
+		`'[1, 2]'`, `'[true, false]'`, `'1'`, `'"foo"'`, `'null'`, `NULL`,
+	}
+	for _, fn := range []string{"JSON_MERGE_PATCH", "JSON_MERGE_PRESERVE", "JSON_MERGE"} {
+		for _, a := range docs {
+			for _, b := range docs {
+				yield(fmt.Sprintf("%s(%s, %s)", fn, a, b), nil)
+				for _, c := range docs {
+					yield(fmt.Sprintf("%s(%s, %s, %s)", fn, a, b, c), nil)
+				}
+			}
+		}
+	}
+}
+
+func JSONSearch(yield Query) {
+	const doc = `'["abc", [{"k": "10"}, "def"], {"x": "abc"}, {"y": "bcd"}, {"a b": "ABC"}]'`
+	patterns := []string{`'abc'`, `'ABC'`, `'%b%'`, `'10'`, `'1_'`, `'%'`, `'xyz'`, `NULL`, `10`, `_latin1 'abc'`, `_binary 'ABC'`}
+
+	for _, oneOrAll := range []string{`'one'`, `'all'`, `'none'`, `NULL`} {
+		for _, pattern := range patterns {
+			yield(fmt.Sprintf("JSON_SEARCH(%s, %s, %s)", doc, oneOrAll, pattern), nil)
+			yield(fmt.Sprintf("JSON_SEARCH(%s, %s, %s, NULL, '$[2]')", doc, oneOrAll, pattern), nil)
+			yield(fmt.Sprintf("JSON_SEARCH(%s, %s, %s, '', '$[1]', '$**.x')", doc, oneOrAll, pattern), nil)
+		}
+	}
+	yield(`JSON_SEARCH('["a%c", "abc"]', 'all', 'a|%c', '|')`, nil)
+	yield(`JSON_SEARCH('["a%c", "abc"]', 'all', 'a%c', 'ab')`, nil)
+	yield(`JSON_SEARCH('["a%c", "abc"]', 'all', 'a%c', NULL, NULL)`, nil)
+	yield(`JSON_SEARCH(NULL, 'all', 'abc')`, nil)
+}
+
+func JSONOverlaps(yield Query) {
+	docs := []string{
+		`'[1, 2, 3]'`, `'[3, 4]'`, `'[[1, 2]]'`, `'[1, 2]'`, `'{"a": 1, "b": 2}'`, `'{"a": 1, "c": 3}'`,
+		`'{"a": 2}'`, `'1'`, `'1.0'`, `'"1"'`, `'true'`, `'null'`, `NULL`,
+	}
+	for _, a := range docs {
+		for _, b := range docs {
+			yield(fmt.Sprintf("JSON_OVERLAPS(%s, %s)", a, b), nil)
+		}
+	}
+}
+
+func JSONValue(yield Query) {
+	const doc = `'{"a": 1, "b": "2.5", "c": [1, 2], "d": null, "e": true, "f": {"g": "2024-01-02"}}'`
+	paths := []string{`'$.a'`, `'$.b'`, `'$.c'`, `'$.c[1]'`, `'$.d'`, `'$.e'`, `'$.f.g'`, `'$.x'`, `'$[*]'`, `NULL`}
+	returning := []string{"", " RETURNING SIGNED", " RETURNING DECIMAL(4, 2)", " RETURNING CHAR(10)", " RETURNING DATE", " RETURNING DOUBLE"}
+	responses := []string{"", " NULL ON EMPTY", " ERROR ON EMPTY", " DEFAULT 42 ON EMPTY", " NULL ON ERROR", " ERROR ON ERROR", " DEFAULT 'oops' ON ERROR", " DEFAULT 1 ON EMPTY DEFAULT 2 ON ERROR"}
+
+	for _, path := range paths {
+		for _, ret := range returning {
+			yield(fmt.Sprintf("JSON_VALUE(%s, %s%s)", doc, path, ret), nil)
+		}
+		for _, resp := range responses {
+			yield(fmt.Sprintf("JSON_VALUE(%s, %s%s)", doc, path, resp), nil)
+		}
+		for _, ret := range returning {
+			for _, resp := range []string{" NULL ON ERROR", " ERROR ON ERROR", " DEFAULT 7 ON ERROR"} {
+				yield(fmt.Sprintf("JSON_VALUE(%s, %s%s%s)", doc, path, ret, resp), nil)
+			}
+		}
+	}
+
+	values := []string{`'{"a": "x"}'`, `'{"a": "123.456"}'`, `'{"a": "-1"}'`, `'{"a": "99999999999999999999"}'`, `'{"a": "abcdefghijklmnop"}'`, `'{"a": "2024-13-45"}'`}
+	for _, value := range values {
+		for _, ret := range returning {
+			for _, resp := range []string{"", " ERROR ON ERROR", " DEFAULT 7 ON ERROR"} {
+				yield(fmt.Sprintf("JSON_VALUE(%s, '$.a'%s%s)", value, ret, resp), nil)
+			}
+		}
+	}
+	yield("JSON_VALUE(NULL, '$.a')", nil)
+}
+
+func JSONSchemaValid(yield Query) {
+	schemas := []string{
+		`'{"type": "object", "properties": {"latitude": {"type": "number", "minimum": -90, "maximum": 90}, "longitude": {"type": "number", "minimum": -180, "maximum": 180}}, "required": ["latitude", "longitude"]}'`,
+		`'{"type": "array", "items": {"type": "integer"}, "minItems": 2, "uniqueItems": true}'`,
+		`'{"type": "string", "pattern": "^a", "maxLength": 3}'`,
+		`'{"enum": [1, "a", null]}'`,
+		`'{"anyOf": [{"type": "string"}, {"type": "boolean"}]}'`,
+		`'{}'`,
+		`'[]'`,
+		`NULL`,
+	}
+	docs := []string{
+		`'{"latitude": 63.444697, "longitude": 10.445118}'`, `'{"latitude": 63.444697}'`, `'{"latitude": 100, "longitude": 0}'`,
+		`'[1, 2]'`, `'[1, 1]'`, `'[1.5, 2]'`, `'"abc"'`, `'"abcd"'`, `'"bcd"'`, `'1'`, `'null'`, `'true'`, `NULL`,
+	}
+	for _, schema := range schemas {
+		for _, doc := range docs {
+			yield(fmt.Sprintf("JSON_SCHEMA_VALID(%s, %s)", schema, doc), nil)
+		}
+	}
+}
+
+func JSONMemberOf(yield Query) {
+	arrays := []string{
+		`'[23, "abc", 17, "ab", 10, [1, 2], {"a": 1}, null, true]'`, `'17'`, `'"abc"'`, `'{"a": 1}'`, `NULL`,
+	}
+	values := []string{
+		`17`, `'17'`, `17.0`, `'abc'`, `'ABC'`, `CAST('[1, 2]' AS JSON)`, `JSON_OBJECT('a', 1)`, `true`, `NULL`,
+	}
+	for _, arr := range arrays {
+		for _, value := range values {
+			yield(fmt.Sprintf("%s MEMBER OF (%s)", value, arr), nil)
+		}
+	}
+}
+
+func JSONPretty(yield Query) {
+	for _, obj := range inputJSONObjects {
+		yield(fmt.Sprintf("JSON_PRETTY('%s')", obj), nil)
+	}
+	for _, doc := range []string{`'[]'`, `'{}'`, `'"abc"'`, `'[1, [2, {}], {"a": []}]'`, `NULL`, `JSON_OBJECT('a', 1.5e0)`} {
+		yield(fmt.Sprintf("JSON_PRETTY(%s)", doc), nil)
+	}
+}
+
 func CharsetConversionOperators(yield Query) {
 	var introducers = []string{
 		"", "_latin1", "_utf8mb4", "_utf8", "_binary",
@@ -752,6 +925,50 @@ func FnRandomBytes(yield Query) {
 	}
 }
 
+func FnAESEncrypt(yield Query) {
+	keys := []string{"'key'", "'a very long key that is longer than the block size'", "''", "1234", "NULL"}
+	for _, key := range keys {
+		for _, str := range inputConversions {
+			yield(fmt.Sprintf("AES_ENCRYPT(%s, %s)", str, key), nil)
+			yield(fmt.Sprintf("AES_DECRYPT(AES_ENCRYPT(%s, %s), %s)", str, key, key), nil)
+			yield(fmt.Sprintf("AES_ENCRYPT(%s, %s, '1234567890abcdef')", str, key), nil)
+		}
+	}
+
+	for _, str := range inputConversions {
+		yield(fmt.Sprintf("AES_DECRYPT(%s, 'key')", str), nil)
+	}
+}
+
+func FnCompress(yield Query) {
+	for _, str := range inputConversions {
+		yield(fmt.Sprintf("UNCOMPRESS(COMPRESS(%s))", str), nil)
+		yield(fmt.Sprintf("UNCOMPRESSED_LENGTH(COMPRESS(%s))", str), nil)
+		yield(fmt.Sprintf("UNCOMPRESS(%s)", str), nil)
+		yield(fmt.Sprintf("UNCOMPRESSED_LENGTH(%s)", str), nil)
+	}
+
+	yield("LENGTH(COMPRESS(REPEAT('a', 1000)))", nil)
+	yield("UNCOMPRESS(COMPRESS(REPEAT('vitess ', 100)))", nil)
+	yield("UNCOMPRESSED_LENGTH(REPEAT('a', 30))", nil)
+}
+
+func FnStatementDigest(yield Query) {
+	statements := []string{
+		"'SELECT * FROM t1 WHERE a = 2'",
+		"'select a, b from t1 where a in (1, 2, 3) and b = ''foo'''",
+		"'insert into t1 (a, b) values (1, 2)'",
+		"'update t1 set a = a + 1 where b = 2'",
+		"'delete from t1 where a > 10 limit 1'",
+		"NULL",
+	}
+
+	for _, stmt := range statements {
+		yield(fmt.Sprintf("STATEMENT_DIGEST(%s)", stmt), nil)
+		yield(fmt.Sprintf("STATEMENT_DIGEST_TEXT(%s)", stmt), nil)
+	}
+}
+
 func CaseExprWithValue(yield Query) {
 	var elements []string
 	elements = append(elements, inputBitwise...)
@@ -1745,6 +1962,200 @@ func FnConcatWs(yield Query) {
 	}
 }
 
+func FnSubstringIndex(yield Query) {
+	mysqlDocSamples := []string{
+		`SUBSTRING_INDEX('www.mysql.com', '.', 2)`,
+		`SUBSTRING_INDEX('www.mysql.com', '.', -2)`,
+		`SUBSTRING_INDEX('www.mysql.com', '.', 0)`,
+		`SUBSTRING_INDEX('www.mysql.com', '.', 10)`,
+		`SUBSTRING_INDEX('www.mysql.com', '.', -10)`,
+		`SUBSTRING_INDEX('www.mysql.com', '', 1)`,
+		`SUBSTRING_INDEX('www.MySQL.com', 'mysql', 1)`,
+		`SUBSTRING_INDEX('aaaa', 'aa', -1)`,
+		`SUBSTRING_INDEX('aaaaa', 'aa', 2)`,
+		`SUBSTRING_INDEX('中文测试中文', '文', -1)`,
+		`SUBSTRING_INDEX('中文测试中文', '文', 2)`,
+		`SUBSTRING_INDEX(_latin1 'aÿbÿc', _latin1 0xFF, -2)`,
+		`SUBSTRING_INDEX('1.2.3', 0x2e, 18446744073709551615)`,
+		`SUBSTRING_INDEX(123.456, 4, 1)`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+
+	counts := []string{"0", "1", "-1", "2", "-2", "NULL", "'1'", "1.5", "18446744073709551615", "-9223372036854775808"}
+	for _, str := range locateStrings {
+		for _, delim := range locateStrings {
+			for _, count := range counts {
+				yield(fmt.Sprintf("SUBSTRING_INDEX(%s, %s, %s)", str, delim, count), nil)
+			}
+		}
+	}
+}
+
+func FnFormat(yield Query) {
+	mysqlDocSamples := []string{
+		`FORMAT(12332.123456, 4)`,
+		`FORMAT(12332.1,4)`,
+		`FORMAT(12332.2,0)`,
+		`FORMAT(12332.2,2,'de_DE')`,
+		`FORMAT(-1234567.891, 2, 'en_IN')`,
+		`FORMAT(1234567.891, 2, 'fr_FR')`,
+		`FORMAT(1234567.891, 2, 'DE_ch')`,
+		`FORMAT(1234567.891, 2, 'xx_XX')`,
+		`FORMAT(1234567.891, 2, NULL)`,
+		`FORMAT(1234567.891, 50)`,
+		`FORMAT(1e300, 2)`,
+		`FORMAT('1234.5678', 2)`,
+		`FORMAT(18446744073709551615, 2)`,
+		`FORMAT(-0.001, 2)`,
+		`FORMAT(DATE'2024-01-01', 1)`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+
+	decimals := []string{"0", "1", "2", "-1", "31", "NULL", "'3'", "18446744073709551615"}
+	for _, num := range inputConversions {
+		for _, d := range decimals {
+			yield(fmt.Sprintf("FORMAT(%s, %s)", num, d), nil)
+			yield(fmt.Sprintf("FORMAT(%s, %s, 'de_DE')", num, d), nil)
+		}
+	}
+}
+
+func FnSoundex(yield Query) {
+	mysqlDocSamples := []string{
+		`SOUNDEX('Hello')`,
+		`SOUNDEX('Quadratically')`,
+		`SOUNDEX('Tymczak')`,
+		`SOUNDEX('  !!Lee')`,
+		`SOUNDEX('Ñandú')`,
+		`SOUNDEX(_latin1 'Ñandú')`,
+		`SOUNDEX(_binary 'Ñandú')`,
+		`SOUNDEX('!!!')`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+
+	for _, str := range inputStrings {
+		yield(fmt.Sprintf("SOUNDEX(%s)", str), nil)
+	}
+	for _, str := range inputConversions {
+		yield(fmt.Sprintf("SOUNDEX(%s)", str), nil)
+	}
+}
+
+func FnMakeSet(yield Query) {
+	mysqlDocSamples := []string{
+		`MAKE_SET(1,'a','b','c')`,
+		`MAKE_SET(1 | 4,'hello','nice','world')`,
+		`MAKE_SET(1 | 4,'hello','nice',NULL,'world')`,
+		`MAKE_SET(0,'a','b','c')`,
+		`MAKE_SET(-1,'a','b','c')`,
+		`MAKE_SET(NULL,'a','b','c')`,
+		`MAKE_SET(3, _latin1 'a', 'ü')`,
+		`MAKE_SET(3, _binary 'a', 'ü')`,
+		`MAKE_SET(3, 1, 2.5)`,
+		`MAKE_SET(3, NULL, NULL)`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+
+	for _, bits := range inputBitwise {
+		for _, str := range inputStrings {
+			yield(fmt.Sprintf("MAKE_SET(%s, %s, 'b', %s)", bits, str, str), nil)
+		}
+	}
+}
+
+func FnExportSet(yield Query) {
+	mysqlDocSamples := []string{
+		`EXPORT_SET(5,'Y','N',',',4)`,
+		`EXPORT_SET(6,'1','0',',',10)`,
+		`EXPORT_SET(6,'1','0')`,
+		`EXPORT_SET(6,'1','0','')`,
+		`EXPORT_SET(6,'1','0',',',0)`,
+		`EXPORT_SET(6,'1','0',',',-1)`,
+		`EXPORT_SET(6,'1','0',',',100)`,
+		`EXPORT_SET(-1,'1','0',',',3)`,
+		`EXPORT_SET(NULL,'1','0',',',3)`,
+		`EXPORT_SET(6,NULL,'0',',',3)`,
+		`EXPORT_SET(6,'1','0',NULL,3)`,
+		`EXPORT_SET(6,'1','0',',',NULL)`,
+		`EXPORT_SET(6,_latin1 'ÿ','0',_binary ',',3)`,
+		`EXPORT_SET(6,1,0,2,3)`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+
+	for _, bits := range inputBitwise {
+		for _, str := range inputStrings {
+			yield(fmt.Sprintf("EXPORT_SET(%s, %s, 'off', '|', 8)", bits, str), nil)
+		}
+	}
+}
+
+func FnFindInSet(yield Query) {
+	mysqlDocSamples := []string{
+		`FIND_IN_SET('b','a,b,c,d')`,
+		`FIND_IN_SET('B','a,b,c,d')`,
+		`FIND_IN_SET('B' COLLATE utf8mb4_0900_as_cs,'a,b,c,d')`,
+		`FIND_IN_SET('e','a,b,c,d')`,
+		`FIND_IN_SET('','a,b,')`,
+		`FIND_IN_SET('',',a')`,
+		`FIND_IN_SET('','')`,
+		`FIND_IN_SET('a,b','a,b,c')`,
+		`FIND_IN_SET('d','a,b,c,d')`,
+		`FIND_IN_SET('ü','a,ü')`,
+		`FIND_IN_SET(_latin1 'ÿ','a,ÿ')`,
+		`FIND_IN_SET(2, '1,2,3')`,
+		`FIND_IN_SET(NULL, '1,2,3')`,
+		`FIND_IN_SET('1', NULL)`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+
+	for _, str := range inputStrings {
+		for _, list := range inputStrings {
+			yield(fmt.Sprintf("FIND_IN_SET(%s, %s)", str, list), nil)
+			yield(fmt.Sprintf("FIND_IN_SET(%s, CONCAT('x,', %s, ',y'))", str, list), nil)
+		}
+	}
+}
+
+func FnQuote(yield Query) {
+	mysqlDocSamples := []string{
+		`QUOTE('Don\'t!')`,
+		`QUOTE(NULL)`,
+		`QUOTE('a\\b')`,
+		`QUOTE(CONCAT('a', CHAR(0), 'b', CHAR(26)))`,
+		`QUOTE(_latin1 'Don\'t ÿ')`,
+		`QUOTE(_binary 'Don\'t')`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+
+	for _, str := range inputStrings {
+		yield(fmt.Sprintf("QUOTE(%s)", str), nil)
+	}
+	for _, str := range inputConversions {
+		yield(fmt.Sprintf("QUOTE(%s)", str), nil)
+	}
+}
+
 func FnChar(yield Query) {
 	mysqlDocSamples := []string{
 		`CHAR(77,121,83,81,'76')`,
@@ -2267,6 +2678,206 @@ func FnPeriodDiff(yield Query) {
 	}
 }
 
+var inputTemporalPairs = []string{
+	`DATE'2018-05-01'`,
+	`DATE'2024-02-29'`,
+	`TIMESTAMP'2020-12-31 23:59:59'`,
+	`TIMESTAMP'2025-01-01 00:00:00.000001'`,
+	`TIME'10:04:58'`,
+	`TIME'-31:34:58.5'`,
+	`'2018-05-01'`,
+	`'2020-12-31 23:59:59'`,
+	`'2025-01-01 00:00:00.123'`,
+	`'10:04:58'`,
+	`'838:59:59'`,
+	`20250101`,
+	`103458`,
+	`103458.123`,
+	`'pokemon trainers'`,
+	`NULL`,
+}
+
+func FnStrToDate(yield Query) {
+	formats := []string{
+		`'%Y-%m-%d'`, `'%Y-%m-%d %H:%i:%s'`, `'%Y-%m-%d %T.%f'`, `'%H:%i:%s'`, `'%T.%f'`,
+		`'%d,%m,%Y'`, `'%M %d,%Y'`, `'%b %D %Y'`, `'%W %M %Y'`, `'%r'`, `'%h:%i %p'`,
+		`'%Y%j'`, `'%X%V %W'`, `'%x%v %a'`, `'%Y %U %w'`, `'%y%m%d'`, `'%Y%.%m%.%d'`, `'%#%Y'`,
+		`'%Q'`, `''`, `NULL`,
+	}
+	inputs := []string{
+		`'2013-05-01'`, `'2013-05-01 10:11:12'`, `'2013-05-01 10:11:12.123'`, `'10:11:12'`, `'10:11:12.5'`,
+		`'01,5,2013'`, `'May 1, 2013'`, `'Jun 3rd 2020'`, `'Friday June 2020'`, `'10:11:12 PM'`, `'12:30 am'`,
+		`'2020060'`, `'200442 Monday'`, `'200442 Mon'`, `'2004 42 1'`, `'690102'`, `'2020/06/03'`, `'abc2020'`,
+		`'04/31/2004'`, `'0000-00-00'`, `'0000-01-05'`, `'2013-02-30'`, `'2012-02-29'`, `'2020-13-01'`, `'  2020-01-01 '`, `''`, `20130501`, `NULL`,
+	}
+
+	for _, f := range formats {
+		for _, i := range inputs {
+			yield(fmt.Sprintf("STR_TO_DATE(%s, %s)", i, f), nil)
+		}
+	}
+
+	mysqlDocSamples := []string{
+		`STR_TO_DATE('01,5,2013','%d,%m,%Y')`,
+		`STR_TO_DATE('May 1, 2013','%M %d,%Y')`,
+		`STR_TO_DATE('a09:30:17','a%h:%i:%s')`,
+		`STR_TO_DATE('a09:30:17','%h:%i:%s')`,
+		`STR_TO_DATE('09:30:17a','%h:%i:%s')`,
+		`STR_TO_DATE('abc','abc')`,
+		`STR_TO_DATE('9','%m')`,
+		`STR_TO_DATE('9','%s')`,
+		`STR_TO_DATE('00/00/0000', '%m/%d/%Y')`,
+		`STR_TO_DATE('04/31/2004', '%m/%d/%Y')`,
+		`STR_TO_DATE('0000-01-05','%Y-%m-%d')`,
+		`STR_TO_DATE('2013-02-30','%Y-%m-%d')`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+}
+
+func FnTimestampDiff(yield Query) {
+	units := []string{"MICROSECOND", "SECOND", "MINUTE", "HOUR", "DAY", "WEEK", "MONTH", "QUARTER", "YEAR"}
+	for _, u := range units {
+		for _, d1 := range inputTemporalPairs {
+			for _, d2 := range inputTemporalPairs {
+				yield(fmt.Sprintf("TIMESTAMPDIFF(%s, %s, %s)", u, d1, d2), nil)
+			}
+		}
+	}
+
+	mysqlDocSamples := []string{
+		`TIMESTAMPDIFF(MONTH,'2003-02-01','2003-05-01')`,
+		`TIMESTAMPDIFF(YEAR,'2002-05-01','2001-01-01')`,
+		`TIMESTAMPDIFF(MINUTE,'2003-02-01','2003-05-01 12:05:55')`,
+		`TIMESTAMPDIFF(MONTH,'2024-01-31','2024-02-29')`,
+		`TIMESTAMPDIFF(MONTH,'2024-01-31 10:00:00','2024-02-29 09:59:59')`,
+		`TIMESTAMPDIFF(MONTH,'2024-02-29 10:00:00','2024-01-29 10:00:01')`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+}
+
+func FnDateDiff(yield Query) {
+	for _, d1 := range inputTemporalPairs {
+		for _, d2 := range inputTemporalPairs {
+			yield(fmt.Sprintf("DATEDIFF(%s, %s)", d1, d2), nil)
+		}
+	}
+
+	mysqlDocSamples := []string{
+		`DATEDIFF('2007-12-31 23:59:59','2007-12-30')`,
+		`DATEDIFF('2010-11-30 23:59:59','2010-12-31')`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+}
+
+func FnTimeDiff(yield Query) {
+	for _, d1 := range inputTemporalPairs {
+		for _, d2 := range inputTemporalPairs {
+			yield(fmt.Sprintf("TIMEDIFF(%s, %s)", d1, d2), nil)
+		}
+	}
+
+	mysqlDocSamples := []string{
+		`TIMEDIFF('2000-01-01 00:00:00', '2000-01-01 00:00:00.000001')`,
+		`TIMEDIFF('2008-12-31 23:59:59.000001', '2008-12-30 01:01:01.000002')`,
+		`TIMEDIFF('-838:00:00', '10:00:00')`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+}
+
+var inputTimeIntervals = []string{
+	`TIME'01:00:00'`, `TIME'-01:00:00.5'`, `TIME'100:00:00'`, `'1 1:1:1.000002'`, `'10:04:58'`, `'-10:04:58'`,
+	`'2020-01-01 10:00:00'`, `103458`, `1.5`, `'foobar'`, `NULL`,
+}
+
+func FnAddTime(yield Query) {
+	for _, d := range inputTemporalPairs {
+		for _, t := range inputTimeIntervals {
+			yield(fmt.Sprintf("ADDTIME(%s, %s)", d, t), nil)
+		}
+	}
+
+	mysqlDocSamples := []string{
+		`ADDTIME('2007-12-31 23:59:59.999999', '1 1:1:1.000002')`,
+		`ADDTIME('01:00:00.999999', '02:00:00.999998')`,
+		`ADDTIME('9999-12-31 23:59:59', '00:00:01')`,
+		`ADDTIME(TIME'838:00:00', '10:00:00')`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+}
+
+func FnSubTime(yield Query) {
+	for _, d := range inputTemporalPairs {
+		for _, t := range inputTimeIntervals {
+			yield(fmt.Sprintf("SUBTIME(%s, %s)", d, t), nil)
+		}
+	}
+
+	mysqlDocSamples := []string{
+		`SUBTIME('2007-12-31 23:59:59.999999','1 1:1:1.000002')`,
+		`SUBTIME('01:00:00.999999', '02:00:00.999998')`,
+		`SUBTIME('0001-01-01 00:00:00', '00:00:01')`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+}
+
+func FnGetFormat(yield Query) {
+	types := []string{"DATE", "DATETIME", "TIME", "TIMESTAMP"}
+	locales := []string{`'USA'`, `'JIS'`, `'ISO'`, `'EUR'`, `'INTERNAL'`, `'usa'`, `'Eur'`, `'foo'`, `''`, `NULL`}
+
+	for _, t := range types {
+		for _, l := range locales {
+			yield(fmt.Sprintf("GET_FORMAT(%s, %s)", t, l), nil)
+		}
+	}
+}
+
+func FnTimeFormat(yield Query) {
+	var buf strings.Builder
+	for _, f := range dateFormats {
+		buf.WriteByte('%')
+		buf.WriteByte(f.c)
+		buf.WriteByte(' ')
+	}
+	format := buf.String()
+
+	formats := []string{
+		"%H:%i:%s", "%k %h %I %l %p %r %T %f", "%Y-%m-%d %y %c %e", "%M", "%W", "%j %%", "%H%", "",
+	}
+
+	for _, d := range inputConversions {
+		yield(fmt.Sprintf("TIME_FORMAT(%s, %q)", d, format), nil)
+		for _, f := range formats {
+			yield(fmt.Sprintf("TIME_FORMAT(%s, %q)", d, f), nil)
+		}
+	}
+}
+
+func FnExtract(yield Query) {
+	for _, i := range inputIntervals {
+		for _, d := range inputConversions {
+			yield(fmt.Sprintf("EXTRACT(%s FROM %s)", i, d), nil)
+		}
+	}
+}
+
 func FnInetAton(yield Query) {
 	for _, d := range ipInputs {
 		yield(fmt.Sprintf("INET_ATON(%s)", d), nil)
@@ -21,6 +21,8 @@ import (
 	"strings"
 
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/json"
+	"vitess.io/vitess/go/sqltypes"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -45,6 +47,10 @@ func (ast *astCompiler) translateFuncArgs(fnargs []sqlparser.Expr) ([]IR, error)
 }
 
 func (ast *astCompiler) translateFuncExpr(fn *sqlparser.FuncExpr) (IR, error) {
+	if fn.Name.EqualString("get_format") {
+		return ast.translateGetFormat(fn)
+	}
+
 	var args TupleExpr
 	for _, expr := range fn.Exprs {
 		convertedExpr, err := ast.translateExpr(expr)
@@ -542,6 +548,32 @@ func (ast *astCompiler) translateFuncExpr(fn *sqlparser.FuncExpr) (IR, error) {
 		default:
 			return nil, argError(method)
 		}
+	case "str_to_date":
+		if len(args) != 2 {
+			return nil, argError(method)
+		}
+		t, prec := strToDateType(args[1])
+		return &builtinStrToDate{CallExpr: call, t: t, prec: prec}, nil
+	case "datediff":
+		if len(args) != 2 {
+			return nil, argError(method)
+		}
+		return &builtinDateDiff{CallExpr: call}, nil
+	case "timediff":
+		if len(args) != 2 {
+			return nil, argError(method)
+		}
+		return &builtinTimeDiff{CallExpr: call}, nil
+	case "addtime", "subtime":
+		if len(args) != 2 {
+			return nil, argError(method)
+		}
+		return &builtinAddTime{CallExpr: call, sub: method == "subtime", collate: ast.cfg.Collation}, nil
+	case "time_format":
+		if len(args) != 2 {
+			return nil, argError(method)
+		}
+		return &builtinTimeFormat{CallExpr: call, collate: ast.cfg.Collation}, nil
 	case "inet_aton":
 		if len(args) != 1 {
 			return nil, argError(method)
@@ -641,6 +673,44 @@ func (ast *astCompiler) translateFuncExpr(fn *sqlparser.FuncExpr) (IR, error) {
 			return nil, argError(method)
 		}
 		return &builtinSHA2{CallExpr: call, collate: ast.cfg.Collation}, nil
+	case "aes_encrypt", "aes_decrypt":
+		switch len(args) {
+		case 2, 3:
+		case 4, 5, 6:
+			// key derivation functions are only supported by MySQL
+			return nil, translateExprNotSupported(fn)
+		default:
+			return nil, argError(method)
+		}
+		if method == "aes_encrypt" {
+			return &builtinAESEncrypt{CallExpr: call}, nil
+		}
+		return &builtinAESDecrypt{CallExpr: call}, nil
+	case "compress":
+		if len(args) != 1 {
+			return nil, argError(method)
+		}
+		return &builtinCompress{CallExpr: call}, nil
+	case "uncompress":
+		if len(args) != 1 {
+			return nil, argError(method)
+		}
+		return &builtinUncompress{CallExpr: call}, nil
+	case "uncompressed_length":
+		if len(args) != 1 {
+			return nil, argError(method)
+		}
+		return &builtinUncompressedLength{CallExpr: call}, nil
+	case "statement_digest":
+		if len(args) != 1 {
+			return nil, argError(method)
+		}
+		return &builtinStatementDigest{CallExpr: call, collate: ast.cfg.Collation}, nil
+	case "statement_digest_text":
+		if len(args) != 1 {
+			return nil, argError(method)
+		}
+		return &builtinStatementDigestText{CallExpr: call, collate: ast.cfg.Collation}, nil
 	case "convert_tz":
 		if len(args) != 3 {
 			return nil, argError(method)
@@ -662,6 +732,41 @@ func (ast *astCompiler) translateFuncExpr(fn *sqlparser.FuncExpr) (IR, error) {
 			return nil, argError(method)
 		}
 		return &builtinReplace{CallExpr: call, collate: ast.cfg.Collation}, nil
+	case "substring_index":
+		if len(args) != 3 {
+			return nil, argError(method)
+		}
+		return &builtinSubstringIndex{CallExpr: call, collate: ast.cfg.Collation}, nil
+	case "format":
+		if len(args) != 2 && len(args) != 3 {
+			return nil, argError(method)
+		}
+		return &builtinFormat{CallExpr: call, collate: ast.cfg.Collation}, nil
+	case "soundex":
+		if len(args) != 1 {
+			return nil, argError(method)
+		}
+		return &builtinSoundex{CallExpr: call, collate: ast.cfg.Collation}, nil
+	case "make_set":
+		if len(args) < 2 {
+			return nil, argError(method)
+		}
+		return &builtinMakeSet{CallExpr: call, collate: ast.cfg.Collation}, nil
+	case "export_set":
+		if len(args) < 3 || len(args) > 5 {
+			return nil, argError(method)
+		}
+		return &builtinExportSet{CallExpr: call, collate: ast.cfg.Collation}, nil
+	case "find_in_set":
+		if len(args) != 2 {
+			return nil, argError(method)
+		}
+		return &builtinFindInSet{CallExpr: call, collate: ast.cfg.Collation}, nil
+	case "quote":
+		if len(args) != 1 {
+			return nil, argError(method)
+		}
+		return &builtinQuote{CallExpr: call, collate: ast.cfg.Collation}, nil
 	default:
 		return nil, translateExprNotSupported(fn)
 	}
@@ -782,6 +887,113 @@ func (ast *astCompiler) translateCallable(call sqlparser.Callable) (IR, error) {
 			Method:    "JSON_KEYS",
 		}}, nil
 
+	case *sqlparser.JSONValueModifierExpr:
+		exprs := []sqlparser.Expr{call.JSONDoc}
+		for _, param := range call.Params {
+			exprs = append(exprs, param.Key, param.Value)
+		}
+		args, err := ast.translateFuncArgs(exprs)
+		if err != nil {
+			return nil, err
+		}
+		var t json.Transformation
+		switch call.Type {
+		case sqlparser.JSONSetType:
+			t = json.Set
+		case sqlparser.JSONInsertType:
+			t = json.Insert
+		case sqlparser.JSONReplaceType:
+			t = json.Replace
+		case sqlparser.JSONArrayAppendType:
+			t = json.ArrayAppend
+		case sqlparser.JSONArrayInsertType:
+			t = json.ArrayInsert
+		default:
+			return nil, translateExprNotSupported(call)
+		}
+		return &builtinJSONModify{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    strings.ToUpper(call.Type.ToString()),
+		}, transform: t}, nil
+
+	case *sqlparser.JSONRemoveExpr:
+		args, err := ast.translateFuncArgs(append([]sqlparser.Expr{call.JSONDoc}, call.PathList...))
+		if err != nil {
+			return nil, err
+		}
+		return &builtinJSONModify{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    "JSON_REMOVE",
+		}, transform: json.Remove}, nil
+
+	case *sqlparser.JSONValueMergeExpr:
+		args, err := ast.translateFuncArgs(append([]sqlparser.Expr{call.JSONDoc}, call.JSONDocList...))
+		if err != nil {
+			return nil, err
+		}
+		return &builtinJSONMerge{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    strings.ToUpper(call.Type.ToString()),
+		}, patch: call.Type == sqlparser.JSONMergePatchType}, nil
+
+	case *sqlparser.JSONSearchExpr:
+		exprs := []sqlparser.Expr{call.JSONDoc, call.OneOrAll, call.SearchStr}
+		if call.EscapeChar != nil {
+			exprs = append(exprs, call.EscapeChar)
+			exprs = append(exprs, call.PathList...)
+		}
+		args, err := ast.translateFuncArgs(exprs)
+		if err != nil {
+			return nil, err
+		}
+		return &builtinJSONSearch{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    "JSON_SEARCH",
+		}, collate: ast.cfg.Collation}, nil
+
+	case *sqlparser.JSONOverlapsExpr:
+		args, err := ast.translateFuncArgs([]sqlparser.Expr{call.JSONDoc1, call.JSONDoc2})
+		if err != nil {
+			return nil, err
+		}
+		return &builtinJSONOverlaps{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    "JSON_OVERLAPS",
+		}}, nil
+
+	case *sqlparser.JSONValueExpr:
+		return ast.translateJSONValueExpr(call)
+
+	case *sqlparser.JSONSchemaValidFuncExpr:
+		args, err := ast.translateFuncArgs([]sqlparser.Expr{call.Schema, call.Document})
+		if err != nil {
+			return nil, err
+		}
+		return &builtinJSONSchemaValid{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    "JSON_SCHEMA_VALID",
+		}}, nil
+
+	case *sqlparser.MemberOfExpr:
+		args, err := ast.translateFuncArgs([]sqlparser.Expr{call.Value, call.JSONArr})
+		if err != nil {
+			return nil, err
+		}
+		return &builtinMemberOf{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    "MEMBER OF",
+		}}, nil
+
+	case *sqlparser.JSONPrettyExpr:
+		arg, err := ast.translateExpr(call.JSONVal)
+		if err != nil {
+			return nil, err
+		}
+		return &builtinJSONPretty{CallExpr: CallExpr{
+			Arguments: []IR{arg},
+			Method:    "JSON_PRETTY",
+		}}, nil
+
 	case *sqlparser.CurTimeFuncExpr:
 		if call.Fsp > 6 {
 			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Too-big precision %d specified for '%s'. Maximum is 6.", call.Fsp, call.Name.String())
@@ -903,6 +1115,30 @@ func (ast *astCompiler) translateCallable(call sqlparser.Callable) (IR, error) {
 			collate:  ast.cfg.Collation,
 		}, nil
 
+	case *sqlparser.TimestampDiffExpr:
+		args, err := ast.translateFuncArgs([]sqlparser.Expr{call.Expr1, call.Expr2})
+		if err != nil {
+			return nil, err
+		}
+
+		cexpr := CallExpr{Arguments: args, Method: "TIMESTAMPDIFF"}
+		return &builtinTimestampDiff{
+			CallExpr: cexpr,
+			unit:     call.Unit,
+		}, nil
+
+	case *sqlparser.ExtractFuncExpr:
+		arg, err := ast.translateExpr(call.Expr)
+		if err != nil {
+			return nil, err
+		}
+
+		cexpr := CallExpr{Arguments: []IR{arg}, Method: "EXTRACT"}
+		return &builtinExtract{
+			CallExpr: cexpr,
+			unit:     call.IntervalType,
+		}, nil
+
 	case *sqlparser.RegexpLikeExpr:
 		input, err := ast.translateExpr(call.Expr)
 		if err != nil {
@@ -1124,6 +1360,41 @@ func (ast *astCompiler) translateCallable(call sqlparser.Callable) (IR, error) {
 	}
 }
 
+func (ast *astCompiler) translateJSONValueExpr(call *sqlparser.JSONValueExpr) (IR, error) {
+	exprs := []sqlparser.Expr{call.JSONDoc, call.Path}
+	value := &builtinJSONValue{
+		CallExpr: CallExpr{Method: "JSON_VALUE"},
+		onEmpty:  sqlparser.NullJSONType,
+		onError:  sqlparser.NullJSONType,
+	}
+	if call.EmptyOnResponse != nil {
+		value.onEmpty = call.EmptyOnResponse.ResponseType
+		if value.onEmpty == sqlparser.DefaultJSONType {
+			exprs = append(exprs, call.EmptyOnResponse.Expr)
+		}
+	}
+	if call.ErrorOnResponse != nil {
+		value.onError = call.ErrorOnResponse.ResponseType
+		if value.onError == sqlparser.DefaultJSONType {
+			exprs = append(exprs, call.ErrorOnResponse.Expr)
+		}
+	}
+
+	var err error
+	value.Arguments, err = ast.translateFuncArgs(exprs)
+	if err != nil {
+		return nil, err
+	}
+	if call.ReturningType != nil {
+		returning, err := ast.translateConvertType(nil, call, call.ReturningType)
+		if err != nil {
+			return nil, err
+		}
+		value.returning = returning.(*ConvertExpr)
+	}
+	return value, nil
+}
+
 func builtinJSONExtractUnquoteRewrite(left IR, right IR) (IR, error) {
 	extract, err := builtinJSONExtractRewrite(left, right)
 	if err != nil {
@@ -1216,3 +1487,35 @@ func builtinIfRewrite(args []IR) (IR, error) {
 		Else: args[2],
 	}, nil
 }
+
+func (ast *astCompiler) translateGetFormat(fn *sqlparser.FuncExpr) (IR, error) {
+	if len(fn.Exprs) != 2 {
+		return nil, argError("get_format")
+	}
+
+	// The first argument to GET_FORMAT is a type keyword, which is parsed as a column name
+	col, ok := fn.Exprs[0].(*sqlparser.ColName)
+	if !ok || !col.Qualifier.IsEmpty() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid type for GET_FORMAT: %s", sqlparser.String(fn.Exprs[0]))
+	}
+
+	var t sqltypes.Type
+	switch col.Name.Lowered() {
+	case "date":
+		t = sqltypes.Date
+	case "time":
+		t = sqltypes.Time
+	case "datetime", "timestamp":
+		t = sqltypes.Datetime
+	default:
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid type for GET_FORMAT: %s", sqlparser.String(fn.Exprs[0]))
+	}
+
+	locale, err := ast.translateExpr(fn.Exprs[1])
+	if err != nil {
+		return nil, err
+	}
+
+	call := CallExpr{Arguments: []IR{locale}, Method: "GET_FORMAT"}
+	return &builtinGetFormat{CallExpr: call, t: t, collate: ast.cfg.Collation}, nil
+}
@@ -331,6 +331,46 @@ func TestEvaluate(t *testing.T) {
 	}
 }
 
+func TestEvaluateJSONValueReturningErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		err        string
+	}{{
+		expression: `json_value('{"a": "x"}', '$.a' returning signed error on error)`,
+		err:        "Invalid JSON value for CAST to INTEGER from column json_value at row 1",
+	}, {
+		expression: `json_value('{"a": "123.456"}', '$.a' returning decimal(4,2) error on error)`,
+		err:        "Out of range JSON value for CAST to DECIMAL from column json_value at row 1",
+	}, {
+		expression: `json_value('{"a": "abcdef"}', '$.a' returning char(3) error on error)`,
+		err:        "Data too long for column 'json_value' at row 1",
+	}, {
+		expression: `json_value('{"a": "2024-13-45"}', '$.a' returning date error on error)`,
+		err:        "Invalid JSON value for CAST to DATE from column json_value at row 1",
+	}}
+
+	venv := vtenv.NewTestEnv()
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			stmt, err := sqlparser.NewTestParser().Parse("select " + test.expression)
+			require.NoError(t, err)
+			astExpr := stmt.(*sqlparser.Select).SelectExprs[0].(*sqlparser.AliasedExpr).Expr
+			expr, err := Translate(astExpr, &Config{
+				Collation:         venv.CollationEnv().DefaultConnectionCharset(),
+				Environment:       venv,
+				NoConstantFolding: true,
+			})
+			require.NoError(t, err)
+
+			env := NewExpressionEnv(context.Background(), nil, NewEmptyVCursor(venv, time.Local))
+			_, err = env.EvaluateAST(expr)
+			require.EqualError(t, err, test.err)
+			_, err = env.Evaluate(expr)
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
+
 func TestEvaluateTuple(t *testing.T) {
 	type testCase struct {
 		expression string
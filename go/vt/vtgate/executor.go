@@ -35,6 +35,7 @@ import (
 	"vitess.io/vitess/go/mysql/capabilities"
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/ptr"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/streamlog"
@@ -130,6 +131,10 @@ type Executor struct {
 
 	warmingReadsPercent int
 	warmingReadsChannel chan bool
+
+	// snowflake generates the values of the snowflake auto_increment columns.
+	// It is nil when --sequence-node-id is not set.
+	snowflake *engine.SnowflakeGenerator
 }
 
 var executorOnce sync.Once
@@ -162,6 +167,14 @@ func NewExecutor(
 	pv plancontext.PlannerVersion,
 	warmingReadsPercent int,
 ) *Executor {
+	var snowflake *engine.SnowflakeGenerator
+	if sequenceNodeID != -1 {
+		var err error
+		snowflake, err = engine.NewSnowflakeGenerator(int64(sequenceNodeID))
+		if err != nil {
+			log.Fatalf("Invalid value for --sequence-node-id: %v", err)
+		}
+	}
 	e := &Executor{
 		env:                 env,
 		serv:                serv,
@@ -178,16 +191,19 @@ func NewExecutor(
 		plans:               plans,
 		warmingReadsPercent: warmingReadsPercent,
 		warmingReadsChannel: make(chan bool, warmingReadsConcurrency),
+		snowflake:           snowflake,
 	}
 
 	vschemaacl.Init()
 	// we subscribe to update from the VSchemaManager
 	e.vm = &VSchemaManager{
-		subscriber: e.SaveVSchema,
-		serv:       serv,
-		cell:       cell,
-		schema:     e.schemaTracker,
-		parser:     env.Parser(),
+		subscriber:         e.SaveVSchema,
+		serv:               serv,
+		cell:               cell,
+		schema:             e.schemaTracker,
+		parser:             env.Parser(),
+		ctx:                ctx,
+		metadataRetryDelay: metadataWatchRetryDelay,
 	}
 	serv.WatchSrvVSchema(ctx, cell, e.vm.VSchemaUpdate)
 
@@ -225,6 +241,7 @@ func (e *Executor) Execute(ctx context.Context, mysqlCtx vtgateservice.MySQLConn
 	defer span.Finish()
 
 	logStats := logstats.NewLogStats(ctx, method, sql, safeSession.GetSessionUUID(), bindVars)
+	logStats.QueryAttributes = safeSession.GetOptions().GetQueryAttributes()
 	stmtType, result, err := e.execute(ctx, mysqlCtx, safeSession, sql, bindVars, logStats)
 	logStats.Error = err
 	if result == nil {
@@ -292,6 +309,7 @@ func (e *Executor) StreamExecute(
 	defer span.Finish()
 
 	logStats := logstats.NewLogStats(ctx, method, sql, safeSession.GetSessionUUID(), bindVars)
+	logStats.QueryAttributes = safeSession.GetOptions().GetQueryAttributes()
 	srr := &streaminResultReceiver{callback: callback}
 	var err error
 
@@ -464,6 +482,8 @@ func (e *Executor) addNeededBindVars(vcursor *vcursorImpl, bindVarNeeds *sqlpars
 			bindVars[key] = sqltypes.BoolBindVariable(session.Autocommit)
 		case sysvars.QueryTimeout.Name:
 			bindVars[key] = sqltypes.Int64BindVariable(session.GetQueryTimeout())
+		case sysvars.MaxExecutionTime.Name:
+			bindVars[key] = sqltypes.Int64BindVariable(session.GetMaxExecutionTime())
 		case sysvars.ClientFoundRows.Name:
 			var v bool
 			ifOptionsExist(session, func(options *querypb.ExecuteOptions) {
@@ -731,9 +751,18 @@ func (e *Executor) setVitessMetadata(ctx context.Context, name, value string) er
 	}
 
 	if value == "" {
-		return ts.DeleteMetadata(ctx, name)
+		err = ts.DeleteMetadata(ctx, name)
+	} else {
+		err = ts.UpsertMetadata(ctx, name, value)
+	}
+	if err != nil {
+		return err
 	}
-	return ts.UpsertMetadata(ctx, name, value)
+	// Vindexes can keep their split table in vitess_metadata. Every vtgate watches
+	// the keys it uses, but we reload the VSchema here so that this session sees
+	// the change right away.
+	e.vm.Rebuild()
+	return nil
 }
 
 func (e *Executor) showVitessMetadata(ctx context.Context, filter *sqlparser.ShowFilter) (*sqltypes.Result, error) {
@@ -1114,7 +1143,7 @@ func (e *Executor) getPlan(
 	vcursor.SetWorkloadName(qh.Workload)
 	vcursor.UpdateForeignKeyChecksState(qh.ForeignKeyChecks)
 	vcursor.SetPriority(qh.Priority)
-	vcursor.SetExecQueryTimeout(qh.Timeout)
+	vcursor.SetExecQueryTimeout(queryTimeoutForStatement(vcursor.safeSession, stmt, qh, vcursor.getQueryTimeout()))
 
 	setVarComment, err := prepareSetVarComment(vcursor, stmt)
 	if err != nil {
@@ -1152,6 +1181,26 @@ func (e *Executor) getPlan(
 	return e.cacheAndBuildStatement(ctx, vcursor, query, stmt, reservedVars, bindVarNeeds, logStats)
 }
 
+// queryTimeoutForStatement returns the per-query timeout for stmt. A QUERY_TIMEOUT_MS directive is authoritative;
+// otherwise, SELECT statements are bounded by the MAX_EXECUTION_TIME hint or the max_execution_time session variable,
+// like in MySQL. max_execution_time only ever tightens queryTimeout, the session or flag query timeout, it never lifts it.
+func queryTimeoutForStatement(safeSession *SafeSession, stmt sqlparser.Statement, qh sqlparser.QueryHints, queryTimeout int) *int {
+	if qh.Timeout != nil {
+		return qh.Timeout
+	}
+	if _, isSelect := stmt.(sqlparser.SelectStatement); !isSelect {
+		return nil
+	}
+	maxExecutionTime := int(safeSession.GetMaxExecutionTime())
+	if qh.MaxExecutionTime != nil {
+		maxExecutionTime = *qh.MaxExecutionTime
+	}
+	if maxExecutionTime <= 0 || (queryTimeout > 0 && queryTimeout <= maxExecutionTime) {
+		return nil
+	}
+	return ptr.Of(maxExecutionTime)
+}
+
 func (e *Executor) hashPlan(ctx context.Context, vcursor *vcursorImpl, query string) PlanCacheKey {
 	hasher := vthash.New256()
 	vcursor.keyForPlan(ctx, query, hasher)
@@ -3140,3 +3140,29 @@ func TestDeleteMultiTable(t *testing.T) {
 	// delete from `user` where (`user`.id) in ::dml_vals - 1 shard
 	testQueryLog(t, executor, logChan, "TestExecute", "DELETE", "delete `user` from `user` join music on `user`.col = music.col where music.user_id = 1", 18)
 }
+
+func TestDeleteOrderByLimitMultiShard(t *testing.T) {
+	executor, sbc1, sbc2, _, ctx := createExecutorEnv(t)
+	executor.vschema.Keyspaces["TestExecutor"].Tables["user_extra"].PrimaryKey = sqlparser.Columns{sqlparser.NewIdentifierCI("id")}
+
+	fields := sqltypes.MakeTestFields("id|weight_string(id)", "int64|varbinary")
+	sbc1.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(fields, "7|7", "5|5", "3|3")})
+	sbc2.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(fields, "6|6", "4|4", "2|2")})
+
+	session := &vtgatepb.Session{TargetString: "@primary"}
+	// the other shards return the default sandbox row with id 1, which doesn't make it into the top 3
+	_, err := executorExec(ctx, executor, session, "delete from user_extra where col > 10 order by id desc limit 3", nil)
+	require.NoError(t, err)
+
+	upperLimit := sqltypes.Int64BindVariable(3)
+	dmlVals := sqltypes.TestBindVariable([]any{int64(7), int64(6), int64(5)})
+	wantQueries := []*querypb.BoundQuery{{
+		Sql:           "select user_extra.id, weight_string(user_extra.id) from user_extra where col > 10 order by id desc limit :__upper_limit for update",
+		BindVariables: map[string]*querypb.BindVariable{"__upper_limit": upperLimit},
+	}, {
+		Sql:           "delete from user_extra where user_extra.id in ::dml_vals",
+		BindVariables: map[string]*querypb.BindVariable{"__upper_limit": upperLimit, "dml_vals": dmlVals},
+	}}
+	assertQueries(t, sbc1, wantQueries)
+	assertQueries(t, sbc2, wantQueries)
+}
@@ -263,6 +263,9 @@ func TestExecutorSet(t *testing.T) {
 	}, {
 		in:  "set @@query_timeout = 50, query_timeout = 75",
 		out: &vtgatepb.Session{Autocommit: true, QueryTimeout: 75},
+	}, {
+		in:  "set @@max_execution_time = 250",
+		out: &vtgatepb.Session{Autocommit: true, MaxExecutionTime: 250},
 	}}
 	for i, tcase := range testcases {
 		t.Run(fmt.Sprintf("%d-%s", i, tcase.in), func(t *testing.T) {
@@ -38,6 +38,7 @@ import (
 
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/ptr"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/vt/callerid"
@@ -2947,3 +2948,64 @@ func exec(executor *Executor, session *SafeSession, sql string) (*sqltypes.Resul
 func makeComments(text string) sqlparser.MarginComments {
 	return sqlparser.MarginComments{Trailing: text}
 }
+
+func TestQueryTimeoutForStatement(t *testing.T) {
+	parser := sqlparser.NewTestParser()
+	safeSession := NewSafeSession(&vtgatepb.Session{
+		MaxExecutionTime: 250,
+	})
+
+	testCases := []struct {
+		query        string
+		queryTimeout int
+		want         *int
+	}{{
+		query: "select * from user",
+		want:  ptr.Of(250),
+	}, {
+		query: "select * from user union select * from music",
+		want:  ptr.Of(250),
+	}, {
+		query: "select /*+ MAX_EXECUTION_TIME(60) */ * from user",
+		want:  ptr.Of(60),
+	}, {
+		query: "select /*vt+ QUERY_TIMEOUT_MS=900 */ * from user",
+		want:  ptr.Of(900),
+	}, {
+		query:        "select /*vt+ QUERY_TIMEOUT_MS=900 */ * from user",
+		queryTimeout: 100,
+		want:         ptr.Of(900),
+	}, {
+		// max_execution_time tightens a looser query timeout.
+		query:        "select * from user",
+		queryTimeout: 1000,
+		want:         ptr.Of(250),
+	}, {
+		// max_execution_time never lifts a stricter query timeout.
+		query:        "select * from user",
+		queryTimeout: 100,
+		want:         nil,
+	}, {
+		query:        "select /*+ MAX_EXECUTION_TIME(600) */ * from user",
+		queryTimeout: 100,
+		want:         nil,
+	}, {
+		query: "select /*+ MAX_EXECUTION_TIME(0) */ * from user",
+		want:  ptr.Of(250),
+	}, {
+		query: "update user set a = 1",
+		want:  nil,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			stmt, err := parser.Parse(tc.query)
+			require.NoError(t, err)
+			qh, err := sqlparser.BuildQueryHints(stmt)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, queryTimeoutForStatement(safeSession, stmt, qh, tc.queryTimeout))
+		})
+	}
+	stmt, err := parser.Parse("select * from user")
+	require.NoError(t, err)
+	assert.Nil(t, queryTimeoutForStatement(NewSafeSession(nil), stmt, sqlparser.QueryHints{}, 0))
+}
@@ -44,6 +44,8 @@ type LogStats struct {
 	ShardQueries   uint64
 	RowsAffected   uint64
 	RowsReturned   uint64
+	SpilledRows    uint64
+	SpilledBytes   uint64
 	PlanTime       time.Duration
 	ExecuteTime    time.Duration
 	CommitTime     time.Duration
@@ -52,6 +54,8 @@ type LogStats struct {
 	SessionUUID    string
 	CachedPlan     bool
 	ActiveKeyspace string // ActiveKeyspace is the selected keyspace `use ks`
+	// QueryAttributes are the query attributes the client sent along with the query.
+	QueryAttributes map[string]string
 }
 
 // NewLogStats constructs a new LogStats with supplied Method and ctx
@@ -177,6 +181,12 @@ func (stats *LogStats) Logf(w io.Writer, params url.Values) error {
 	log.Strings(stats.TablesUsed)
 	log.Key("ActiveKeyspace")
 	log.String(stats.ActiveKeyspace)
+	log.Key("SpilledRows")
+	log.Uint(stats.SpilledRows)
+	log.Key("SpilledBytes")
+	log.Uint(stats.SpilledBytes)
+	log.Key("QueryAttributes")
+	log.StringMap(stats.QueryAttributes)
 
 	return log.Flush(w)
 }
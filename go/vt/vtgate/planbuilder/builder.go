@@ -197,7 +197,7 @@ func createInstructionFor(ctx context.Context, query string, stmt sqlparser.Stat
 	case *sqlparser.Set:
 		return buildSetPlan(stmt, vschema)
 	case *sqlparser.Load:
-		return buildLoadPlan(query, vschema)
+		return buildLoadPlan(query, stmt, vschema)
 	case sqlparser.DBDDLStatement:
 		return buildRoutePlan(stmt, reservedVars, vschema, buildDBDDLPlan)
 	case *sqlparser.Begin, *sqlparser.Commit, *sqlparser.Rollback,
@@ -309,7 +309,12 @@ func buildDBDDLPlan(stmt sqlparser.Statement, _ *sqlparser.ReservedVars, vschema
 	return nil, vterrors.VT13001(fmt.Sprintf("database DDL not recognized: %s", sqlparser.String(dbDDLstmt)))
 }
 
-func buildLoadPlan(query string, vschema plancontext.VSchema) (*planResult, error) {
+func buildLoadPlan(query string, stmt *sqlparser.Load, vschema plancontext.VSchema) (*planResult, error) {
+	if stmt.Local {
+		// the file is on the client, so vtgate reads it and inserts its rows itself
+		return buildLoadLocalPlan(stmt, vschema)
+	}
+
 	keyspace, err := vschema.DefaultKeyspace()
 	if err != nil {
 		return nil, err
@@ -0,0 +1,191 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+)
+
+// buildLoadLocalPlan plans a LOAD DATA LOCAL INFILE statement. The rows of the file are inserted
+// in batches by the executor, so they are routed through the vindexes of the table like any other insert.
+func buildLoadLocalPlan(stmt *sqlparser.Load, vschema plancontext.VSchema) (*planResult, error) {
+	vTbl, _, _, _, err := vschema.FindTable(stmt.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	load := &engine.Load{
+		Keyspace:  vTbl.Keyspace,
+		TableName: vTbl.Name.String(),
+		Infile:    stmt.Infile,
+		Insert: &sqlparser.Insert{
+			Action: stmt.Action,
+			// with LOCAL, duplicate keys and invalid values are warnings, just like with IGNORE
+			Ignore:     stmt.Action != sqlparser.ReplaceAct,
+			Table:      sqlparser.NewAliasedTableExpr(stmt.Table, ""),
+			Partitions: stmt.Partitions,
+		},
+		FieldsTerminatedBy: "\t",
+		FieldsEscapedBy:    "\\",
+		LinesTerminatedBy:  "\n",
+		IgnoreLines:        stmt.IgnoreLines,
+	}
+	if err := setLoadFormat(load, stmt); err != nil {
+		return nil, err
+	}
+	if load.Charset, err = loadCharset(stmt.Charset.Name, vschema); err != nil {
+		return nil, err
+	}
+
+	fields := stmt.Columns
+	if len(fields) == 0 && vTbl.ColumnListAuthoritative {
+		for _, col := range vTbl.Columns {
+			fields = append(fields, &sqlparser.ColName{Name: col.Name})
+		}
+	}
+	if len(fields) == 0 {
+		if len(stmt.SetExprs) > 0 {
+			return nil, vterrors.VT12001("LOAD DATA with a SET clause but without a column list, on a table without an authoritative column list")
+		}
+		// every field of a line is inserted as is, and mysql checks they match the columns of the table
+		return newPlanResult(load, singleTable(vTbl.Keyspace.Name, vTbl.Name.String())), nil
+	}
+
+	colTypes := make(map[string]querypb.Type, len(vTbl.Columns))
+	for _, col := range vTbl.Columns {
+		colTypes[col.Name.Lowered()] = col.Type
+	}
+
+	fieldArg := func(idx int) *sqlparser.Argument {
+		return sqlparser.NewArgument(engine.LoadFieldPrefix + strconv.Itoa(idx))
+	}
+	var (
+		columns  sqlparser.Columns
+		row      sqlparser.ValTuple
+		colField = map[string]int{}
+		varField = map[string]int{}
+	)
+	load.FieldTypes = make([]querypb.Type, len(fields))
+	for idx, field := range fields {
+		switch field := field.(type) {
+		case *sqlparser.ColName:
+			name := field.Name.Lowered()
+			colField[name] = idx
+			columns = append(columns, field.Name)
+			row = append(row, fieldArg(idx))
+			load.FieldTypes[idx] = colTypes[name]
+		case *sqlparser.Variable:
+			varField[field.Name.Lowered()] = idx
+		}
+	}
+
+	for _, set := range stmt.SetExprs {
+		var err error
+		expr := sqlparser.CopyOnRewrite(set.Expr, nil, func(cursor *sqlparser.CopyOnWriteCursor) {
+			switch node := cursor.Node().(type) {
+			case *sqlparser.Variable:
+				if idx, ok := varField[node.Name.Lowered()]; ok && node.Scope == sqlparser.VariableScope {
+					cursor.Replace(fieldArg(idx))
+				}
+			case *sqlparser.ColName:
+				idx, ok := colField[node.Name.Lowered()]
+				if !ok {
+					err = vterrors.VT12001("LOAD DATA with a SET clause using a column that is not read from the file: " + sqlparser.String(node))
+					cursor.StopTreeWalk()
+					return
+				}
+				cursor.Replace(fieldArg(idx))
+			}
+		}, nil).(sqlparser.Expr)
+		if err != nil {
+			return nil, err
+		}
+
+		if pos := columns.FindColumn(set.Name.Name); pos >= 0 {
+			row[pos] = expr
+			continue
+		}
+		columns = append(columns, set.Name.Name)
+		row = append(row, expr)
+	}
+
+	load.Insert.Columns = columns
+	load.Row = row
+	return newPlanResult(load, singleTable(vTbl.Keyspace.Name, vTbl.Name.String())), nil
+}
+
+// setLoadFormat copies the FIELDS and LINES options of the statement to the primitive
+func setLoadFormat(load *engine.Load, stmt *sqlparser.Load) error {
+	if fields := stmt.Fields; fields != nil {
+		if fields.TerminatedBy != nil {
+			load.FieldsTerminatedBy = *fields.TerminatedBy
+		}
+		if fields.EnclosedBy != nil {
+			load.FieldsEnclosedBy = *fields.EnclosedBy
+		}
+		if fields.EscapedBy != nil {
+			load.FieldsEscapedBy = *fields.EscapedBy
+		}
+	}
+	if lines := stmt.Lines; lines != nil {
+		if lines.StartingBy != nil {
+			load.LinesStartingBy = *lines.StartingBy
+		}
+		if lines.TerminatedBy != nil {
+			load.LinesTerminatedBy = *lines.TerminatedBy
+		}
+	}
+
+	if len(load.FieldsEnclosedBy) > 1 || len(load.FieldsEscapedBy) > 1 {
+		return vterrors.VT12001("LOAD DATA with a FIELDS ENCLOSED BY or ESCAPED BY longer than one character")
+	}
+	if load.FieldsTerminatedBy == "" || load.LinesTerminatedBy == "" {
+		return vterrors.VT12001("LOAD DATA with an empty FIELDS TERMINATED BY or LINES TERMINATED BY")
+	}
+	return nil
+}
+
+// loadCharset returns the collation to convert the file from, or collations.Unknown
+// when the file can be inserted as is
+func loadCharset(name string, vschema plancontext.VSchema) (collations.ID, error) {
+	if strings.HasPrefix(name, "'") {
+		var err error
+		if name, err = sqltypes.DecodeStringSQL(name); err != nil {
+			return collations.Unknown, err
+		}
+	}
+	name = strings.ToLower(name)
+	switch name {
+	case "", "binary", "utf8mb4", "utf8mb3", "utf8":
+		return collations.Unknown, nil
+	}
+	coll := vschema.Environment().CollationEnv().DefaultCollationForCharset(name)
+	if coll == collations.Unknown {
+		return collations.Unknown, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Unknown character set: '%s'", name)
+	}
+	return coll, nil
+}
@@ -61,6 +61,8 @@ func transformToPrimitive(ctx *plancontext.PlanningContext, op operators.Operato
 		return transformOrdering(ctx, op)
 	case *operators.Aggregator:
 		return transformAggregator(ctx, op)
+	case *operators.Window:
+		return transformWindow(ctx, op)
 	case *operators.Distinct:
 		return transformDistinct(ctx, op)
 	case *operators.FkCascade:
@@ -126,13 +128,31 @@ func transformDMLWithInput(ctx *plancontext.PlanningContext, op *operators.DMLWi
 }
 
 func transformUpsert(ctx *plancontext.PlanningContext, op *operators.Upsert) (engine.Primitive, error) {
-	upsert := &engine.Upsert{}
+	upsert := &engine.Upsert{
+		Rows:     op.Rows,
+		BindVars: op.BindVars,
+	}
+	if op.Input != nil {
+		input, err := transformToPrimitive(ctx, op.Input)
+		if err != nil {
+			return nil, err
+		}
+		upsert.Input = input
+	}
 	for _, source := range op.Sources {
 		iLp, uLp, err := transformOneUpsert(ctx, source)
 		if err != nil {
 			return nil, err
 		}
-		upsert.AddUpsert(iLp, uLp)
+		if source.Check == nil {
+			upsert.AddUpsert(iLp, uLp)
+			continue
+		}
+		cLp, err := transformToPrimitive(ctx, source.Check)
+		if err != nil {
+			return nil, err
+		}
+		upsert.AddCheckedUpsert(cLp, iLp, uLp)
 	}
 	return upsert, nil
 }
@@ -204,6 +224,19 @@ func transformInsertionSelection(ctx *plancontext.PlanningContext, op *operators
 	}
 
 	eins.Input = selectionPlan
+
+	for _, del := range op.DeleteBeforeInsert {
+		delLP, err := transformToPrimitive(ctx, del.Op)
+		if err != nil {
+			return nil, err
+		}
+		eins.DeleteBeforeInsert = append(eins.DeleteBeforeInsert, &engine.FkChild{
+			BVName: del.BVName,
+			Cols:   del.Cols,
+			Exec:   delLP,
+		})
+		eins.PreventAutoCommit = true
+	}
 	return eins, nil
 }
 
@@ -258,6 +291,10 @@ func transformSubQuery(ctx *plancontext.PlanningContext, op *operators.SubQuery)
 		return nil, err
 	}
 
+	if op.Apply {
+		return transformCorrelatedSubquery(ctx, op, outer, inner), nil
+	}
+
 	cols, err := op.GetJoinColumns(ctx, op.Outer)
 	if err != nil {
 		return nil, err
@@ -280,6 +317,31 @@ func transformSubQuery(ctx *plancontext.PlanningContext, op *operators.SubQuery)
 	}, nil
 }
 
+func transformCorrelatedSubquery(ctx *plancontext.PlanningContext, op *operators.SubQuery, outer, inner engine.Primitive) engine.Primitive {
+	cs := &engine.CorrelatedSubquery{
+		Opcode:         op.FilterType,
+		SubqueryResult: op.SubqueryValueName,
+		HasValues:      op.HasValuesName,
+		Vars:           op.Vars,
+		Outer:          outer,
+		Subquery:       inner,
+	}
+	if op.ApplyPredicate != nil {
+		cs.Predicate = op.ApplyPredicateWithOffsets
+		cs.ASTPredicate = op.ApplyPredicate
+	}
+	if op.BatchVar != "" {
+		cs.BatchVar = op.BatchVar
+		cs.OuterKey = op.BatchOuterKey
+		cs.SubqueryKey = op.BatchInnerKey
+		cs.Collation = op.BatchType.Collation()
+		cs.ComparisonType = op.BatchType.Type()
+		cs.CollationEnv = ctx.VSchema.Environment().CollationEnv()
+		cs.Values = op.BatchType.Values()
+	}
+	return cs
+}
+
 // transformFkVerify transforms a FkVerify operator into a engine primitive
 func transformFkVerify(ctx *plancontext.PlanningContext, fkv *operators.FkVerify) (engine.Primitive, error) {
 	inputLP, err := transformToPrimitive(ctx, fkv.Input)
@@ -311,9 +373,6 @@ func transformFkVerify(ctx *plancontext.PlanningContext, fkv *operators.FkVerify
 }
 
 func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggregator) (engine.Primitive, error) {
-	if op.WithRollup {
-		return nil, vterrors.VT12001("GROUP BY WITH ROLLUP not supported for sharded queries")
-	}
 	src, err := transformToPrimitive(ctx, op.Source)
 	if err != nil {
 		return nil, err
@@ -330,6 +389,10 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 			message := fmt.Sprintf("Aggregate UDF '%s' must be pushed down to MySQL", sqlparser.String(aggr.Original.Expr))
 			return nil, vterrors.VT12001(message)
 		}
+		if op.WithRollup && aggr.Distinct {
+			// the super-aggregate rows can't be computed from the distinct values of each group
+			return nil, vterrors.VT12001(fmt.Sprintf("in scatter query: distinct aggregation '%s' with rollup", sqlparser.String(aggr.Original)))
+		}
 
 		aggrParam := engine.NewAggregateParam(aggr.OpCode, aggr.ColOffset, aggr.Alias, ctx.VSchema.Environment().CollationEnv())
 		aggrParam.Func = aggr.Func
@@ -340,6 +403,8 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 		aggrParam.OrigOpcode = aggr.OriginalOpCode
 		aggrParam.WCol = aggr.WSOffset
 		aggrParam.Type = aggr.GetTypeCollation(ctx)
+		aggrParam.Args = groupConcatComparison(ctx, aggr.GroupConcatArgs)
+		aggrParam.OrderBy = groupConcatComparison(ctx, aggr.GroupConcatOrder)
 		aggregates = append(aggregates, aggrParam)
 	}
 
@@ -365,6 +430,60 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 	return &engine.OrderedAggregate{
 		Aggregates:          aggregates,
 		GroupByKeys:         groupByKeys,
+		WithRollup:          op.WithRollup,
+		TruncateColumnCount: op.ResultColumns,
+		Input:               src,
+	}, nil
+}
+
+func groupConcatComparison(ctx *plancontext.PlanningContext, cols []operators.GroupConcatColumn) evalengine.Comparison {
+	var cmp evalengine.Comparison
+	for _, col := range cols {
+		typ, _ := ctx.TypeForExpr(col.Expr)
+		cmp = append(cmp, evalengine.OrderByParams{
+			Col:             col.ColOffset,
+			WeightStringCol: col.WSOffset,
+			Desc:            col.Desc,
+			Type:            typ,
+			CollationEnv:    ctx.VSchema.Environment().CollationEnv(),
+		})
+	}
+	return cmp
+}
+
+func transformWindow(ctx *plancontext.PlanningContext, op *operators.Window) (engine.Primitive, error) {
+	src, err := transformToPrimitive(ctx, op.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	var functions []*engine.WindowFuncParams
+	for _, fn := range op.Functions {
+		param := engine.NewWindowFuncParam(fn.OpCode, fn.ColOffset, fn.Original.As.String())
+		param.Offset = fn.N
+		param.DefaultCol = fn.DefaultOffset
+		param.Original = fn.Original
+		functions = append(functions, param)
+	}
+
+	keys := func(in []operators.GroupBy) (out []*engine.GroupByParams) {
+		for _, key := range in {
+			typ, _ := ctx.TypeForExpr(key.Inner)
+			out = append(out, &engine.GroupByParams{
+				KeyCol:          key.ColOffset,
+				WeightStringCol: key.WSOffset,
+				Expr:            key.Inner,
+				Type:            typ,
+				CollationEnv:    ctx.VSchema.Environment().CollationEnv(),
+			})
+		}
+		return
+	}
+
+	return &engine.Window{
+		Functions:           functions,
+		PartitionBy:         keys(op.PartitionBy),
+		OrderBy:             keys(op.OrderBy),
 		TruncateColumnCount: op.ResultColumns,
 		Input:               src,
 	}, nil
@@ -684,16 +803,22 @@ func autoIncGenerate(gen *operators.Generate) *engine.Generate {
 	if gen == nil {
 		return nil
 	}
-	selNext := &sqlparser.Select{
-		From:        []sqlparser.TableExpr{&sqlparser.AliasedTableExpr{Expr: gen.TableName}},
-		SelectExprs: sqlparser.SelectExprs{&sqlparser.Nextval{Expr: &sqlparser.Argument{Name: "n", Type: sqltypes.Int64}}},
-	}
-	return &engine.Generate{
+	eGen := &engine.Generate{
 		Keyspace: gen.Keyspace,
-		Query:    sqlparser.String(selNext),
 		Values:   gen.Values,
 		Offset:   gen.Offset,
 	}
+	if gen.Mode != vindexes.AutoIncrementSequence {
+		eGen.Mode = gen.Mode
+	}
+	if gen.Mode != vindexes.AutoIncrementSnowflake {
+		selNext := &sqlparser.Select{
+			From:        []sqlparser.TableExpr{&sqlparser.AliasedTableExpr{Expr: gen.TableName}},
+			SelectExprs: sqlparser.SelectExprs{&sqlparser.Nextval{Expr: &sqlparser.Argument{Name: "n", Type: sqltypes.Int64}}},
+		}
+		eGen.Query = sqlparser.String(selNext)
+	}
+	return eGen
 }
 
 func generateInsertShardedQuery(ins *sqlparser.Insert) (prefix string, mids sqlparser.Values, suffix sqlparser.OnDup) {
@@ -746,9 +871,6 @@ func buildUpdatePrimitive(
 		upd.OwnedVindexQuery.Where = stmt.Where
 		vQuery = sqlparser.String(upd.OwnedVindexQuery)
 		vindexes = upd.Target.VTable.ColumnVindexes
-		if upd.OwnedVindexQuery.Limit != nil && len(upd.OwnedVindexQuery.OrderBy) == 0 {
-			return nil, vterrors.VT12001("Vindex update should have ORDER BY clause when using LIMIT")
-		}
 	}
 	if upd.VerifyAll {
 		stmt.SetComments(stmt.GetParsedComments().SetMySQLSetVarValue(sysvars.ForeignKeyChecks, "OFF"))
@@ -922,8 +1044,8 @@ func transformHashJoin(ctx *plancontext.PlanningContext, op *operators.HashJoin)
 		return nil, err
 	}
 
-	if len(op.LHSKeys) != 1 {
-		return nil, vterrors.VT12001("hash joins must have exactly one join predicate")
+	if len(op.LHSKeys) == 0 {
+		return nil, vterrors.VT12001("hash joins without equality join predicates")
 	}
 
 	joinOp := engine.InnerJoin
@@ -932,14 +1054,31 @@ func transformHashJoin(ctx *plancontext.PlanningContext, op *operators.HashJoin)
 	}
 
 	var missingTypes []string
+	var keys []engine.HashJoinKey
+	for i, cmp := range op.JoinComparisons {
+		ltyp, found := ctx.TypeForExpr(cmp.LHS)
+		if !found {
+			missingTypes = append(missingTypes, sqlparser.String(cmp.LHS))
+		}
+		rtyp, found := ctx.TypeForExpr(cmp.RHS)
+		if !found {
+			missingTypes = append(missingTypes, sqlparser.String(cmp.RHS))
+		}
+		if len(missingTypes) > 0 {
+			continue
+		}
 
-	ltyp, found := ctx.TypeForExpr(op.JoinComparisons[0].LHS)
-	if !found {
-		missingTypes = append(missingTypes, sqlparser.String(op.JoinComparisons[0].LHS))
-	}
-	rtyp, found := ctx.TypeForExpr(op.JoinComparisons[0].RHS)
-	if !found {
-		missingTypes = append(missingTypes, sqlparser.String(op.JoinComparisons[0].RHS))
+		comparisonType, err := evalengine.CoerceTypes(ltyp, rtyp, ctx.VSchema.Environment().CollationEnv())
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, engine.HashJoinKey{
+			LHS:            op.LHSKeys[i],
+			RHS:            op.RHSKeys[i],
+			Collation:      comparisonType.Collation(),
+			ComparisonType: comparisonType.Type(),
+			Values:         comparisonType.Values(),
+		})
 	}
 
 	if len(missingTypes) > 0 {
@@ -947,24 +1086,40 @@ func transformHashJoin(ctx *plancontext.PlanningContext, op *operators.HashJoin)
 			fmt.Sprintf("missing type information for [%s]", strings.Join(missingTypes, ", ")))
 	}
 
-	comparisonType, err := evalengine.CoerceTypes(ltyp, rtyp, ctx.VSchema.Environment().CollationEnv())
-	if err != nil {
-		return nil, err
+	hj := &engine.HashJoin{
+		Left:         lhs,
+		Right:        rhs,
+		Opcode:       joinOp,
+		Cols:         op.ColumnOffsets,
+		Keys:         keys,
+		Residual:     op.Residual,
+		ResidualCols: op.ResidualOffsets,
+		ASTPred:      op.JoinPredicate(),
+		CollationEnv: ctx.VSchema.Environment().CollationEnv(),
 	}
 
-	return &engine.HashJoin{
-		Left:           lhs,
-		Right:          rhs,
-		Opcode:         joinOp,
-		Cols:           op.ColumnOffsets,
-		LHSKey:         op.LHSKeys[0],
-		RHSKey:         op.RHSKeys[0],
-		ASTPred:        op.JoinPredicate(),
-		Collation:      comparisonType.Collation(),
-		ComparisonType: comparisonType.Type(),
-		CollationEnv:   ctx.VSchema.Environment().CollationEnv(),
-		Values:         comparisonType.Values(),
-	}, nil
+	// the probe table is built from the LHS, so we want the smaller of the two inputs there
+	if operators.EstimateRows(op.RHS) < operators.EstimateRows(op.LHS) {
+		swapHashJoinInputs(hj)
+	}
+	return hj, nil
+}
+
+// swapHashJoinInputs switches the LHS and RHS of the hash join. A left join becomes a right join,
+// so the rows that have to be kept are still coming from the same input
+func swapHashJoinInputs(hj *engine.HashJoin) {
+	hj.Left, hj.Right = hj.Right, hj.Left
+	if hj.Opcode == engine.LeftJoin {
+		hj.Opcode = engine.RightJoin
+	}
+	negate := func(cols []int) []int {
+		return slice.Map(cols, func(col int) int { return -col })
+	}
+	hj.Cols = negate(hj.Cols)
+	hj.ResidualCols = negate(hj.ResidualCols)
+	for i, key := range hj.Keys {
+		hj.Keys[i].LHS, hj.Keys[i].RHS = key.RHS, key.LHS
+	}
 }
 
 func transformVindexPlan(ctx *plancontext.PlanningContext, op *operators.Vindex) (engine.Primitive, error) {
@@ -446,6 +446,8 @@ func buildQuery(op Operator, qb *queryBuilder) {
 		buildOrdering(op, qb)
 	case *Aggregator:
 		buildAggregation(op, qb)
+	case *Window:
+		buildWindow(op, qb)
 	case *Union:
 		buildUnion(op, qb)
 	case *Distinct:
@@ -536,6 +538,25 @@ func buildAggregation(op *Aggregator, qb *queryBuilder) {
 	}
 }
 
+func buildWindow(op *Window, qb *queryBuilder) {
+	buildQuery(op.Source, qb)
+
+	qb.clearProjections()
+
+	cols := op.GetColumns(qb.ctx)
+	for _, column := range cols {
+		qb.addProjection(column)
+	}
+
+	if op.DT != nil {
+		sel := qb.asSelectStatement()
+		qb.stmt = nil
+		qb.addTableExpr(op.DT.Alias, op.DT.Alias, TableID(op), &sqlparser.DerivedTable{
+			Select: sel,
+		}, nil, op.DT.Columns)
+	}
+}
+
 func buildOrdering(op *Ordering, qb *queryBuilder) {
 	buildQuery(op.Source, qb)
 
@@ -40,7 +40,7 @@ func tryPushAggregator(ctx *plancontext.PlanningContext, aggregator *Aggregator)
 	}
 
 	// this rewrite is always valid, and we should do it whenever possible
-	if route, ok := aggregator.Source.(*Route); ok && (route.IsSingleShard() || overlappingUniqueVindex(ctx, aggregator.Grouping)) {
+	if route, ok := aggregator.Source.(*Route); ok && (route.IsSingleShard() || (!aggregator.WithRollup && overlappingUniqueVindex(ctx, aggregator.Grouping))) {
 		return Swap(aggregator, route, "push down aggregation under route - remove original")
 	}
 
@@ -149,6 +149,14 @@ func pushAggregationThroughRoute(
 	aggregator *Aggregator,
 	route *Route,
 ) (Operator, *ApplyResult) {
+	for _, aggr := range aggregator.Aggregations {
+		if aggr.needsGroupConcatValues() {
+			// the partial results of the shards can't be combined,
+			// so the aggregation is done at the vtgate from the individual rows
+			return nil, nil
+		}
+	}
+
 	// Create a new aggregator to be placed below the route.
 	aggrBelowRoute := aggregator.SplitAggregatorBelowOperators(ctx, route.Inputs())
 	aggrBelowRoute.Aggregations = nil
@@ -212,7 +220,8 @@ func checkIfWeCanPush(ctx *plancontext.PlanningContext, aggregator *Aggregator)
 	var differentExpr *sqlparser.AliasedExpr
 
 	for _, aggr := range aggregator.Aggregations {
-		if !aggr.Distinct {
+		if !aggr.Distinct || aggr.OpCode == opcode.AggregateGroupConcat {
+			// GROUP_CONCAT is computed from the individual values, and takes care of DISTINCT itself
 			continue
 		}
 
@@ -400,6 +409,11 @@ func pushAggregationThroughApplyJoin(ctx *plancontext.PlanningContext, rootAggr
 
 // pushAggregationThroughHashJoin pushes aggregation through a hash-join in a similar way to pushAggregationThroughApplyJoin
 func pushAggregationThroughHashJoin(ctx *plancontext.PlanningContext, rootAggr *Aggregator, join *HashJoin) (Operator, *ApplyResult) {
+	if len(join.Residuals) > 0 {
+		// the residual predicate has to be evaluated on the rows before they are aggregated
+		return nil, nil
+	}
+
 	lhs := createJoinPusher(rootAggr, join.LHS)
 	rhs := createJoinPusher(rootAggr, join.RHS)
 
@@ -133,12 +133,9 @@ func (ab *aggBuilder) handleAggr(ctx *plancontext.PlanningContext, aggr Aggr) er
 	case opcode.AggregateMax, opcode.AggregateMin, opcode.AggregateAnyValue:
 		return ab.handlePushThroughAggregation(ctx, aggr)
 	case opcode.AggregateGroupConcat:
-		f := aggr.Func.(*sqlparser.GroupConcatExpr)
-		if f.Distinct || len(f.OrderBy) > 0 {
-			panic(vterrors.VT12001("cannot evaluate group concat with distinct or order by"))
-		}
 		// this needs special handling, currently aborting the push of function
-		// and later will try pushing the column instead.
+		// and later will try pushing the columns instead, so the vtgate
+		// can compute the function from the individual values.
 		// TODO: this should be handled better by pushing the function down.
 		return errAbortAggrPushing
 	case opcode.AggregateUnassigned:
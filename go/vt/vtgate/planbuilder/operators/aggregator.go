@@ -19,6 +19,7 @@ package operators
 import (
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 
 	"vitess.io/vitess/go/slice"
@@ -422,10 +423,8 @@ func (aggr Aggr) getPushColumn() sqlparser.Expr {
 	case opcode.AggregateCountStar:
 		return sqlparser.NewIntLiteral("1")
 	case opcode.AggregateGroupConcat:
-		if len(aggr.Func.GetArgs()) > 1 {
-			panic(vterrors.VT12001("group_concat with more than 1 column"))
-		}
-		return aggr.Func.GetArg()
+		// the remaining arguments are added as extra columns, see pushGroupConcatColumns
+		return aggr.Func.GetArgs()[0]
 	default:
 		if len(aggr.Func.GetArgs()) > 1 {
 			panic(vterrors.VT03001(sqlparser.String(aggr.Func)))
@@ -465,6 +464,65 @@ func (a *Aggregator) planOffsetsNotPushed(ctx *plancontext.PlanningContext) {
 	}
 
 	a.pushRemainingGroupingColumnsAndWeightStrings(ctx)
+	a.pushGroupConcatColumns(ctx)
+}
+
+// pushGroupConcatColumns adds the columns needed to compute GROUP_CONCAT from the individual values
+// of each group: all the arguments of the function, and the expressions of its ORDER BY clause.
+func (a *Aggregator) pushGroupConcatColumns(ctx *plancontext.PlanningContext) {
+	for idx, aggr := range a.Aggregations {
+		if aggr.OpCode != opcode.AggregateGroupConcat {
+			continue
+		}
+		f := aggr.Func.(*sqlparser.GroupConcatExpr)
+		if !f.Distinct && len(f.OrderBy) == 0 && len(f.Exprs) == 1 {
+			// the simple case only needs the column already added for the aggregation
+			continue
+		}
+
+		var args []GroupConcatColumn
+		for argIdx, arg := range f.Exprs {
+			col := GroupConcatColumn{Expr: arg, ColOffset: aggr.ColOffset, WSOffset: -1}
+			if argIdx > 0 {
+				col.ColOffset = a.internalAddColumn(ctx, aeWrap(arg), false)
+			}
+			if f.Distinct && ctx.NeedsWeightString(arg) {
+				col.WSOffset = a.internalAddWSColumn(ctx, col.ColOffset, aeWrap(weightStringFor(arg)))
+			}
+			args = append(args, col)
+		}
+
+		var order []GroupConcatColumn
+		for _, by := range f.OrderBy {
+			col := GroupConcatColumn{Expr: by.Expr, Desc: by.Direction == sqlparser.DescOrder, WSOffset: -1}
+			if pos, isPos := groupConcatOrderPosition(by.Expr, len(args)); isPos {
+				// ORDER BY <position> refers to the arguments of the function
+				col.Expr, col.ColOffset, col.WSOffset = args[pos].Expr, args[pos].ColOffset, args[pos].WSOffset
+			} else {
+				col.ColOffset = a.internalAddColumn(ctx, aeWrap(by.Expr), false)
+			}
+			if col.WSOffset == -1 && ctx.NeedsWeightString(col.Expr) {
+				col.WSOffset = a.internalAddWSColumn(ctx, col.ColOffset, aeWrap(weightStringFor(col.Expr)))
+			}
+			order = append(order, col)
+		}
+
+		a.Aggregations[idx].GroupConcatArgs = args
+		a.Aggregations[idx].GroupConcatOrder = order
+	}
+}
+
+// groupConcatOrderPosition returns the zero based index of the argument an ORDER BY <position> refers to
+func groupConcatOrderPosition(expr sqlparser.Expr, numArgs int) (int, bool) {
+	lit, ok := expr.(*sqlparser.Literal)
+	if !ok || lit.Type != sqlparser.IntVal {
+		return 0, false
+	}
+	pos, err := strconv.Atoi(lit.Val)
+	if err != nil || pos < 1 || pos > numArgs {
+		return 0, false
+	}
+	return pos - 1, true
 }
 
 func (a *Aggregator) addIfAggregationColumn(ctx *plancontext.PlanningContext, colIdx int) int {
@@ -578,6 +636,8 @@ func (a *Aggregator) SplitAggregatorBelowOperators(ctx *plancontext.PlanningCont
 	newOp := a.Clone(input).(*Aggregator)
 	newOp.Pushed = false
 	newOp.Original = false
+	// the super-aggregate rows can only be computed once all the groups have been aggregated
+	newOp.WithRollup = false
 	newOp.DT = nil
 
 	// We need to make sure that the columns are cloned so that the original operator is not affected
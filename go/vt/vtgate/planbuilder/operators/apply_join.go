@@ -235,6 +235,10 @@ func (aj *ApplyJoin) AddColumn(
 	col := aj.getJoinColumnFor(ctx, expr, expr.Expr, groupBy)
 	offset := len(aj.JoinColumns.columns)
 	aj.JoinColumns.add(col)
+	if len(aj.Columns) > 0 {
+		// offsets have already been planned, so this column needs its offset right away
+		aj.planOffsetFor(ctx, col)
+	}
 	return offset
 }
 
@@ -265,13 +269,7 @@ func (aj *ApplyJoin) AddWSColumn(ctx *plancontext.PlanningContext, offset int, u
 		aj.JoinColumns.addRight(wsExpr)
 	}
 
-	if out >= 0 {
-		aj.addOffset(out)
-	} else {
-		col := aj.getJoinColumnFor(ctx, aeWrap(wsExpr), wsExpr, !ctx.ContainsAggr(wsExpr))
-		aj.JoinColumns.add(col)
-		aj.planOffsetFor(ctx, col)
-	}
+	aj.addOffset(out)
 
 	return len(aj.Columns) - 1
 }
@@ -29,6 +29,8 @@ import (
 const (
 	foreignKeyConstraintValues = "fkc_vals"
 	foreignKeyUpdateExpr       = "fkc_upd"
+	replaceValues              = "replace_vals"
+	upsertValues               = "upsert"
 )
 
 // translateQueryToOp creates an operator tree that represents the input SELECT or UNION query
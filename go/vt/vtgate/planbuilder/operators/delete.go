@@ -66,7 +66,7 @@ func createOperatorFromDelete(ctx *plancontext.PlanningContext, deleteStmt *sqlp
 	// We check if delete with input plan is required. DML with input planning is generally
 	// slower, because it does a selection and then creates a delete statement wherein we have to
 	// list all the primary key values.
-	if deleteWithInputPlanningRequired(childFks, deleteStmt) {
+	if deleteWithInputPlanningRequired(ctx, childFks, deleteStmt) {
 		return createDeleteWithInputOp(ctx, deleteStmt)
 	}
 
@@ -91,10 +91,15 @@ func createOperatorFromDelete(ctx *plancontext.PlanningContext, deleteStmt *sqlp
 	return createFkCascadeOpForDelete(ctx, op, delClone, childFks, vTbl)
 }
 
-func deleteWithInputPlanningRequired(childFks []vindexes.ChildFKInfo, deleteStmt *sqlparser.Delete) bool {
+func deleteWithInputPlanningRequired(ctx *plancontext.PlanningContext, childFks []vindexes.ChildFKInfo, deleteStmt *sqlparser.Delete) bool {
 	if len(deleteStmt.Targets) > 1 {
 		return true
 	}
+	// The owned vindexes have to be cleaned up for exactly the rows picked by the limit,
+	// so we select their primary keys first.
+	if deleteStmt.Limit != nil && hasOwnedVindexes(ctx) {
+		return true
+	}
 	// If there are no foreign keys, we don't need to use delete with input.
 	if len(childFks) == 0 {
 		return false
@@ -110,13 +115,24 @@ func deleteWithInputPlanningRequired(childFks []vindexes.ChildFKInfo, deleteStmt
 	return !deleteStmt.IsSingleAliasExpr()
 }
 
+// hasOwnedVindexes returns true if the target table of the DML owns any vindexes.
+func hasOwnedVindexes(ctx *plancontext.PlanningContext) bool {
+	ti, err := ctx.SemTable.TableInfoFor(ctx.SemTable.Targets)
+	if err != nil {
+		return false
+	}
+	vTbl := ti.GetVindexTable()
+	return vTbl != nil && len(vTbl.Owned) > 0
+}
+
 func createDeleteWithInputOp(ctx *plancontext.PlanningContext, del *sqlparser.Delete) (op Operator) {
 	delClone := ctx.SemTable.Clone(del).(*sqlparser.Delete)
 	del.Limit = nil
 	del.OrderBy = nil
 
 	selectStmt := &sqlparser.Select{
-		From:    delClone.TableExprs,
+		// the table expressions are not cloned, so that derived tables keep their table id in the semantic table
+		From:    del.TableExprs,
 		Where:   delClone.Where,
 		OrderBy: delClone.OrderBy,
 		Limit:   delClone.Limit,
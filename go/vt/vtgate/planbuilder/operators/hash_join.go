@@ -39,6 +39,10 @@ type (
 		// Before offset planning
 		JoinComparisons []Comparison
 
+		// Residuals are the join predicates that can't be used for hashing.
+		// They are evaluated on every pair of rows whose hash keys match.
+		Residuals []sqlparser.Expr
+
 		// These columns are the output columns of the hash join. While in operator mode we keep track of complex expression,
 		// but once we move to the engine primitives, the hash join only passes through column from either left or right.
 		// anything more complex will be solved by a projection on top of the hash join
@@ -53,6 +57,11 @@ type (
 		// These are the values that will be hashed together
 		LHSKeys, RHSKeys []int
 
+		// Residual is the AND of all Residuals, rewritten to use offsets into
+		// ResidualOffsets, which uses the same encoding as ColumnOffsets
+		Residual        evalengine.Expr
+		ResidualOffsets []int
+
 		offset bool
 	}
 
@@ -93,6 +102,8 @@ func (hj *HashJoin) Clone(inputs []Operator) Operator {
 	kopy.LHSKeys = slices.Clone(hj.LHSKeys)
 	kopy.RHSKeys = slices.Clone(hj.RHSKeys)
 	kopy.JoinComparisons = slices.Clone(hj.JoinComparisons)
+	kopy.Residuals = slices.Clone(hj.Residuals)
+	kopy.ResidualOffsets = slices.Clone(hj.ResidualOffsets)
 	return &kopy
 }
 
@@ -150,6 +161,11 @@ func (hj *HashJoin) planOffsets(ctx *plancontext.PlanningContext) Operator {
 		hj.RHSKeys = append(hj.RHSKeys, rOffset)
 	}
 
+	if len(hj.Residuals) > 0 {
+		residual, _ := hj.addColumn(ctx, sqlparser.AndExpressions(hj.Residuals...), &hj.ResidualOffsets)
+		hj.Residual = residual.Info.(*EvalEngine).EExpr
+	}
+
 	needsProj := false
 	lID := TableID(hj.LHS)
 	rID := TableID(hj.RHS)
@@ -159,7 +175,7 @@ func (hj *HashJoin) planOffsets(ctx *plancontext.PlanningContext) Operator {
 
 		switch in.side {
 		case Unknown:
-			column, pureOffset = hj.addColumn(ctx, in.expr)
+			column, pureOffset = hj.addColumn(ctx, in.expr, &hj.ColumnOffsets)
 		case Left:
 			column, pureOffset = hj.addSingleSidedColumn(ctx, in.expr, lID, hj.LHS, lhsOffset)
 		case Right:
@@ -204,6 +220,9 @@ func (hj *HashJoin) ShortDescription() string {
 	comparisons := slice.Map(hj.JoinComparisons, func(from Comparison) string {
 		return from.String()
 	})
+	for _, residual := range hj.Residuals {
+		comparisons = append(comparisons, sqlparser.String(residual))
+	}
 	cmp := strings.Join(comparisons, " AND ")
 
 	if len(hj.columns.columns) > 0 {
@@ -254,9 +273,29 @@ func (hj *HashJoin) IsInner() bool {
 }
 
 func (hj *HashJoin) AddJoinPredicate(ctx *plancontext.PlanningContext, expr sqlparser.Expr) {
+	if cmp, ok := hj.hashComparison(ctx, expr); ok {
+		hj.JoinComparisons = append(hj.JoinComparisons, cmp)
+		return
+	}
+
+	deps := ctx.SemTable.RecursiveDeps(expr)
+	switch {
+	case deps.IsSolvedBy(TableID(hj.RHS)):
+		// the RHS is never the outer side, so we can filter it before joining
+		hj.RHS = newFilterSinglePredicate(hj.RHS, expr)
+	case !hj.LeftJoin && deps.IsSolvedBy(TableID(hj.LHS)):
+		hj.LHS = newFilterSinglePredicate(hj.LHS, expr)
+	default:
+		hj.Residuals = append(hj.Residuals, expr)
+	}
+}
+
+// hashComparison checks if the predicate is an equality comparison between one
+// expression from the LHS and one from the RHS, which is what we can hash on
+func (hj *HashJoin) hashComparison(ctx *plancontext.PlanningContext, expr sqlparser.Expr) (Comparison, bool) {
 	cmp, ok := expr.(*sqlparser.ComparisonExpr)
 	if !ok || !canBeSolvedWithHashJoin(cmp.Operator) {
-		panic(vterrors.VT12001(fmt.Sprintf("can't use [%s] with hash joins", sqlparser.String(expr))))
+		return Comparison{}, false
 	}
 	lExpr := cmp.Left
 	lDeps := ctx.SemTable.RecursiveDeps(lExpr)
@@ -270,14 +309,14 @@ func (hj *HashJoin) AddJoinPredicate(ctx *plancontext.PlanningContext, expr sqlp
 		lDeps, rDeps = rDeps, lDeps
 	}
 
-	if !lDeps.IsSolvedBy(lID) || !rDeps.IsSolvedBy(rID) {
-		panic(vterrors.VT12001(fmt.Sprintf("can't use [%s] with hash joins", sqlparser.String(expr))))
+	if lDeps.IsEmpty() || rDeps.IsEmpty() || !lDeps.IsSolvedBy(lID) || !rDeps.IsSolvedBy(rID) {
+		return Comparison{}, false
 	}
 
-	hj.JoinComparisons = append(hj.JoinComparisons, Comparison{
+	return Comparison{
 		LHS: lExpr,
 		RHS: rExpr,
-	})
+	}, true
 }
 
 func canBeSolvedWithHashJoin(op sqlparser.ComparisonExprOperator) bool {
@@ -294,7 +333,7 @@ func (c Comparison) String() string {
 }
 func lhsOffset(i int) int { return (i * -1) - 1 }
 func rhsOffset(i int) int { return i + 1 }
-func (hj *HashJoin) addColumn(ctx *plancontext.PlanningContext, in sqlparser.Expr) (*ProjExpr, bool) {
+func (hj *HashJoin) addColumn(ctx *plancontext.PlanningContext, in sqlparser.Expr, offsets *[]int) (*ProjExpr, bool) {
 	lId, rId := TableID(hj.LHS), TableID(hj.RHS)
 	r := new(replacer) // this is the expression we will put in instead of whatever we find there
 	pre := func(node, parent sqlparser.SQLNode) bool {
@@ -319,8 +358,8 @@ func (hj *HashJoin) addColumn(ctx *plancontext.PlanningContext, in sqlparser.Exp
 
 			// we have to turn the incoming offset to an outgoing offset of the columns this operator is exposing
 			internalOffset := offsetter(inOffset)
-			hj.ColumnOffsets = append(hj.ColumnOffsets, internalOffset)
-			return len(hj.ColumnOffsets) - 1
+			*offsets = append(*offsets, internalOffset)
+			return len(*offsets) - 1
 		}
 
 		if lOffset := check(lId, hj.LHS, lhsOffset); lOffset >= 0 {
@@ -365,7 +404,7 @@ func (hj *HashJoin) JoinPredicate() sqlparser.Expr {
 			Right: from.RHS,
 		}
 	})
-	return sqlparser.AndExpressions(exprs...)
+	return sqlparser.AndExpressions(append(exprs, hj.Residuals...)...)
 }
 
 type replacer struct {
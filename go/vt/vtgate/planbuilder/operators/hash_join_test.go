@@ -58,6 +58,39 @@ func TestJoinPredicates(t *testing.T) {
 	require.Len(t, hj.RHSKeys, 1)
 }
 
+func TestResidualJoinPredicates(t *testing.T) {
+	lcol1, lcol2 := sqlparser.NewColName("lhs1"), sqlparser.NewColName("lhs2")
+	rcol1, rcol2 := sqlparser.NewColName("rhs1"), sqlparser.NewColName("rhs2")
+	ctx := &plancontext.PlanningContext{
+		SemTable: semantics.EmptySemTable(),
+		VSchema: &vschemawrapper.VSchemaWrapper{
+			V:             &vindexes.VSchema{},
+			SysVarEnabled: true,
+			Env:           vtenv.NewTestEnv(),
+		},
+	}
+	lid := semantics.SingleTableSet(0)
+	rid := semantics.SingleTableSet(1)
+	ctx.SemTable.Recursive[lcol1] = lid
+	ctx.SemTable.Recursive[lcol2] = lid
+	ctx.SemTable.Recursive[rcol1] = rid
+	ctx.SemTable.Recursive[rcol2] = rid
+	hj := NewHashJoin(&fakeOp{id: lid}, &fakeOp{id: rid}, true)
+
+	hj.AddJoinPredicate(ctx, &sqlparser.ComparisonExpr{Operator: sqlparser.EqualOp, Left: rcol1, Right: lcol1})
+	hj.AddJoinPredicate(ctx, &sqlparser.ComparisonExpr{Operator: sqlparser.EqualOp, Left: lcol2, Right: rcol2})
+	residual := &sqlparser.ComparisonExpr{Operator: sqlparser.LessThanOp, Left: lcol1, Right: rcol2}
+	hj.AddJoinPredicate(ctx, residual)
+	require.Len(t, hj.JoinComparisons, 2)
+	require.Equal(t, []sqlparser.Expr{residual}, hj.Residuals)
+
+	hj.planOffsets(ctx)
+	assert.Equal(t, []int{0, 1}, hj.LHSKeys)
+	assert.Equal(t, []int{0, 1}, hj.RHSKeys)
+	assert.Equal(t, []int{-1, 2}, hj.ResidualOffsets)
+	require.NotNil(t, hj.Residual)
+}
+
 func TestOffsetPlanning(t *testing.T) {
 	lcol1, lcol2 := sqlparser.NewColName("lhs1"), sqlparser.NewColName("lhs2")
 	rcol1, rcol2 := sqlparser.NewColName("rhs1"), sqlparser.NewColName("rhs2")
@@ -19,8 +19,10 @@ package operators
 import (
 	"fmt"
 	"sort"
+	"strconv"
 
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 	"vitess.io/vitess/go/vt/vtgate/semantics"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
@@ -116,6 +118,87 @@ func CostOf(op Operator) (cost int) {
 	return
 }
 
+// These are the row counts EstimateRows guesses for the different kinds of routes. We don't have
+// table statistics at the vtgate level, so they only need to be good enough to compare plans.
+const (
+	estimatedRowsUnique  = 1
+	estimatedRowsLookup  = 10
+	estimatedRowsIN      = 100
+	estimatedRowsScatter = 10_000
+)
+
+// EstimateRows returns a rough estimate of how many rows the operator will produce
+func EstimateRows(op Operator) int {
+	switch op := op.(type) {
+	case *Route:
+		rows := estimatedRowsFor(op.Routing)
+		// a LIMIT inside the route caps the number of rows per shard
+		for src := op.Source; src != nil; {
+			if limit, ok := src.(*Limit); ok {
+				rows = limitedRows(limit, rows)
+			}
+			inputs := src.Inputs()
+			if len(inputs) != 1 {
+				break
+			}
+			src = inputs[0]
+		}
+		return rows
+	case *Limit:
+		return limitedRows(op, EstimateRows(op.Source))
+	case *Union:
+		rows := 0
+		for _, source := range op.Sources {
+			rows += EstimateRows(source)
+		}
+		return rows
+	}
+
+	// for everything else, we assume the operator produces as many rows as its biggest input
+	rows := 0
+	for _, input := range op.Inputs() {
+		rows = max(rows, EstimateRows(input))
+	}
+	return rows
+}
+
+func estimatedRowsFor(routing Routing) int {
+	switch routing := routing.(type) {
+	case *ShardedRouting:
+		switch routing.RouteOpCode {
+		case engine.EqualUnique:
+			return estimatedRowsUnique
+		case engine.Equal, engine.SubShard:
+			return estimatedRowsLookup
+		case engine.IN, engine.MultiEqual:
+			return estimatedRowsIN
+		default:
+			return estimatedRowsScatter
+		}
+	case *DualRouting, *SequenceRouting:
+		return estimatedRowsUnique
+	case *NoneRouting:
+		return 0
+	default:
+		return estimatedRowsScatter
+	}
+}
+
+func limitedRows(limit *Limit, rows int) int {
+	if limit.AST == nil {
+		return rows
+	}
+	lit, ok := limit.AST.Rowcount.(*sqlparser.Literal)
+	if !ok || lit.Type != sqlparser.IntVal {
+		return rows
+	}
+	count, err := strconv.Atoi(lit.Val)
+	if err != nil {
+		return rows
+	}
+	return min(rows, count)
+}
+
 func QualifiedIdentifier(ks *vindexes.Keyspace, i sqlparser.IdentifierCS) string {
 	return QualifiedString(ks, i.String())
 }
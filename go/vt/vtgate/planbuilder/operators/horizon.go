@@ -96,7 +96,7 @@ func (h *Horizon) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparser.
 	}
 
 	newExpr := ctx.RewriteDerivedTableExpression(expr, tableInfo)
-	if ctx.ContainsAggr(newExpr) {
+	if ctx.ContainsAggr(newExpr) || sqlparser.ContainsWindowFunction(newExpr) {
 		return newFilter(h, expr)
 	}
 	h.Source = h.Source.AddPredicate(ctx, newExpr)
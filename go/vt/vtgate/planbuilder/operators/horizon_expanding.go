@@ -81,9 +81,12 @@ func expandSelectHorizon(ctx *plancontext.PlanningContext, horizon *Horizon, sel
 	}
 
 	op := createProjectionFromSelect(ctx, horizon)
-	if qp.HasAggr {
+	switch {
+	case qp.HasAggr:
 		extracted = append(extracted, "Aggregation")
-	} else {
+	case qp.HasWindow:
+		extracted = append(extracted, "Window")
+	default:
 		extracted = append(extracted, "Projection")
 	}
 
@@ -188,9 +191,16 @@ func createProjectionFromSelect(ctx *plancontext.PlanningContext, horizon *Horiz
 	}
 
 	if qp.NeedsAggregation() {
+		if qp.HasWindow {
+			panic(vterrors.VT12001("in scatter query: window functions together with aggregation"))
+		}
 		return createProjectionWithAggr(ctx, qp, dt, horizon)
 	}
 
+	if qp.HasWindow {
+		return createWindowFromSelect(ctx, qp, dt, horizon)
+	}
+
 	projX := createProjectionWithoutAggr(ctx, qp, horizon.src())
 	projX.DT = dt
 	return projX
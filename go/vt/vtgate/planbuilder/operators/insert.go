@@ -17,6 +17,9 @@ limitations under the License.
 package operators
 
 import (
+	"fmt"
+	"io"
+	"slices"
 	"strconv"
 
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
@@ -57,7 +60,10 @@ type Insert struct {
 
 // Generate represents an auto-increment generator for the insert operation.
 type Generate struct {
+	// Mode is the vindexes.AutoIncrement mode of the column.
+	Mode string
 	// Keyspace represents the keyspace information for the table.
+	// It is nil, like TableName, for the snowflake mode.
 	Keyspace *vindexes.Keyspace
 	// TableName represents the name of the table.
 	TableName sqlparser.TableName
@@ -110,12 +116,30 @@ func createOperatorFromInsert(ctx *plancontext.PlanningContext, ins *sqlparser.I
 	vTbl, routing := buildVindexTableForDML(ctx, tableInfo, qt, ins, "insert")
 
 	deleteBeforeInsert := false
-	if ins.Action == sqlparser.ReplaceAct &&
-		(ctx.SemTable.ForeignKeysPresent() || vTbl.Keyspace.Sharded) &&
-		(len(vTbl.PrimaryKey) > 0 || len(vTbl.UniqueKeys) > 0) {
-		// this needs a delete before insert as there can be row clash which needs to be deleted first.
-		ins.Action = sqlparser.InsertAct
-		deleteBeforeInsert = true
+	if ins.Action == sqlparser.ReplaceAct && (ctx.SemTable.ForeignKeysPresent() || vTbl.Keyspace.Sharded) {
+		if len(vTbl.PrimaryKey) > 0 || len(vTbl.UniqueKeys) > 0 {
+			// this needs a delete before insert as there can be row clash which needs to be deleted first.
+			ins.Action = sqlparser.InsertAct
+			deleteBeforeInsert = true
+		} else if vTbl.Keyspace.Sharded {
+			// without knowing the keys, we can't find the rows that need to be replaced on the other shards
+			panic(vterrors.VT12001("REPLACE INTO with sharded keyspace"))
+		}
+	}
+
+	// the delete needs to be built from the values as written by the user,
+	// before the insert operator replaces them with bind variables.
+	var whereExpr sqlparser.Expr
+	var columns sqlparser.Columns
+	rows, isRows := ins.Rows.(sqlparser.Values)
+	if deleteBeforeInsert {
+		ins = addMissingColumnList(ins, vTbl)
+		columns = slices.Clone(ins.Columns)
+	}
+	if deleteBeforeInsert && isRows {
+		pkCompExpr := pkCompExpression(vTbl, ins, rows)
+		uniqKeyCompExprs := uniqKeyCompExpressions(vTbl, ins, rows)
+		whereExpr = getWhereCondExpr(append(uniqKeyCompExprs, pkCompExpr))
 	}
 
 	insOp := checkAndCreateInsertOperator(ctx, ins, vTbl, routing)
@@ -124,14 +148,14 @@ func createOperatorFromInsert(ctx *plancontext.PlanningContext, ins *sqlparser.I
 		return insOp
 	}
 
-	rows, isRows := ins.Rows.(sqlparser.Values)
 	if !isRows {
-		panic(vterrors.VT12001("REPLACE INTO using select statement"))
+		return replaceSelectPlan(ctx, ins, columns, vTbl, insOp)
 	}
 
-	pkCompExpr := pkCompExpression(vTbl, ins, rows)
-	uniqKeyCompExprs := uniqKeyCompExpressions(vTbl, ins, rows)
-	whereExpr := getWhereCondExpr(append(uniqKeyCompExprs, pkCompExpr))
+	if whereExpr == nil {
+		// none of the keys are given, so no existing row can clash with the new rows.
+		return insOp
+	}
 
 	delStmt := &sqlparser.Delete{
 		Comments:   ins.Comments,
@@ -142,40 +166,108 @@ func createOperatorFromInsert(ctx *plancontext.PlanningContext, ins *sqlparser.I
 	return &Sequential{Sources: []Operator{delOp, insOp}}
 }
 
-func checkAndCreateInsertOperator(ctx *plancontext.PlanningContext, ins *sqlparser.Insert, vTbl *vindexes.Table, routing Routing) Operator {
-	insOp := createInsertOperator(ctx, ins, vTbl, routing)
+// replaceSelectPlan plans the deletes that REPLACE INTO ... SELECT needs. The rows clashing with
+// the selected rows on the primary key or any of the unique keys are deleted before the selected
+// rows are inserted. The deletes are planned like any other delete, so they take care of foreign
+// key cascades and owned vindexes.
+func replaceSelectPlan(ctx *plancontext.PlanningContext, ins *sqlparser.Insert, columns sqlparser.Columns, vTbl *vindexes.Table, insOp Operator) Operator {
+	var insSel *InsertSelection
+	_ = Visit(insOp, func(op Operator) error {
+		if is, ok := op.(*InsertSelection); ok {
+			insSel = is
+			return io.EOF
+		}
+		return nil
+	})
+	if insSel == nil {
+		panic(vterrors.VT13001("InsertSelection not found for REPLACE INTO using select statement"))
+	}
 
-	// Find the foreign key mode and for unmanaged foreign-key-mode, we don't need to do anything.
-	ksMode, err := ctx.VSchema.ForeignKeyMode(vTbl.Keyspace.Name)
-	if err != nil {
-		panic(err)
+	keys := vTbl.UniqueKeys
+	if len(vTbl.PrimaryKey) > 0 {
+		var pk sqlparser.Exprs
+		for _, col := range vTbl.PrimaryKey {
+			pk = append(pk, sqlparser.NewColName(col.String()))
+		}
+		keys = append([]sqlparser.Exprs{pk}, keys...)
 	}
-	if ksMode != vschemapb.Keyspace_managed {
-		return insOp
+
+	for _, key := range keys {
+		offsets, cols := replaceKeyOffsets(columns, vTbl, key)
+		if offsets == nil {
+			continue
+		}
+		bvName := ctx.ReservedVars.ReserveVariable(replaceValues)
+		delStmt := &sqlparser.Delete{
+			Comments:   ins.Comments,
+			TableExprs: sqlparser.TableExprs{sqlparser.Clone(ins.Table)},
+			Where:      sqlparser.NewWhere(sqlparser.WhereClause, sqlparser.NewComparisonExpr(sqlparser.InOp, cols, sqlparser.NewListArg(bvName), nil)),
+		}
+		insSel.DeleteBeforeInsert = append(insSel.DeleteBeforeInsert, &FkChild{
+			BVName: bvName,
+			Cols:   offsets,
+			Op:     createOpFromStmt(ctx, delStmt, false, ""),
+		})
 	}
 
-	parentFKs := ctx.SemTable.GetParentForeignKeysList()
-	childFks := ctx.SemTable.GetChildForeignKeysList()
-	if len(parentFKs) > 0 {
-		panic(vterrors.VT12002())
+	if len(insSel.DeleteBeforeInsert) > 0 {
+		// we need all the rows before we start deleting, or we might delete the rows we are selecting
+		insSel.ForceNonStreaming = true
 	}
-	if len(childFks) > 0 {
-		if ins.Action == sqlparser.ReplaceAct {
-			panic(vterrors.VT12001("REPLACE INTO with foreign keys"))
+	return insOp
+}
+
+// replaceKeyOffsets returns the offsets of the key columns in the inserted rows, using the column list
+// as written by the user. It returns nil if no existing row can clash on this key.
+func replaceKeyOffsets(columns sqlparser.Columns, vTbl *vindexes.Table, key sqlparser.Exprs) ([]int, sqlparser.ValTuple) {
+	var offsets []int
+	var cols sqlparser.ValTuple
+	for _, expr := range key {
+		col, isCol := expr.(*sqlparser.ColName)
+		if !isCol {
+			panic(vterrors.VT12001("REPLACE INTO using select statement on a table with functional unique keys"))
 		}
-		if len(ins.OnDup) > 0 {
-			rows := getRowsOrError(ins)
-			return createUpsertOperator(ctx, ins, insOp, rows, vTbl)
+		idx := columns.FindColumn(col.Name)
+		if idx == -1 {
+			if findDefault(vTbl, col.Name) == nil {
+				// the column gets NULL or a new auto-increment value, which never clashes with another row
+				return nil, nil
+			}
+			panic(vterrors.VT12001(fmt.Sprintf("REPLACE INTO using select statement without the key column '%s' in the column list", col.Name.String())))
 		}
+		offsets = append(offsets, idx)
+		cols = append(cols, sqlparser.NewColName(col.Name.String()))
 	}
-	return insOp
+	return offsets, cols
 }
 
-func getRowsOrError(ins *sqlparser.Insert) sqlparser.Values {
-	if rows, ok := ins.Rows.(sqlparser.Values); ok {
-		return rows
+func checkAndCreateInsertOperator(ctx *plancontext.PlanningContext, ins *sqlparser.Insert, vTbl *vindexes.Table, routing Routing) Operator {
+	// Find the foreign key mode and for unmanaged foreign-key-mode, we don't need to do anything.
+	ksMode, err := ctx.VSchema.ForeignKeyMode(vTbl.Keyspace.Name)
+	if err != nil {
+		panic(err)
+	}
+	var childFks []vindexes.ChildFKInfo
+	if ksMode == vschemapb.Keyspace_managed {
+		parentFKs := ctx.SemTable.GetParentForeignKeysList()
+		childFks = ctx.SemTable.GetChildForeignKeysList()
+		if len(parentFKs) > 0 {
+			panic(vterrors.VT12002())
+		}
+		if len(childFks) > 0 && ins.Action == sqlparser.ReplaceAct {
+			panic(vterrors.VT12001("REPLACE INTO with foreign keys"))
+		}
+	}
+
+	if len(ins.OnDup) > 0 {
+		lookupChanged := onDupChangesLookupVindex(vTbl, ins.OnDup)
+		if len(childFks) > 0 || lookupChanged {
+			if upsert := createUpsertOperator(ctx, ins, vTbl, lookupChanged); upsert != nil {
+				return upsert
+			}
+		}
 	}
-	panic(vterrors.VT12001("ON DUPLICATE KEY UPDATE with foreign keys with select statement"))
+	return createInsertOperator(ctx, ins, vTbl, routing)
 }
 
 func getWhereCondExpr(compExprs []*sqlparser.ComparisonExpr) sqlparser.Expr {
@@ -201,6 +293,9 @@ func pkCompExpression(vTbl *vindexes.Table, ins *sqlparser.Insert, rows sqlparse
 		return nil
 	}
 	pIndexes, pColTuple := findPKIndexes(vTbl, ins)
+	if pIndexes == nil {
+		return nil
+	}
 
 	var pValTuple sqlparser.ValTuple
 	for _, row := range rows {
@@ -241,6 +336,10 @@ func findPKIndexes(vTbl *vindexes.Table, ins *sqlparser.Insert) (pIndexes []pCom
 }
 
 func findDefault(vTbl *vindexes.Table, pCol sqlparser.IdentifierCI) sqlparser.Expr {
+	if vTbl.AutoIncrement != nil && vTbl.AutoIncrement.Column.Equal(pCol) {
+		// a missing auto-increment column gets a new value, which can't clash with an existing row
+		return nil
+	}
 	for _, column := range vTbl.Columns {
 		if column.Name.Equal(pCol) {
 			return column.Default
@@ -367,16 +466,7 @@ func createInsertOperator(ctx *plancontext.PlanningContext, insStmt *sqlparser.I
 		Routing:       routing,
 	}
 
-	// Table column list is nil then add all the columns
-	// If the column list is empty then add only the auto-inc column and
-	// this happens on calling modifyForAutoinc
-	if insStmt.Columns == nil && valuesProvided(insStmt.Rows) {
-		if vTbl.ColumnListAuthoritative {
-			insStmt = populateInsertColumnlist(insStmt, vTbl)
-		} else {
-			panic(vterrors.VT09004())
-		}
-	}
+	insStmt = addMissingColumnList(insStmt, vTbl)
 
 	// modify column list or values for autoincrement column.
 	autoIncGen := modifyForAutoinc(ctx, insStmt, vTbl)
@@ -591,6 +681,19 @@ func findColumn(ins *sqlparser.Insert, col sqlparser.IdentifierCI) int {
 	return -1
 }
 
+// addMissingColumnList adds all the columns of the table when the column list is nil.
+// If the column list is empty then add only the auto-inc column and
+// this happens on calling modifyForAutoinc
+func addMissingColumnList(insStmt *sqlparser.Insert, vTbl *vindexes.Table) *sqlparser.Insert {
+	if insStmt.Columns != nil || !valuesProvided(insStmt.Rows) {
+		return insStmt
+	}
+	if !vTbl.ColumnListAuthoritative {
+		panic(vterrors.VT09004())
+	}
+	return populateInsertColumnlist(insStmt, vTbl)
+}
+
 func populateInsertColumnlist(ins *sqlparser.Insert, table *vindexes.Table) *sqlparser.Insert {
 	cols := make(sqlparser.Columns, 0, len(table.Columns))
 	for _, c := range table.Columns {
@@ -607,8 +710,11 @@ func modifyForAutoinc(ctx *plancontext.PlanningContext, ins *sqlparser.Insert, v
 		return nil
 	}
 	gen := &Generate{
-		Keyspace:  vTable.AutoIncrement.Sequence.Keyspace,
-		TableName: sqlparser.TableName{Name: vTable.AutoIncrement.Sequence.Name},
+		Mode: vTable.AutoIncrement.Mode,
+	}
+	if seq := vTable.AutoIncrement.Sequence; seq != nil {
+		gen.Keyspace = seq.Keyspace
+		gen.TableName = sqlparser.TableName{Name: seq.Name}
 	}
 	colNum, newColAdded := findOrAddColumn(ins, vTable.AutoIncrement.Column)
 	switch rows := ins.Rows.(type) {
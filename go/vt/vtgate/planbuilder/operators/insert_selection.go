@@ -17,6 +17,8 @@ limitations under the License.
 package operators
 
 import (
+	"slices"
+
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 )
 
@@ -28,6 +30,9 @@ type InsertSelection struct {
 	// ForceNonStreaming when true, select first then insert, this is to avoid locking rows by select for insert.
 	ForceNonStreaming bool
 
+	// DeleteBeforeInsert are the deletes REPLACE needs to remove the existing rows that clash with the selected rows
+	DeleteBeforeInsert []*FkChild
+
 	noColumns
 	noPredicates
 }
@@ -38,9 +43,32 @@ func (is *InsertSelection) Clone(inputs []Operator) Operator {
 	klone := *is
 	klone.LHS = inputs[0]
 	klone.RHS = inputs[1]
+	klone.DeleteBeforeInsert = nil
+	for idx, del := range is.DeleteBeforeInsert {
+		klone.DeleteBeforeInsert = append(klone.DeleteBeforeInsert, &FkChild{
+			BVName: del.BVName,
+			Cols:   slices.Clone(del.Cols),
+			Op:     inputs[idx+2],
+		})
+	}
 	return &klone
 }
 
+func (is *InsertSelection) Inputs() []Operator {
+	inputs := []Operator{is.LHS, is.RHS}
+	for _, del := range is.DeleteBeforeInsert {
+		inputs = append(inputs, del.Op)
+	}
+	return inputs
+}
+
+func (is *InsertSelection) SetInputs(inputs []Operator) {
+	is.LHS, is.RHS = inputs[0], inputs[1]
+	for idx, del := range is.DeleteBeforeInsert {
+		del.Op = inputs[idx+2]
+	}
+}
+
 func (is *InsertSelection) ShortDescription() string {
 	if is.ForceNonStreaming {
 		return "NonStreaming"
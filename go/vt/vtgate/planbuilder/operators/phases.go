@@ -38,6 +38,7 @@ const (
 	delegateAggregation
 	recursiveCTEHorizons
 	addAggrOrdering
+	addWindowOrdering
 	cleanOutPerfDistinct
 	dmlWithInput
 	subquerySettling
@@ -58,6 +59,8 @@ func (p Phase) String() string {
 		return "expand recursive CTE horizons"
 	case addAggrOrdering:
 		return "optimize aggregations with ORDER BY"
+	case addWindowOrdering:
+		return "add ordering for window functions"
 	case cleanOutPerfDistinct:
 		return "optimize Distinct operations"
 	case subquerySettling:
@@ -79,6 +82,8 @@ func (p Phase) shouldRun(s semantics.QuerySignature) bool {
 		return s.RecursiveCTE
 	case addAggrOrdering:
 		return s.Aggregation
+	case addWindowOrdering:
+		return s.Window
 	case cleanOutPerfDistinct:
 		return s.Distinct
 	case subquerySettling:
@@ -98,6 +103,8 @@ func (p Phase) act(ctx *plancontext.PlanningContext, op Operator) Operator {
 		return enableDelegateAggregation(ctx, op)
 	case addAggrOrdering:
 		return addOrderingForAllAggregations(ctx, op)
+	case addWindowOrdering:
+		return addOrderingForAllWindows(ctx, op)
 	case recursiveCTEHorizons:
 		return planRecursiveCTEHorizons(ctx, op)
 	case cleanOutPerfDistinct:
@@ -170,6 +177,17 @@ func createDMLWithInput(ctx *plancontext.PlanningContext, op, src Operator, in *
 		}
 		return nil
 	})
+
+	// with a LIMIT, the rows picked by the input are the rows the DML will change,
+	// so we lock them the same way MySQL would have done when running the DML on its own
+	if hasLimit(src) {
+		_ = Visit(src, func(operator Operator) error {
+			if rb, ok := operator.(*Route); ok && TableID(rb).IsOverlapping(in.Target.ID) {
+				rb.Lock = sqlparser.ForUpdateLock.GetHighestOrderLock(rb.Lock)
+			}
+			return nil
+		})
+	}
 	if targetTable == nil {
 		panic(vterrors.VT13001("target DELETE table not found"))
 	}
@@ -202,6 +220,16 @@ func createDMLWithInput(ctx *plancontext.PlanningContext, op, src Operator, in *
 	return dm, Rewrote("changed Delete to DMLWithInput")
 }
 
+// hasLimit returns true if the DML input picks a limited number of rows
+func hasLimit(src Operator) bool {
+	return Visit(src, func(operator Operator) error {
+		if _, ok := operator.(*Limit); ok {
+			return io.EOF
+		}
+		return nil
+	}) != nil
+}
+
 func removePerformanceDistinctAboveRoute(_ *plancontext.PlanningContext, op Operator) Operator {
 	return BottomUp(op, TableID, func(innerOp Operator, _ semantics.TableSet, _ bool) (Operator, *ApplyResult) {
 		d, ok := innerOp.(*Distinct)
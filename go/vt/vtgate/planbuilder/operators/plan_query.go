@@ -158,11 +158,23 @@ func tryTruncateColumnsAt(op Operator, truncateAt int) bool {
 	case *Limit:
 		return tryTruncateColumnsAt(op.Source, truncateAt)
 	case *SubQuery:
+		if op.isApplyArgument() {
+			// the subquery value is the first column, so the outer columns can't be truncated
+			return false
+		}
 		for _, offset := range op.Vars {
 			if offset >= truncateAt {
 				return false
 			}
 		}
+		for _, offset := range op.predicateOffsets {
+			if offset >= truncateAt {
+				return false
+			}
+		}
+		if op.BatchVar != "" && op.BatchOuterKey >= truncateAt {
+			return false
+		}
 		return tryTruncateColumnsAt(op.Outer, truncateAt)
 	default:
 		return false
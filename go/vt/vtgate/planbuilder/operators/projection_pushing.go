@@ -130,6 +130,11 @@ func pushProjectionToOuter(ctx *plancontext.PlanningContext, p *Projection, sq *
 		return p, NoRewrite
 	}
 
+	if p.DT != nil && sq.correlated {
+		// the columns the subquery needs from the outer side would be hidden inside the derived table
+		return p, NoRewrite
+	}
+
 	outer := TableID(sq.Outer)
 	for _, pe := range ap {
 		_, isOffset := pe.Info.(Offset)
@@ -137,6 +142,11 @@ func pushProjectionToOuter(ctx *plancontext.PlanningContext, p *Projection, sq *
 			continue
 		}
 
+		if sq.usesValueColumn(pe.EvalExpr) {
+			// the subquery value is only available on top of the subquery
+			return p, NoRewrite
+		}
+
 		if !ctx.SemTable.RecursiveDeps(pe.EvalExpr).IsSolvedBy(outer) {
 			return p, NoRewrite
 		}
@@ -172,6 +182,11 @@ func pushProjectionToOuterContainer(ctx *plancontext.PlanningContext, p *Project
 		return p, NoRewrite
 	}
 
+	if p.DT != nil && slices.ContainsFunc(src.Inner, func(sq *SubQuery) bool { return sq.correlated }) {
+		// the columns the subqueries need from the outer side would be hidden inside the derived table
+		return p, NoRewrite
+	}
+
 	outer := TableID(src.Outer)
 	for _, pe := range ap {
 		_, isOffset := pe.Info.(Offset)
@@ -86,6 +86,8 @@ func runRewriters(ctx *plancontext.PlanningContext, root Operator) Operator {
 			return tryPushOrdering(ctx, in)
 		case *Aggregator:
 			return tryPushAggregator(ctx, in)
+		case *Window:
+			return tryPushWindow(ctx, in)
 		case *Filter:
 			return tryPushFilter(ctx, in)
 		case *Distinct:
@@ -207,6 +209,7 @@ func pushOrExpandHorizon(ctx *plancontext.PlanningContext, in *Horizon) (Operato
 		!hasHaving &&
 		!needsOrdering &&
 		!qp.NeedsAggregation() &&
+		(!qp.HasWindow || partitionedByUniqueVindex(ctx, qp.windowFunctions())) &&
 		!in.selectStatement().IsDistinct() &&
 		in.selectStatement().GetLimit() == nil
 
@@ -221,7 +224,7 @@ func tryPushLimit(ctx *plancontext.PlanningContext, in *Limit) (Operator, *Apply
 	switch src := in.Source.(type) {
 	case *Route:
 		return tryPushingDownLimitInRoute(ctx, in, src)
-	case *Aggregator:
+	case *Aggregator, *Window:
 		return in, NoRewrite
 	case *ApplyJoin:
 		if in.Pushed {
@@ -485,6 +488,9 @@ func setUpperLimit(in *Limit) (Operator, *ApplyResult) {
 		case *Join, *ApplyJoin, *SubQueryContainer, *SubQuery:
 			// we can't push limits down on either side
 			return SkipChildren
+		case *Window:
+			// window functions need to see all the rows of the partition
+			return SkipChildren
 		case *Aggregator:
 			if len(op.Grouping) > 0 {
 				// we can't push limits down if we have a group by
@@ -521,6 +527,10 @@ func tryPushOrdering(ctx *plancontext.PlanningContext, in *Ordering) (Operator,
 	case *Projection:
 		return pushOrderingUnderProjection(ctx, in, src)
 	case *Aggregator:
+		if src.WithRollup {
+			// the super-aggregate rows are produced by the aggregator, so they have to be sorted after it
+			return in, NoRewrite
+		}
 		if !src.QP.AlignGroupByAndOrderBy(ctx) && !overlaps(ctx, in.Order, src.Grouping) {
 			return in, NoRewrite
 		}
@@ -545,6 +555,10 @@ func pushOrderingToOuterOfSubqueryContainer(ctx *plancontext.PlanningContext, in
 }
 
 func pushOrderingUnderProjection(ctx *plancontext.PlanningContext, in *Ordering, proj *Projection) (Operator, *ApplyResult) {
+	if proj.DT != nil {
+		// the ordering uses the columns of the derived table, which are not available below it
+		return in, NoRewrite
+	}
 	// we can move ordering under a projection if it's not introducing a column we're sorting by
 	for _, by := range in.Order {
 		if !mustFetchFromInput(ctx, by.SimplifiedExpr) {
@@ -693,7 +707,7 @@ func tryPushFilter(ctx *plancontext.PlanningContext, in *Filter) (Operator, *App
 		outerTableID := TableID(src.Outer)
 		for _, pred := range in.Predicates {
 			deps := ctx.SemTable.RecursiveDeps(pred)
-			if !deps.IsSolvedBy(outerTableID) {
+			if !deps.IsSolvedBy(outerTableID) || src.usesValueColumn(pred) {
 				return in, NoRewrite
 			}
 		}
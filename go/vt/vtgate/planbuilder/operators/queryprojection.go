@@ -42,6 +42,7 @@ type (
 		// If you change the contents here, please update the toString() method
 		SelectExprs  []SelectExpr
 		HasAggr      bool
+		HasWindow    bool
 		Distinct     bool
 		WithRollup   bool
 		groupByExprs []GroupBy
@@ -85,9 +86,34 @@ type (
 		SubQueryExpression []*SubQuery // Subqueries associated with this aggregation
 
 		PushedDown bool // Whether the aggregation has been pushed down to the next layer
+
+		// GroupConcatArgs and GroupConcatOrder are only used for GROUP_CONCAT when it is
+		// computed at the vtgate from the individual values of each group
+		GroupConcatArgs  []GroupConcatColumn
+		GroupConcatOrder []GroupConcatColumn
+	}
+
+	// GroupConcatColumn is an argument or an ORDER BY expression of GROUP_CONCAT
+	GroupConcatColumn struct {
+		Expr sqlparser.Expr
+		Desc bool
+
+		// Offsets pointing to columns within the same aggregator
+		ColOffset int
+		WSOffset  int
 	}
 )
 
+// needsGroupConcatValues returns true for a GROUP_CONCAT that can't be computed
+// by concatenating the partial results of the shards
+func (aggr Aggr) needsGroupConcatValues() bool {
+	if aggr.OpCode != opcode.AggregateGroupConcat {
+		return false
+	}
+	f := aggr.Func.(*sqlparser.GroupConcatExpr)
+	return f.Distinct || len(f.OrderBy) > 0
+}
+
 func (aggr Aggr) NeedsWeightString(ctx *plancontext.PlanningContext) bool {
 	return aggr.OpCode.NeedsComparableValues() && ctx.NeedsWeightString(aggr.Func.GetArg())
 }
@@ -186,6 +212,9 @@ func (qp *QueryProjection) addSelectExpressions(ctx *plancontext.PlanningContext
 				col.Aggr = true
 				qp.HasAggr = true
 			}
+			if sqlparser.ContainsWindowFunction(selExp.Expr) {
+				qp.HasWindow = true
+			}
 
 			qp.SelectExprs = append(qp.SelectExprs, col)
 		case *sqlparser.StarExpr:
@@ -370,6 +399,18 @@ func (qp *QueryProjection) NeedsAggregation() bool {
 	return qp.HasAggr || len(qp.groupByExprs) > 0
 }
 
+// windowFunctions returns all the window functions used in the SELECT and ORDER BY expressions
+func (qp *QueryProjection) windowFunctions() []sqlparser.Expr {
+	var nodes []sqlparser.SQLNode
+	for _, se := range qp.SelectExprs {
+		nodes = append(nodes, se.Col)
+	}
+	for _, order := range qp.OrderExprs {
+		nodes = append(nodes, order.SimplifiedExpr)
+	}
+	return findWindowFunctions(nodes...)
+}
+
 func (qp *QueryProjection) onlyAggr() bool {
 	if !qp.HasAggr {
 		return false
@@ -415,10 +456,18 @@ func (qp *QueryProjection) AggregationExpressions(ctx *plancontext.PlanningConte
 			if err != nil {
 				panic(err)
 			}
-			if !qp.isExprInGroupByExprs(ctx, getExpr) {
-				aggr := NewAggr(opcode.AggregateAnyValue, nil, aliasedExpr, aliasedExpr.ColumnName())
-				out = append(out, aggr)
+			if qp.isExprInGroupByExprs(ctx, getExpr) {
+				continue
+			}
+			if qp.WithRollup && allowComplexExpression && qp.containsGroupByExpr(ctx, getExpr) {
+				// the grouping keys are NULL in the super-aggregate rows, so the expression
+				// has to be evaluated on top of the aggregation instead of being aggregated
+				makeComplex()
+				sqlparser.CopyOnRewrite(getExpr, qp.extractAggr(ctx, aliasedExpr, addAggr, makeComplex), nil, nil)
+				continue
 			}
+			aggr := NewAggr(opcode.AggregateAnyValue, nil, aliasedExpr, aliasedExpr.ColumnName())
+			out = append(out, aggr)
 			continue
 		}
 		if !ctx.IsAggr(aliasedExpr.Expr) && !allowComplexExpression {
@@ -461,14 +510,36 @@ func (qp *QueryProjection) extractAggr(
 			makeComplex()
 			return true
 		}
-		if !qp.isExprInGroupByExprs(ctx, ex) {
-			aggr := NewAggr(opcode.AggregateAnyValue, nil, aeWrap(ex), "")
-			addAggr(aggr)
+		if qp.isExprInGroupByExprs(ctx, ex) {
+			return false
+		}
+		if qp.WithRollup && qp.containsGroupByExpr(ctx, ex) {
+			// only the other parts of the expression are aggregated, the grouping keys
+			// are taken from the aggregation so they are NULL in the super-aggregate rows
+			return true
 		}
+		aggr := NewAggr(opcode.AggregateAnyValue, nil, aeWrap(ex), "")
+		addAggr(aggr)
 		return false
 	}
 }
 
+// containsGroupByExpr returns true if the expression uses one of the grouping expressions
+func (qp *QueryProjection) containsGroupByExpr(ctx *plancontext.PlanningContext, expr sqlparser.Expr) bool {
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if found {
+			return false, nil
+		}
+		if e, ok := node.(sqlparser.Expr); ok && qp.isExprInGroupByExprs(ctx, e) {
+			found = true
+			return false, nil
+		}
+		return true, nil
+	}, expr)
+	return found
+}
+
 func (qp *QueryProjection) addOrderByToSelect(ctx *plancontext.PlanningContext) {
 orderBy:
 	// We need to return all columns that are being used for ordering
@@ -664,6 +664,8 @@ func addWSColumnToInput(ctx *plancontext.PlanningContext, source Operator, offse
 		return true, op.AddWSColumn(ctx, offset, true)
 	case *Aggregator:
 		return true, op.AddWSColumn(ctx, offset, true)
+	case *Window:
+		return true, op.AddWSColumn(ctx, offset, true)
 	}
 	return false, -1
 }
@@ -18,6 +18,7 @@ package operators
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -25,6 +26,7 @@ import (
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 	"vitess.io/vitess/go/vt/vtgate/semantics"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
@@ -101,7 +103,7 @@ func buildVindexTableForDML(
 
 	tblName, ok := table.Alias.Expr.(sqlparser.TableName)
 	if !ok {
-		panic(vterrors.VT12001("multi shard UPDATE with LIMIT"))
+		panic(vterrors.VT13001(fmt.Sprintf("the target of a %s is not a table: %s", dmlType, sqlparser.String(table.Alias.Expr))))
 	}
 
 	_, _, _, typ, dest, err := ctx.VSchema.FindTableOrVindex(tblName)
@@ -312,6 +314,10 @@ func mergeOrJoin(ctx *plancontext.PlanningContext, lhs, rhs Operator, joinPredic
 		return join, Rewrote("logical join to applyJoin, switching side because LIMIT")
 	}
 
+	if hj := tryHashJoin(ctx, lhs, rhs, joinPredicates, joinType); hj != nil {
+		return hj, Rewrote("use a hash join because it needs fewer round-trips than a nested loop join")
+	}
+
 	join := NewApplyJoin(ctx, Clone(lhs), Clone(rhs), nil, joinType)
 	for _, pred := range joinPredicates {
 		join.AddJoinPredicate(ctx, pred)
@@ -320,6 +326,68 @@ func mergeOrJoin(ctx *plancontext.PlanningContext, lhs, rhs Operator, joinPredic
 	return join, Rewrote("logical join to applyJoin ")
 }
 
+// roundTripCost is how many rows we think it's worth reading to save one query to the shards
+const roundTripCost = 100
+
+// tryHashJoin returns a hash join between the two sides if the query allows hash joins,
+// and the estimated row counts say that it will be cheaper than a nested loop join.
+// A nested loop join sends one query to the RHS for every row coming from the LHS, while
+// the hash join reads the RHS once, and does the matching at the vtgate level.
+func tryHashJoin(ctx *plancontext.PlanningContext, lhs, rhs Operator, joinPredicates []sqlparser.Expr, joinType sqlparser.JoinType) *HashJoin {
+	if len(joinPredicates) == 0 || !allowHashJoin(ctx) {
+		return nil
+	}
+	if !joinType.IsInner() && joinType != sqlparser.LeftJoinType {
+		return nil
+	}
+	if EstimateRows(rhs) >= EstimateRows(lhs)*roundTripCost {
+		return nil
+	}
+
+	join := NewHashJoin(Clone(lhs), Clone(rhs), !joinType.IsInner())
+	for _, pred := range joinPredicates {
+		join.AddJoinPredicate(ctx, pred)
+	}
+	if len(join.JoinComparisons) == 0 {
+		// without equality comparisons, all we have is a cross product
+		return nil
+	}
+	for _, cmp := range join.JoinComparisons {
+		if !canHashOn(ctx, cmp) {
+			return nil
+		}
+	}
+
+	ctx.SemTable.QuerySignature.HashJoin = true
+	return join
+}
+
+// allowHashJoin returns true when the SELECT query has the ALLOW_HASH_JOIN comment directive
+func allowHashJoin(ctx *plancontext.PlanningContext) bool {
+	if _, isSelect := ctx.Statement.(sqlparser.SelectStatement); !isSelect {
+		return false
+	}
+	cmt, ok := ctx.Statement.(sqlparser.Commented)
+	if !ok {
+		return false
+	}
+	return cmt.GetParsedComments().Directives().IsSet(sqlparser.DirectiveAllowHashJoin)
+}
+
+// canHashOn checks that we have the type information needed to hash both sides of the comparison
+func canHashOn(ctx *plancontext.PlanningContext, cmp Comparison) bool {
+	ltyp, found := ctx.TypeForExpr(cmp.LHS)
+	if !found {
+		return false
+	}
+	rtyp, found := ctx.TypeForExpr(cmp.RHS)
+	if !found {
+		return false
+	}
+	_, err := evalengine.CoerceTypes(ltyp, rtyp, ctx.VSchema.Environment().CollationEnv())
+	return err == nil
+}
+
 func operatorsToRoutes(a, b Operator) (*Route, *Route) {
 	aRoute, ok := a.(*Route)
 	if !ok {
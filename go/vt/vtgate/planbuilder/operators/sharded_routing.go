@@ -22,6 +22,7 @@ import (
 
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
@@ -229,6 +230,10 @@ func (tr *ShardedRouting) searchForNewVindexes(ctx *plancontext.PlanningContext,
 	case *sqlparser.IsExpr:
 		found := tr.planIsExpr(ctx, node)
 		newVindexFound = newVindexFound || found
+
+	case *sqlparser.BetweenExpr:
+		found := tr.planBetweenOp(ctx, node)
+		newVindexFound = newVindexFound || found
 	}
 
 	return nil, newVindexFound
@@ -245,7 +250,9 @@ func (tr *ShardedRouting) planComparison(ctx *plancontext.PlanningContext, cmp *
 	case sqlparser.LikeOp:
 		found := tr.planLikeOp(ctx, cmp)
 		return nil, found
-
+	case sqlparser.LessThanOp, sqlparser.LessEqualOp, sqlparser.GreaterThanOp, sqlparser.GreaterEqualOp:
+		found := tr.planRangeOp(ctx, cmp)
+		return nil, found
 	}
 	return nil, false
 }
@@ -324,6 +331,129 @@ func (tr *ShardedRouting) planLikeOp(ctx *plancontext.PlanningContext, node *sql
 	return tr.haveMatchingVindex(ctx, node, vdValue, column, val, selectEqual, vdx)
 }
 
+func (tr *ShardedRouting) planBetweenOp(ctx *plancontext.PlanningContext, node *sqlparser.BetweenExpr) bool {
+	column, ok := node.Left.(*sqlparser.ColName)
+	if !ok || !node.IsBetween {
+		return false
+	}
+	return tr.haveMatchingRangeVindex(ctx, node, column, node.From, node.To)
+}
+
+func (tr *ShardedRouting) planRangeOp(ctx *plancontext.PlanningContext, node *sqlparser.ComparisonExpr) bool {
+	// With the column on the left, `>` and `>=` bound the start of the range, and `<` and `<=` its end.
+	isStart := node.Operator == sqlparser.GreaterThanOp || node.Operator == sqlparser.GreaterEqualOp
+	column, ok := node.Left.(*sqlparser.ColName)
+	vdValue := node.Right
+	if !ok {
+		column, ok = node.Right.(*sqlparser.ColName)
+		if !ok {
+			// either the LHS or RHS have to be a column to be useful for the vindex
+			return false
+		}
+		vdValue = node.Left
+		isStart = !isStart
+	}
+
+	if isStart {
+		return tr.haveMatchingRangeVindex(ctx, node, column, vdValue, nil)
+	}
+	return tr.haveMatchingRangeVindex(ctx, node, column, nil, vdValue)
+}
+
+// haveMatchingRangeVindex adds a Range option to every RangeMapper vindex on the given column.
+// A nil from or to leaves that side of the range open.
+func (tr *ShardedRouting) haveMatchingRangeVindex(
+	ctx *plancontext.PlanningContext,
+	node sqlparser.Expr,
+	column *sqlparser.ColName,
+	from, to sqlparser.Expr,
+) bool {
+	if !rangeComparable(ctx, column) {
+		return false
+	}
+
+	values := []evalengine.Expr{evalengine.NullExpr, evalengine.NullExpr}
+	var valueExprs []sqlparser.Expr
+	for i, expr := range []sqlparser.Expr{from, to} {
+		if expr == nil {
+			continue
+		}
+		values[i] = makeEvalEngineExpr(ctx, expr)
+		if values[i] == nil {
+			return false
+		}
+		valueExprs = append(valueExprs, expr)
+	}
+
+	newVindexFound := false
+	for _, v := range tr.VindexPreds {
+		if !ctx.SemTable.DirectDeps(column).IsSolvedBy(v.TableID) {
+			continue
+		}
+		if _, ok := v.ColVindex.Vindex.(vindexes.RangeMapper); !ok || !column.Name.Equal(v.ColVindex.Columns[0]) {
+			continue
+		}
+
+		option := &VindexOption{
+			Values:      values,
+			ValueExprs:  valueExprs,
+			Predicates:  []sqlparser.Expr{node},
+			OpCode:      engine.Range,
+			FoundVindex: v.ColVindex.Vindex,
+			Cost:        costFor(v.ColVindex, engine.Range),
+			Ready:       true,
+		}
+		var combined []*VindexOption
+		for _, other := range v.Options {
+			if other.OpCode != engine.Range {
+				continue
+			}
+			if c := combineRangeOptions(option, other); c != nil {
+				combined = append(combined, c)
+			}
+		}
+		// The combined options go last, so that they win over the open ranges they were built from.
+		v.Options = append(v.Options, option)
+		v.Options = append(v.Options, combined...)
+		newVindexFound = true
+	}
+	return newVindexFound
+}
+
+// combineRangeOptions returns a range option bounded by the start of one option and the end
+// of the other, when both are open on the other side, as in `id >= 10 and id < 20`.
+func combineRangeOptions(a, b *VindexOption) *VindexOption {
+	if isOpenRangeBound(a.Values[0]) {
+		a, b = b, a
+	}
+	if isOpenRangeBound(a.Values[0]) || !isOpenRangeBound(a.Values[1]) || !isOpenRangeBound(b.Values[0]) || isOpenRangeBound(b.Values[1]) {
+		return nil
+	}
+	return &VindexOption{
+		Values:      []evalengine.Expr{a.Values[0], b.Values[1]},
+		ValueExprs:  append(slices.Clone(a.ValueExprs), b.ValueExprs...),
+		Predicates:  append(slices.Clone(a.Predicates), b.Predicates...),
+		OpCode:      engine.Range,
+		FoundVindex: a.FoundVindex,
+		Cost:        a.Cost,
+		Ready:       true,
+	}
+}
+
+func isOpenRangeBound(value evalengine.Expr) bool {
+	return value == evalengine.NullExpr
+}
+
+// rangeComparable returns false when the column is known to be a string with a non-binary
+// collation, because MySQL does not compare its values in the order of their keyspace ids.
+func rangeComparable(ctx *plancontext.PlanningContext, column *sqlparser.ColName) bool {
+	typ, found := ctx.TypeForExpr(column)
+	if !found || typ.Type() == sqltypes.Unknown {
+		return true
+	}
+	return !sqltypes.IsText(typ.Type()) || typ.Collation() == collations.CollationBinaryID
+}
+
 func (tr *ShardedRouting) Cost() int {
 	switch tr.RouteOpCode {
 	case engine.EqualUnique:
@@ -334,6 +464,8 @@ func (tr *ShardedRouting) Cost() int {
 		return 10
 	case engine.MultiEqual:
 		return 10
+	case engine.Range:
+		return 15
 	case engine.Scatter:
 		return 20
 	default:
@@ -662,7 +794,7 @@ func tryMergeShardedRouting(
 		// can merge via join predicates instead.
 		fallthrough
 
-	case engine.Scatter, engine.IN, engine.None:
+	case engine.Scatter, engine.IN, engine.Range, engine.None:
 		if len(joinPredicates) == 0 {
 			// If we are doing two Scatters, we have to make sure that the
 			// joins are on the correct vindex to allow them to be merged
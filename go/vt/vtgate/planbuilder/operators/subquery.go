@@ -18,13 +18,16 @@ package operators
 
 import (
 	"fmt"
+	"io"
 	"maps"
 	"slices"
 
 	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 	"vitess.io/vitess/go/vt/vtgate/semantics"
 )
@@ -55,9 +58,51 @@ type SubQuery struct {
 
 	// IsArgument is set to true if the subquery puts the
 	IsArgument bool
+
+	// Fields related to correlated subqueries that are evaluated for every row of the outer query:
+	Apply bool // Apply is set when the subquery can't be merged or planned as a semi-join.
+	// ApplyPredicate is the predicate that decides which outer rows to keep, using the subquery result.
+	// It is nil when the subquery is used as a value, in which case the value is added as the first column.
+	ApplyPredicate            sqlparser.Expr
+	ApplyPredicateWithOffsets evalengine.Expr
+	predicateOffsets          []int // the outer columns used by ApplyPredicate, set during offset planning
+	// outerRefs is set when the subquery references the outer query outside of its predicates.
+	outerRefs bool
+
+	// When BatchVar is set, the outer rows are sent to the subquery in a single tuple bind variable,
+	// and the subquery rows are matched back to the outer rows using the two key columns.
+	BatchVar                     string
+	BatchOuterKey, BatchInnerKey int
+	BatchType                    evalengine.Type
+	batchOuter, batchInner       sqlparser.Expr
 }
 
 func (sq *SubQuery) planOffsets(ctx *plancontext.PlanningContext) Operator {
+	if sq.ApplyPredicate != nil {
+		cfg := &evalengine.Config{
+			ResolveType: ctx.TypeForExpr,
+			Collation:   ctx.SemTable.Collation,
+			Environment: ctx.VSchema.Environment(),
+		}
+		rewritten := useOffsets(ctx, sq.ApplyPredicate, sq)
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			if offset, ok := node.(*sqlparser.Offset); ok {
+				sq.predicateOffsets = append(sq.predicateOffsets, offset.V)
+			}
+			return true, nil
+		}, rewritten)
+		eexpr, err := evalengine.Translate(rewritten, cfg)
+		if err != nil {
+			panic(err)
+		}
+		sq.ApplyPredicateWithOffsets = eexpr
+	}
+	if sq.BatchVar != "" {
+		sq.BatchOuterKey = sq.Outer.AddColumn(ctx, true, false, aeWrap(sq.batchOuter))
+		sq.BatchInnerKey = sq.Subquery.AddColumn(ctx, true, false, aeWrap(sq.batchInner))
+		return nil
+	}
+
 	sq.Vars = make(map[string]int)
 	columns, err := sq.GetJoinColumns(ctx, sq.Outer)
 	if err != nil {
@@ -126,6 +171,7 @@ func (sq *SubQuery) Clone(inputs []Operator) Operator {
 	klone.JoinColumns = slices.Clone(sq.JoinColumns)
 	klone.Vars = maps.Clone(sq.Vars)
 	klone.Predicates = sqlparser.Clone(sq.Predicates)
+	klone.predicateOffsets = slices.Clone(sq.predicateOffsets)
 	return &klone
 }
 
@@ -162,6 +208,9 @@ func (sq *SubQuery) ShortDescription() string {
 	} else {
 		typ = "FILTER"
 	}
+	if sq.Apply {
+		typ = "APPLY " + typ
+	}
 	var pred string
 
 	if len(sq.Predicates) > 0 || sq.OuterPredicate != nil {
@@ -177,6 +226,12 @@ func (sq *SubQuery) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparse
 }
 
 func (sq *SubQuery) AddColumn(ctx *plancontext.PlanningContext, reuseExisting bool, addToGroupBy bool, ae *sqlparser.AliasedExpr) int {
+	if sq.isApplyArgument() {
+		if sq.isValueColumn(ae.Expr) {
+			return 0
+		}
+		return sq.Outer.AddColumn(ctx, reuseExisting, addToGroupBy, ae) + 1
+	}
 	ae = sqlparser.Clone(ae)
 	// we need to rewrite the column name to an argument if it's the same as the subquery column name
 	ae.Expr = rewriteColNameToArgument(ctx, ae.Expr, []*SubQuery{sq}, sq)
@@ -184,21 +239,76 @@ func (sq *SubQuery) AddColumn(ctx *plancontext.PlanningContext, reuseExisting bo
 }
 
 func (sq *SubQuery) AddWSColumn(ctx *plancontext.PlanningContext, offset int, underRoute bool) int {
+	if sq.isApplyArgument() {
+		if offset == 0 {
+			panic(vterrors.VT12001("weight_string of a correlated subquery value"))
+		}
+		return sq.Outer.AddWSColumn(ctx, offset-1, underRoute) + 1
+	}
 	return sq.Outer.AddWSColumn(ctx, offset, underRoute)
 }
 
 func (sq *SubQuery) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, underRoute bool) int {
+	if sq.isApplyArgument() {
+		if sq.isValueColumn(expr) {
+			return 0
+		}
+		offset := sq.Outer.FindCol(ctx, expr, underRoute)
+		if offset < 0 {
+			return offset
+		}
+		return offset + 1
+	}
 	return sq.Outer.FindCol(ctx, expr, underRoute)
 }
 
 func (sq *SubQuery) GetColumns(ctx *plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	if sq.isApplyArgument() {
+		return append([]*sqlparser.AliasedExpr{aeWrap(sqlparser.NewColName(sq.ArgName))}, sq.Outer.GetColumns(ctx)...)
+	}
 	return sq.Outer.GetColumns(ctx)
 }
 
 func (sq *SubQuery) GetSelectExprs(ctx *plancontext.PlanningContext) sqlparser.SelectExprs {
+	if sq.isApplyArgument() {
+		return transformColumnsToSelectExprs(ctx, sq)
+	}
 	return sq.Outer.GetSelectExprs(ctx)
 }
 
+// isApplyArgument returns true when the subquery is evaluated for every outer row,
+// and its value is added as the first column of the outer rows
+func (sq *SubQuery) isApplyArgument() bool {
+	return sq.Apply && sq.IsArgument
+}
+
+// isValueColumn returns true if the expression is the column that represents the subquery value
+func (sq *SubQuery) isValueColumn(expr sqlparser.Expr) bool {
+	switch expr := expr.(type) {
+	case *sqlparser.ColName:
+		return expr.Qualifier.IsEmpty() && expr.Name.String() == sq.ArgName
+	case *sqlparser.Argument:
+		return expr.Name == sq.ArgName
+	}
+	return false
+}
+
+// usesValueColumn returns true if the expression needs the value column of an apply subquery
+func (sq *SubQuery) usesValueColumn(expr sqlparser.Expr) bool {
+	if !sq.isApplyArgument() {
+		return false
+	}
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if expr, ok := node.(sqlparser.Expr); ok && sq.isValueColumn(expr) {
+			found = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, expr)
+	return found
+}
+
 // GetMergePredicates returns the predicates that we can use to try to merge this subquery with the outer query.
 func (sq *SubQuery) GetMergePredicates() []sqlparser.Expr {
 	if sq.OuterPredicate != nil {
@@ -208,13 +318,10 @@ func (sq *SubQuery) GetMergePredicates() []sqlparser.Expr {
 }
 
 func (sq *SubQuery) settle(ctx *plancontext.PlanningContext, outer Operator) Operator {
-	// We can allow uncorrelated queries even when subquery isn't the top level construct,
-	// like if its underneath an Aggregator, because they will be pulled out and run separately.
-	if !sq.TopLevel && sq.correlated {
-		panic(subqueryNotAtTopErr)
-	}
-	if sq.correlated && sq.FilterType != opcode.PulloutExists {
-		panic(correlatedSubqueryErr)
+	if sq.correlated && !(sq.TopLevel && !sq.IsArgument && sq.FilterType == opcode.PulloutExists) {
+		// correlated EXISTS at the top level are planned as semi-joins,
+		// all other correlated subqueries are evaluated for every outer row
+		return sq.settleApply(ctx, outer)
 	}
 	if sq.IsArgument {
 		if len(sq.GetMergePredicates()) > 0 {
@@ -227,9 +334,166 @@ func (sq *SubQuery) settle(ctx *plancontext.PlanningContext, outer Operator) Ope
 	return sq.settleFilter(ctx, outer)
 }
 
-var correlatedSubqueryErr = vterrors.VT12001("correlated subquery is only supported for EXISTS")
+var correlatedSubqueryErr = vterrors.VT12001("correlated subquery that references the outer query outside of its predicates")
+var correlatedListSubqueryErr = vterrors.VT12001("correlated IN subquery used as a value")
 var subqueryNotAtTopErr = vterrors.VT12001("unmergable subquery can not be inside complex expression")
 
+// settleApply plans a correlated subquery that is executed for the rows of the outer query
+func (sq *SubQuery) settleApply(ctx *plancontext.PlanningContext, outer Operator) Operator {
+	if len(sq.Predicates) == 0 || sq.outerRefs {
+		panic(correlatedSubqueryErr)
+	}
+	for _, pred := range sq.Predicates {
+		if sqlparser.ContainsAggregation(pred) {
+			panic(correlatedSubqueryErr)
+		}
+	}
+	if sq.IsArgument && sq.FilterType.NeedsListArg() {
+		panic(correlatedListSubqueryErr)
+	}
+	if countSubqueries(sq.Original) > 1 {
+		panic(subqueryNotAtTopErr)
+	}
+
+	sq.Apply = true
+	if sq.IsArgument {
+		sq.SubqueryValueName = sq.ArgName
+	} else {
+		sq.ApplyPredicate = sq.rewriteOriginal(ctx)
+		if sq.FilterType != opcode.PulloutExists && sq.FilterType != opcode.PulloutNotExists {
+			sq.SubqueryValueName = sq.ArgName
+		}
+	}
+	if sq.FilterType == opcode.PulloutNotExists {
+		sq.FilterType = opcode.PulloutExists
+	}
+
+	if !sq.planBatch(ctx, outer) && sq.FilterType == opcode.PulloutExists {
+		sq.addLimit()
+	}
+	return outer
+}
+
+// planBatch checks if the outer rows can be sent to the subquery all at once.
+// This is possible when the subquery is a single route that is correlated to the outer query
+// through a single equality between columns. The equality is then turned into an IN over a tuple bind variable.
+func (sq *SubQuery) planBatch(ctx *plancontext.PlanningContext, outer Operator) bool {
+	joinColumns, err := sq.GetJoinColumns(ctx, outer)
+	if err != nil || len(joinColumns) != 1 || len(sq.Predicates) != 1 {
+		return false
+	}
+	cmp, ok := sq.Predicates[0].(*sqlparser.ComparisonExpr)
+	if !ok || cmp.Operator != sqlparser.EqualOp {
+		return false
+	}
+	route, ok := sq.Subquery.(*Route)
+	if !ok || !canBatchRoute(route) {
+		return false
+	}
+
+	innerID, outerID := TableID(sq.Subquery), TableID(outer)
+	innerCol, outerCol := cmp.Left, cmp.Right
+	if !ctx.SemTable.RecursiveDeps(innerCol).IsSolvedBy(innerID) {
+		innerCol, outerCol = outerCol, innerCol
+	}
+	if _, ok := innerCol.(*sqlparser.ColName); !ok || !ctx.SemTable.RecursiveDeps(innerCol).IsSolvedBy(innerID) {
+		return false
+	}
+	if _, ok := outerCol.(*sqlparser.ColName); !ok || !ctx.SemTable.RecursiveDeps(outerCol).IsSolvedBy(outerID) {
+		return false
+	}
+
+	innerType, found := ctx.TypeForExpr(innerCol)
+	if !found {
+		return false
+	}
+	outerType, found := ctx.TypeForExpr(outerCol)
+	if !found {
+		return false
+	}
+	typ, err := evalengine.CoerceTypes(outerType, innerType, ctx.VSchema.Environment().CollationEnv())
+	if err != nil || typ.Type() == sqltypes.Unknown {
+		// without a known type, we can't match the subquery rows back to the outer rows
+		return false
+	}
+
+	batchVar := ctx.ReservedVars.ReserveVariable(string(sqlparser.BatchSubQueryBaseName))
+	batchPred := &sqlparser.ComparisonExpr{
+		Operator: sqlparser.InOp,
+		Left:     innerCol,
+		Right:    sqlparser.NewListArg(batchVar),
+	}
+	if !replacePredicateInRoute(ctx, route, joinColumns[0].RHSExpr, batchPred) {
+		return false
+	}
+
+	sq.BatchVar = batchVar
+	sq.BatchType = typ
+	sq.batchOuter = outerCol
+	sq.batchInner = innerCol
+	return true
+}
+
+// canBatchRoute returns true if the rows of the route can be matched back to the outer rows using a column
+func canBatchRoute(route *Route) bool {
+	err := Visit(route.Source, func(op Operator) error {
+		switch op.(type) {
+		case *Filter, *Projection, *Table, *Join, *Ordering:
+			return nil
+		}
+		return io.EOF
+	})
+	return err == nil
+}
+
+// replacePredicateInRoute replaces the predicate inside the route with a new one, and updates the routing of the route
+func replacePredicateInRoute(ctx *plancontext.PlanningContext, route *Route, old, new sqlparser.Expr) bool {
+	replaced := false
+	replace := func(predicates []sqlparser.Expr) {
+		for i, pred := range predicates {
+			if ctx.SemTable.EqualsExpr(pred, old) {
+				predicates[i] = new
+				replaced = true
+			}
+		}
+	}
+	_ = Visit(route.Source, func(op Operator) error {
+		switch op := op.(type) {
+		case *Filter:
+			replace(op.Predicates)
+		case *Table:
+			replace(op.QTable.Predicates)
+		}
+		return nil
+	})
+	if !replaced {
+		return false
+	}
+
+	if tr, ok := route.Routing.(*ShardedRouting); ok {
+		for i, pred := range tr.SeenPredicates {
+			if ctx.SemTable.EqualsExpr(pred, old) {
+				tr.SeenPredicates[i] = new
+			}
+		}
+		route.Routing = tr.resetRoutingLogic(ctx)
+	}
+	return true
+}
+
+// countSubqueries returns the number of subqueries found in the expression, without entering them
+func countSubqueries(expr sqlparser.Expr) int {
+	count := 0
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if _, ok := node.(*sqlparser.Subquery); ok {
+			count++
+			return false, nil
+		}
+		return true, nil
+	}, expr)
+	return count
+}
+
 func (sq *SubQuery) addLimit() {
 	// for a correlated subquery, we can add a limit 1 to the subquery
 	sq.Subquery = newLimit(sq.Subquery, &sqlparser.Limit{Rowcount: sqlparser.NewIntLiteral("1")}, true)
@@ -244,50 +508,17 @@ func (sq *SubQuery) settleFilter(ctx *plancontext.PlanningContext, outer Operato
 		return outer
 	}
 
-	hasValuesArg := func() string {
-		s := ctx.ReservedVars.ReserveVariable(string(sqlparser.HasValueSubQueryBaseName))
-		sq.HasValuesName = s
-		return s
-	}
-	post := func(cursor *sqlparser.CopyOnWriteCursor) {
-		node := cursor.Node()
-		// For IN and NOT IN type filters, we have to add a Expression that checks if we got any rows back or not
-		// for correctness. That expression should be ANDed with the expression that has the IN/NOT IN comparison.
-		if compExpr, isCompExpr := node.(*sqlparser.ComparisonExpr); sq.FilterType.NeedsListArg() && isCompExpr {
-			if listArg, isListArg := compExpr.Right.(sqlparser.ListArg); isListArg && listArg.String() == sq.ArgName {
-				if sq.FilterType == opcode.PulloutIn {
-					cursor.Replace(sqlparser.AndExpressions(sqlparser.NewArgument(hasValuesArg()), compExpr))
-				} else {
-					cursor.Replace(&sqlparser.OrExpr{
-						Left:  sqlparser.NewNotExpr(sqlparser.NewArgument(hasValuesArg())),
-						Right: compExpr,
-					})
-				}
-			}
-		}
-		if _, ok := node.(*sqlparser.Subquery); !ok {
-			return
-		}
-
-		var arg sqlparser.Expr
-		if sq.FilterType.NeedsListArg() {
-			arg = sqlparser.NewListArg(sq.ArgName)
-		} else {
-			arg = sqlparser.NewArgument(sq.ArgName)
-		}
-		cursor.Replace(arg)
-	}
-	rhsPred := sqlparser.CopyOnRewrite(sq.Original, dontEnterSubqueries, post, ctx.SemTable.CopySemanticInfo).(sqlparser.Expr)
+	rhsPred := sq.rewriteOriginal(ctx)
 
 	var predicates []sqlparser.Expr
 	switch sq.FilterType {
 	case opcode.PulloutExists:
 		sq.addLimit()
-		predicates = append(predicates, sqlparser.NewArgument(hasValuesArg()))
+		predicates = append(predicates, sqlparser.NewArgument(sq.hasValuesArg(ctx)))
 	case opcode.PulloutNotExists:
 		sq.addLimit()
 		sq.FilterType = opcode.PulloutExists // it's the same pullout as EXISTS, just with a NOT in front of the predicate
-		predicates = append(predicates, sqlparser.NewNotExpr(sqlparser.NewArgument(hasValuesArg())))
+		predicates = append(predicates, sqlparser.NewNotExpr(sqlparser.NewArgument(sq.hasValuesArg(ctx))))
 	case opcode.PulloutIn:
 		// Because we replace the comparison expression with an AND expression, it might be the top level construct there.
 		// In this case, it is better to send the two sides of the AND expression separately in the predicates because it can
@@ -309,6 +540,50 @@ func (sq *SubQuery) settleFilter(ctx *plancontext.PlanningContext, outer Operato
 	return newFilter(outer, predicates...)
 }
 
+func (sq *SubQuery) hasValuesArg(ctx *plancontext.PlanningContext) string {
+	s := ctx.ReservedVars.ReserveVariable(string(sqlparser.HasValueSubQueryBaseName))
+	sq.HasValuesName = s
+	return s
+}
+
+// rewriteOriginal returns the original expression with the subquery replaced by the arguments holding its result
+func (sq *SubQuery) rewriteOriginal(ctx *plancontext.PlanningContext) sqlparser.Expr {
+	post := func(cursor *sqlparser.CopyOnWriteCursor) {
+		node := cursor.Node()
+		// For IN and NOT IN type filters, we have to add a Expression that checks if we got any rows back or not
+		// for correctness. That expression should be ANDed with the expression that has the IN/NOT IN comparison.
+		if compExpr, isCompExpr := node.(*sqlparser.ComparisonExpr); sq.FilterType.NeedsListArg() && isCompExpr {
+			if listArg, isListArg := compExpr.Right.(sqlparser.ListArg); isListArg && listArg.String() == sq.ArgName {
+				if sq.FilterType == opcode.PulloutIn {
+					cursor.Replace(sqlparser.AndExpressions(sqlparser.NewArgument(sq.hasValuesArg(ctx)), compExpr))
+				} else {
+					cursor.Replace(&sqlparser.OrExpr{
+						Left:  sqlparser.NewNotExpr(sqlparser.NewArgument(sq.hasValuesArg(ctx))),
+						Right: compExpr,
+					})
+				}
+			}
+		}
+		if _, ok := node.(*sqlparser.ExistsExpr); ok && sq.Apply {
+			// when evaluating the subquery per row, the EXISTS is answered by the has_values argument
+			cursor.Replace(sqlparser.NewArgument(sq.hasValuesArg(ctx)))
+			return
+		}
+		if _, ok := node.(*sqlparser.Subquery); !ok {
+			return
+		}
+
+		var arg sqlparser.Expr
+		if sq.FilterType.NeedsListArg() {
+			arg = sqlparser.NewListArg(sq.ArgName)
+		} else {
+			arg = sqlparser.NewArgument(sq.ArgName)
+		}
+		cursor.Replace(arg)
+	}
+	return sqlparser.CopyOnRewrite(sq.Original, dontEnterSubqueries, post, ctx.SemTable.CopySemanticInfo).(sqlparser.Expr)
+}
+
 func dontEnterSubqueries(node, _ sqlparser.SQLNode) bool {
 	if _, ok := node.(*sqlparser.Subquery); ok {
 		return false
@@ -17,6 +17,8 @@ limitations under the License.
 package operators
 
 import (
+	"io"
+
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
@@ -165,11 +167,14 @@ func createSubquery(
 	original = cloneASTAndSemState(ctx, original)
 	originalSq := cloneASTAndSemState(ctx, subq)
 	subqID := findTablesContained(ctx, subq.Select)
+	// for nested subqueries, the outer tables we get passed in also contain the tables of this subquery
+	outerID = outerID.Remove(subqID)
 	totalID := subqID.Merge(outerID)
 	sqc := &SubQueryBuilder{totalID: totalID, subqID: subqID, outerID: outerID}
 
 	predicates, joinCols := sqc.inspectStatement(ctx, subq.Select)
 	correlated := !ctx.SemTable.RecursiveDeps(subq).IsEmpty()
+	outerRefs := correlated && sqc.hasOuterReferences(ctx, subq.Select)
 
 	opInner := translateQueryToOp(ctx, subq.Select)
 
@@ -185,7 +190,35 @@ func createSubquery(
 		TopLevel:         topLevel,
 		JoinColumns:      joinCols,
 		correlated:       correlated,
+		outerRefs:        outerRefs,
+	}
+}
+
+// hasOuterReferences returns true if the subquery still uses columns from the outer query,
+// once the predicates connecting the two have been replaced by arguments
+func (sqb *SubQueryBuilder) hasOuterReferences(ctx *plancontext.PlanningContext, stmt sqlparser.SelectStatement) bool {
+	for _, inner := range sqb.Inner {
+		for _, pred := range inner.Predicates {
+			if !ctx.SemTable.RecursiveDeps(pred).IsSolvedBy(sqb.subqID) {
+				return true
+			}
+		}
 	}
+
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		col, ok := node.(*sqlparser.ColName)
+		if !ok {
+			return true, nil
+		}
+		deps := ctx.SemTable.RecursiveDeps(col)
+		if !deps.IsEmpty() && !deps.IsSolvedBy(sqb.subqID) {
+			found = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, stmt)
+	return found
 }
 
 func (sqb *SubQueryBuilder) inspectWhere(
@@ -43,6 +43,10 @@ func isMergeable(ctx *plancontext.PlanningContext, query sqlparser.SelectStateme
 	switch node := query.(type) {
 	case *sqlparser.Select:
 		if node.GroupBy != nil && len(node.GroupBy.Exprs) > 0 {
+			if node.GroupBy.WithRollup {
+				// the super-aggregate rows are computed over all the shards
+				return false
+			}
 			// iff we are grouping, we need to check that we can perform the grouping inside a single shard, and we check that
 			// by checking that one of the grouping expressions used is a unique single column vindex.
 			// TODO: we could also support the case where all the columns of a multi-column vindex are used in the grouping
@@ -64,6 +68,10 @@ func isMergeable(ctx *plancontext.PlanningContext, query sqlparser.SelectStateme
 			return false
 		}
 
+		if windowFuncs := findWindowFunctions(node.SelectExprs); len(windowFuncs) > 0 && !partitionedByUniqueVindex(ctx, windowFuncs) {
+			return false
+		}
+
 		return true
 	case *sqlparser.Union:
 		return isMergeable(ctx, node.Left, op) && isMergeable(ctx, node.Right, op)
@@ -77,7 +85,7 @@ func settleSubqueries(ctx *plancontext.PlanningContext, op Operator) Operator {
 		switch op := op.(type) {
 		case *SubQueryContainer:
 			outer := op.Outer
-			for _, subq := range op.Inner {
+			for _, subq := range applyArgumentsFirst(op.Inner) {
 				subq.Outer = subq.settle(ctx, outer)
 				outer = subq
 			}
@@ -112,6 +120,21 @@ func settleSubqueries(ctx *plancontext.PlanningContext, op Operator) Operator {
 	return BottomUp(op, TableID, visit, nil)
 }
 
+// applyArgumentsFirst orders the subqueries so that the correlated subqueries used as values are settled first.
+// These are evaluated for every outer row and add their value as a column, so projections using them can't be pushed
+// below them. Keeping them at the bottom allows the other subqueries to still bind their arguments for such projections.
+func applyArgumentsFirst(subqueries []*SubQuery) []*SubQuery {
+	var first, rest []*SubQuery
+	for _, subq := range subqueries {
+		if subq.correlated && subq.IsArgument {
+			first = append(first, subq)
+		} else {
+			rest = append(rest, subq)
+		}
+	}
+	return append(first, rest...)
+}
+
 func (o *Ordering) settleOrderingExpressions(ctx *plancontext.PlanningContext) {
 	for idx, order := range o.Order {
 		for _, sq := range ctx.MergedSubqueries {
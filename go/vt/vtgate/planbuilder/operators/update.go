@@ -129,6 +129,11 @@ func updateWithInputPlanningRequired(
 	if isMultiTargetUpdate(ctx, updateStmt) {
 		return true
 	}
+	// The owned vindexes have to be updated for exactly the rows picked by the limit,
+	// so we select their primary keys first.
+	if updateStmt.Limit != nil && isVindexUpdate(ctx, updateStmt) {
+		return true
+	}
 	// If there are no foreign keys, we don't need to use delete with input.
 	if len(childFks) == 0 && len(parentFks) == 0 {
 		return false
@@ -140,6 +145,26 @@ func updateWithInputPlanningRequired(
 	return false
 }
 
+// isVindexUpdate returns true if the update changes any of the vindex columns of the target table.
+func isVindexUpdate(ctx *plancontext.PlanningContext, updateStmt *sqlparser.Update) bool {
+	ti, err := ctx.SemTable.TableInfoFor(ctx.SemTable.Targets)
+	if err != nil {
+		return false
+	}
+	vTbl := ti.GetVindexTable()
+	if vTbl == nil {
+		return false
+	}
+	for _, ue := range updateStmt.Exprs {
+		for _, cv := range vTbl.ColumnVindexes {
+			if slices.ContainsFunc(cv.Columns, ue.Name.Name.Equal) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func isMultiTargetUpdate(ctx *plancontext.PlanningContext, updateStmt *sqlparser.Update) bool {
 	var targetTS semantics.TableSet
 	for _, ue := range updateStmt.Exprs {
@@ -172,7 +197,8 @@ func createUpdateWithInputOp(ctx *plancontext.PlanningContext, upd *sqlparser.Up
 	updOps = sortDmlOps(updOps)
 
 	selectStmt := &sqlparser.Select{
-		From:    updClone.TableExprs,
+		// the table expressions are not cloned, so that derived tables keep their table id in the semantic table
+		From:    upd.TableExprs,
 		Where:   updClone.Where,
 		OrderBy: updClone.OrderBy,
 		Limit:   updClone.Limit,
@@ -249,6 +275,9 @@ func createUpdateOpWithTarget(ctx *plancontext.PlanningContext, updStmt *sqlpars
 		panic(vterrors.VT13001(err.Error()))
 	}
 	vTbl := ti.GetVindexTable()
+	if len(vTbl.PrimaryKey) == 0 {
+		panic(vterrors.VT09015())
+	}
 	tblName, err := ti.Name()
 	if err != nil {
 		panic(err)
@@ -1037,12 +1066,13 @@ func buildChangedVindexesValues(
 ) (changedVindexes map[string]*engine.VindexValues, ovq *sqlparser.Select, subQueriesArgOnChangedVindex []string) {
 	changedVindexes = make(map[string]*engine.VindexValues)
 	selExprs, offset := initialQuery(ksidCols, table)
+	var nonLiterals []nonLiteralVindexValue
 	for i, vindex := range table.ColumnVindexes {
 		vindexValueMap := make(map[string]evalengine.Expr)
 		var compExprs []sqlparser.Expr
 		for _, vcol := range vindex.Columns {
-			subQueriesArgOnChangedVindex, compExprs =
-				createAssignmentExpressions(ctx, assignments, vcol, subQueriesArgOnChangedVindex, vindexValueMap, compExprs)
+			subQueriesArgOnChangedVindex, compExprs, nonLiterals =
+				createAssignmentExpressions(ctx, assignments, vcol, subQueriesArgOnChangedVindex, vindexValueMap, compExprs, nonLiterals)
 		}
 		if len(vindexValueMap) == 0 {
 			// Vindex not changing, continue
@@ -1066,6 +1096,13 @@ func buildChangedVindexesValues(
 	if len(changedVindexes) == 0 {
 		return nil, nil, nil
 	}
+	// the new values that vtgate can't evaluate on its own are selected from the rows being updated
+	for _, nl := range nonLiterals {
+		typ, _ := ctx.TypeForExpr(nl.expr)
+		nl.vindexValueMap[nl.col] = evalengine.NewColumn(offset, typ, nl.expr)
+		selExprs = append(selExprs, aeWrap(nl.expr))
+		offset++
+	}
 	// generate rest of the owned vindex query.
 	ovq = &sqlparser.Select{
 		SelectExprs: selExprs,
@@ -1092,6 +1129,14 @@ func initialQuery(ksidCols []sqlparser.IdentifierCI, table *vindexes.Table) (sql
 	return selExprs, offset
 }
 
+// nonLiteralVindexValue is a new vindex column value that depends on the row being updated,
+// like `name = concat(name, 'x')`. It is evaluated by MySQL as part of the owned vindex query.
+type nonLiteralVindexValue struct {
+	vindexValueMap map[string]evalengine.Expr
+	col            string
+	expr           sqlparser.Expr
+}
+
 func createAssignmentExpressions(
 	ctx *plancontext.PlanningContext,
 	assignments []SetExpr,
@@ -1099,7 +1144,8 @@ func createAssignmentExpressions(
 	subQueriesArgOnChangedVindex []string,
 	vindexValueMap map[string]evalengine.Expr,
 	compExprs []sqlparser.Expr,
-) ([]string, []sqlparser.Expr) {
+	nonLiterals []nonLiteralVindexValue,
+) ([]string, []sqlparser.Expr, []nonLiteralVindexValue) {
 	// Searching in order of columns in colvindex.
 	found := false
 	for _, assignment := range assignments {
@@ -1116,7 +1162,12 @@ func createAssignmentExpressions(
 			Environment: ctx.VSchema.Environment(),
 		})
 		if err != nil {
-			panic(invalidUpdateExpr(assignment.Name.Name.String(), assignment.Expr.EvalExpr))
+			// the new value depends on the row, so it will be read from the owned vindex query
+			nonLiterals = append(nonLiterals, nonLiteralVindexValue{
+				vindexValueMap: vindexValueMap,
+				col:            vcol.String(),
+				expr:           assignment.Expr.EvalExpr,
+			})
 		}
 
 		if assignment.Expr.Info != nil {
@@ -1131,9 +1182,5 @@ func createAssignmentExpressions(
 		vindexValueMap[vcol.String()] = pv
 		compExprs = append(compExprs, sqlparser.NewComparisonExpr(sqlparser.EqualOp, assignment.Name, assignment.Expr.EvalExpr, nil))
 	}
-	return subQueriesArgOnChangedVindex, compExprs
-}
-
-func invalidUpdateExpr(upd string, expr sqlparser.Expr) error {
-	return vterrors.VT12001(fmt.Sprintf("only values are supported; invalid update on column: `%s` with expr: [%s]", upd, sqlparser.String(expr)))
+	return subQueriesArgOnChangedVindex, compExprs, nonLiterals
 }
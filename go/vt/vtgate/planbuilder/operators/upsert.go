@@ -17,8 +17,11 @@ limitations under the License.
 package operators
 
 import (
+	"slices"
+
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
@@ -29,41 +32,59 @@ var _ Operator = (*Upsert)(nil)
 type Upsert struct {
 	Sources []UpsertSource
 
+	// Rows and Input are set when a single UpsertSource is planned for all the rows, using the
+	// bind variables in BindVars for the values of the row. The rows are either the evaluated
+	// Rows expressions, or the rows returned by Input.
+	Rows     [][]evalengine.Expr
+	Input    Operator
+	BindVars []string
+
 	noColumns
 	noPredicates
 }
 
 type UpsertSource struct {
+	// Check, if set, is used to find out if the row already exists before trying to insert it.
+	Check  Operator
 	Insert Operator
 	Update Operator
 }
 
 func (u *Upsert) Clone(inputs []Operator) Operator {
-	up := &Upsert{}
-	up.setInputs(inputs)
-	return up
-}
-
-func (u *Upsert) setInputs(inputs []Operator) {
-	for i := 0; i < len(inputs); i += 2 {
-		u.Sources = append(u.Sources, UpsertSource{
-			Insert: inputs[i],
-			Update: inputs[i+1],
-		})
+	up := &Upsert{
+		Sources:  slices.Clone(u.Sources),
+		Rows:     u.Rows,
+		Input:    u.Input,
+		BindVars: u.BindVars,
 	}
+	up.SetInputs(inputs)
+	return up
 }
 
 func (u *Upsert) Inputs() []Operator {
 	var inputs []Operator
+	if u.Input != nil {
+		inputs = append(inputs, u.Input)
+	}
 	for _, source := range u.Sources {
 		inputs = append(inputs, source.Insert, source.Update)
+		if source.Check != nil {
+			inputs = append(inputs, source.Check)
+		}
 	}
 	return inputs
 }
 
 func (u *Upsert) SetInputs(inputs []Operator) {
-	u.Sources = nil
-	u.setInputs(inputs)
+	if u.Input != nil {
+		u.Input, inputs = inputs[0], inputs[1:]
+	}
+	for i := range u.Sources {
+		u.Sources[i].Insert, u.Sources[i].Update, inputs = inputs[0], inputs[1], inputs[2:]
+		if u.Sources[i].Check != nil {
+			u.Sources[i].Check, inputs = inputs[0], inputs[1:]
+		}
+	}
 }
 
 func (u *Upsert) ShortDescription() string {
@@ -74,20 +95,142 @@ func (u *Upsert) GetOrdering(ctx *plancontext.PlanningContext) []OrderBy {
 	return nil
 }
 
-func createUpsertOperator(ctx *plancontext.PlanningContext, ins *sqlparser.Insert, insOp Operator, rows sqlparser.Values, vTbl *vindexes.Table) Operator {
-	if len(vTbl.UniqueKeys) != 0 {
-		panic(vterrors.VT12001("ON DUPLICATE KEY UPDATE with foreign keys with unique keys"))
-	}
-
+// createUpsertOperator plans the INSERT ... ON DUPLICATE KEY UPDATE as an insert, followed by an update of
+// the existing row when the insert fails on a duplicate key. Planning the update on its own lets it cascade
+// to the child foreign keys and maintain the lookup vindexes, which MySQL can't do for us.
+// When checkFirst is set, we look for the existing row before inserting, so that a failing insert doesn't
+// leave behind the lookup vindex entries it created.
+// It returns nil when there is no key we can use to find the existing row.
+func createUpsertOperator(ctx *plancontext.PlanningContext, ins *sqlparser.Insert, vTbl *vindexes.Table, checkFirst bool) Operator {
+	ins = addMissingColumnList(ins, vTbl)
 	pIndexes, _ := findPKIndexes(vTbl, ins)
-	if len(pIndexes) == 0 {
+	if len(pIndexes) == 0 && len(uniqKeyCompExpressions(vTbl, ins, nil)) == 0 {
 		// nothing to compare for update.
 		// Hence, only perform insert.
-		return insOp
+		return nil
 	}
 
 	upsert := &Upsert{}
+	var rows sqlparser.Values
+	switch insRows := ins.Rows.(type) {
+	case sqlparser.Values:
+		rows = insRows
+		if exprs, ok := translateUpsertRows(ctx, insRows); ok {
+			upsert.Rows = exprs
+			rows = sqlparser.Values{upsert.reserveBindVars(ctx, ins)}
+		}
+	case sqlparser.SelectStatement:
+		if columnMismatch(nil, ins, insRows) {
+			panic(vterrors.VT03006())
+		}
+		selOp, err := PlanQuery(ctx, insRows)
+		if err != nil {
+			panic(err)
+		}
+		upsert.Input = newLockAndComment(selOp, nil, sqlparser.ShareModeLock)
+		rows = sqlparser.Values{upsert.reserveBindVars(ctx, ins)}
+	}
+
 	for _, row := range rows {
+		upsert.Sources = append(upsert.Sources, createUpsertSource(ctx, ins, vTbl, row, checkFirst))
+	}
+	return upsert
+}
+
+// translateUpsertRows translates the values of the rows to evalengine expressions, so that all the rows can
+// share the same plan. It returns false if any of the values can't be evaluated by vtgate.
+func translateUpsertRows(ctx *plancontext.PlanningContext, rows sqlparser.Values) ([][]evalengine.Expr, bool) {
+	cfg := &evalengine.Config{
+		ResolveType: ctx.TypeForExpr,
+		Collation:   ctx.SemTable.Collation,
+		Environment: ctx.VSchema.Environment(),
+	}
+	exprs := make([][]evalengine.Expr, 0, len(rows))
+	for _, row := range rows {
+		rowExprs := make([]evalengine.Expr, 0, len(row))
+		for _, val := range row {
+			expr, err := evalengine.Translate(val, cfg)
+			if err != nil {
+				return nil, false
+			}
+			rowExprs = append(rowExprs, expr)
+		}
+		exprs = append(exprs, rowExprs)
+	}
+	return exprs, true
+}
+
+// reserveBindVars reserves a bind variable for each of the inserted columns,
+// and returns the row that uses them
+func (u *Upsert) reserveBindVars(ctx *plancontext.PlanningContext, ins *sqlparser.Insert) sqlparser.ValTuple {
+	var row sqlparser.ValTuple
+	for _, col := range ins.Columns {
+		bvName := ctx.ReservedVars.ReserveVariable(upsertValues + "_" + col.Lowered())
+		u.BindVars = append(u.BindVars, bvName)
+		row = append(row, sqlparser.NewArgument(bvName))
+	}
+	return row
+}
+
+func createUpsertSource(ctx *plancontext.PlanningContext, ins *sqlparser.Insert, vTbl *vindexes.Table, row sqlparser.ValTuple, checkFirst bool) UpsertSource {
+	whereExpr := upsertCondition(vTbl, ins, row)
+
+	var updExprs sqlparser.UpdateExprs
+	for _, ue := range ins.OnDup {
+		expr := sqlparser.CopyOnRewrite(ue.Expr, nil, func(cursor *sqlparser.CopyOnWriteCursor) {
+			vfExpr, ok := cursor.Node().(*sqlparser.ValuesFuncExpr)
+			if !ok {
+				return
+			}
+			idx := ins.Columns.FindColumn(vfExpr.Name.Name)
+			if idx == -1 {
+				panic(vterrors.VT03014(sqlparser.String(vfExpr.Name), "field list"))
+			}
+			cursor.Replace(row[idx])
+		}, nil).(sqlparser.Expr)
+		updExprs = append(updExprs, &sqlparser.UpdateExpr{
+			Name: ue.Name,
+			Expr: expr,
+		})
+	}
+
+	upd := &sqlparser.Update{
+		Comments:   ins.Comments,
+		TableExprs: sqlparser.TableExprs{ins.Table},
+		Exprs:      updExprs,
+		Where:      sqlparser.NewWhere(sqlparser.WhereClause, whereExpr),
+	}
+	updOp := createOpFromStmt(ctx, upd, false, "")
+
+	// replan insert statement without on duplicate key update.
+	newInsert := sqlparser.Clone(ins)
+	newInsert.OnDup = nil
+	newInsert.Rows = sqlparser.Values{row}
+	insOp := createOpFromStmt(ctx, newInsert, false, "")
+
+	source := UpsertSource{
+		Insert: insOp,
+		Update: updOp,
+	}
+	if checkFirst {
+		sel := &sqlparser.Select{
+			SelectExprs: sqlparser.SelectExprs{aeWrap(sqlparser.NewIntLiteral("1"))},
+			From:        sqlparser.TableExprs{sqlparser.Clone(ins.Table)},
+			Where:       sqlparser.NewWhere(sqlparser.WhereClause, sqlparser.Clone(whereExpr)),
+			Lock:        sqlparser.ForUpdateLock,
+		}
+		source.Check = createOpFromStmt(ctx, sel, false, "")
+	}
+	return source
+}
+
+// upsertCondition returns the predicate that finds the existing row that clashes with the inserted row,
+// on the primary key or on any of the unique keys.
+// When the row clashes with different rows on different keys, MySQL only updates one of them, while we update all of them.
+func upsertCondition(vTbl *vindexes.Table, ins *sqlparser.Insert, row sqlparser.ValTuple) sqlparser.Expr {
+	var whereExpr sqlparser.Expr
+	pIndexes, _ := findPKIndexes(vTbl, ins)
+	if len(pIndexes) > 0 {
 		var comparisons []sqlparser.Expr
 		for _, pIdx := range pIndexes {
 			var expr sqlparser.Expr
@@ -99,45 +242,47 @@ func createUpsertOperator(ctx *plancontext.PlanningContext, ins *sqlparser.Inser
 			comparisons = append(comparisons,
 				sqlparser.NewComparisonExpr(sqlparser.EqualOp, sqlparser.NewColName(pIdx.col.String()), expr, nil))
 		}
-		whereExpr := sqlparser.AndExpressions(comparisons...)
-
-		var updExprs sqlparser.UpdateExprs
-		for _, ue := range ins.OnDup {
-			expr := sqlparser.CopyOnRewrite(ue.Expr, nil, func(cursor *sqlparser.CopyOnWriteCursor) {
-				vfExpr, ok := cursor.Node().(*sqlparser.ValuesFuncExpr)
-				if !ok {
-					return
-				}
-				idx := ins.Columns.FindColumn(vfExpr.Name.Name)
-				if idx == -1 {
-					panic(vterrors.VT03014(sqlparser.String(vfExpr.Name), "field list"))
-				}
-				cursor.Replace(row[idx])
-			}, nil).(sqlparser.Expr)
-			updExprs = append(updExprs, &sqlparser.UpdateExpr{
-				Name: ue.Name,
-				Expr: expr,
-			})
+		whereExpr = sqlparser.AndExpressions(comparisons...)
+	}
+	for _, ukComp := range uniqKeyCompExpressions(vTbl, ins, sqlparser.Values{row}) {
+		if whereExpr == nil {
+			whereExpr = ukComp
+			continue
 		}
+		whereExpr = &sqlparser.OrExpr{Left: whereExpr, Right: ukComp}
+	}
+	return whereExpr
+}
 
-		upd := &sqlparser.Update{
-			Comments:   ins.Comments,
-			TableExprs: sqlparser.TableExprs{ins.Table},
-			Exprs:      updExprs,
-			Where:      sqlparser.NewWhere(sqlparser.WhereClause, whereExpr),
+// onDupChangesLookupVindex returns true if ON DUPLICATE KEY UPDATE changes a column of an owned lookup vindex.
+// MySQL can't keep the lookup vindex entries up to date, so we have to plan the update ourselves.
+// Changes to the primary vindex are left to the insert planning, which rejects them.
+func onDupChangesLookupVindex(vTbl *vindexes.Table, onDup sqlparser.OnDup) bool {
+	if !vTbl.Keyspace.Sharded || len(vTbl.ColumnVindexes) == 0 {
+		return false
+	}
+	assigned := func(col sqlparser.IdentifierCI) bool {
+		for _, ue := range onDup {
+			if ue.Name.Name.Equal(col) {
+				return true
+			}
 		}
-		updOp := createOpFromStmt(ctx, upd, false, "")
-
-		// replan insert statement without on duplicate key update.
-		newInsert := sqlparser.Clone(ins)
-		newInsert.OnDup = nil
-		newInsert.Rows = sqlparser.Values{row}
-		insOp = createOpFromStmt(ctx, newInsert, false, "")
-		upsert.Sources = append(upsert.Sources, UpsertSource{
-			Insert: insOp,
-			Update: updOp,
-		})
+		return false
 	}
-
-	return upsert
+	for _, col := range vTbl.ColumnVindexes[0].Columns {
+		if assigned(col) {
+			return false
+		}
+	}
+	for _, colVindex := range vTbl.ColumnVindexes[1:] {
+		if !colVindex.Owned {
+			continue
+		}
+		for _, col := range colVindex.Columns {
+			if assigned(col) {
+				return true
+			}
+		}
+	}
+	return false
 }
@@ -0,0 +1,579 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
+)
+
+type (
+	// Window represents the evaluation of window functions.
+	// When it can't be pushed under a route, all the functions it evaluates must share
+	// the same window specification, and the input must be sorted by the
+	// PARTITION BY expressions followed by the window ORDER BY expressions.
+	Window struct {
+		unaryOperator
+		Columns []*sqlparser.AliasedExpr
+
+		Functions []WindowFunc
+
+		// Spec is the window specification of the first window function.
+		Spec        *sqlparser.WindowSpecification
+		PartitionBy []GroupBy
+		OrderBy     []GroupBy
+
+		offsetPlanned bool
+
+		// ResultColumns signals how many columns will be produced by this operator
+		// This is used to truncate the columns in the final result
+		ResultColumns int
+
+		// Truncate is set to true if the columns produced by this operator should be truncated if we added any additional columns
+		Truncate bool
+
+		DT *DerivedTable
+	}
+
+	// WindowFunc encodes all information needed to evaluate a window function at the vtgate
+	WindowFunc struct {
+		Original *sqlparser.AliasedExpr
+		Func     sqlparser.Expr
+		OpCode   opcode.WindowOpcode
+
+		// N is the number of rows LAG and LEAD look behind or ahead
+		N int
+		// Default is the default value of LAG and LEAD, if one was specified
+		Default sqlparser.Expr
+
+		// Offsets pointing to columns within the same operator
+		ColOffset     int
+		DefaultOffset int
+	}
+)
+
+func newWindowFunc(ae *sqlparser.AliasedExpr, colOffset int) WindowFunc {
+	wf := WindowFunc{
+		Original:      ae,
+		Func:          ae.Expr,
+		N:             1,
+		ColOffset:     colOffset,
+		DefaultOffset: -1,
+	}
+
+	switch fn := ae.Expr.(type) {
+	case *sqlparser.ArgumentLessWindowExpr:
+		switch fn.Type {
+		case sqlparser.RowNumberExprType:
+			wf.OpCode = opcode.WindowRowNumber
+		case sqlparser.RankExprType:
+			wf.OpCode = opcode.WindowRank
+		case sqlparser.DenseRankExprType:
+			wf.OpCode = opcode.WindowDenseRank
+		}
+	case *sqlparser.LagLeadExpr:
+		if fn.NullTreatmentClause != nil {
+			return wf
+		}
+		if fn.N != nil {
+			lit, ok := fn.N.(*sqlparser.Literal)
+			if !ok || lit.Type != sqlparser.IntVal {
+				return wf
+			}
+			n, err := strconv.Atoi(lit.Val)
+			if err != nil {
+				return wf
+			}
+			wf.N = n
+		}
+		wf.Default = fn.Default
+		wf.OpCode = opcode.WindowLag
+		if fn.Type == sqlparser.LeadExprType {
+			wf.OpCode = opcode.WindowLead
+		}
+	case *sqlparser.Sum:
+		if !fn.Distinct {
+			wf.OpCode = opcode.WindowSum
+		}
+	case *sqlparser.Avg:
+		if !fn.Distinct {
+			wf.OpCode = opcode.WindowAvg
+		}
+	case *sqlparser.Count:
+		if !fn.Distinct && len(fn.Args) == 1 {
+			wf.OpCode = opcode.WindowCount
+		}
+	case *sqlparser.CountStar:
+		wf.OpCode = opcode.WindowCountStar
+	}
+	return wf
+}
+
+// getPushColumn returns the expression the window function needs from the input.
+// Functions that don't take an argument still need a column in the input, since
+// this is where the result of the function will be written.
+func (wf WindowFunc) getPushColumn() sqlparser.Expr {
+	switch fn := wf.Func.(type) {
+	case *sqlparser.LagLeadExpr:
+		return fn.Expr
+	case *sqlparser.Sum:
+		return fn.Arg
+	case *sqlparser.Avg:
+		return fn.Arg
+	case *sqlparser.Count:
+		return fn.Args[0]
+	default:
+		return sqlparser.NewIntLiteral("1")
+	}
+}
+
+func createWindowFromSelect(ctx *plancontext.PlanningContext, qp *QueryProjection, dt *DerivedTable, horizon *Horizon) Operator {
+	w := &Window{
+		unaryOperator: newUnaryOp(horizon.src()),
+		DT:            dt,
+		Truncate:      horizon.Truncate,
+	}
+
+	for _, se := range qp.SelectExprs {
+		ae, err := se.GetAliasedExpr()
+		if err != nil {
+			panic(err)
+		}
+		if sqlparser.IsWindowFunction(ae.Expr) {
+			w.Functions = append(w.Functions, newWindowFunc(ae, len(w.Columns)))
+		}
+		w.Columns = append(w.Columns, ae)
+	}
+
+	if len(w.Functions) > 0 {
+		w.Spec = sqlparser.GetOverClause(w.Functions[0].Func).WindowSpec
+	}
+	if w.Spec != nil {
+		for _, expr := range w.Spec.PartitionClause {
+			w.PartitionBy = append(w.PartitionBy, NewGroupBy(expr))
+		}
+		for _, order := range w.Spec.OrderClause {
+			w.OrderBy = append(w.OrderBy, NewGroupBy(order.Expr))
+		}
+	}
+
+	return w
+}
+
+func (w *Window) Clone(inputs []Operator) Operator {
+	kopy := *w
+	kopy.Source = inputs[0]
+	kopy.Columns = slices.Clone(w.Columns)
+	kopy.Functions = slices.Clone(w.Functions)
+	kopy.PartitionBy = slices.Clone(w.PartitionBy)
+	kopy.OrderBy = slices.Clone(w.OrderBy)
+	return &kopy
+}
+
+func (w *Window) AddPredicate(_ *plancontext.PlanningContext, expr sqlparser.Expr) Operator {
+	return newFilter(w, expr)
+}
+
+func (w *Window) addColumnWithoutPushing(_ *plancontext.PlanningContext, expr *sqlparser.AliasedExpr, _ bool) int {
+	offset := len(w.Columns)
+	w.Columns = append(w.Columns, expr)
+	return offset
+}
+
+func (w *Window) derivedName() string {
+	if w.DT == nil {
+		return ""
+	}
+
+	return w.DT.Alias
+}
+
+func (w *Window) FindCol(ctx *plancontext.PlanningContext, in sqlparser.Expr, underRoute bool) int {
+	if underRoute && w.DT != nil {
+		// We don't want to use columns on this operator if it's a derived table under a route.
+		// In this case, we need to add a Projection on top of this operator to make the column available
+		return -1
+	}
+
+	expr := w.DT.RewriteExpression(ctx, in)
+	if offset, found := canReuseColumn(ctx, w.Columns, expr, extractExpr); found {
+		w.checkOffset(offset)
+		return offset
+	}
+	return -1
+}
+
+func (w *Window) checkOffset(offset int) {
+	// if the offset is greater than the number of columns we expect to produce, we need to update the number of columns
+	// this is to make sure that the column is not truncated in the final result
+	if w.ResultColumns > 0 && w.ResultColumns <= offset {
+		w.ResultColumns = offset + 1
+	}
+}
+
+func (w *Window) AddColumn(ctx *plancontext.PlanningContext, reuse bool, groupBy bool, ae *sqlparser.AliasedExpr) (offset int) {
+	w.planOffsets(ctx)
+
+	defer func() {
+		w.checkOffset(offset)
+	}()
+	rewritten := w.DT.RewriteExpression(ctx, ae.Expr)
+
+	ae = &sqlparser.AliasedExpr{
+		Expr: rewritten,
+		As:   ae.As,
+	}
+
+	if reuse {
+		if offset := w.FindCol(ctx, rewritten, false); offset >= 0 {
+			return offset
+		}
+	}
+
+	if sqlparser.ContainsWindowFunction(rewritten) {
+		panic(vterrors.VT12001(fmt.Sprintf("in scatter query: window function in '%s'", sqlparser.String(rewritten))))
+	}
+
+	offset = len(w.Columns)
+	w.Columns = append(w.Columns, ae)
+	incomingOffset := w.Source.AddColumn(ctx, false, groupBy, ae)
+
+	if offset != incomingOffset {
+		panic(errFailedToPlan(ae))
+	}
+
+	return offset
+}
+
+func (w *Window) AddWSColumn(ctx *plancontext.PlanningContext, offset int, underRoute bool) int {
+	if !underRoute {
+		w.planOffsets(ctx)
+	}
+
+	if len(w.Columns) <= offset {
+		panic(vterrors.VT13001("offset out of range"))
+	}
+
+	expr := w.Columns[offset].Expr
+	wsExpr := weightStringFor(expr)
+	if offset := w.FindCol(ctx, wsExpr, underRoute); offset >= 0 {
+		return offset
+	}
+
+	wsAe := aeWrap(wsExpr)
+	wsOffset := len(w.Columns)
+	w.Columns = append(w.Columns, wsAe)
+	if underRoute {
+		// if we are under a route, we are done here.
+		// the column will be use when creating the query to send to the tablet, and that is all we need
+		return wsOffset
+	}
+
+	if slices.ContainsFunc(w.Functions, func(wf WindowFunc) bool { return wf.ColOffset == offset }) {
+		// the input holds the argument of the function at this offset, not its result
+		panic(vterrors.VT12001(fmt.Sprintf("in scatter query: comparing the result of window function '%s'", sqlparser.String(expr))))
+	}
+
+	incomingOffset := w.Source.AddWSColumn(ctx, offset, false)
+	if wsOffset != incomingOffset {
+		panic(errFailedToPlan(wsAe))
+	}
+	w.checkOffset(wsOffset)
+	return wsOffset
+}
+
+func (w *Window) GetColumns(*plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	return truncate(w, w.Columns)
+}
+
+func (w *Window) GetSelectExprs(ctx *plancontext.PlanningContext) sqlparser.SelectExprs {
+	return transformColumnsToSelectExprs(ctx, w)
+}
+
+func (w *Window) ShortDescription() string {
+	columns := slice.Map(w.Columns, func(from *sqlparser.AliasedExpr) string {
+		return sqlparser.String(from)
+	})
+	if w.DT != nil {
+		columns = append([]string{w.DT.String()}, columns...)
+	}
+
+	desc := strings.Join(columns, ", ")
+	if w.ResultColumns > 0 {
+		desc = fmt.Sprintf(":%d %s", w.ResultColumns, desc)
+	}
+	if w.Spec == nil {
+		return desc
+	}
+	return fmt.Sprintf("%s over %s", desc, sqlparser.String(w.Spec))
+}
+
+func (w *Window) GetOrdering(ctx *plancontext.PlanningContext) []OrderBy {
+	return w.Source.GetOrdering(ctx)
+}
+
+// requiredOrdering returns the ordering the input needs to have,
+// so that rows of the same partition are consecutive and sorted by the window ORDER BY
+func (w *Window) requiredOrdering() []OrderBy {
+	if w.Spec == nil {
+		return nil
+	}
+	orderBy := slice.Map(w.PartitionBy, func(from GroupBy) OrderBy {
+		return from.AsOrderBy()
+	})
+	for _, order := range w.Spec.OrderClause {
+		orderBy = append(orderBy, OrderBy{
+			Inner:          order,
+			SimplifiedExpr: order.Expr,
+		})
+	}
+	return orderBy
+}
+
+// checkCanBeEvaluatedAtVTGate fails the planning with an unsupported error
+// if the window functions are using something the vtgate can't evaluate
+func (w *Window) checkCanBeEvaluatedAtVTGate() {
+	for _, wf := range w.Functions {
+		over := sqlparser.GetOverClause(wf.Func)
+		switch {
+		case over.WindowSpec == nil:
+			panic(vterrors.VT12001(fmt.Sprintf("in scatter query: named window in '%s'", sqlparser.String(wf.Func))))
+		case over.WindowSpec.FrameClause != nil:
+			panic(vterrors.VT12001(fmt.Sprintf("in scatter query: window frame in '%s'", sqlparser.String(wf.Func))))
+		case wf.OpCode == opcode.WindowUnassigned:
+			panic(vterrors.VT12001(fmt.Sprintf("in scatter query: window function '%s'", sqlparser.String(wf.Func))))
+		case !sqlparser.Equals.RefOfWindowSpecification(over.WindowSpec, w.Spec):
+			panic(vterrors.VT12001("in scatter query: window functions with different window specifications"))
+		}
+	}
+
+	for idx, col := range w.Columns {
+		if slices.ContainsFunc(w.Functions, func(wf WindowFunc) bool { return wf.ColOffset == idx }) {
+			continue
+		}
+		if sqlparser.ContainsWindowFunction(col.Expr) {
+			panic(vterrors.VT12001(fmt.Sprintf("in scatter query: window function in '%s'", sqlparser.String(col.Expr))))
+		}
+		if containsSubquery(col.Expr) {
+			panic(vterrors.VT12001(fmt.Sprintf("in scatter query: subquery '%s' with window functions", sqlparser.String(col.Expr))))
+		}
+	}
+}
+
+func (w *Window) planOffsets(ctx *plancontext.PlanningContext) Operator {
+	if w.offsetPlanned {
+		return nil
+	}
+	defer func() {
+		w.offsetPlanned = true
+	}()
+
+	w.checkCanBeEvaluatedAtVTGate()
+
+	w.Source = newAliasedProjection(w.Source)
+	// we need to keep things in the column order, so the function results end up where the user expects them
+	for colIdx, col := range w.Columns {
+		ae := col
+		if idx := slices.IndexFunc(w.Functions, func(wf WindowFunc) bool { return wf.ColOffset == colIdx }); idx >= 0 {
+			ae = aeWrap(w.Functions[idx].getPushColumn())
+		}
+		offset := w.Source.AddColumn(ctx, false, false, ae)
+		if offset != colIdx {
+			panic(errFailedToPlan(col))
+		}
+	}
+
+	for idx, wf := range w.Functions {
+		if wf.Default != nil {
+			w.Functions[idx].DefaultOffset = w.internalAddColumn(ctx, aeWrap(wf.Default))
+		}
+	}
+
+	w.PartitionBy = w.addKeyColumns(ctx, w.PartitionBy)
+	w.OrderBy = w.addKeyColumns(ctx, w.OrderBy)
+	return nil
+}
+
+// addKeyColumns makes sure that the input produces the columns and weight strings
+// needed to compare the PARTITION BY and ORDER BY values
+func (w *Window) addKeyColumns(ctx *plancontext.PlanningContext, keys []GroupBy) []GroupBy {
+	for idx, key := range keys {
+		// window function results are written to the column of their argument,
+		// but the partition and order values are read from the incoming rows,
+		// so we can safely reuse any column with the same expression
+		keys[idx].ColOffset = w.internalAddColumn(ctx, aeWrap(key.Inner))
+		if !ctx.NeedsWeightString(key.Inner) {
+			continue
+		}
+		keys[idx].WSOffset = w.internalAddWSColumn(ctx, keys[idx].ColOffset, aeWrap(weightStringFor(key.Inner)))
+	}
+	return keys
+}
+
+func (w *Window) internalAddColumn(ctx *plancontext.PlanningContext, aliasedExpr *sqlparser.AliasedExpr) int {
+	if w.ResultColumns == 0 && w.Truncate {
+		// if we need to use `internalAddColumn`, it means we are adding columns that are not part of the original list,
+		// so we need to set the ResultColumns to the current length of the columns list
+		w.ResultColumns = len(w.Columns)
+	}
+	offset := w.Source.AddColumn(ctx, true, false, aliasedExpr)
+
+	if offset == len(w.Columns) {
+		// if we get an offset at the end of our current column list, it means we added a new column
+		w.Columns = append(w.Columns, aliasedExpr)
+	}
+	return offset
+}
+
+func (w *Window) internalAddWSColumn(ctx *plancontext.PlanningContext, inOffset int, aliasedExpr *sqlparser.AliasedExpr) int {
+	if w.ResultColumns == 0 && w.Truncate {
+		w.ResultColumns = len(w.Columns)
+	}
+
+	offset := w.Source.AddWSColumn(ctx, inOffset, false)
+
+	if offset == len(w.Columns) {
+		w.Columns = append(w.Columns, aliasedExpr)
+	}
+	return offset
+}
+
+func (w *Window) setTruncateColumnCount(offset int) {
+	w.ResultColumns = offset
+}
+
+func (w *Window) getTruncateColumnCount() int {
+	return w.ResultColumns
+}
+
+func (w *Window) introducesTableID() semantics.TableSet {
+	return w.DT.introducesTableID()
+}
+
+// partitionedByUniqueVindex returns true if all the window functions are partitioned by a column
+// that has a unique vindex. All the rows of such partitions live on the same shard,
+// so mysql can evaluate the functions without having to see the rows of the other shards.
+func partitionedByUniqueVindex(ctx *plancontext.PlanningContext, windowFuncs []sqlparser.Expr) bool {
+	for _, fn := range windowFuncs {
+		spec := sqlparser.GetOverClause(fn).WindowSpec
+		if spec == nil {
+			return false
+		}
+		if !slices.ContainsFunc(spec.PartitionClause, func(expr sqlparser.Expr) bool {
+			return exprHasUniqueVindex(ctx, expr)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsSubquery(expr sqlparser.Expr) bool {
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
+		if _, isSubq := node.(*sqlparser.Subquery); isSubq {
+			found = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, expr)
+	return found
+}
+
+// findWindowFunctions returns all the window functions used in the given nodes
+func findWindowFunctions(nodes ...sqlparser.SQLNode) (windowFuncs []sqlparser.Expr) {
+	visit := func(node sqlparser.SQLNode) (kontinue bool, err error) {
+		switch node := node.(type) {
+		case *sqlparser.Subquery:
+			return false, nil
+		case sqlparser.Expr:
+			if sqlparser.IsWindowFunction(node) {
+				windowFuncs = append(windowFuncs, node)
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+	for _, node := range nodes {
+		_ = sqlparser.Walk(visit, node)
+	}
+	return
+}
+
+func tryPushWindow(ctx *plancontext.PlanningContext, in *Window) (Operator, *ApplyResult) {
+	src, ok := in.Source.(*Route)
+	if !ok {
+		return in, NoRewrite
+	}
+
+	var columns []sqlparser.SQLNode
+	for _, col := range in.Columns {
+		columns = append(columns, col)
+	}
+	if src.IsSingleShard() || partitionedByUniqueVindex(ctx, findWindowFunctions(columns...)) {
+		return Swap(in, src, "push window under route")
+	}
+
+	return in, NoRewrite
+}
+
+// addOrderingForAllWindows is run after we have pushed down Windows as far down as possible.
+// The Windows left above routes need their input sorted by partition and window ordering
+func addOrderingForAllWindows(ctx *plancontext.PlanningContext, root Operator) Operator {
+	visitor := func(in Operator, _ semantics.TableSet, isRoot bool) (Operator, *ApplyResult) {
+		w, ok := in.(*Window)
+		if !ok {
+			return in, NoRewrite
+		}
+
+		requiredOrder := w.requiredOrdering()
+		if len(requiredOrder) == 0 || orderingIsSatisfied(ctx, w.Source, requiredOrder) {
+			return in, NoRewrite
+		}
+
+		w.Source = newOrdering(w.Source, requiredOrder)
+		return in, Rewrote("added ordering before window")
+	}
+
+	return BottomUp(root, TableID, visitor, stopAtRoute)
+}
+
+func orderingIsSatisfied(ctx *plancontext.PlanningContext, op Operator, requiredOrder []OrderBy) bool {
+	srcOrdering := op.GetOrdering(ctx)
+	if len(srcOrdering) < len(requiredOrder) {
+		return false
+	}
+	for idx, order := range requiredOrder {
+		if !ctx.SemTable.EqualsExprWithDeps(srcOrdering[idx].SimplifiedExpr, order.SimplifiedExpr) ||
+			srcOrdering[idx].Inner.Direction != order.Inner.Direction {
+			return false
+		}
+	}
+	return true
+}
+
+var _ Operator = (*Window)(nil)
@@ -83,6 +83,7 @@ func (s *planTestSuite) TestPlan() {
 	}
 	s.addPKs(vschemaWrapper.V, "user", []string{"user", "music"})
 	s.addPKsProvided(vschemaWrapper.V, "user", []string{"user_extra"}, []string{"id", "user_id"})
+	s.addPKsProvided(vschemaWrapper.V, "user", []string{"user_metadata"}, []string{"user_id"})
 	s.addPKsProvided(vschemaWrapper.V, "ordering", []string{"order"}, []string{"oid", "region_id"})
 	s.addPKsProvided(vschemaWrapper.V, "ordering", []string{"order_event"}, []string{"oid", "ename"})
 
@@ -120,6 +121,7 @@ func (s *planTestSuite) TestPlan() {
 	s.testFile("vexplain_cases.json", vschemaWrapper, false)
 	s.testFile("misc_cases.json", vschemaWrapper, false)
 	s.testFile("cte_cases.json", vschemaWrapper, false)
+	s.testFile("window_cases.json", vschemaWrapper, false)
 }
 
 // TestForeignKeyPlanning tests the planning of foreign keys in a managed mode by Vitess.
@@ -302,6 +304,7 @@ func (s *planTestSuite) TestOne() {
 	s.addPKs(lv, "user", []string{"user", "music"})
 	s.addPKs(lv, "main", []string{"unsharded"})
 	s.addPKsProvided(lv, "user", []string{"user_extra"}, []string{"id", "user_id"})
+	s.addPKsProvided(lv, "user", []string{"user_metadata"}, []string{"user_id"})
 	s.addPKsProvided(lv, "ordering", []string{"order"}, []string{"oid", "region_id"})
 	s.addPKsProvided(lv, "ordering", []string{"order_event"}, []string{"oid", "ename"})
 	vschema := &vschemawrapper.VSchemaWrapper{
@@ -358,7 +358,8 @@ func (ctx *PlanningContext) NeedsWeightString(e sqlparser.Expr) bool {
 func (ctx *PlanningContext) IsAggr(e sqlparser.SQLNode) bool {
 	switch node := e.(type) {
 	case sqlparser.AggrFunc:
-		return true
+		// windowed aggregations are evaluated per row and not by grouping
+		return !sqlparser.IsWindowFunction(node)
 	case *sqlparser.FuncExpr:
 		return node.Name.EqualsAnyString(ctx.VSchema.GetAggregateUDFs())
 	}
@@ -374,6 +375,9 @@ func (ctx *PlanningContext) ContainsAggr(e sqlparser.SQLNode) (hasAggr bool) {
 			// so we don't need to worry about aggregation in the original
 			return false, nil
 		case sqlparser.AggrFunc:
+			if sqlparser.IsWindowFunction(node) {
+				return true, nil
+			}
 			hasAggr = true
 			return false, io.EOF
 		case *sqlparser.Subquery:
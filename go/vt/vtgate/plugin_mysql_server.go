@@ -58,6 +58,9 @@ var (
 	mysqlAuthServerImpl               = "static"
 	mysqlAllowClearTextWithoutTLS     bool
 	mysqlProxyProtocol                bool
+	mysqlEnableCompression            bool
+	mysqlEnableZstdCompression        bool
+	mysqlEnableLocalInfile            bool
 	mysqlServerRequireSecureTransport bool
 	mysqlSslCert                      string
 	mysqlSslKey                       string
@@ -73,6 +76,8 @@ var (
 	mysqlSlowConnectWarnThreshold time.Duration
 	mysqlConnBufferPooling        bool
 
+	mysqlMaxMaterializedCursorRows int64 = mysql.DefaultMaxMaterializedCursorRows
+
 	mysqlDefaultWorkloadName = "OLTP"
 	mysqlDefaultWorkload     int32
 	mysqlDrainOnTerm         bool
@@ -88,6 +93,9 @@ func registerPluginFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&mysqlAuthServerImpl, "mysql_auth_server_impl", mysqlAuthServerImpl, "Which auth server implementation to use. Options: none, ldap, clientcert, static, vault.")
 	fs.BoolVar(&mysqlAllowClearTextWithoutTLS, "mysql_allow_clear_text_without_tls", mysqlAllowClearTextWithoutTLS, "If set, the server will allow the use of a clear text password over non-SSL connections.")
 	fs.BoolVar(&mysqlProxyProtocol, "proxy_protocol", mysqlProxyProtocol, "Enable HAProxy PROXY protocol on MySQL listener socket")
+	fs.BoolVar(&mysqlEnableCompression, "mysql-server-enable-compression", mysqlEnableCompression, "If set, the server will allow clients to use zlib compression of the MySQL protocol.")
+	fs.BoolVar(&mysqlEnableZstdCompression, "mysql-server-enable-zstd-compression", mysqlEnableZstdCompression, "If set, the server will allow clients to use zstd compression of the MySQL protocol.")
+	fs.BoolVar(&mysqlEnableLocalInfile, "mysql-server-enable-local-infile", mysqlEnableLocalInfile, "If set, the server will allow clients to send files for LOAD DATA LOCAL INFILE, like the local_infile system variable of MySQL.")
 	fs.BoolVar(&mysqlServerRequireSecureTransport, "mysql_server_require_secure_transport", mysqlServerRequireSecureTransport, "Reject insecure connections but only if mysql_server_ssl_cert and mysql_server_ssl_key are provided")
 	fs.StringVar(&mysqlSslCert, "mysql_server_ssl_cert", mysqlSslCert, "Path to the ssl cert for mysql server plugin SSL")
 	fs.StringVar(&mysqlSslKey, "mysql_server_ssl_key", mysqlSslKey, "Path to ssl key for mysql server plugin SSL")
@@ -101,6 +109,7 @@ func registerPluginFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&mysqlQueryTimeout, "mysql_server_query_timeout", mysqlQueryTimeout, "mysql query timeout")
 	fs.BoolVar(&mysqlConnBufferPooling, "mysql-server-pool-conn-read-buffers", mysqlConnBufferPooling, "If set, the server will pool incoming connection read buffers")
 	fs.DurationVar(&mysqlKeepAlivePeriod, "mysql-server-keepalive-period", mysqlKeepAlivePeriod, "TCP period between keep-alives")
+	fs.Int64Var(&mysqlMaxMaterializedCursorRows, "mysql-server-max-materialized-cursor-rows", mysqlMaxMaterializedCursorRows, "Maximum number of rows a cursor can read in memory when another command is run before all of its rows were fetched. The cursor is closed instead if more rows are left.")
 	fs.DurationVar(&mysqlServerFlushDelay, "mysql_server_flush_delay", mysqlServerFlushDelay, "Delay after which buffered response will be flushed to the client.")
 	fs.StringVar(&mysqlDefaultWorkloadName, "mysql_default_workload", mysqlDefaultWorkloadName, "Default session workload (OLTP, OLAP, DBA)")
 	fs.BoolVar(&mysqlDrainOnTerm, "mysql-server-drain-onterm", mysqlDrainOnTerm, "If set, the server waits for --onterm_timeout for already connected clients to complete their in flight work")
@@ -149,6 +158,23 @@ func (vh *vtgateHandler) ComResetConnection(c *mysql.Conn) {
 	}
 }
 
+// ComChangeUser closes the session of the previous user: its transaction is rolled back,
+// and its reserved connections are released. The next queries start a new session, and
+// are made with the immediate caller ID of the new user.
+func (vh *vtgateHandler) ComChangeUser(c *mysql.Conn) {
+	ctx := context.Background()
+	session := vh.session(c)
+	if session.InTransaction {
+		defer vh.busyConnections.Add(-1)
+	}
+	err := vh.vtg.CloseSession(ctx, session)
+	if err != nil {
+		log.Errorf("Error happened in transaction rollback: %v", err)
+	}
+	c.ClientData = nil
+	fillInTxStatusFlags(c, vh.session(c))
+}
+
 func (vh *vtgateHandler) ConnectionClosed(c *mysql.Conn) {
 	// Rollback if there is an ongoing transaction. Ignore error.
 	defer func() {
@@ -208,6 +234,8 @@ func startSpan(ctx context.Context, query, label string) (trace.Span, context.Co
 
 func (vh *vtgateHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
 	session := vh.session(c)
+	// The query attributes only apply to the query they were sent along with.
+	session.Options.QueryAttributes = c.QueryAttributes()
 	if c.IsShuttingDown() && !session.InTransaction {
 		c.MarkForClose()
 		return sqlerror.NewSQLError(sqlerror.ERServerShutdown, sqlerror.SSNetError, "Server shutdown in progress")
@@ -228,6 +256,7 @@ func (vh *vtgateHandler) ComQuery(c *mysql.Conn, query string, callback func(*sq
 	defer span.Finish()
 
 	ctx = callinfo.MysqlCallInfo(ctx, c)
+	ctx = withLocalInfileReader(ctx, c)
 
 	// Fill in the ImmediateCallerID with the UserData returned by
 	// the AuthServer plugin for that user. If nothing was
@@ -347,6 +376,7 @@ func (vh *vtgateHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareDat
 	ctx = callerid.NewContext(ctx, ef, im)
 
 	session := vh.session(c)
+	session.Options.QueryAttributes = c.QueryAttributes()
 	if !session.InTransaction {
 		vh.busyConnections.Add(1)
 	}
@@ -356,7 +386,8 @@ func (vh *vtgateHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareDat
 		}
 	}()
 
-	if session.Options.Workload == querypb.ExecuteOptions_OLAP {
+	// The rows of a cursor are streamed as the client fetches them.
+	if session.Options.Workload == querypb.ExecuteOptions_OLAP || prepare.CursorType&mysql.CursorTypeReadOnly != 0 {
 		_, err := vh.vtg.StreamExecute(ctx, vh, session, prepare.PrepareStmt, prepare.BindVars, callback)
 		if err != nil {
 			return sqlerror.NewSQLErrorFromError(err)
@@ -550,6 +581,10 @@ func initMySQLProtocol(vtgate *VTGate) *mysqlServer {
 			_ = initTLSConfig(context.Background(), srv, mysqlSslCert, mysqlSslKey, mysqlSslCa, mysqlSslCrl, mysqlSslServerCA, mysqlServerRequireSecureTransport, tlsVersion)
 		}
 		srv.tcpListener.AllowClearTextWithoutTLS.Store(mysqlAllowClearTextWithoutTLS)
+		srv.tcpListener.EnableCompression.Store(mysqlEnableCompression)
+		srv.tcpListener.EnableZstdCompression.Store(mysqlEnableZstdCompression)
+		srv.tcpListener.EnableLocalInfile.Store(mysqlEnableLocalInfile)
+		srv.tcpListener.MaxMaterializedCursorRows.Store(mysqlMaxMaterializedCursorRows)
 		// Check for the connection threshold
 		if mysqlSlowConnectWarnThreshold != 0 {
 			log.Infof("setting mysql slow connection threshold to %v", mysqlSlowConnectWarnThreshold)
@@ -422,3 +422,78 @@ func TestGracefulShutdownWithTransaction(t *testing.T) {
 
 	require.True(t, mysqlConn.IsMarkedForClose())
 }
+
+func TestComChangeUser(t *testing.T) {
+	executor, _, _, _, _ := createExecutorEnv(t)
+
+	vh := newVtgateHandler(&VTGate{executor: executor, timings: timings, rowsReturned: rowsReturned, rowsAffected: rowsAffected, queryTextCharsProcessed: queryTextCharsProcessed})
+	th := &testHandler{}
+	listener, err := mysql.NewListener("tcp", "127.0.0.1:", mysql.NewAuthServerNone(), th, 0, 0, false, false, 0, 0)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	// add a connection
+	mysqlConn := mysql.GetTestServerConn(listener)
+	mysqlConn.ConnectionID = 1
+	mysqlConn.UserData = &mysql.StaticUserData{}
+	vh.connections[1] = mysqlConn
+
+	for _, query := range []string{"set @foo = 1", "BEGIN", "select 1"} {
+		err = vh.ComQuery(mysqlConn, query, func(result *sqltypes.Result) error {
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	session := vh.session(mysqlConn)
+	require.True(t, session.InTransaction)
+	require.Contains(t, session.UserDefinedVariables, "foo")
+	require.EqualValues(t, 1, vh.busyConnections.Load())
+	require.NotZero(t, mysqlConn.StatusFlags&mysql.ServerStatusInTrans)
+
+	// the session of the previous user is closed, and a new one is started
+	mysqlConn.User = "other"
+	mysqlConn.UserData = &mysql.StaticUserData{Username: "other"}
+	vh.ComChangeUser(mysqlConn)
+
+	newSession := vh.session(mysqlConn)
+	assert.NotSame(t, session, newSession)
+	assert.NotEqual(t, session.SessionUUID, newSession.SessionUUID)
+	assert.False(t, newSession.InTransaction)
+	assert.Empty(t, newSession.UserDefinedVariables)
+	assert.Empty(t, newSession.ShardSessions)
+	assert.Zero(t, vh.busyConnections.Load())
+	assert.Zero(t, mysqlConn.StatusFlags&mysql.ServerStatusInTrans)
+	assert.NotZero(t, mysqlConn.StatusFlags&mysql.ServerStatusAutocommit)
+}
+
+func TestComQueryAttributes(t *testing.T) {
+	executor, _, _, _, _ := createExecutorEnv(t)
+
+	vh := newVtgateHandler(&VTGate{executor: executor, timings: timings, rowsReturned: rowsReturned, rowsAffected: rowsAffected, queryTextCharsProcessed: queryTextCharsProcessed})
+	th := &testHandler{}
+	listener, err := mysql.NewListener("tcp", "127.0.0.1:", mysql.NewAuthServerNone(), th, 0, 0, false, false, 0, 0)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	// add a connection
+	mysqlConn := mysql.GetTestServerConn(listener)
+	mysqlConn.ConnectionID = 1
+	mysqlConn.UserData = &mysql.StaticUserData{}
+	vh.connections[1] = mysqlConn
+
+	attributes := map[string]string{"tenant": "acme", "request_id": "42"}
+	mysqlConn.SetQueryAttributes(attributes)
+	err = vh.ComQuery(mysqlConn, "select 1", func(result *sqltypes.Result) error {
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, attributes, vh.session(mysqlConn).Options.QueryAttributes)
+
+	// the attributes don't outlive the query they were sent along with
+	mysqlConn.SetQueryAttributes(nil)
+	err = vh.ComQuery(mysqlConn, "select 1", func(result *sqltypes.Result) error {
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Empty(t, vh.session(mysqlConn).Options.QueryAttributes)
+}
@@ -34,6 +34,8 @@ func setupUnixSocket(srv *mysqlServer, authServer mysql.AuthServer, path string)
 	if err != nil {
 		return err
 	}
+	srv.unixListener.EnableLocalInfile.Store(mysqlEnableLocalInfile)
+	srv.unixListener.MaxMaterializedCursorRows.Store(mysqlMaxMaterializedCursorRows)
 	// Listen for unix socket
 	go srv.unixListener.Accept()
 	return nil
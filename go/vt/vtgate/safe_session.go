@@ -19,12 +19,14 @@ package vtgate
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"google.golang.org/protobuf/proto"
 
+	"vitess.io/vitess/go/mysql/config"
 	"vitess.io/vitess/go/mysql/datetime"
 
 	"vitess.io/vitess/go/vt/sqlparser"
@@ -217,6 +219,20 @@ func (session *SafeSession) GetQueryTimeout() int64 {
 	return session.QueryTimeout
 }
 
+// SetMaxExecutionTime sets the max_execution_time, in milliseconds
+func (session *SafeSession) SetMaxExecutionTime(maxExecutionTime int64) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.MaxExecutionTime = maxExecutionTime
+}
+
+// GetMaxExecutionTime gets the max_execution_time, in milliseconds
+func (session *SafeSession) GetMaxExecutionTime() int64 {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.MaxExecutionTime
+}
+
 // SavePoints returns the save points of the session. It's safe to use concurrently
 func (session *SafeSession) SavePoints() []string {
 	session.mu.Lock()
@@ -573,6 +589,36 @@ func (session *SafeSession) TimeZone() *time.Location {
 	return loc
 }
 
+// GroupConcatMaxLen returns the group_concat_max_len stored in system_variables map in the session,
+// or the MySQL default if the session has not changed it.
+func (session *SafeSession) GroupConcatMaxLen() uint64 {
+	session.mu.Lock()
+	val, ok := session.SystemVariables[sysvars.GroupConcatMaxLen]
+	session.mu.Unlock()
+
+	if !ok {
+		return config.DefaultGroupConcatMaxLen
+	}
+	maxLen, err := strconv.ParseUint(strings.Trim(val, "'"), 10, 64)
+	if err != nil {
+		return config.DefaultGroupConcatMaxLen
+	}
+	return maxLen
+}
+
+// BlockEncryptionMode returns the block_encryption_mode stored in system_variables map in the session,
+// or the MySQL default if the session has not changed it.
+func (session *SafeSession) BlockEncryptionMode() string {
+	session.mu.Lock()
+	val, ok := session.SystemVariables[sysvars.BlockEncryptionMode]
+	session.mu.Unlock()
+
+	if !ok {
+		return config.DefaultBlockEncryptionMode
+	}
+	return strings.ToLower(strings.Trim(val, "'"))
+}
+
 // ForeignKeyChecks returns the foreign_key_checks stored in system_variables map in the session.
 func (session *SafeSession) ForeignKeyChecks() *bool {
 	session.mu.Lock()
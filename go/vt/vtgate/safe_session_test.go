@@ -24,6 +24,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"vitess.io/vitess/go/mysql/config"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
@@ -98,3 +99,56 @@ func TestTimeZone(t *testing.T) {
 		})
 	}
 }
+
+func TestGroupConcatMaxLen(t *testing.T) {
+	testCases := []struct {
+		val  string
+		want uint64
+	}{
+		{
+			val:  "2048",
+			want: 2048,
+		},
+		{
+			val:  "'4'",
+			want: 4,
+		},
+		{
+			val:  "foo",
+			want: config.DefaultGroupConcatMaxLen,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.val, func(t *testing.T) {
+			session := NewSafeSession(&vtgatepb.Session{
+				SystemVariables: map[string]string{
+					"group_concat_max_len": tc.val,
+				},
+			})
+
+			assert.Equal(t, tc.want, session.GroupConcatMaxLen())
+		})
+	}
+
+	assert.EqualValues(t, config.DefaultGroupConcatMaxLen, NewSafeSession(nil).GroupConcatMaxLen())
+}
+
+func TestBlockEncryptionMode(t *testing.T) {
+	session := NewSafeSession(&vtgatepb.Session{
+		SystemVariables: map[string]string{
+			"block_encryption_mode": "'AES-256-CBC'",
+		},
+	})
+	assert.Equal(t, "aes-256-cbc", session.BlockEncryptionMode())
+	assert.Equal(t, config.DefaultBlockEncryptionMode, NewSafeSession(nil).BlockEncryptionMode())
+}
+
+func TestMaxExecutionTime(t *testing.T) {
+	session := NewSafeSession(&vtgatepb.Session{})
+	assert.Zero(t, session.GetMaxExecutionTime())
+
+	session.SetMaxExecutionTime(2500)
+	assert.EqualValues(t, 2500, session.GetMaxExecutionTime())
+	assert.Empty(t, session.SystemVariables)
+}
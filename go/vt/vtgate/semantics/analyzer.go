@@ -459,8 +459,12 @@ func (a *analyzer) noteQuerySignature(node sqlparser.SQLNode) {
 		if node.Recursive {
 			a.sig.RecursiveCTE = true
 		}
+	case *sqlparser.OverClause:
+		a.sig.Window = true
 	case sqlparser.AggrFunc:
-		a.sig.Aggregation = true
+		if !sqlparser.IsWindowFunction(node) {
+			a.sig.Aggregation = true
+		}
 	case *sqlparser.Delete, *sqlparser.Update, *sqlparser.Insert:
 		a.sig.DML = true
 	}
@@ -48,14 +48,6 @@ func (a *analyzer) checkForInvalidConstructs(cursor *sqlparser.Cursor) error {
 		}
 	case *sqlparser.Subquery:
 		return a.checkSubqueryColumns(cursor.Parent(), node)
-	case *sqlparser.Insert:
-		if !a.singleUnshardedKeyspace && node.Action == sqlparser.ReplaceAct {
-			return ShardedError{Inner: &UnsupportedConstruct{errString: "REPLACE INTO with sharded keyspace"}}
-		}
-	case *sqlparser.OverClause:
-		if !a.singleUnshardedKeyspace {
-			return ShardedError{Inner: &UnsupportedConstruct{errString: "OVER CLAUSE with sharded keyspace"}}
-		}
 	}
 
 	return nil
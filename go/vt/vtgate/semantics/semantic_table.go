@@ -87,6 +87,7 @@ type (
 		SubQueries   bool
 		Union        bool
 		RecursiveCTE bool
+		Window       bool
 	}
 
 	// MirrorInfo stores information used to produce mirror
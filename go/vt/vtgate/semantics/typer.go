@@ -18,6 +18,7 @@ package semantics
 
 import (
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
@@ -61,6 +62,12 @@ func (t *typer) up(cursor *sqlparser.Cursor) error {
 			}
 		}
 		t.m[node] = code.ResolveType(inputType, t.collationEnv)
+	case *sqlparser.ArgumentLessWindowExpr:
+		typ := sqltypes.Int64
+		if node.Type == sqlparser.CumeDistExprType || node.Type == sqlparser.PercentRankExprType {
+			typ = sqltypes.Float64
+		}
+		t.m[node] = evalengine.NewTypeEx(typ, collations.CollationBinaryID, false, 0, 0, nil)
 	}
 	return nil
 }
@@ -130,6 +130,8 @@ type vcursorImpl struct {
 	warmingReadsPercent int
 	warmingReadsChannel chan bool
 
+	snowflake *engine.SnowflakeGenerator
+
 	resultsObserver resultsObserver
 }
 
@@ -177,9 +179,11 @@ func newVCursorImpl(
 
 	warmingReadsPct := 0
 	var warmingReadsChan chan bool
+	var snowflake *engine.SnowflakeGenerator
 	if executor != nil {
 		warmingReadsPct = executor.warmingReadsPercent
 		warmingReadsChan = executor.warmingReadsChannel
+		snowflake = executor.snowflake
 	}
 	return &vcursorImpl{
 		safeSession:         safeSession,
@@ -198,6 +202,7 @@ func newVCursorImpl(
 		pv:                  pv,
 		warmingReadsPercent: warmingReadsPct,
 		warmingReadsChannel: warmingReadsChan,
+		snowflake:           snowflake,
 		resultsObserver:     nullResultsObserver{},
 	}, nil
 }
@@ -232,6 +237,16 @@ func (vc *vcursorImpl) SQLMode() string {
 	return config.DefaultSQLMode
 }
 
+// GroupConcatMaxLen returns the group_concat_max_len of the session.
+func (vc *vcursorImpl) GroupConcatMaxLen() uint64 {
+	return vc.safeSession.GroupConcatMaxLen()
+}
+
+// BlockEncryptionMode returns the block_encryption_mode of the session.
+func (vc *vcursorImpl) BlockEncryptionMode() string {
+	return vc.safeSession.BlockEncryptionMode()
+}
+
 // MaxMemoryRows returns the maxMemoryRows flag value.
 func (vc *vcursorImpl) MaxMemoryRows() int {
 	return maxMemoryRows
@@ -243,6 +258,17 @@ func (vc *vcursorImpl) ExceedsMaxMemoryRows(numRows int) bool {
 	return !vc.ignoreMaxMemoryRows && numRows > maxMemoryRows
 }
 
+// SpillDir returns the spill-dir flag value.
+func (vc *vcursorImpl) SpillDir() string {
+	return spillDir
+}
+
+// RecordSpill adds the rows and bytes spilled to disk to the logstats of the query.
+func (vc *vcursorImpl) RecordSpill(rows, bytes int) {
+	atomic.AddUint64(&vc.logStats.SpilledRows, uint64(rows))
+	atomic.AddUint64(&vc.logStats.SpilledBytes, uint64(bytes))
+}
+
 // SetIgnoreMaxMemoryRows sets the ignoreMaxMemoryRows value.
 func (vc *vcursorImpl) SetIgnoreMaxMemoryRows(ignoreMaxMemoryRows bool) {
 	vc.ignoreMaxMemoryRows = ignoreMaxMemoryRows
@@ -554,6 +580,27 @@ func (vc *vcursorImpl) StreamExecutePrimitiveStandalone(ctx context.Context, pri
 	return vterrors.New(vtrpcpb.Code_UNAVAILABLE, "upstream shards are not available")
 }
 
+// localInfileReader is implemented by the client connection a LOAD DATA LOCAL INFILE file is read from
+type localInfileReader interface {
+	ReadLocalInfile(fileName string) (io.ReadCloser, error)
+}
+
+type localInfileKey struct{}
+
+// withLocalInfileReader returns a context that lets LOAD DATA LOCAL INFILE read files from the given client connection
+func withLocalInfileReader(ctx context.Context, r localInfileReader) context.Context {
+	return context.WithValue(ctx, localInfileKey{}, r)
+}
+
+// ReadLocalInfile is part of the engine.VCursor interface.
+func (vc *vcursorImpl) ReadLocalInfile(ctx context.Context, fileName string) (io.ReadCloser, error) {
+	r, ok := ctx.Value(localInfileKey{}).(localInfileReader)
+	if !ok {
+		return nil, vterrors.VT12001("LOAD DATA LOCAL INFILE outside of the MySQL protocol")
+	}
+	return r.ReadLocalInfile(fileName)
+}
+
 // Execute is part of the engine.VCursor interface.
 func (vc *vcursorImpl) Execute(ctx context.Context, method string, query string, bindVars map[string]*querypb.BindVariable, rollbackOnError bool, co vtgatepb.CommitOrder) (*sqltypes.Result, error) {
 	session := vc.safeSession
@@ -873,6 +920,11 @@ func (vc *vcursorImpl) SetQueryTimeout(maxExecutionTime int64) {
 	vc.safeSession.QueryTimeout = maxExecutionTime
 }
 
+// SetMaxExecutionTime implements the SessionActions interface
+func (vc *vcursorImpl) SetMaxExecutionTime(maxExecutionTime int64) {
+	vc.safeSession.SetMaxExecutionTime(maxExecutionTime)
+}
+
 // SetClientFoundRows implements the SessionActions interface
 func (vc *vcursorImpl) SetClientFoundRows(_ context.Context, clientFoundRows bool) error {
 	vc.safeSession.GetOrCreateOptions().ClientFoundRows = clientFoundRows
@@ -1396,6 +1448,10 @@ func (vc *vcursorImpl) GetWarmingReadsChannel() chan bool {
 	return vc.warmingReadsChannel
 }
 
+func (vc *vcursorImpl) SnowflakeGenerator() *engine.SnowflakeGenerator {
+	return vc.snowflake
+}
+
 func (vc *vcursorImpl) CloneForReplicaWarming(ctx context.Context) engine.VCursor {
 	callerId := callerid.EffectiveCallerIDFromContext(ctx)
 	immediateCallerId := callerid.ImmediateCallerIDFromContext(ctx)
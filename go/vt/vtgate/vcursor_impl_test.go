@@ -350,6 +350,21 @@ func TestSetExecQueryTimeout(t *testing.T) {
 	require.NotNil(t, safeSession.Options.Timeout)
 	require.EqualValues(t, 40, safeSession.Options.GetAuthoritativeTimeout())
 
+	// a looser max_execution_time does not lift the session timeout
+	safeSession.SetMaxExecutionTime(600)
+	stmt, err := sqlparser.NewTestParser().Parse("select 1 from dual")
+	require.NoError(t, err)
+	vc.SetExecQueryTimeout(queryTimeoutForStatement(safeSession, stmt, sqlparser.QueryHints{}, vc.getQueryTimeout()))
+	require.Equal(t, 40*time.Millisecond, vc.queryTimeout)
+	require.EqualValues(t, 40, safeSession.Options.GetAuthoritativeTimeout())
+
+	// a stricter max_execution_time tightens it
+	safeSession.SetMaxExecutionTime(30)
+	vc.SetExecQueryTimeout(queryTimeoutForStatement(safeSession, stmt, sqlparser.QueryHints{}, vc.getQueryTimeout()))
+	require.Equal(t, 30*time.Millisecond, vc.queryTimeout)
+	require.EqualValues(t, 30, safeSession.Options.GetAuthoritativeTimeout())
+	safeSession.SetMaxExecutionTime(0)
+
 	// query hint timeout
 	timeoutQueryHint := 60
 	vc.SetExecQueryTimeout(&timeoutQueryHint)
@@ -23,11 +23,14 @@ import (
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/key"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 var (
 	_ SingleColumn    = (*Binary)(nil)
 	_ Reversible      = (*Binary)(nil)
+	_ RangeMapper     = (*Binary)(nil)
 	_ Hashing         = (*Binary)(nil)
 	_ ParamValidating = (*Binary)(nil)
 )
@@ -92,6 +95,38 @@ func (vind *Binary) Map(ctx context.Context, vcursor VCursor, ids []sqltypes.Val
 	return out, nil
 }
 
+// MapRange implements the RangeMapper interface.
+func (vind *Binary) MapRange(ctx context.Context, vcursor VCursor, start, end sqltypes.Value) (key.Destination, error) {
+	// MySQL compares the column with a number as a number, not byte by byte.
+	if !isBinaryRangeBound(start) || !isBinaryRangeBound(end) {
+		return key.DestinationAllShards{}, nil
+	}
+	kr := &topodatapb.KeyRange{}
+	if !start.IsNull() {
+		from, err := vind.Hash(start)
+		if err != nil {
+			return nil, err
+		}
+		kr.Start = from
+	}
+	if !end.IsNull() {
+		to, err := vind.Hash(end)
+		if err != nil {
+			return nil, err
+		}
+		if key.Compare(kr.Start, to) > 0 {
+			return key.DestinationNone{}, nil
+		}
+		// The key range end is exclusive, so end it right after the last id.
+		kr.End = append(bytes.Clone(to), 0x01)
+	}
+	return key.DestinationKeyRange{KeyRange: kr}, nil
+}
+
+func isBinaryRangeBound(v sqltypes.Value) bool {
+	return v.IsNull() || v.IsText() || v.IsBinary()
+}
+
 func (vind *Binary) Hash(id sqltypes.Value) ([]byte, error) {
 	return id.ToBytes()
 }
@@ -24,10 +24,13 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/key"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 var binOnlyVindex SingleColumn
@@ -113,6 +116,46 @@ func TestBinaryMap(t *testing.T) {
 	}
 }
 
+func TestBinaryMapRange(t *testing.T) {
+	testCases := []struct {
+		name       string
+		start, end sqltypes.Value
+		want       key.Destination
+	}{{
+		name:  "closed range",
+		start: sqltypes.NewVarBinary("aa"),
+		end:   sqltypes.NewVarBinary("bb"),
+		want:  key.DestinationKeyRange{KeyRange: &topodatapb.KeyRange{Start: []byte("aa"), End: []byte("bb\x01")}},
+	}, {
+		name:  "open start",
+		start: sqltypes.NULL,
+		end:   sqltypes.NewVarChar("bb"),
+		want:  key.DestinationKeyRange{KeyRange: &topodatapb.KeyRange{End: []byte("bb\x01")}},
+	}, {
+		name:  "open end",
+		start: sqltypes.NewVarChar("aa"),
+		end:   sqltypes.NULL,
+		want:  key.DestinationKeyRange{KeyRange: &topodatapb.KeyRange{Start: []byte("aa")}},
+	}, {
+		name:  "empty range",
+		start: sqltypes.NewVarBinary("bb"),
+		end:   sqltypes.NewVarBinary("aa"),
+		want:  key.DestinationNone{},
+	}, {
+		name:  "numeric bound",
+		start: sqltypes.NewInt64(10),
+		end:   sqltypes.NewVarBinary("bb"),
+		want:  key.DestinationAllShards{},
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := binOnlyVindex.(RangeMapper).MapRange(context.Background(), nil, tc.start, tc.end)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestBinaryVerify(t *testing.T) {
 	hexValStr := "8a1e"
 	hexValStrSQL := fmt.Sprintf("x'%s'", hexValStr)
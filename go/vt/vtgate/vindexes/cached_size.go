@@ -416,6 +416,34 @@ func (cached *NumericStaticMap) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *Range) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(80)
+	}
+	// field name string
+	size += hack.RuntimeAllocSize(int64(len(cached.name)))
+	// field metadataKey string
+	size += hack.RuntimeAllocSize(int64(len(cached.metadataKey)))
+	// field splits []vitess.io/vitess/go/vt/vtgate/vindexes.rangeSplit
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.splits)) * int64(32))
+		for _, elem := range cached.splits {
+			size += elem.CachedSize(false)
+		}
+	}
+	// field unknownParams []string
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.unknownParams)) * int64(16))
+		for _, elem := range cached.unknownParams {
+			size += hack.RuntimeAllocSize(int64(len(elem)))
+		}
+	}
+	return size
+}
 func (cached *RegionExperimental) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -645,3 +673,17 @@ func (cached *prefixCFC) CachedSize(alloc bool) int64 {
 	size += cached.cfcCommon.CachedSize(true)
 	return size
 }
+func (cached *rangeSplit) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(32)
+	}
+	// field prefix []byte
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.prefix)))
+	}
+	return size
+}
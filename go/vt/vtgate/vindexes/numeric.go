@@ -21,14 +21,18 @@ import (
 	"context"
 	"encoding/binary"
 	"fmt"
+	"math"
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/key"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 var (
 	_ SingleColumn    = (*Numeric)(nil)
 	_ Reversible      = (*Numeric)(nil)
+	_ RangeMapper     = (*Numeric)(nil)
 	_ Hashing         = (*Numeric)(nil)
 	_ ParamValidating = (*Numeric)(nil)
 )
@@ -95,6 +99,22 @@ func (vind *Numeric) Map(ctx context.Context, vcursor VCursor, ids []sqltypes.Va
 	return out, nil
 }
 
+// MapRange implements the RangeMapper interface.
+func (vind *Numeric) MapRange(ctx context.Context, vcursor VCursor, start, end sqltypes.Value) (key.Destination, error) {
+	from, to, dest := uint64RangeBounds(start, end)
+	if dest != nil {
+		return dest, nil
+	}
+	kr := &topodatapb.KeyRange{}
+	if from > 0 {
+		kr.Start = binary.BigEndian.AppendUint64(nil, from)
+	}
+	if to < math.MaxUint64 {
+		kr.End = binary.BigEndian.AppendUint64(nil, to+1)
+	}
+	return key.DestinationKeyRange{KeyRange: kr}, nil
+}
+
 // ReverseMap returns the associated ids for the ksids.
 func (*Numeric) ReverseMap(_ VCursor, ksids [][]byte) ([]sqltypes.Value, error) {
 	var reverseIds = make([]sqltypes.Value, len(ksids))
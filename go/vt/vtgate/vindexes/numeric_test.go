@@ -21,10 +21,13 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/key"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 var numeric SingleColumn
@@ -117,6 +120,59 @@ func TestNumericMap(t *testing.T) {
 	}
 }
 
+func TestNumericMapRange(t *testing.T) {
+	testCases := []struct {
+		name       string
+		start, end sqltypes.Value
+		want       key.Destination
+	}{{
+		name:  "closed range",
+		start: sqltypes.NewInt64(1),
+		end:   sqltypes.NewInt64(8),
+		want: key.DestinationKeyRange{KeyRange: &topodatapb.KeyRange{
+			Start: []byte("\x00\x00\x00\x00\x00\x00\x00\x01"),
+			End:   []byte("\x00\x00\x00\x00\x00\x00\x00\x09"),
+		}},
+	}, {
+		name:  "open start",
+		start: sqltypes.NULL,
+		end:   sqltypes.NewInt64(8),
+		want:  key.DestinationKeyRange{KeyRange: &topodatapb.KeyRange{End: []byte("\x00\x00\x00\x00\x00\x00\x00\x09")}},
+	}, {
+		name:  "open end",
+		start: sqltypes.NewInt64(1),
+		end:   sqltypes.NULL,
+		want:  key.DestinationKeyRange{KeyRange: &topodatapb.KeyRange{Start: []byte("\x00\x00\x00\x00\x00\x00\x00\x01")}},
+	}, {
+		name:  "negative start",
+		start: sqltypes.NewInt64(-1),
+		end:   sqltypes.NewInt64(8),
+		want:  key.DestinationKeyRange{KeyRange: &topodatapb.KeyRange{End: []byte("\x00\x00\x00\x00\x00\x00\x00\x09")}},
+	}, {
+		name:  "negative end",
+		start: sqltypes.NewInt64(-8),
+		end:   sqltypes.NewInt64(-1),
+		want:  key.DestinationNone{},
+	}, {
+		name:  "empty range",
+		start: sqltypes.NewInt64(8),
+		end:   sqltypes.NewInt64(1),
+		want:  key.DestinationNone{},
+	}, {
+		name:  "not an integer",
+		start: sqltypes.NewFloat64(1.1),
+		end:   sqltypes.NewInt64(8),
+		want:  key.DestinationAllShards{},
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := numeric.(RangeMapper).MapRange(context.Background(), nil, tc.start, tc.end)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestNumericVerify(t *testing.T) {
 	got, err := numeric.Verify(context.Background(), nil, []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewInt64(2)}, [][]byte{[]byte("\x00\x00\x00\x00\x00\x00\x00\x01"), []byte("\x00\x00\x00\x00\x00\x00\x00\x01")})
 	require.NoError(t, err)
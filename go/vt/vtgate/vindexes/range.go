@@ -0,0 +1,302 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"slices"
+	"sort"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	rangeParamSplitTable            = "split_table"
+	rangeParamSplitTableMetadataKey = "split_table_metadata_key"
+)
+
+var (
+	_ SingleColumn    = (*Range)(nil)
+	_ Reversible      = (*Range)(nil)
+	_ Hashing         = (*Range)(nil)
+	_ ParamValidating = (*Range)(nil)
+	_ TopoSplitTable  = (*Range)(nil)
+	_ RangeMapper     = (*Range)(nil)
+
+	rangeParams = []string{
+		rangeParamSplitTable,
+		rangeParamSplitTableMetadataKey,
+	}
+)
+
+func init() {
+	Register("range", newRange)
+}
+
+// RangeSplit is an entry of the split table of a Range vindex. All the ids
+// from From up to the From of the next split are mapped to keyspace ids that
+// start with KeyspaceID.
+type RangeSplit struct {
+	From       uint64 `json:"from"`
+	KeyspaceID string `json:"keyspace_id"`
+}
+
+type rangeSplit struct {
+	from   uint64
+	prefix []byte
+}
+
+// Range is a unique, reversible vindex that maps ranges of numeric ids
+// to keyspace id prefixes, which allows time- or id-range sharding where
+// recent data lives on new shards.
+//
+// The split table is a JSON list of RangeSplit entries, ordered by id.
+// It is given either inline, through the `split_table` param, or as the
+// name of a vitess_metadata key in the topo (`split_table_metadata_key`),
+// which every vtgate watches and loads when it builds the VSchema. A keyspace
+// whose split table is missing or invalid fails all its queries.
+//
+// The keyspace id of an id is the keyspace id prefix of its split, followed
+// by the id encoded as an 8-byte big-endian integer. All the prefixes have the
+// same length and grow with the ids, so the mapping preserves order: a range
+// of ids maps to a single key range.
+type Range struct {
+	name          string
+	metadataKey   string
+	splits        []rangeSplit
+	unknownParams []string
+}
+
+// newRange creates a Range vindex.
+func newRange(name string, m map[string]string) (Vindex, error) {
+	splitTable, hasSplitTable := m[rangeParamSplitTable]
+	metadataKey, hasMetadataKey := m[rangeParamSplitTableMetadataKey]
+	if hasSplitTable == hasMetadataKey {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "range: exactly one of `%s` or `%s` must be specified", rangeParamSplitTable, rangeParamSplitTableMetadataKey)
+	}
+
+	vind := &Range{
+		name:          name,
+		metadataKey:   metadataKey,
+		unknownParams: FindUnknownParams(m, rangeParams),
+	}
+	if hasSplitTable {
+		if err := vind.LoadSplitTable(splitTable); err != nil {
+			return nil, err
+		}
+	}
+	return vind, nil
+}
+
+// String returns the name of the vindex.
+func (vind *Range) String() string {
+	return vind.name
+}
+
+// Cost returns the cost of this vindex as 1.
+func (*Range) Cost() int {
+	return 1
+}
+
+// IsUnique returns true since the Vindex is unique.
+func (*Range) IsUnique() bool {
+	return true
+}
+
+// NeedsVCursor satisfies the Vindex interface.
+func (*Range) NeedsVCursor() bool {
+	return false
+}
+
+// Verify returns true if ids and ksids match.
+func (vind *Range) Verify(ctx context.Context, vcursor VCursor, ids []sqltypes.Value, ksids [][]byte) ([]bool, error) {
+	if err := vind.checkLoaded(); err != nil {
+		return nil, err
+	}
+	out := make([]bool, 0, len(ids))
+	for i, id := range ids {
+		ksid, err := vind.Hash(id)
+		if err != nil {
+			out = append(out, false)
+			continue
+		}
+		out = append(out, bytes.Equal(ksid, ksids[i]))
+	}
+	return out, nil
+}
+
+// Map can map ids to key.Destination objects.
+func (vind *Range) Map(ctx context.Context, vcursor VCursor, ids []sqltypes.Value) ([]key.Destination, error) {
+	if err := vind.checkLoaded(); err != nil {
+		return nil, err
+	}
+	out := make([]key.Destination, 0, len(ids))
+	for _, id := range ids {
+		ksid, err := vind.Hash(id)
+		if err != nil {
+			out = append(out, key.DestinationNone{})
+			continue
+		}
+		out = append(out, key.DestinationKeyspaceID(ksid))
+	}
+	return out, nil
+}
+
+// MapRange implements the RangeMapper interface.
+func (vind *Range) MapRange(ctx context.Context, vcursor VCursor, start, end sqltypes.Value) (key.Destination, error) {
+	if err := vind.checkLoaded(); err != nil {
+		return nil, err
+	}
+
+	from, to, dest := uint64RangeBounds(start, end)
+	if dest != nil {
+		return dest, nil
+	}
+	if to < vind.splits[0].from {
+		return key.DestinationNone{}, nil
+	}
+	from = max(from, vind.splits[0].from)
+
+	kr := &topodatapb.KeyRange{Start: vind.keyspaceID(from)}
+	if to < math.MaxUint64 {
+		kr.End = vind.keyspaceID(to + 1)
+		// If the range ends right before a split, end it at the prefix of that
+		// split so that it doesn't reach into the shard that the split starts.
+		if i, found := slices.BinarySearchFunc(vind.splits, to+1, func(split rangeSplit, num uint64) int {
+			return cmp.Compare(split.from, num)
+		}); found {
+			kr.End = vind.splits[i].prefix
+		}
+	}
+	return key.DestinationKeyRange{KeyRange: kr}, nil
+}
+
+// ReverseMap returns the associated ids for the ksids.
+func (vind *Range) ReverseMap(_ VCursor, ksids [][]byte) ([]sqltypes.Value, error) {
+	if err := vind.checkLoaded(); err != nil {
+		return nil, err
+	}
+	prefixLen := len(vind.splits[0].prefix)
+	reverseIds := make([]sqltypes.Value, 0, len(ksids))
+	for _, ksid := range ksids {
+		if len(ksid) != prefixLen+8 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "range.ReverseMap: length of keyspace id is not %d: %d", prefixLen+8, len(ksid))
+		}
+		reverseIds = append(reverseIds, sqltypes.NewUint64(binary.BigEndian.Uint64(ksid[prefixLen:])))
+	}
+	return reverseIds, nil
+}
+
+// Hash returns the keyspace id of the given id.
+func (vind *Range) Hash(id sqltypes.Value) ([]byte, error) {
+	if err := vind.checkLoaded(); err != nil {
+		return nil, err
+	}
+	num, err := id.ToCastUint64()
+	if err != nil {
+		return nil, err
+	}
+	if num < vind.splits[0].from {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "range: id %d is below the first split %d", num, vind.splits[0].from)
+	}
+	return vind.keyspaceID(num), nil
+}
+
+// UnknownParams implements the ParamValidating interface.
+func (vind *Range) UnknownParams() []string {
+	return vind.unknownParams
+}
+
+// SplitTableMetadataKey implements the TopoSplitTable interface.
+func (vind *Range) SplitTableMetadataKey() string {
+	return vind.metadataKey
+}
+
+// LoadSplitTable implements the TopoSplitTable interface.
+func (vind *Range) LoadSplitTable(splitTable string) error {
+	splits, err := parseRangeSplitTable([]byte(splitTable))
+	if err != nil {
+		return vterrors.Wrapf(err, "range: invalid split table for vindex %s", vind.name)
+	}
+	vind.splits = splits
+	return nil
+}
+
+func (vind *Range) checkLoaded() error {
+	if len(vind.splits) == 0 {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "range: the split table of vindex %s has not been loaded from vitess_metadata key %s", vind.name, vind.metadataKey)
+	}
+	return nil
+}
+
+// keyspaceID returns the keyspace id of num, which must not be below the first split.
+func (vind *Range) keyspaceID(num uint64) []byte {
+	i := sort.Search(len(vind.splits), func(i int) bool {
+		return vind.splits[i].from > num
+	}) - 1
+	prefix := vind.splits[i].prefix
+	ksid := make([]byte, len(prefix)+8)
+	copy(ksid, prefix)
+	binary.BigEndian.PutUint64(ksid[len(prefix):], num)
+	return ksid
+}
+
+func parseRangeSplitTable(data []byte) ([]rangeSplit, error) {
+	var entries []RangeSplit
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the split table is empty")
+	}
+
+	splits := make([]rangeSplit, 0, len(entries))
+	for i, entry := range entries {
+		prefix, err := hex.DecodeString(entry.KeyspaceID)
+		if err != nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid keyspace_id %q: %v", entry.KeyspaceID, err)
+		}
+		if len(prefix) == 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "empty keyspace_id for split from %d", entry.From)
+		}
+		if i > 0 {
+			prev := splits[i-1]
+			if entry.From <= prev.from {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "splits must be ordered by id: %d follows %d", entry.From, prev.from)
+			}
+			if len(prefix) != len(prev.prefix) {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "all keyspace_ids must have the same length: %q and %q", entry.KeyspaceID, entries[i-1].KeyspaceID)
+			}
+			if slices.Compare(prefix, prev.prefix) <= 0 {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "keyspace_ids must grow with the ids: %q follows %q", entry.KeyspaceID, entries[i-1].KeyspaceID)
+			}
+		}
+		splits = append(splits, rangeSplit{from: entry.From, prefix: prefix})
+	}
+	return splits, nil
+}
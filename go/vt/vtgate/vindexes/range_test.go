@@ -0,0 +1,263 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const rangeTestSplitTable = `[{"from": 0, "keyspace_id": "00"}, {"from": 1000, "keyspace_id": "40"}, {"from": 5000, "keyspace_id": "80"}]`
+
+func createRange(t *testing.T) *Range {
+	vindex, err := CreateVindex("range", "range", map[string]string{"split_table": rangeTestSplitTable})
+	require.NoError(t, err)
+	return vindex.(*Range)
+}
+
+func rangeCreateVindexTestCase(
+	testName string,
+	vindexParams map[string]string,
+	expectErr error,
+	expectUnknownParams []string,
+) createVindexTestCase {
+	return createVindexTestCase{
+		testName: testName,
+
+		vindexType:   "range",
+		vindexName:   "range",
+		vindexParams: vindexParams,
+
+		expectCost:          1,
+		expectErr:           expectErr,
+		expectIsUnique:      true,
+		expectNeedsVCursor:  false,
+		expectString:        "range",
+		expectUnknownParams: expectUnknownParams,
+	}
+}
+
+func TestRangeCreateVindex(t *testing.T) {
+	cases := []createVindexTestCase{
+		rangeCreateVindexTestCase(
+			"split table",
+			map[string]string{"split_table": rangeTestSplitTable},
+			nil,
+			nil,
+		),
+		rangeCreateVindexTestCase(
+			"split table metadata key",
+			map[string]string{"split_table_metadata_key": "range_splits"},
+			nil,
+			nil,
+		),
+		rangeCreateVindexTestCase(
+			"no params",
+			nil,
+			errors.New("range: exactly one of `split_table` or `split_table_metadata_key` must be specified"),
+			nil,
+		),
+		rangeCreateVindexTestCase(
+			"both params",
+			map[string]string{"split_table": rangeTestSplitTable, "split_table_metadata_key": "range_splits"},
+			errors.New("range: exactly one of `split_table` or `split_table_metadata_key` must be specified"),
+			nil,
+		),
+		rangeCreateVindexTestCase(
+			"unordered splits",
+			map[string]string{"split_table": `[{"from": 10, "keyspace_id": "00"}, {"from": 5, "keyspace_id": "80"}]`},
+			errors.New("range: invalid split table for vindex range: splits must be ordered by id: 5 follows 10"),
+			nil,
+		),
+		rangeCreateVindexTestCase(
+			"unordered keyspace ids",
+			map[string]string{"split_table": `[{"from": 0, "keyspace_id": "80"}, {"from": 5, "keyspace_id": "40"}]`},
+			errors.New(`range: invalid split table for vindex range: keyspace_ids must grow with the ids: "40" follows "80"`),
+			nil,
+		),
+		rangeCreateVindexTestCase(
+			"keyspace ids of different lengths",
+			map[string]string{"split_table": `[{"from": 0, "keyspace_id": "40"}, {"from": 5, "keyspace_id": "4080"}]`},
+			errors.New(`range: invalid split table for vindex range: all keyspace_ids must have the same length: "4080" and "40"`),
+			nil,
+		),
+		rangeCreateVindexTestCase(
+			"empty split table",
+			map[string]string{"split_table": `[]`},
+			errors.New("range: invalid split table for vindex range: the split table is empty"),
+			nil,
+		),
+		rangeCreateVindexTestCase(
+			"unknown params",
+			map[string]string{"split_table": rangeTestSplitTable, "hello": "world"},
+			nil,
+			[]string{"hello"},
+		),
+	}
+
+	testCreateVindexes(t, cases)
+}
+
+func TestRangeMap(t *testing.T) {
+	got, err := createRange(t).Map(context.Background(), nil, []sqltypes.Value{
+		sqltypes.NewInt64(1),
+		sqltypes.NewInt64(999),
+		sqltypes.NewInt64(1000),
+		sqltypes.NewUint64(6000),
+		sqltypes.NewInt64(-1),
+		sqltypes.NewFloat64(1.1),
+		sqltypes.NULL,
+	})
+	require.NoError(t, err)
+	want := []key.Destination{
+		key.DestinationKeyspaceID("\x00\x00\x00\x00\x00\x00\x00\x00\x01"),
+		key.DestinationKeyspaceID("\x00\x00\x00\x00\x00\x00\x00\x03\xe7"),
+		key.DestinationKeyspaceID("\x40\x00\x00\x00\x00\x00\x00\x03\xe8"),
+		key.DestinationKeyspaceID("\x80\x00\x00\x00\x00\x00\x00\x17\x70"),
+		key.DestinationNone{},
+		key.DestinationNone{},
+		key.DestinationNone{},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestRangeMapBelowFirstSplit(t *testing.T) {
+	vindex, err := CreateVindex("range", "range", map[string]string{"split_table": `[{"from": 100, "keyspace_id": "00"}]`})
+	require.NoError(t, err)
+	got, err := vindex.(SingleColumn).Map(context.Background(), nil, []sqltypes.Value{sqltypes.NewInt64(99), sqltypes.NewInt64(100)})
+	require.NoError(t, err)
+	assert.Equal(t, []key.Destination{
+		key.DestinationNone{},
+		key.DestinationKeyspaceID("\x00\x00\x00\x00\x00\x00\x00\x00\x64"),
+	}, got)
+}
+
+func TestRangeVerify(t *testing.T) {
+	got, err := createRange(t).Verify(context.Background(), nil,
+		[]sqltypes.Value{sqltypes.NewInt64(1000), sqltypes.NewInt64(1000), sqltypes.NewVarBinary("aa")},
+		[][]byte{[]byte("\x40\x00\x00\x00\x00\x00\x00\x03\xe8"), []byte("\x00\x00\x00\x00\x00\x00\x00\x03\xe8"), nil})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false, false}, got)
+}
+
+func TestRangeReverseMap(t *testing.T) {
+	got, err := createRange(t).ReverseMap(nil, [][]byte{[]byte("\x40\x00\x00\x00\x00\x00\x00\x03\xe8")})
+	require.NoError(t, err)
+	assert.Equal(t, []sqltypes.Value{sqltypes.NewUint64(1000)}, got)
+
+	_, err = createRange(t).ReverseMap(nil, [][]byte{[]byte("\x00\x00\x00\x00\x00\x00\x00\x01")})
+	assert.EqualError(t, err, "range.ReverseMap: length of keyspace id is not 9: 8")
+}
+
+func TestRangeMapRange(t *testing.T) {
+	shards := []*topodatapb.ShardReference{
+		{Name: "-40", KeyRange: &topodatapb.KeyRange{End: []byte{0x40}}},
+		{Name: "40-80", KeyRange: &topodatapb.KeyRange{Start: []byte{0x40}, End: []byte{0x80}}},
+		{Name: "80-", KeyRange: &topodatapb.KeyRange{Start: []byte{0x80}}},
+	}
+	vind := createRange(t)
+
+	testCases := []struct {
+		name       string
+		start, end sqltypes.Value
+		want       []string
+	}{{
+		name:  "within a split",
+		start: sqltypes.NewInt64(10),
+		end:   sqltypes.NewInt64(20),
+		want:  []string{"-40"},
+	}, {
+		name:  "up to the end of a split",
+		start: sqltypes.NewInt64(10),
+		end:   sqltypes.NewInt64(999),
+		want:  []string{"-40"},
+	}, {
+		name:  "across two splits",
+		start: sqltypes.NewInt64(10),
+		end:   sqltypes.NewInt64(1000),
+		want:  []string{"-40", "40-80"},
+	}, {
+		name:  "open start",
+		start: sqltypes.NULL,
+		end:   sqltypes.NewInt64(4999),
+		want:  []string{"-40", "40-80"},
+	}, {
+		name:  "open end",
+		start: sqltypes.NewInt64(1000),
+		end:   sqltypes.NULL,
+		want:  []string{"40-80", "80-"},
+	}, {
+		name:  "negative start",
+		start: sqltypes.NewInt64(-10),
+		end:   sqltypes.NewInt64(10),
+		want:  []string{"-40"},
+	}, {
+		name:  "negative end",
+		start: sqltypes.NewInt64(-10),
+		end:   sqltypes.NewInt64(-5),
+		want:  nil,
+	}, {
+		name:  "empty range",
+		start: sqltypes.NewInt64(20),
+		end:   sqltypes.NewInt64(10),
+		want:  nil,
+	}, {
+		name:  "not a number",
+		start: sqltypes.NewVarChar("abc"),
+		end:   sqltypes.NewInt64(10),
+		want:  []string{"-40", "40-80", "80-"},
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dest, err := vind.MapRange(context.Background(), nil, tc.start, tc.end)
+			require.NoError(t, err)
+			var got []string
+			err = dest.Resolve(shards, func(shard string) error {
+				got = append(got, shard)
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRangeSplitTableFromTopo(t *testing.T) {
+	vindex, err := CreateVindex("range", "range", map[string]string{"split_table_metadata_key": "range_splits"})
+	require.NoError(t, err)
+	vind := vindex.(*Range)
+	assert.Equal(t, "range_splits", vind.SplitTableMetadataKey())
+
+	_, err = vind.Map(context.Background(), nil, []sqltypes.Value{sqltypes.NewInt64(1)})
+	assert.EqualError(t, err, "range: the split table of vindex range has not been loaded from vitess_metadata key range_splits")
+
+	require.NoError(t, vind.LoadSplitTable(rangeTestSplitTable))
+	got, err := vind.Map(context.Background(), nil, []sqltypes.Value{sqltypes.NewInt64(1)})
+	require.NoError(t, err)
+	assert.Equal(t, []key.Destination{key.DestinationKeyspaceID("\x00\x00\x00\x00\x00\x00\x00\x00\x01")}, got)
+
+	assert.EqualError(t, vind.LoadSplitTable("{"), "range: invalid split table for vindex range: unexpected end of JSON input")
+}
@@ -19,6 +19,7 @@ package vindexes
 import (
 	"context"
 	"fmt"
+	"math"
 	"sort"
 
 	"vitess.io/vitess/go/mysql/collations"
@@ -138,6 +139,30 @@ type (
 		PrefixVindex() SingleColumn
 	}
 
+	// A RangeMapper vindex is one that preserves the order of the ids in
+	// their keyspace ids, so that a range of ids maps to a single key range.
+	// It is optional. If present, VTGate uses it to route range predicates
+	// (BETWEEN, <, <=, >, >=) on the vindex column to a subset of the shards.
+	RangeMapper interface {
+		SingleColumn
+		// MapRange maps all the ids between start and end, both included,
+		// to a key.Destination. A NULL start or end leaves that side of the
+		// range open. If the range cannot be narrowed down, e.g. because a
+		// bound has an unexpected type, all the shards are returned.
+		MapRange(ctx context.Context, vcursor VCursor, start, end sqltypes.Value) (key.Destination, error)
+	}
+
+	// A TopoSplitTable vindex is one that can keep its split table in the topo,
+	// as a vitess_metadata value, instead of the VSchema. VTGate watches the value
+	// and loads the split table every time it builds the VSchema.
+	TopoSplitTable interface {
+		// SplitTableMetadataKey returns the vitess_metadata key that holds the
+		// split table, or an empty string if the split table is in the VSchema.
+		SplitTableMetadataKey() string
+		// LoadSplitTable loads the split table read from the topo.
+		LoadSplitTable(splitTable string) error
+	}
+
 	// A Lookup vindex is one that needs to lookup
 	// a previously stored map to compute the keyspace
 	// id from an id. This means that the creation of
@@ -253,3 +278,40 @@ func FindUnknownParams(params map[string]string, knownParams []string) []string
 	sort.Strings(unknownParams)
 	return unknownParams
 }
+
+// uint64RangeBounds returns the bounds of a range of ids that map to uint64 values,
+// as used by RangeMapper vindexes. A NULL bound is open, and a negative start is
+// the same as an open one. If the range is empty, or cannot be narrowed down because
+// a bound is not an unsigned integer, the destination to route to is returned instead.
+func uint64RangeBounds(start, end sqltypes.Value) (from, to uint64, dest key.Destination) {
+	from, to = 0, math.MaxUint64
+	if !start.IsNull() {
+		num, err := start.ToCastUint64()
+		switch {
+		case err == nil:
+			from = num
+		case !isNegativeInteger(start):
+			return 0, 0, key.DestinationAllShards{}
+		}
+	}
+	if !end.IsNull() {
+		num, err := end.ToCastUint64()
+		switch {
+		case err == nil:
+			to = num
+		case isNegativeInteger(end):
+			return 0, 0, key.DestinationNone{}
+		default:
+			return 0, 0, key.DestinationAllShards{}
+		}
+	}
+	if from > to {
+		return 0, 0, key.DestinationNone{}
+	}
+	return from, to, nil
+}
+
+func isNegativeInteger(v sqltypes.Value) bool {
+	num, err := v.ToCastInt64()
+	return err == nil && num < 0
+}
@@ -60,6 +60,13 @@ const (
 	TypeReference = "reference"
 )
 
+// The following constants represent auto-increment modes.
+const (
+	AutoIncrementSequence        = "sequence"
+	AutoIncrementShardedSequence = "sharded_sequence"
+	AutoIncrementSnowflake       = "snowflake"
+)
+
 // VSchema represents the denormalized version of SrvVSchema,
 // used for building routing plans.
 type VSchema struct {
@@ -331,6 +338,8 @@ func (ks *KeyspaceSchema) MarshalJSON() ([]byte, error) {
 type AutoIncrement struct {
 	Column   sqlparser.IdentifierCI `json:"column"`
 	Sequence *Table                 `json:"sequence"`
+	// Mode is one of the AutoIncrement modes. It is empty for AutoIncrementSequence.
+	Mode string `json:"mode,omitempty"`
 }
 
 type Source struct {
@@ -627,6 +636,7 @@ func buildTables(ks *vschemapb.Keyspace, vschema *VSchema, ksvschema *KeyspaceSc
 		}
 		ksvschema.Vindexes[vname] = vindex
 	}
+	shardedSequences := shardedSequenceTables(ks, keyspace.Name, parser)
 	for tname, table := range ks.Tables {
 		t := &Table{
 			Name:                    sqlparser.NewIdentifierCS(tname),
@@ -652,7 +662,7 @@ func buildTables(ks *vschemapb.Keyspace, vschema *VSchema, ksvschema *KeyspaceSc
 			}
 			t.Type = table.Type
 		case TypeSequence:
-			if keyspace.Sharded && table.Pinned == "" {
+			if keyspace.Sharded && table.Pinned == "" && !shardedSequences[tname] {
 				return vterrors.Errorf(
 					vtrpcpb.Code_FAILED_PRECONDITION,
 					"sequence table has to be in an unsharded keyspace or must be pinned: %s",
@@ -679,8 +689,8 @@ func buildTables(ks *vschemapb.Keyspace, vschema *VSchema, ksvschema *KeyspaceSc
 			t.Pinned = decoded
 		}
 
-		// If keyspace is sharded, then any table that's not a reference or pinned must have vindexes.
-		if keyspace.Sharded && t.Type != TypeReference && table.Pinned == "" && len(table.ColumnVindexes) == 0 {
+		// If keyspace is sharded, then any table that's not a reference, the sequence of a sharded sequence or pinned must have vindexes.
+		if keyspace.Sharded && t.Type != TypeReference && !shardedSequences[tname] && table.Pinned == "" && len(table.ColumnVindexes) == 0 {
 			return vterrors.Errorf(
 				vtrpcpb.Code_NOT_FOUND,
 				"missing primary col vindex for table: %s",
@@ -870,15 +880,7 @@ func resolveAutoIncrement(source *vschemapb.SrvVSchema, vschema *VSchema, parser
 			if t == nil || table.AutoIncrement == nil {
 				continue
 			}
-			seqks, seqtab, err := parser.ParseTable(table.AutoIncrement.Sequence)
-			var seq *Table
-			if err == nil {
-				// Ensure that sequence tables also obey routing rules.
-				seq, err = vschema.FindRoutedTable(seqks, seqtab, topodatapb.TabletType_PRIMARY)
-				if seq == nil && err == nil {
-					err = vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found", seqtab)
-				}
-			}
+			seq, err := resolveSequence(table.AutoIncrement, vschema, parser)
 			if err != nil {
 				// Better to remove the table than to leave it partially initialized.
 				delete(ksvschema.Tables, tname)
@@ -895,11 +897,64 @@ func resolveAutoIncrement(source *vschemapb.SrvVSchema, vschema *VSchema, parser
 			t.AutoIncrement = &AutoIncrement{
 				Column:   sqlparser.NewIdentifierCI(table.AutoIncrement.Column),
 				Sequence: seq,
+				Mode:     table.AutoIncrement.Mode,
 			}
 		}
 	}
 }
 
+// shardedSequenceTables returns the names of the sequence tables of the keyspace that back
+// sharded_sequence auto-increment columns. Such a table has a row on every shard, so it needs
+// neither a vindex nor a pin, but it has to be used by a table of its own keyspace.
+func shardedSequenceTables(ks *vschemapb.Keyspace, ksName string, parser *sqlparser.Parser) map[string]bool {
+	shardedSequences := make(map[string]bool)
+	for _, table := range ks.Tables {
+		if table.AutoIncrement == nil || table.AutoIncrement.Mode != AutoIncrementShardedSequence {
+			continue
+		}
+		seqks, seqtab, err := parser.ParseTable(table.AutoIncrement.Sequence)
+		if err != nil || (seqks != "" && seqks != ksName) {
+			continue
+		}
+		if seq, ok := ks.Tables[seqtab]; ok && seq.Type == TypeSequence {
+			shardedSequences[seqtab] = true
+		}
+	}
+	return shardedSequences
+}
+
+// resolveSequence returns the sequence table of an auto-increment column,
+// or nil if its mode does not need one.
+func resolveSequence(autoInc *vschemapb.AutoIncrement, vschema *VSchema, parser *sqlparser.Parser) (*Table, error) {
+	switch autoInc.Mode {
+	case "", AutoIncrementSequence, AutoIncrementShardedSequence:
+	case AutoIncrementSnowflake:
+		if autoInc.Sequence != "" {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the %s mode does not use a sequence", autoInc.Mode)
+		}
+		return nil, nil
+	default:
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown auto_increment mode %s", autoInc.Mode)
+	}
+
+	seqks, seqtab, err := parser.ParseTable(autoInc.Sequence)
+	if err != nil {
+		return nil, err
+	}
+	// Ensure that sequence tables also obey routing rules.
+	seq, err := vschema.FindRoutedTable(seqks, seqtab, topodatapb.TabletType_PRIMARY)
+	if err != nil {
+		return nil, err
+	}
+	if seq == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found", seqtab)
+	}
+	if autoInc.Mode != AutoIncrementShardedSequence && seq.Keyspace.Sharded && seq.Pinned == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "sequence table has to be in an unsharded keyspace or must be pinned, unless the mode is %s: %s", AutoIncrementShardedSequence, seqtab)
+	}
+	return seq, nil
+}
+
 // expects table name of the form <keyspace>.<tablename>
 func escapeQualifiedTable(qualifiedTableName string) (string, error) {
 	keyspace, tableName, err := extractTableParts(qualifiedTableName, false /* allowUnqualified */)
@@ -2653,6 +2653,110 @@ func TestBadShardedSequence(t *testing.T) {
 	}
 }
 
+func TestAutoIncrementModes(t *testing.T) {
+	table := func(autoInc *vschemapb.AutoIncrement) *vschemapb.Table {
+		return &vschemapb.Table{
+			ColumnVindexes: []*vschemapb.ColumnVindex{{
+				Column: "c1",
+				Name:   "stfu1",
+			}},
+			AutoIncrement: autoInc,
+		}
+	}
+	input := vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"sharded": {
+				Sharded: true,
+				Vindexes: map[string]*vschemapb.Vindex{
+					"stfu1": {
+						Type: "stfu",
+					},
+				},
+				Tables: map[string]*vschemapb.Table{
+					"seq": {
+						Type: "sequence",
+					},
+					"sharded_seq": table(&vschemapb.AutoIncrement{Column: "c1", Sequence: "seq", Mode: "sharded_sequence"}),
+					"snowflake":   table(&vschemapb.AutoIncrement{Column: "c1", Mode: "snowflake"}),
+				},
+			},
+		},
+	}
+	got := BuildVSchema(&input, sqlparser.NewTestParser())
+	ks := got.Keyspaces["sharded"]
+	require.NoError(t, ks.Error)
+	assert.Equal(t, AutoIncrementShardedSequence, ks.Tables["sharded_seq"].AutoIncrement.Mode)
+	assert.Equal(t, ks.Tables["seq"], ks.Tables["sharded_seq"].AutoIncrement.Sequence)
+	assert.Equal(t, AutoIncrementSnowflake, ks.Tables["snowflake"].AutoIncrement.Mode)
+	assert.Nil(t, ks.Tables["snowflake"].AutoIncrement.Sequence)
+
+	testCases := []struct {
+		name    string
+		autoInc *vschemapb.AutoIncrement
+		wantErr string
+	}{{
+		name:    "snowflake with a sequence",
+		autoInc: &vschemapb.AutoIncrement{Column: "c1", Sequence: "seq", Mode: "snowflake"},
+		wantErr: "cannot resolve sequence seq: the snowflake mode does not use a sequence",
+	}, {
+		name:    "unknown mode",
+		autoInc: &vschemapb.AutoIncrement{Column: "c1", Sequence: "seq", Mode: "uuid"},
+		wantErr: "cannot resolve sequence seq: unknown auto_increment mode uuid",
+	}, {
+		name:    "sequence mode with the sequence of a sharded sequence",
+		autoInc: &vschemapb.AutoIncrement{Column: "c1", Sequence: "seq"},
+		wantErr: "cannot resolve sequence seq: sequence table has to be in an unsharded keyspace or must be pinned, unless the mode is sharded_sequence: seq",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			input.Keyspaces["sharded"].Tables = map[string]*vschemapb.Table{
+				"seq":         {Type: "sequence"},
+				"sharded_seq": table(&vschemapb.AutoIncrement{Column: "c1", Sequence: "seq", Mode: "sharded_sequence"}),
+				"t1":          table(tc.autoInc),
+			}
+			got := BuildVSchema(&input, sqlparser.NewTestParser())
+			assert.EqualError(t, got.Keyspaces["sharded"].Error, tc.wantErr)
+			assert.Nil(t, got.Keyspaces["sharded"].Tables["t1"])
+		})
+	}
+}
+
+func TestShardedSequenceInAnotherKeyspace(t *testing.T) {
+	// The sequence of a sharded sequence needs neither a vindex nor a pin
+	// only if a table of its own keyspace uses it.
+	input := vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"sharded": {
+				Sharded: true,
+				Vindexes: map[string]*vschemapb.Vindex{
+					"stfu1": {
+						Type: "stfu",
+					},
+				},
+				Tables: map[string]*vschemapb.Table{
+					"t1": {
+						ColumnVindexes: []*vschemapb.ColumnVindex{{
+							Column: "c1",
+							Name:   "stfu1",
+						}},
+						AutoIncrement: &vschemapb.AutoIncrement{Column: "c1", Sequence: "seqks.seq", Mode: "sharded_sequence"},
+					},
+				},
+			},
+			"seqks": {
+				Sharded: true,
+				Tables: map[string]*vschemapb.Table{
+					"seq": {
+						Type: "sequence",
+					},
+				},
+			},
+		},
+	}
+	got := BuildVSchema(&input, sqlparser.NewTestParser())
+	assert.EqualError(t, got.Keyspaces["seqks"].Error, "sequence table has to be in an unsharded keyspace or must be pinned: seq")
+}
+
 func TestFindTable(t *testing.T) {
 	input := vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
@@ -19,7 +19,10 @@ package vtgate
 import (
 	"context"
 	"sync"
+	"time"
 
+	"vitess.io/vitess/go/ptr"
+	"vitess.io/vitess/go/timer"
 	"vitess.io/vitess/go/vt/graph"
 	"vitess.io/vitess/go/vt/log"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -31,6 +34,7 @@ import (
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 var _ VSchemaOperator = (*VSchemaManager)(nil)
@@ -46,8 +50,19 @@ type VSchemaManager struct {
 	subscriber        func(vschema *vindexes.VSchema, stats *VSchemaStats)
 	schema            SchemaInfo
 	parser            *sqlparser.Parser
+
+	// ctx bounds the watches on the vitess_metadata keys holding split tables.
+	// They are not started when it is nil.
+	ctx context.Context
+	// metadataRetryDelay is how long we wait before restarting a failed watch on a vitess_metadata key
+	metadataRetryDelay time.Duration
+	metadataMu         sync.Mutex
+	metadataWatches    map[string]context.CancelFunc
 }
 
+// metadataWatchRetryDelay is the default metadataRetryDelay of the VSchemaManager
+const metadataWatchRetryDelay = 30 * time.Second
+
 // SchemaInfo is an interface to schema tracker.
 type SchemaInfo interface {
 	Tables(ks string) map[string]*vindexes.TableInfo
@@ -191,6 +206,7 @@ func (vm *VSchemaManager) Rebuild() {
 // buildAndEnhanceVSchema builds a new VSchema and uses information from the schema tracker to update it
 func (vm *VSchemaManager) buildAndEnhanceVSchema(v *vschemapb.SrvVSchema) *vindexes.VSchema {
 	vschema := vindexes.BuildVSchema(v, vm.parser)
+	vm.loadTopoSplitTables(vschema)
 	if vm.schema != nil {
 		vm.updateFromSchema(vschema)
 		// We mark the keyspaces that have foreign key management in Vitess and have cyclic foreign keys
@@ -200,6 +216,144 @@ func (vm *VSchemaManager) buildAndEnhanceVSchema(v *vschemapb.SrvVSchema) *vinde
 	return vschema
 }
 
+// loadTopoSplitTables loads the split tables of the vindexes that keep them in the topo, as vitess_metadata values,
+// and makes sure we watch those values so that we rebuild the VSchema when any of them changes.
+// A split table that cannot be loaded marks the vindex keyspace as failed.
+func (vm *VSchemaManager) loadTopoSplitTables(vschema *vindexes.VSchema) {
+	var (
+		metadata    map[string]string
+		metadataErr error
+		watched     = map[string]*string{}
+	)
+	for ksName, ks := range vschema.Keyspaces {
+		for vdxName, vdx := range ks.Vindexes {
+			tst, ok := vdx.(vindexes.TopoSplitTable)
+			if !ok || tst.SplitTableMetadataKey() == "" {
+				continue
+			}
+			key := tst.SplitTableMetadataKey()
+			if metadata == nil && metadataErr == nil {
+				metadata, metadataErr = vm.readVitessMetadata()
+			}
+			var err error
+			switch splitTable, found := metadata[key]; {
+			case metadataErr != nil:
+				err = vterrors.Wrapf(metadataErr, "failed to read the split table of vindex %s from vitess_metadata", vdxName)
+			case !found:
+				watched[key] = nil
+				err = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "split table of vindex %s not found in vitess_metadata key %s", vdxName, key)
+			default:
+				watched[key] = &splitTable
+				if err = tst.LoadSplitTable(splitTable); err != nil {
+					err = vterrors.Wrapf(err, "failed to load the split table of vindex %s", vdxName)
+				}
+			}
+			if err != nil {
+				log.Errorf("Keyspace %s: %v", ksName, err)
+				if ks.Error == nil {
+					ks.Error = err
+				}
+			}
+		}
+	}
+	if metadataErr == nil {
+		vm.updateMetadataWatches(watched)
+	}
+}
+
+func (vm *VSchemaManager) readVitessMetadata() (map[string]string, error) {
+	if vm.serv == nil {
+		return nil, vterrors.New(vtrpcpb.Code_UNAVAILABLE, "no topo server available to read vitess_metadata")
+	}
+	ts, err := vm.serv.GetTopoServer()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
+	defer cancel()
+	metadata, err := ts.GetMetadata(ctx, "")
+	if topo.IsErrType(err, topo.NoNode) {
+		return map[string]string{}, nil
+	}
+	return metadata, err
+}
+
+// updateMetadataWatches starts watching the given vitess_metadata keys, and stops watching the keys
+// that are no longer used. Each key maps to the value the VSchema was built with, or nil if it was missing.
+// The VSchema of every vtgate is rebuilt whenever a split table changes in the topo, not only the
+// one of the vtgate that ran the SET statement.
+func (vm *VSchemaManager) updateMetadataWatches(keys map[string]*string) {
+	if vm.ctx == nil {
+		return
+	}
+	vm.metadataMu.Lock()
+	defer vm.metadataMu.Unlock()
+
+	for key, cancel := range vm.metadataWatches {
+		if _, ok := keys[key]; !ok {
+			cancel()
+			delete(vm.metadataWatches, key)
+		}
+	}
+	for key, value := range keys {
+		if _, ok := vm.metadataWatches[key]; ok {
+			continue
+		}
+		if vm.metadataWatches == nil {
+			vm.metadataWatches = make(map[string]context.CancelFunc)
+		}
+		ctx, cancel := context.WithCancel(vm.ctx)
+		vm.metadataWatches[key] = cancel
+		go vm.watchMetadata(ctx, key, value)
+	}
+}
+
+// watchMetadata rebuilds the VSchema every time the given vitess_metadata key changes, until ctx is done.
+// The watch is restarted after metadataRetryDelay if it fails, e.g. because the key does not exist yet.
+func (vm *VSchemaManager) watchMetadata(ctx context.Context, key string, value *string) {
+	for ctx.Err() == nil {
+		// The last value we saw is kept across restarts, so that we only rebuild when it really changed.
+		value = vm.watchMetadataOnce(ctx, key, value)
+		_ = timer.SleepContext(ctx, vm.metadataRetryDelay)
+	}
+}
+
+// watchMetadataOnce runs a single watch on the key and rebuilds the VSchema every time the value of the key
+// differs from the last one seen, which is nil while the key is missing. It returns the last value seen.
+func (vm *VSchemaManager) watchMetadataOnce(ctx context.Context, key string, value *string) *string {
+	ts, err := vm.serv.GetTopoServer()
+	if err != nil {
+		return value
+	}
+	current, changes, err := ts.WatchMetadata(ctx, key)
+	if err != nil {
+		if topo.IsErrType(err, topo.NoNode) {
+			return vm.rebuildOnMetadataChange(value, nil)
+		}
+		return value
+	}
+	value = vm.rebuildOnMetadataChange(value, ptr.Of(string(current.Contents)))
+	for wd := range changes {
+		if wd.Err != nil {
+			if topo.IsErrType(wd.Err, topo.NoNode) {
+				return vm.rebuildOnMetadataChange(value, nil)
+			}
+			return value
+		}
+		value = vm.rebuildOnMetadataChange(value, ptr.Of(string(wd.Contents)))
+	}
+	return value
+}
+
+// rebuildOnMetadataChange rebuilds the VSchema if the new value of a vitess_metadata key, nil if the key is
+// missing, differs from the last value seen. It returns the new value, which becomes the last value seen.
+func (vm *VSchemaManager) rebuildOnMetadataChange(value, newValue *string) *string {
+	if (value == nil) != (newValue == nil) || (value != nil && *value != *newValue) {
+		vm.Rebuild()
+	}
+	return newValue
+}
+
 func (vm *VSchemaManager) updateFromSchema(vschema *vindexes.VSchema) {
 	for ksName, ks := range vschema.Keyspaces {
 		vm.updateTableInfo(vschema, ks, ksName)
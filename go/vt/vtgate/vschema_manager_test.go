@@ -1,11 +1,17 @@
 package vtgate
 
 import (
+	"context"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"vitess.io/vitess/go/ptr"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/key"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	"vitess.io/vitess/go/vt/sqlparser"
@@ -906,3 +912,182 @@ func (f *fakeSchema) Views(string) map[string]sqlparser.SelectStatement {
 func (f *fakeSchema) UDFs(string) []string { return f.udfs }
 
 var _ SchemaInfo = (*fakeSchema)(nil)
+
+func TestLoadTopoSplitTables(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+	serv := newSandboxForCells(ctx, []string{"aa"})
+	ts, err := serv.GetTopoServer()
+	require.NoError(t, err)
+	require.NoError(t, ts.UpsertMetadata(ctx, "orders_splits", `[{"from": 0, "keyspace_id": "00"}, {"from": 1000, "keyspace_id": "80"}]`))
+	require.NoError(t, ts.UpsertMetadata(ctx, "bad_splits", `not json`))
+
+	vm := &VSchemaManager{serv: serv, parser: sqlparser.NewTestParser()}
+	var vs *vindexes.VSchema
+	vm.subscriber = func(vschema *vindexes.VSchema, _ *VSchemaStats) {
+		vs = vschema
+	}
+	vm.VSchemaUpdate(&vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"ks":      splitTableKeyspace("orders_splits"),
+			"missing": splitTableKeyspace("missing_splits"),
+			"bad":     splitTableKeyspace("bad_splits"),
+		},
+	}, nil)
+	require.NotNil(t, vs)
+
+	require.NoError(t, vs.Keyspaces["ks"].Error)
+	ordersRange := vs.Keyspaces["ks"].Vindexes["range_vdx"].(vindexes.SingleColumn)
+	dests, err := ordersRange.Map(ctx, nil, []sqltypes.Value{sqltypes.NewInt64(1500)})
+	require.NoError(t, err)
+	require.Equal(t, []key.Destination{key.DestinationKeyspaceID("\x80\x00\x00\x00\x00\x00\x00\x05\xdc")}, dests)
+
+	require.EqualError(t, vs.Keyspaces["missing"].Error, "split table of vindex range_vdx not found in vitess_metadata key missing_splits")
+	require.ErrorContains(t, vs.Keyspaces["bad"].Error, "failed to load the split table of vindex range_vdx")
+
+	// without a topo server we cannot read any split table
+	vm = &VSchemaManager{parser: sqlparser.NewTestParser()}
+	vm.subscriber = func(vschema *vindexes.VSchema, _ *VSchemaStats) {
+		vs = vschema
+	}
+	vm.VSchemaUpdate(&vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{"ks": splitTableKeyspace("orders_splits")},
+	}, nil)
+	require.ErrorContains(t, vs.Keyspaces["ks"].Error, "failed to read the split table of vindex range_vdx from vitess_metadata")
+}
+
+// TestWatchTopoSplitTables checks that a split table change made through one vtgate
+// is picked up by the VSchema of another vtgate.
+func TestWatchTopoSplitTables(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+	serv := newSandboxForCells(ctx, []string{"aa"})
+	ts, err := serv.GetTopoServer()
+	require.NoError(t, err)
+
+	srvVSchema := &vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{"ks": splitTableKeyspace("orders_splits")},
+	}
+	newManager := func() *atomic.Pointer[vindexes.VSchema] {
+		var vs atomic.Pointer[vindexes.VSchema]
+		vm := &VSchemaManager{serv: serv, parser: sqlparser.NewTestParser(), ctx: ctx, metadataRetryDelay: 10 * time.Millisecond}
+		vm.subscriber = func(vschema *vindexes.VSchema, _ *VSchemaStats) {
+			vs.Store(vschema)
+		}
+		vm.VSchemaUpdate(srvVSchema.CloneVT(), nil)
+		return &vs
+	}
+	mapValue := func(vs *vindexes.VSchema) (key.Destination, error) {
+		if err := vs.Keyspaces["ks"].Error; err != nil {
+			return nil, err
+		}
+		dests, err := vs.Keyspaces["ks"].Vindexes["range_vdx"].(vindexes.SingleColumn).Map(ctx, nil, []sqltypes.Value{sqltypes.NewInt64(1500)})
+		if err != nil {
+			return nil, err
+		}
+		return dests[0], nil
+	}
+
+	// the split table does not exist yet when both vtgates start
+	vtgate1, vtgate2 := newManager(), newManager()
+	_, err = mapValue(vtgate2.Load())
+	require.EqualError(t, err, "split table of vindex range_vdx not found in vitess_metadata key orders_splits")
+
+	waitFor := func(want key.Destination) {
+		t.Helper()
+		for _, vs := range []*atomic.Pointer[vindexes.VSchema]{vtgate1, vtgate2} {
+			require.Eventually(t, func() bool {
+				got, err := mapValue(vs.Load())
+				return err == nil && got.String() == want.String()
+			}, 5*time.Second, 10*time.Millisecond)
+		}
+	}
+
+	require.NoError(t, ts.UpsertMetadata(ctx, "orders_splits", `[{"from": 0, "keyspace_id": "00"}]`))
+	waitFor(key.DestinationKeyspaceID("\x00\x00\x00\x00\x00\x00\x00\x05\xdc"))
+
+	require.NoError(t, ts.UpsertMetadata(ctx, "orders_splits", `[{"from": 0, "keyspace_id": "00"}, {"from": 1000, "keyspace_id": "80"}]`))
+	waitFor(key.DestinationKeyspaceID("\x80\x00\x00\x00\x00\x00\x00\x05\xdc"))
+
+	require.NoError(t, ts.DeleteMetadata(ctx, "orders_splits"))
+	require.Eventually(t, func() bool {
+		_, err := mapValue(vtgate2.Load())
+		return err != nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// TestWatchMetadataLastValue checks that a restarted watch only rebuilds the VSchema
+// if the value of the key differs from the last one seen by the previous watch.
+func TestWatchMetadataLastValue(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+	serv := newSandboxForCells(ctx, []string{"aa"})
+	ts, err := serv.GetTopoServer()
+	require.NoError(t, err)
+
+	var rebuilds atomic.Int64
+	vm := &VSchemaManager{serv: serv, parser: sqlparser.NewTestParser()}
+	vm.subscriber = func(*vindexes.VSchema, *VSchemaStats) {
+		rebuilds.Add(1)
+	}
+	vm.VSchemaUpdate(&vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{"ks": splitTableKeyspace("orders_splits")},
+	}, nil)
+	rebuilds.Store(0)
+
+	// watchOnce runs a single watch that is interrupted once wantRebuilds were done
+	watchOnce := func(value *string, wantRebuilds int64, change func()) *string {
+		t.Helper()
+		watchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		done := make(chan *string)
+		go func() {
+			done <- vm.watchMetadataOnce(watchCtx, "orders_splits", value)
+		}()
+		if change != nil {
+			change()
+		}
+		require.Eventually(t, func() bool {
+			return rebuilds.Load() == wantRebuilds
+		}, 5*time.Second, 10*time.Millisecond)
+		// give the watch the time to see changes that it should not rebuild for
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+		return <-done
+	}
+
+	require.NoError(t, ts.UpsertMetadata(ctx, "orders_splits", "a"))
+	value := watchOnce(ptr.Of("a"), 0, nil)
+	require.Equal(t, "a", *value)
+
+	// the value changed between two watches
+	require.NoError(t, ts.UpsertMetadata(ctx, "orders_splits", "b"))
+	value = watchOnce(value, 1, nil)
+	require.Equal(t, "b", *value)
+
+	// the value changed during the watch, and the next watch starts from it
+	value = watchOnce(value, 2, func() {
+		require.NoError(t, ts.UpsertMetadata(ctx, "orders_splits", "c"))
+	})
+	require.Equal(t, "c", *value)
+	value = watchOnce(value, 2, nil)
+	require.Equal(t, "c", *value)
+
+	// the key was deleted since the last watch
+	require.NoError(t, ts.DeleteMetadata(ctx, "orders_splits"))
+	value = vm.watchMetadataOnce(ctx, "orders_splits", value)
+	require.Nil(t, value)
+	require.EqualValues(t, 3, rebuilds.Load())
+	value = vm.watchMetadataOnce(ctx, "orders_splits", value)
+	require.Nil(t, value)
+	require.EqualValues(t, 3, rebuilds.Load())
+}
+
+func splitTableKeyspace(metadataKey string) *vschemapb.Keyspace {
+	return &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"range_vdx": {
+				Type:   "range",
+				Params: map[string]string{"split_table_metadata_key": metadataKey},
+			},
+		},
+	}
+}
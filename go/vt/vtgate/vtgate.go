@@ -70,6 +70,7 @@ var (
 
 	maxMemoryRows   = 300000
 	warnMemoryRows  = 30000
+	spillDir        string
 	maxPayloadSize  int
 	warnPayloadSize int
 
@@ -117,6 +118,11 @@ var (
 	warmingReadsPercent      = 0
 	warmingReadsQueryTimeout = 5 * time.Second
 	warmingReadsConcurrency  = 500
+
+	// sequenceNodeID identifies this vtgate among the vtgates that generate
+	// snowflake ids and sharded sequence values. It is -1 when not set, in which
+	// case the inserts that need such values fail.
+	sequenceNodeID = -1
 )
 
 func registerFlags(fs *pflag.FlagSet) {
@@ -127,6 +133,7 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&streamBufferSize, "stream_buffer_size", streamBufferSize, "the number of bytes sent from vtgate for each stream call. It's recommended to keep this value in sync with vttablet's query-server-config-stream-buffer-size.")
 	fs.Int64Var(&queryPlanCacheMemory, "gate_query_cache_memory", queryPlanCacheMemory, "gate server query cache size in bytes, maximum amount of memory to be cached. vtgate analyzes every incoming query and generate a query plan, these plans are being cached in a lru cache. This config controls the capacity of the lru cache.")
 	fs.IntVar(&maxMemoryRows, "max_memory_rows", maxMemoryRows, "Maximum number of rows that will be held in memory for intermediate results as well as the final result.")
+	fs.StringVar(&spillDir, "spill-dir", spillDir, "Temporary directory where sorts, distincts, hash joins and the sorts feeding aggregations spill sorted runs and hash partitions to disk, instead of failing once they hold more than max_memory_rows rows. Spilling is disabled when empty.")
 	fs.IntVar(&warnMemoryRows, "warn_memory_rows", warnMemoryRows, "Warning threshold for in-memory results. A row count higher than this amount will cause the VtGateWarnings.ResultsExceeded counter to be incremented.")
 	fs.StringVar(&defaultDDLStrategy, "ddl_strategy", defaultDDLStrategy, "Set default strategy for DDL statements. Override with @@ddl_strategy session variable")
 	fs.StringVar(&dbDDLPlugin, "dbddl_plugin", dbDDLPlugin, "controls how to handle CREATE/DROP DATABASE. use it if you are using your own database provisioning service")
@@ -154,6 +161,7 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&warmingReadsPercent, "warming-reads-percent", 0, "Percentage of reads on the primary to forward to replicas. Useful for keeping buffer pools warm")
 	fs.IntVar(&warmingReadsConcurrency, "warming-reads-concurrency", 500, "Number of concurrent warming reads allowed")
 	fs.DurationVar(&warmingReadsQueryTimeout, "warming-reads-query-timeout", 5*time.Second, "Timeout of warming read queries")
+	fs.IntVar(&sequenceNodeID, "sequence-node-id", sequenceNodeID, "Node id of this vtgate, between 0 and 1023, for the snowflake and sharded_sequence auto_increment modes. Every vtgate must have its own node id to generate unique values. Inserts that need such values fail when it is not set.")
 }
 
 func init() {
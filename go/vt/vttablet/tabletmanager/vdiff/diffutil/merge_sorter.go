@@ -0,0 +1,49 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diffutil
+
+import (
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+// SortColumn is a column that the rows of the streams are sorted by.
+type SortColumn struct {
+	Col       int           // index of the column in the rows
+	Collation collations.ID // collation of the column, if any
+}
+
+// NewMergeSorter creates an engine.MergeSort that merges the rows of the
+// streams, which are each sorted by the sort columns.
+func NewMergeSorter(streams []engine.StreamExecutor, sortColumns []SortColumn, collationEnv *collations.Environment) *engine.MergeSort {
+	ob := make([]evalengine.OrderByParams, len(sortColumns))
+	for i, sc := range sortColumns {
+		weightStringCol := -1
+		// if the collation is nil or unknown, use binary collation to compare as bytes
+		var collation collations.ID = collations.CollationBinaryID
+		if sc.Collation != collations.Unknown {
+			collation = sc.Collation
+		}
+		ob[i] = evalengine.OrderByParams{Col: sc.Col, WeightStringCol: weightStringCol, Type: evalengine.NewType(sqltypes.Unknown, collation), CollationEnv: collationEnv}
+	}
+	return &engine.MergeSort{
+		Primitives: streams,
+		OrderBy:    ob,
+	}
+}
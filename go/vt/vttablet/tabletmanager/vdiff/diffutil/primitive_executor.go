@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package vdiff
+package diffutil
 
 import (
 	"context"
@@ -22,7 +22,7 @@ import (
 	"vitess.io/vitess/go/sqltypes"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/vterrors"
-	vtgateEngine "vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/engine"
 )
 
 /*
@@ -33,17 +33,19 @@ import (
 
 	We leverage the merge sorting functionality that vtgate uses to return sorted data from a scatter query.
 		* Merge sorter engine.Primitives are set up, one each for the source and target shards.
-		* This merge sorter is embedded by a primitiveExecutor, which also contains a query result channel,
+		* This merge sorter is embedded by a PrimitiveExecutor, which also contains a query result channel,
 			and a list of rows, sorted for the specific shard. These rows have already been popped
 			from the query result, but not yet compared since they do not yet contain the "topmost" row.
-		* The result channel is populated by the shardStreamer, which satisfies the engine.StreamExecutor interface.
-			The shardStreamer gets data using VStreamRows for that shard.
+		* The result channel is populated by the shard streamers, which satisfy the engine.StreamExecutor interface.
+			VDiff's shardStreamer gets data using VStreamRows for that shard.
+
+	The same pieces are used by other tools that compare sorted streams across shards, like LookupVindexCheck.
 */
 
-// primitiveExecutor starts execution on the top level primitive
+// PrimitiveExecutor starts execution on the top level primitive
 // and provides convenience functions for row-by-row iteration.
-type primitiveExecutor struct {
-	prim     vtgateEngine.Primitive
+type PrimitiveExecutor struct {
+	prim     engine.Primitive
 	rows     [][]sqltypes.Value
 	resultch chan *sqltypes.Result
 	err      error
@@ -51,13 +53,15 @@ type primitiveExecutor struct {
 	name string // for debug purposes only
 }
 
-func newPrimitiveExecutor(ctx context.Context, prim vtgateEngine.Primitive, name string) *primitiveExecutor {
-	pe := &primitiveExecutor{
+// NewPrimitiveExecutor starts streaming the rows of prim in the background.
+// name is only used for debugging.
+func NewPrimitiveExecutor(ctx context.Context, prim engine.Primitive, name string) *PrimitiveExecutor {
+	pe := &PrimitiveExecutor{
 		prim:     prim,
 		resultch: make(chan *sqltypes.Result, 1),
 		name:     name,
 	}
-	vcursor := &contextVCursor{ctx: ctx}
+	vcursor := &ContextVCursor{}
 
 	// handles each callback from the merge sorter, waits for a result set from the shard streamer and pushes it on the result channel
 	go func() {
@@ -75,9 +79,9 @@ func newPrimitiveExecutor(ctx context.Context, prim vtgateEngine.Primitive, name
 	return pe
 }
 
-// next gets the next row in the stream for this shard, if there's currently no rows to process in the stream then wait on the
+// Next gets the next row in the stream for this shard, if there's currently no rows to process in the stream then wait on the
 // result channel for the shard streamer to produce them.
-func (pe *primitiveExecutor) next() ([]sqltypes.Value, error) {
+func (pe *PrimitiveExecutor) Next() ([]sqltypes.Value, error) {
 	for len(pe.rows) == 0 {
 		qr, ok := <-pe.resultch
 		if !ok {
@@ -91,12 +95,12 @@ func (pe *primitiveExecutor) next() ([]sqltypes.Value, error) {
 	return row, nil
 }
 
-// drain fastforward's a shard to process (and ignore) everything from its results stream and return a count of the
+// Drain fastforward's a shard to process (and ignore) everything from its results stream and return a count of the
 // discarded rows.
-func (pe *primitiveExecutor) drain(ctx context.Context) (int64, error) {
+func (pe *PrimitiveExecutor) Drain(ctx context.Context) (int64, error) {
 	var count int64
 	for {
-		row, err := pe.next()
+		row, err := pe.Next()
 		if err != nil {
 			return 0, err
 		}
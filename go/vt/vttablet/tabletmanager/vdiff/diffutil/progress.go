@@ -0,0 +1,43 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diffutil
+
+import (
+	"math"
+	"time"
+)
+
+// EstimateProgress returns the percentage, rounded to 2 decimal points, of
+// the total rows that have been processed since startedAt, and the estimated
+// time at which all of them will be. The returned time is zero when it cannot
+// be estimated yet, or is more than a year away.
+func EstimateProgress(processed, total int64, startedAt, now time.Time) (float64, time.Time) {
+	if processed < 1 || total < 1 {
+		return 0, time.Time{}
+	}
+	percentage := math.Round(math.Min(float64(processed)/float64(total)*100, 100.00)*100) / 100
+	if percentage < 1 {
+		return percentage, time.Time{}
+	}
+	// Calculate how long 1% took, on avg, and multiply that by the % left.
+	eta := now.Add(time.Duration(float64(now.Sub(startedAt)) / percentage * (100 - percentage)))
+	// Cap the ETA at 1 year out to prevent providing nonsensical ETAs.
+	if !eta.Before(now.AddDate(1, 0, 0)) {
+		return percentage, time.Time{}
+	}
+	return percentage, eta
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diffutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateProgress(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		processed  int64
+		total      int64
+		startedAt  time.Time
+		percentage float64
+		eta        time.Time
+	}{
+		{
+			name:      "nothing processed",
+			total:     100,
+			startedAt: now.Add(-time.Minute),
+		},
+		{
+			name:      "no estimated total",
+			processed: 100,
+			startedAt: now.Add(-time.Minute),
+		},
+		{
+			name:       "less than one percent",
+			processed:  1,
+			total:      1000,
+			startedAt:  now.Add(-time.Minute),
+			percentage: 0.1,
+		},
+		{
+			name:       "a quarter",
+			processed:  25,
+			total:      100,
+			startedAt:  now.Add(-time.Minute),
+			percentage: 25,
+			eta:        now.Add(3 * time.Minute),
+		},
+		{
+			name:       "an eighth",
+			processed:  1,
+			total:      8,
+			startedAt:  now.Add(-time.Minute),
+			percentage: 12.5,
+			eta:        now.Add(7 * time.Minute),
+		},
+		{
+			name:       "more rows than estimated",
+			processed:  150,
+			total:      100,
+			startedAt:  now.Add(-time.Minute),
+			percentage: 100,
+			eta:        now,
+		},
+		{
+			name:       "more than a year away",
+			processed:  10,
+			total:      1000,
+			startedAt:  now.Add(-30 * 24 * time.Hour),
+			percentage: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			percentage, eta := EstimateProgress(tt.processed, tt.total, tt.startedAt, now)
+			require.Equal(t, tt.percentage, percentage)
+			require.Equal(t, tt.eta, eta)
+		})
+	}
+}
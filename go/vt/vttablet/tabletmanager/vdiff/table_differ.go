@@ -41,6 +41,7 @@ import (
 	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vdiff/diffutil"
 
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -525,8 +526,8 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 	}
 	dr.TableName = td.table.Name
 
-	sourceExecutor := newPrimitiveExecutor(ctx, td.sourcePrimitive, "source")
-	targetExecutor := newPrimitiveExecutor(ctx, td.targetPrimitive, "target")
+	sourceExecutor := diffutil.NewPrimitiveExecutor(ctx, td.sourcePrimitive, "source")
+	targetExecutor := diffutil.NewPrimitiveExecutor(ctx, td.targetPrimitive, "target")
 	var sourceRow, lastProcessedRow, targetRow []sqltypes.Value
 	advanceSource := true
 	advanceTarget := true
@@ -571,14 +572,14 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 			return dr, nil
 		}
 		if advanceSource {
-			sourceRow, err = sourceExecutor.next()
+			sourceRow, err = sourceExecutor.Next()
 			if err != nil {
 				log.Error(err)
 				return nil, err
 			}
 		}
 		if advanceTarget {
-			targetRow, err = targetExecutor.next()
+			targetRow, err = targetExecutor.Next()
 			if err != nil {
 				log.Error(err)
 				return nil, err
@@ -599,7 +600,7 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 			dr.ExtraRowsTargetDiffs = append(dr.ExtraRowsTargetDiffs, diffRow)
 
 			// Drain target, update count.
-			count, err := targetExecutor.drain(ctx)
+			count, err := targetExecutor.Drain(ctx)
 			if err != nil {
 				return nil, err
 			}
@@ -615,7 +616,7 @@ func (td *tableDiffer) diff(ctx context.Context, coreOpts *tabletmanagerdatapb.V
 				return nil, vterrors.Wrap(err, "unexpected error generating diff")
 			}
 			dr.ExtraRowsSourceDiffs = append(dr.ExtraRowsSourceDiffs, diffRow)
-			count, err := sourceExecutor.drain(ctx)
+			count, err := sourceExecutor.Drain(ctx)
 			if err != nil {
 				return nil, err
 			}
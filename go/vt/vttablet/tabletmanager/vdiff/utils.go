@@ -22,7 +22,7 @@ import (
 	"strings"
 
 	"vitess.io/vitess/go/vt/sqlparser"
-	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vdiff/diffutil"
 
 	"vitess.io/vitess/go/vt/binlog/binlogplayer"
 	"vitess.io/vitess/go/vt/log"
@@ -39,20 +39,11 @@ func newMergeSorter(participants map[string]*shardStreamer, comparePKs []compare
 	for _, participant := range participants {
 		prims = append(prims, participant)
 	}
-	ob := make([]evalengine.OrderByParams, len(comparePKs))
+	sortColumns := make([]diffutil.SortColumn, len(comparePKs))
 	for i, cpk := range comparePKs {
-		weightStringCol := -1
-		// if the collation is nil or unknown, use binary collation to compare as bytes
-		var collation collations.ID = collations.CollationBinaryID
-		if cpk.collation != collations.Unknown {
-			collation = cpk.collation
-		}
-		ob[i] = evalengine.OrderByParams{Col: cpk.colIndex, WeightStringCol: weightStringCol, Type: evalengine.NewType(sqltypes.Unknown, collation), CollationEnv: collationEnv}
-	}
-	return &engine.MergeSort{
-		Primitives: prims,
-		OrderBy:    ob,
+		sortColumns[i] = diffutil.SortColumn{Col: cpk.colIndex, Collation: cpk.collation}
 	}
+	return diffutil.NewMergeSorter(prims, sortColumns, collationEnv)
 }
 
 // -----------------------------------------------------------------
@@ -177,9 +177,12 @@ func (dbc *Conn) execOnce(ctx context.Context, query string, maxrows int, wantfi
 		err    error
 	}
 
+	attributes := tabletenv.QueryAttributesFromContext(ctx)
 	ch := make(chan execResult)
 	go func() {
+		dbc.conn.SetQueryAttributes(attributes)
 		result, err := dbc.conn.ExecuteFetch(query, maxrows, wantfields)
+		dbc.conn.SetQueryAttributes(nil)
 		ch <- execResult{result, err}
 		close(ch)
 	}()
@@ -312,9 +315,13 @@ func (dbc *Conn) streamOnce(
 	now := time.Now()
 	defer dbc.stats.MySQLTimings.Record("ExecStream", now)
 
+	attributes := tabletenv.QueryAttributesFromContext(ctx)
 	ch := make(chan error)
 	go func() {
-		ch <- dbc.conn.ExecuteStreamFetch(query, callback, alloc, streamBufferSize)
+		dbc.conn.SetQueryAttributes(attributes)
+		err := dbc.conn.ExecuteStreamFetch(query, callback, alloc, streamBufferSize)
+		dbc.conn.SetQueryAttributes(nil)
+		ch <- err
 		close(ch)
 	}()
 
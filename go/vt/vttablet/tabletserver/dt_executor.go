@@ -93,7 +93,7 @@ func (dte *DTExecutor) Prepare(transactionID int64, dtid string) error {
 	for _, query := range conn.TxProperties().Queries {
 		qr := dte.qe.queryRuleSources.FilterByPlan(query.Sql, 0, query.Tables...)
 		if qr != nil {
-			act, _, _, _ := qr.GetAction("", "", nil, sqlparser.MarginComments{})
+			act, _, _, _ := qr.GetAction("", "", nil, sqlparser.MarginComments{}, nil)
 			if act != rules.QRContinue {
 				dte.te.txPool.RollbackAndRelease(dte.ctx, conn)
 				return vterrors.VT10002("cannot prepare the transaction due to query rule")
@@ -113,7 +113,7 @@ func (dte *DTExecutor) Prepare(transactionID int64, dtid string) error {
 	for _, query := range conn.TxProperties().Queries {
 		qr := dte.qe.queryRuleSources.FilterByPlan(query.Sql, 0, query.Tables...)
 		if qr != nil {
-			act, _, _, _ := qr.GetAction("", "", nil, sqlparser.MarginComments{})
+			act, _, _, _ := qr.GetAction("", "", nil, sqlparser.MarginComments{}, nil)
 			if act != rules.QRContinue {
 				dte.te.txPool.RollbackAndRelease(dte.ctx, conn)
 				dte.te.preparedPool.FetchForRollback(dtid)
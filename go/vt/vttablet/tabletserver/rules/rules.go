@@ -175,13 +175,14 @@ func (qrs *Rules) GetAction(
 	user string,
 	bindVars map[string]*querypb.BindVariable,
 	marginComments sqlparser.MarginComments,
+	queryAttributes map[string]string,
 ) (
 	action Action,
 	cancelCtx context.Context,
 	timeout time.Duration,
 	desc string) {
 	for _, qr := range qrs.rules {
-		if act := qr.GetAction(ip, user, bindVars, marginComments); act != QRContinue {
+		if act := qr.GetAction(ip, user, bindVars, marginComments, queryAttributes); act != QRContinue {
 			return act, qr.cancelCtx, qr.timeout, qr.Description
 		}
 	}
@@ -216,6 +217,9 @@ type Rule struct {
 	// All BindVar conditions have to be fulfilled to make this true (AND)
 	bindVarConds []BindVarCond
 
+	// All query attribute conditions have to match to make this true (AND)
+	queryAttributes map[string]namedRegexp
+
 	// Action to be performed on trigger
 	act Action
 
@@ -272,9 +276,23 @@ func (qr *Rule) Equal(other *Rule) bool {
 		reflect.DeepEqual(qr.plans, other.plans) &&
 		reflect.DeepEqual(qr.tableNames, other.tableNames) &&
 		reflect.DeepEqual(qr.bindVarConds, other.bindVarConds) &&
+		queryAttributesEqual(qr.queryAttributes, other.queryAttributes) &&
 		qr.act == other.act)
 }
 
+func queryAttributesEqual(a, b map[string]namedRegexp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, nr := range a {
+		other, ok := b[name]
+		if !ok || !nr.Equal(other) {
+			return false
+		}
+	}
+	return true
+}
+
 // Copy performs a deep copy of a Rule.
 func (qr *Rule) Copy() (newqr *Rule) {
 	newqr = &Rule{
@@ -301,6 +319,12 @@ func (qr *Rule) Copy() (newqr *Rule) {
 		newqr.bindVarConds = make([]BindVarCond, len(qr.bindVarConds))
 		copy(newqr.bindVarConds, qr.bindVarConds)
 	}
+	if qr.queryAttributes != nil {
+		newqr.queryAttributes = make(map[string]namedRegexp, len(qr.queryAttributes))
+		for name, nr := range qr.queryAttributes {
+			newqr.queryAttributes[name] = nr
+		}
+	}
 	return newqr
 }
 
@@ -333,6 +357,9 @@ func (qr *Rule) MarshalJSON() ([]byte, error) {
 	if qr.bindVarConds != nil {
 		safeEncode(b, `,"BindVarConds":`, qr.bindVarConds)
 	}
+	if qr.queryAttributes != nil {
+		safeEncode(b, `,"QueryAttributes":`, qr.queryAttributes)
+	}
 	if qr.act != QRContinue {
 		safeEncode(b, `,"Action":`, qr.act)
 	}
@@ -394,6 +421,21 @@ func (qr *Rule) SetTrailingCommentCond(pattern string) (err error) {
 	return
 }
 
+// AddQueryAttributeCond adds a regular expression condition for the value of
+// the query attribute name. An absent query attribute matches as an empty string.
+// All query attribute conditions have to match for the Rule to be a match.
+func (qr *Rule) AddQueryAttributeCond(name, pattern string) error {
+	re, err := regexp.Compile(makeExact(pattern))
+	if err != nil {
+		return err
+	}
+	if qr.queryAttributes == nil {
+		qr.queryAttributes = make(map[string]namedRegexp)
+	}
+	qr.queryAttributes[name] = namedRegexp{name: pattern, Regexp: re}
+	return nil
+}
+
 // makeExact forces a full string match for the regex instead of substring
 func makeExact(pattern string) string {
 	return fmt.Sprintf("^%s$", pattern)
@@ -486,6 +528,7 @@ func (qr *Rule) GetAction(
 	user string,
 	bindVars map[string]*querypb.BindVariable,
 	marginComments sqlparser.MarginComments,
+	queryAttributes map[string]string,
 ) Action {
 	if qr.cancelCtx != nil {
 		select {
@@ -514,6 +557,11 @@ func (qr *Rule) GetAction(
 			return QRContinue
 		}
 	}
+	for name, nr := range qr.queryAttributes {
+		if !reMatch(nr.Regexp, queryAttributes[name]) {
+			return QRContinue
+		}
+	}
 	return qr.act
 }
 
@@ -874,6 +922,7 @@ func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 	for k, v := range ruleInfo {
 		var sv string
 		var lv []any
+		var mv map[string]any
 		var ok bool
 		switch k {
 		case "Name", "Description", "RequestIP", "User", "Query", "Action", "LeadingComment", "TrailingComment":
@@ -886,6 +935,11 @@ func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 			if !ok {
 				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want list for %s", k)
 			}
+		case "QueryAttributes":
+			mv, ok = v.(map[string]any)
+			if !ok {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want map for %s", k)
+			}
 		default:
 			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unrecognized tag %s", k)
 		}
@@ -950,6 +1004,17 @@ func BuildQueryRule(ruleInfo map[string]any) (qr *Rule, err error) {
 					return nil, err
 				}
 			}
+		case "QueryAttributes":
+			for name, p := range mv {
+				pattern, ok := p.(string)
+				if !ok {
+					return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "want string for QueryAttributes")
+				}
+				err = qr.AddQueryAttributeCond(name, pattern)
+				if err != nil {
+					return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "could not set QueryAttributes condition: %v", pattern)
+				}
+			}
 		case "Action":
 			switch sv {
 			case "FAIL":
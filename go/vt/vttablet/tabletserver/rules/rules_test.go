@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/sqlparser"
@@ -88,6 +89,7 @@ func TestCopy(t *testing.T) {
 	qr1.AddPlanCond(planbuilder.PlanSelect)
 	qr1.AddTableCond("aa")
 	qr1.AddBindVarCond("a", true, false, QRNoOp, nil)
+	qr1.AddQueryAttributeCond("tenant", "acme")
 
 	qr2 := NewQueryRule("rule 2", "r2", QRFail)
 	qrs1.Add(qr1)
@@ -543,23 +545,23 @@ func TestAction(t *testing.T) {
 		Trailing: "other trailing comments",
 	}
 
-	action, cancelCtx, timeout, desc := qrs.GetAction("123", "user1", bv, mc)
+	action, cancelCtx, timeout, desc := qrs.GetAction("123", "user1", bv, mc, nil)
 	assert.Equalf(t, action, QRFail, "expected fail, got %v", action)
 	assert.Equalf(t, timeout, time.Duration(0), "expected zero timeout")
 	assert.Equalf(t, desc, "rule 1", "want rule 1, got %s", desc)
 	assert.Nil(t, cancelCtx)
 
-	action, cancelCtx, timeout, desc = qrs.GetAction("1234", "user", bv, mc)
+	action, cancelCtx, timeout, desc = qrs.GetAction("1234", "user", bv, mc, nil)
 	assert.Equalf(t, action, QRFailRetry, "want fail_retry, got: %s", action)
 	assert.Equalf(t, timeout, time.Duration(0), "expected zero timeout")
 	assert.Equalf(t, desc, "rule 2", "want rule 2, got %s", desc)
 	assert.Nil(t, cancelCtx)
 
-	action, _, _, _ = qrs.GetAction("1234", "user1", bv, mc)
+	action, _, _, _ = qrs.GetAction("1234", "user1", bv, mc, nil)
 	assert.Equalf(t, action, QRContinue, "want continue, got %s", action)
 
 	bv["a"] = sqltypes.Uint64BindVariable(1)
-	action, _, _, desc = qrs.GetAction("1234", "user1", bv, mc)
+	action, _, _, desc = qrs.GetAction("1234", "user1", bv, mc, nil)
 	assert.Equalf(t, action, QRFail, "want fail, got %s", action)
 	assert.Equalf(t, desc, "rule 3", "want rule 3, got %s", desc)
 
@@ -572,7 +574,7 @@ func TestAction(t *testing.T) {
 	newQrs := qrs.Copy()
 	newQrs.Add(qr4)
 
-	action, _, _, desc = newQrs.GetAction("1234", "user1", bv, mc)
+	action, _, _, desc = newQrs.GetAction("1234", "user1", bv, mc, nil)
 	assert.Equalf(t, action, QRFail, "want fail, got %s", action)
 	assert.Equalf(t, desc, "rule 4", "want rule 4, got %s", desc)
 
@@ -581,9 +583,25 @@ func TestAction(t *testing.T) {
 
 	newQrs = qrs.Copy()
 	newQrs.Add(qr5)
-	action, _, _, desc = newQrs.GetAction("1234", "user1", bv, mc)
+	action, _, _, desc = newQrs.GetAction("1234", "user1", bv, mc, nil)
 	assert.Equalf(t, action, QRFail, "want fail, got %s", action)
 	assert.Equalf(t, desc, "rule 5", "want rule 5, got %s", desc)
+
+	qr6 := NewQueryRule("rule 6", "r6", QRFail)
+	err := qr6.AddQueryAttributeCond("tenant", "acme.*")
+	require.NoError(t, err)
+
+	newQrs = qrs.Copy()
+	newQrs.Add(qr6)
+	action, _, _, _ = newQrs.GetAction("1234", "user1", bv, mc, nil)
+	assert.Equalf(t, action, QRContinue, "want continue, got %s", action)
+
+	action, _, _, _ = newQrs.GetAction("1234", "user1", bv, mc, map[string]string{"tenant": "other"})
+	assert.Equalf(t, action, QRContinue, "want continue, got %s", action)
+
+	action, _, _, desc = newQrs.GetAction("1234", "user1", bv, mc, map[string]string{"tenant": "acme1", "request_id": "42"})
+	assert.Equalf(t, action, QRFail, "want fail, got %s", action)
+	assert.Equalf(t, desc, "rule 6", "want rule 6, got %s", desc)
 }
 
 func TestImport(t *testing.T) {
@@ -607,6 +625,7 @@ func TestImport(t *testing.T) {
 			"Operator": "==",
 			"Value": 123
 		}],
+		"QueryAttributes": {"request_id": "[0-9]+", "tenant": "acme"},
 		"Action": "FAIL_RETRY"
 	},{
 		"Description": "desc2",
@@ -709,6 +728,9 @@ var invalidjsons = []InvalidJSONCase{
 	{`[{"Plans": 1 }]`, "want list for Plans"},
 	{`[{"TableNames": 1 }]`, "want list for TableNames"},
 	{`[{"BindVarConds": 1 }]`, "want list for BindVarConds"},
+	{`[{"QueryAttributes": 1 }]`, "want map for QueryAttributes"},
+	{`[{"QueryAttributes": {"tenant": 1} }]`, "want string for QueryAttributes"},
+	{`[{"QueryAttributes": {"tenant": "["} }]`, "could not set QueryAttributes condition: ["},
 	{`[{"RequestIP": "[" }]`, "could not set IP condition: ["},
 	{`[{"User": "[" }]`, "could not set User condition: ["},
 	{`[{"Query": "[" }]`, "could not set Query condition: ["},
@@ -62,6 +62,8 @@ type LogStats struct {
 	ReservedID           int64
 	Error                error
 	CachedPlan           bool
+	// QueryAttributes are the attributes the client sent along with the query.
+	QueryAttributes map[string]string
 }
 
 // NewLogStats constructs a new LogStats with supplied Method and ctx
@@ -236,6 +238,8 @@ func (stats *LogStats) Logf(w io.Writer, params url.Values) error {
 	log.Int(int64(stats.SizeOfResponse()))
 	log.Key("Error")
 	log.String(stats.ErrorStr())
+	log.Key("QueryAttributes")
+	log.StringMap(stats.QueryAttributes)
 
 	// logstats from the vttablet are always tab-terminated; keep this for backwards
 	// compatibility for existing parsers
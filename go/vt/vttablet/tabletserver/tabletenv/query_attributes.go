@@ -0,0 +1,39 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletenv
+
+import (
+	"context"
+)
+
+type queryAttributesKey struct{}
+
+// NewContextWithQueryAttributes returns a context that carries the query
+// attributes the client sent along with the query.
+func NewContextWithQueryAttributes(ctx context.Context, attributes map[string]string) context.Context {
+	if len(attributes) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, queryAttributesKey{}, attributes)
+}
+
+// QueryAttributesFromContext returns the query attributes stored in the
+// context, if any.
+func QueryAttributesFromContext(ctx context.Context) map[string]string {
+	attributes, _ := ctx.Value(queryAttributesKey{}).(map[string]string)
+	return attributes
+}
@@ -261,6 +261,26 @@ func (tsv *TabletServer) loadQueryTimeoutWithOptions(options *querypb.ExecuteOpt
 	return time.Duration(tsv.QueryTimeout.Load())
 }
 
+// loadStreamQueryTimeoutWithTxAndOptions returns the timeout for a streaming query. Streaming queries are not
+// bounded by the query timeout, but they do honor the authoritative timeout sent by vtgate and, inside
+// a transaction, the OLAP transaction timeout.
+func (tsv *TabletServer) loadStreamQueryTimeoutWithTxAndOptions(txID int64, options *querypb.ExecuteOptions) time.Duration {
+	var timeout time.Duration
+	if options != nil && options.Timeout != nil {
+		timeout = time.Duration(options.GetAuthoritativeTimeout()) * time.Millisecond
+	}
+
+	if txID == 0 {
+		return timeout
+	}
+
+	// StreamExecute calls happen for OLAP only, so we can directly fetch the OLAP TX timeout.
+	txTimeout := tsv.config.TxTimeoutForWorkload(querypb.ExecuteOptions_OLAP)
+
+	// Use the smaller of the two values (0 means infinity).
+	return smallerTimeout(timeout, txTimeout)
+}
+
 // onlineDDLExecutorToggleTableBuffer is called by onlineDDLExecutor as a callback function. onlineDDLExecutor
 // uses it to start/stop query buffering for a given table.
 // It is onlineDDLExecutor's responsibility to make sure buffering is stopped after some definite amount of time.
@@ -899,14 +919,8 @@ func (tsv *TabletServer) StreamExecute(ctx context.Context, target *querypb.Targ
 }
 
 func (tsv *TabletServer) streamExecute(ctx context.Context, target *querypb.Target, sql string, bindVariables map[string]*querypb.BindVariable, transactionID int64, reservedID int64, settings []string, options *querypb.ExecuteOptions, callback func(*sqltypes.Result) error) error {
-	allowOnShutdown := false
-	var timeout time.Duration
-	if transactionID != 0 {
-		allowOnShutdown = true
-		// Use the transaction timeout. StreamExecute calls happen for OLAP only,
-		// so we can directly fetch the OLAP TX timeout.
-		timeout = tsv.config.TxTimeoutForWorkload(querypb.ExecuteOptions_OLAP)
-	}
+	allowOnShutdown := transactionID != 0
+	timeout := tsv.loadStreamQueryTimeoutWithTxAndOptions(transactionID, options)
 
 	return tsv.execRequest(
 		ctx, timeout,
@@ -1449,12 +1463,14 @@ func (tsv *TabletServer) execRequest(
 	logStats.Target = target
 	logStats.OriginalSQL = sql
 	logStats.BindVariables = sqltypes.CopyBindVariables(bindVariables)
+	logStats.QueryAttributes = options.GetQueryAttributes()
 	defer tsv.handlePanicAndSendLogStats(sql, bindVariables, logStats)
 
 	if err = tsv.sm.StartRequest(ctx, target, allowOnShutdown); err != nil {
 		return err
 	}
 
+	ctx = tabletenv.NewContextWithQueryAttributes(ctx, options.GetQueryAttributes())
 	ctx, cancel := withTimeout(ctx, timeout, options)
 	defer func() {
 		cancel()
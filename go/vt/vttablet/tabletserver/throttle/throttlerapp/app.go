@@ -71,6 +71,8 @@ const (
 	MessagerName      Name = "messager"
 	SchemaTrackerName Name = "schema-tracker"
 
+	LookupVindexCheckName Name = "lookup-vindex-check"
+
 	TestingName                Name = "test"
 	TestingAlwaysThrottlerName Name = "always-throttled-app"
 )